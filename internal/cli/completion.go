@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ProgramName is the name of the binary that completions are generated for
+const ProgramName = "prompter"
+
+// CompletionShells lists the shells GenerateCompletion supports
+var CompletionShells = []string{"bash", "zsh", "fish"}
+
+// flagValueCompletions lists fixed values for flags that take one of a known set
+var flagValueCompletions = map[string][]string{
+	"completion": CompletionShells,
+	"format":     {"xml", "json", "markdown"},
+}
+
+// fileFlags lists the flags whose value is a file path
+var fileFlags = map[string]bool{
+	"files":  true,
+	"output": true,
+}
+
+// completionFlag describes a command line flag for completion scripts
+type completionFlag struct {
+	name   string
+	usage  string
+	isBool bool
+}
+
+// GenerateCompletion writes a completion script for shell to w, covering the
+// flags registered on flag.CommandLine. The directory argument completes to
+// directories, -personas to the personas/*.md files of the project and
+// -files and -output to file paths.
+func GenerateCompletion(shell string, w io.Writer) error {
+	return generateCompletion(shell, flag.CommandLine, w)
+}
+
+// generateCompletion writes a completion script for the flags of fs
+func generateCompletion(shell string, fs *flag.FlagSet, w io.Writer) error {
+	flags := commandLineFlags(fs)
+
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w, flags)
+	case "zsh":
+		return writeZshCompletion(w, flags)
+	case "fish":
+		return writeFishCompletion(w, flags)
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(CompletionShells, ", "))
+	}
+}
+
+// commandLineFlags collects the flags of a flag set in name order
+func commandLineFlags(fs *flag.FlagSet) []completionFlag {
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, completionFlag{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && boolFlag.IsBoolFlag(),
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].name < flags[j].name })
+	return flags
+}
+
+// personaListCommand is a shell snippet listing persona names for the project
+// directory in $dir
+const personaListCommand = `for f in "$dir"/personas/*.md; do [ -e "$f" ] && basename "$f" .md; done`
+
+func writeBashCompletion(w io.Writer, flags []completionFlag) error {
+	var names, valueFlags []string
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+		if !f.isBool {
+			valueFlags = append(valueFlags, "-"+f.name)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", ProgramName)
+	fmt.Fprintf(&b, "_%s_completion() {\n", ProgramName)
+	b.WriteString("    local cur prev dir i\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	b.WriteString("    # The project directory is the first argument that isn't a flag or flag value\n")
+	b.WriteString("    dir=.\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	fmt.Fprintf(&b, "            %s) ((i++)) ;;\n", strings.Join(valueFlags, "|"))
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) dir=\"${COMP_WORDS[i]}\" ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n\n")
+	b.WriteString("    case \"$prev\" in\n")
+	for _, f := range flags {
+		if f.isBool {
+			continue
+		}
+		fmt.Fprintf(&b, "        -%s)\n", f.name)
+		switch {
+		case flagValueCompletions[f.name] != nil:
+			fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(flagValueCompletions[f.name], " "))
+		case f.name == "personas":
+			fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"$(%s)\" -- \"$cur\"))\n", personaListCommand)
+		case fileFlags[f.name]:
+			b.WriteString("            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		}
+		b.WriteString("            return ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(names, " "))
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    COMPREPLY=($(compgen -d -- \"$cur\"))\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o filenames -F _%s_completion %s\n", ProgramName, ProgramName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeZshCompletion(w io.Writer, flags []completionFlag) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", ProgramName)
+	fmt.Fprintf(&b, "_%s_personas() {\n", ProgramName)
+	b.WriteString("    local dir=${line[1]:-.}\n")
+	fmt.Fprintf(&b, "    local -a personas\n    personas=(${(f)\"$(%s)\"})\n", personaListCommand)
+	b.WriteString("    _describe 'persona' personas\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "_%s() {\n", ProgramName)
+	b.WriteString("    _arguments -s \\\n")
+	for _, f := range flags {
+		usage := zshEscape(f.usage)
+		switch {
+		case f.isBool:
+			fmt.Fprintf(&b, "        '-%s[%s]' \\\n", f.name, usage)
+		case flagValueCompletions[f.name] != nil:
+			fmt.Fprintf(&b, "        '-%s[%s]:%s:(%s)' \\\n", f.name, usage, f.name, strings.Join(flagValueCompletions[f.name], " "))
+		case f.name == "personas":
+			fmt.Fprintf(&b, "        '-%s[%s]:persona:_%s_personas' \\\n", f.name, usage, ProgramName)
+		case fileFlags[f.name]:
+			fmt.Fprintf(&b, "        '-%s[%s]:file:_files' \\\n", f.name, usage)
+		default:
+			fmt.Fprintf(&b, "        '-%s[%s]:%s:' \\\n", f.name, usage, f.name)
+		}
+	}
+	b.WriteString("        '1:directory:_directories'\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "compdef _%s %s\n", ProgramName, ProgramName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishCompletion(w io.Writer, flags []completionFlag) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", ProgramName)
+	fmt.Fprintf(&b, "function __%s_personas\n", ProgramName)
+	b.WriteString("    set -l dir .\n")
+	b.WriteString("    for arg in (commandline -opc)[2..-1]\n")
+	b.WriteString("        if test -d \"$arg\"\n")
+	b.WriteString("            set dir $arg\n")
+	b.WriteString("        end\n")
+	b.WriteString("    end\n")
+	b.WriteString("    for f in $dir/personas/*.md\n")
+	b.WriteString("        basename $f .md\n")
+	b.WriteString("    end\n")
+	b.WriteString("end\n\n")
+	fmt.Fprintf(&b, "complete -c %s -f -a '(__fish_complete_directories)'\n", ProgramName)
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c %s -o %s -d '%s'", ProgramName, f.name, fishEscape(f.usage))
+		switch {
+		case f.isBool:
+		case flagValueCompletions[f.name] != nil:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(flagValueCompletions[f.name], " "))
+		case f.name == "personas":
+			line += fmt.Sprintf(" -x -a '(__%s_personas)'", ProgramName)
+		case fileFlags[f.name]:
+			line += " -r -F"
+		default:
+			line += " -x"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zshEscape escapes a flag description for use inside a single-quoted _arguments spec
+func zshEscape(s string) string {
+	s = strings.NewReplacer("[", "\\[", "]", "\\]", ":", "\\:").Replace(s)
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
+
+// fishEscape escapes a flag description for use inside single quotes
+func fishEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testFlagSet mirrors the flags registered by main
+func testFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(ProgramName, flag.ContinueOnError)
+	fs.Bool("headless", false, "generate the prompt to stdout without starting the TUI")
+	fs.String("files", "", "comma-separated files to include in headless mode")
+	fs.String("personas", "", "comma-separated personas to use in headless mode")
+	fs.String("prompt", "", "user prompt to use in headless mode")
+	fs.String("format", "xml", "output format in headless mode (xml or json)")
+	fs.String("completion", "", "print a completion script for the given shell (bash, zsh or fish)")
+	return fs
+}
+
+func TestGenerateCompletion_Bash(t *testing.T) {
+	var out bytes.Buffer
+	if err := generateCompletion("bash", testFlagSet(), &out); err != nil {
+		t.Fatalf("generateCompletion failed: %v", err)
+	}
+	script := out.String()
+
+	expected := []string{
+		"_prompter_completion() {",
+		"complete -o filenames -F _prompter_completion prompter",
+		`compgen -W "bash zsh fish"`,
+		`compgen -W "xml json markdown"`,
+		"/personas/*.md",
+		"-completion -files -format -headless -personas -prompt",
+		"compgen -d",
+	}
+	for _, want := range expected {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected bash completion to contain %q, got:\n%s", want, script)
+		}
+	}
+
+	// Boolean flags take no value, so they get no value completion
+	if strings.Contains(script, "        -headless)") {
+		t.Error("Expected no value completion for boolean flag -headless")
+	}
+}
+
+func TestGenerateCompletion_ZshAndFish(t *testing.T) {
+	var zsh bytes.Buffer
+	if err := generateCompletion("zsh", testFlagSet(), &zsh); err != nil {
+		t.Fatalf("generateCompletion(zsh) failed: %v", err)
+	}
+	for _, want := range []string{"#compdef prompter", "'-format[output format in headless mode (xml or json)]:format:(xml json markdown)'", "'1:directory:_directories'"} {
+		if !strings.Contains(zsh.String(), want) {
+			t.Errorf("Expected zsh completion to contain %q, got:\n%s", want, zsh.String())
+		}
+	}
+
+	var fish bytes.Buffer
+	if err := generateCompletion("fish", testFlagSet(), &fish); err != nil {
+		t.Fatalf("generateCompletion(fish) failed: %v", err)
+	}
+	for _, want := range []string{"complete -c prompter -o completion", "-x -a 'bash zsh fish'", "(__prompter_personas)"} {
+		if !strings.Contains(fish.String(), want) {
+			t.Errorf("Expected fish completion to contain %q, got:\n%s", want, fish.String())
+		}
+	}
+}
+
+func TestGenerateCompletion_UnsupportedShell(t *testing.T) {
+	if err := GenerateCompletion("powershell", io.Discard); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"coding-prompts-tui/internal/prompt"
+)
+
+// RunHeadless generates a prompt for the given files without starting the TUI
+// and writes it to w. Relative file paths are resolved against targetDir and
+// files are included in the order given.
+func RunHeadless(targetDir string, files []string, personaNames []string, userPrompt string, format string, w io.Writer) error {
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("error getting absolute path: %w", err)
+	}
+
+	paths, err := ResolveFileList(absTarget, files)
+	if err != nil {
+		return err
+	}
+
+	output, err := prompt.BuildOrdered(absTarget, paths, userPrompt, personaNames, prompt.OutputFormat(format))
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, output); err != nil {
+		return fmt.Errorf("error writing prompt: %w", err)
+	}
+	return nil
+}
+
+// ResolveFileList makes file paths absolute, resolving relative paths against
+// targetDir. It returns an error for any path outside targetDir.
+func ResolveFileList(targetDir string, files []string) ([]string, error) {
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return nil, fmt.Errorf("error getting absolute path: %w", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(absTarget, file)
+		}
+		file = filepath.Clean(file)
+		rel, err := filepath.Rel(absTarget, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside %s", file, absTarget)
+		}
+		paths = append(paths, file)
+	}
+	return paths, nil
+}
+
+// ReadFileList reads file paths from r, one per line, skipping blank lines
+func ReadFileList(r io.Reader) ([]string, error) {
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		files = append(files, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file list: %w", err)
+	}
+	return files, nil
+}
+
+// SplitList splits a comma-separated flag value, dropping empty items
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
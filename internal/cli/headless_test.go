@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type headlessPrompt struct {
+	XMLName  xml.Name `xml:"prompt"`
+	FileTree string   `xml:"filetree"`
+	Files    []struct {
+		Name    string `xml:"name,attr"`
+		Content string `xml:",chardata"`
+	} `xml:"file"`
+	SystemPrompts []struct {
+		Type    string `xml:"type,attr"`
+		Content string `xml:",chardata"`
+	} `xml:"SystemPrompt"`
+	UserPrompt string `xml:"UserPrompt"`
+}
+
+func setupProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":        "package main",
+		"pkg/util.go":    "package pkg",
+		"pkg/helpers.go": "package pkg // helpers",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestRunHeadless_XML(t *testing.T) {
+	dir := setupProject(t)
+
+	fileList, err := ReadFileList(strings.NewReader("pkg/util.go\n\n" + filepath.Join(dir, "main.go") + "\n"))
+	if err != nil {
+		t.Fatalf("ReadFileList failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := RunHeadless(dir, fileList, []string{"reviewer"}, "Review this", "xml", &out); err != nil {
+		t.Fatalf("RunHeadless failed: %v", err)
+	}
+
+	var p headlessPrompt
+	if err := xml.Unmarshal(out.Bytes(), &p); err != nil {
+		t.Fatalf("Output is not valid XML: %v\n%s", err, out.String())
+	}
+
+	if len(p.Files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(p.Files))
+	}
+	if p.Files[0].Name != filepath.Join("pkg", "util.go") || p.Files[1].Name != "main.go" {
+		t.Errorf("Expected files in input order, got %q, %q", p.Files[0].Name, p.Files[1].Name)
+	}
+	if p.Files[0].Content != "package pkg" {
+		t.Errorf("Unexpected file content: %q", p.Files[0].Content)
+	}
+	if !strings.Contains(p.FileTree, "helpers.go") {
+		t.Errorf("Expected file tree to list all project files, got:\n%s", p.FileTree)
+	}
+	if len(p.SystemPrompts) != 1 || p.SystemPrompts[0].Type != "reviewer" {
+		t.Errorf("Expected a single reviewer system prompt, got %+v", p.SystemPrompts)
+	}
+	if p.UserPrompt != "Review this" {
+		t.Errorf("Expected user prompt 'Review this', got %q", p.UserPrompt)
+	}
+}
+
+func TestRunHeadless_JSON(t *testing.T) {
+	dir := setupProject(t)
+
+	var out bytes.Buffer
+	if err := RunHeadless(dir, SplitList("main.go, pkg/helpers.go"), nil, "", "json", &out); err != nil {
+		t.Fatalf("RunHeadless failed: %v", err)
+	}
+
+	var p struct {
+		Files []struct {
+			Name string `json:"name"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &p); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(p.Files) != 2 {
+		t.Errorf("Expected 2 files, got %d", len(p.Files))
+	}
+}
+
+func TestRunHeadless_Errors(t *testing.T) {
+	dir := setupProject(t)
+
+	var out bytes.Buffer
+	if err := RunHeadless(dir, []string{"missing.go"}, nil, "", "xml", &out); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if err := RunHeadless(dir, []string{"main.go"}, nil, "", "yaml", &out); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output on error, got %q", out.String())
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" a.go, ,b.go,")
+	if len(got) != 2 || got[0] != "a.go" || got[1] != "b.go" {
+		t.Errorf("SplitList returned %q", got)
+	}
+	if SplitList("") != nil {
+		t.Error("Expected nil for empty input")
+	}
+}
+
+func TestResolveFileList(t *testing.T) {
+	dir := setupProject(t)
+
+	paths, err := ResolveFileList(dir, []string{"main.go", "./pkg/../pkg/util.go", filepath.Join(dir, "pkg", "helpers.go")})
+	if err != nil {
+		t.Fatalf("ResolveFileList failed: %v", err)
+	}
+	expected := []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "pkg", "util.go"),
+		filepath.Join(dir, "pkg", "helpers.go"),
+	}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	for _, outside := range []string{"../other.go", filepath.Join(filepath.Dir(dir), "other.go")} {
+		if _, err := ResolveFileList(dir, []string{outside}); err == nil {
+			t.Errorf("Expected error for %s outside the target directory", outside)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/persona"
+)
+
+// ListPersonas writes the names of the personas in the personas/ directory of
+// targetDir to w, one per line in alphabetical order
+func ListPersonas(targetDir string, w io.Writer) error {
+	manager := persona.NewManager(targetDir)
+	if err := manager.DiscoverPersonas(); err != nil {
+		return err
+	}
+
+	for _, name := range manager.GetAvailablePersonas() {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return fmt.Errorf("error writing personas: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListWorkspaces writes one line per workspace to w: the path and the time it
+// was last opened (RFC 3339), separated by a tab. Workspaces are listed in the
+// order given, most recent first as returned by GetRecentWorkspaces.
+func ListWorkspaces(workspaces []config.WorkspaceState, w io.Writer) error {
+	for _, ws := range workspaces {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", ws.Path, ws.LastAccessed.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("error writing workspaces: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+)
+
+func TestListPersonas(t *testing.T) {
+	dir := t.TempDir()
+	personasDir := filepath.Join(dir, "personas")
+	if err := os.MkdirAll(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas directory: %v", err)
+	}
+	for _, name := range []string{"reviewer.md", "default.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(personasDir, name), []byte("persona"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ListPersonas(dir, &buf); err != nil {
+		t.Fatalf("ListPersonas failed: %v", err)
+	}
+	if got := buf.String(); got != "default\nreviewer\n" {
+		t.Errorf("Expected default and reviewer, got %q", got)
+	}
+
+	if err := ListPersonas(t.TempDir(), &buf); err == nil {
+		t.Error("Expected an error without a personas directory")
+	}
+}
+
+func TestListWorkspaces(t *testing.T) {
+	accessed := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	workspaces := []config.WorkspaceState{
+		{Path: "/projects/new", LastAccessed: accessed},
+		{Path: "/projects/old", LastAccessed: accessed.Add(-24 * time.Hour)},
+	}
+
+	var buf bytes.Buffer
+	if err := ListWorkspaces(workspaces, &buf); err != nil {
+		t.Fatalf("ListWorkspaces failed: %v", err)
+	}
+	expected := "/projects/new\t2024-05-01T12:30:00Z\n/projects/old\t2024-04-30T12:30:00Z\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
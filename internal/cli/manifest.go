@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadManifest reads the files listed in the manifest at path, one per line,
+// skipping blank lines and comments starting with #. Relative paths are
+// resolved against baseDir. It returns an error for a file that is outside
+// baseDir, doesn't exist or is a directory.
+func ReadManifest(path, baseDir string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	defer file.Close()
+
+	var files []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		files = append(files, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	paths, err := ResolveFileList(baseDir, files)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s: %s is a directory", path, p)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadManifest(t *testing.T) {
+	dir := setupProject(t)
+	manifest := filepath.Join(t.TempDir(), "files.txt")
+	content := "# files to review\nmain.go\n\n  " + filepath.Join(dir, "pkg", "util.go") + "  \n"
+	if err := os.WriteFile(manifest, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	paths, err := ReadManifest(manifest, dir)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	expected := []string{filepath.Join(dir, "main.go"), filepath.Join(dir, "pkg", "util.go")}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestReadManifestErrors(t *testing.T) {
+	dir := setupProject(t)
+	tests := map[string]string{
+		"missing file":   "main.go\nmissing.go\n",
+		"directory":      "pkg\n",
+		"outside target": "../main.go\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			manifest := filepath.Join(t.TempDir(), "files.txt")
+			if err := os.WriteFile(manifest, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write manifest: %v", err)
+			}
+			if _, err := ReadManifest(manifest, dir); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	if _, err := ReadManifest(filepath.Join(dir, "missing.txt"), dir); err == nil {
+		t.Error("Expected an error for a missing manifest")
+	}
+}
@@ -0,0 +1,141 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// Names of the backends that can be chosen in the settings
+const (
+	Auto        = "auto"
+	WlClipboard = "wl-clipboard"
+	Xclip       = "xclip"
+	Xsel        = "xsel"
+	Pbcopy      = "pbcopy"
+	Stdout      = "stdout"
+)
+
+// Names lists every backend name accepted by ForName
+var Names = []string{Auto, WlClipboard, Xclip, Xsel, Pbcopy, Stdout}
+
+// Backend copies text to and reads text from a clipboard
+type Backend interface {
+	WriteAll(text string) error
+	ReadAll() (string, error)
+}
+
+// commandBackend uses external commands to copy and paste
+type commandBackend struct {
+	copyCmd  []string
+	pasteCmd []string
+}
+
+// WriteAll pipes text into the copy command
+func (b commandBackend) WriteAll(text string) error {
+	cmd := exec.Command(b.copyCmd[0], b.copyCmd[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", b.copyCmd[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ReadAll returns the output of the paste command
+func (b commandBackend) ReadAll() (string, error) {
+	out, err := exec.Command(b.pasteCmd[0], b.pasteCmd[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", b.pasteCmd[0], err)
+	}
+	return string(out), nil
+}
+
+// commandBackends are the command line clipboard tools, in the order they're tried
+var commandBackends = []struct {
+	name    string
+	display string // Environment variable that must be set for the tool to work
+	backend commandBackend
+}{
+	{WlClipboard, "WAYLAND_DISPLAY", commandBackend{[]string{"wl-copy"}, []string{"wl-paste", "--no-newline"}}},
+	{Xclip, "DISPLAY", commandBackend{[]string{"xclip", "-selection", "clipboard"}, []string{"xclip", "-selection", "clipboard", "-o"}}},
+	{Xsel, "DISPLAY", commandBackend{[]string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}}},
+	{Pbcopy, "", commandBackend{[]string{"pbcopy"}, []string{"pbpaste"}}},
+}
+
+// systemBackend uses the platform clipboard API, for platforms without clipboard commands
+type systemBackend struct{}
+
+// WriteAll copies text to the system clipboard
+func (systemBackend) WriteAll(text string) error {
+	return clipboard.WriteAll(text)
+}
+
+// ReadAll returns the contents of the system clipboard
+func (systemBackend) ReadAll() (string, error) {
+	return clipboard.ReadAll()
+}
+
+// StdoutMessage is written before the text when no clipboard is available
+const StdoutMessage = "No clipboard available (install wl-clipboard, xclip or xsel); writing to stdout instead:\n"
+
+// stdoutBackend writes copied text to w, for machines without a clipboard
+type stdoutBackend struct {
+	w io.Writer
+}
+
+// WriteAll writes text to w after a message explaining why
+func (b stdoutBackend) WriteAll(text string) error {
+	_, err := fmt.Fprintf(b.w, "%s%s\n", StdoutMessage, text)
+	return err
+}
+
+// ReadAll always fails, as there is no clipboard to read
+func (b stdoutBackend) ReadAll() (string, error) {
+	return "", errors.New("no clipboard available")
+}
+
+// NewBackend returns the first clipboard tool that works here, falling back
+// to writing to stdout when there is none
+func NewBackend() Backend {
+	return detect(exec.LookPath, os.Getenv, runtime.GOOS, os.Stdout)
+}
+
+// ForName returns the backend with the given name, detecting one for Auto or
+// an empty name. The named tool isn't checked to be installed.
+func ForName(name string) (Backend, error) {
+	switch name {
+	case Auto, "":
+		return NewBackend(), nil
+	case Stdout:
+		return stdoutBackend{w: os.Stdout}, nil
+	}
+	for _, candidate := range commandBackends {
+		if candidate.name == name {
+			return candidate.backend, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown clipboard backend %q (supported: %s)", name, strings.Join(Names, ", "))
+}
+
+// detect picks a backend using lookPath to find commands and getenv to check
+// for a display server
+func detect(lookPath func(string) (string, error), getenv func(string) string, goos string, stdout io.Writer) Backend {
+	if goos == "windows" {
+		return systemBackend{}
+	}
+	for _, candidate := range commandBackends {
+		if candidate.display != "" && getenv(candidate.display) == "" {
+			continue
+		}
+		if _, err := lookPath(candidate.backend.copyCmd[0]); err == nil {
+			return candidate.backend
+		}
+	}
+	return stdoutBackend{w: stdout}
+}
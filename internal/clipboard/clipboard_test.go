@@ -0,0 +1,83 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []string
+		env       map[string]string
+		goos      string
+		expected  string // First copy command, or "stdout"
+	}{
+		{"wayland", []string{"wl-copy", "xclip"}, map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "linux", "wl-copy"},
+		{"x11", []string{"wl-copy", "xclip"}, map[string]string{"DISPLAY": ":0"}, "linux", "xclip"},
+		{"xsel only", []string{"xsel"}, map[string]string{"DISPLAY": ":0"}, "linux", "xsel"},
+		{"macOS", []string{"pbcopy"}, nil, "darwin", "pbcopy"},
+		{"headless server", []string{"xclip", "xsel"}, nil, "linux", "stdout"},
+		{"nothing installed", nil, map[string]string{"DISPLAY": ":0"}, "linux", "stdout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath := func(file string) (string, error) {
+				for _, installed := range tt.installed {
+					if installed == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+			getenv := func(key string) string { return tt.env[key] }
+
+			got := "stdout"
+			switch backend := detect(lookPath, getenv, tt.goos, &bytes.Buffer{}).(type) {
+			case commandBackend:
+				got = backend.copyCmd[0]
+			case stdoutBackend:
+			default:
+				t.Fatalf("Unexpected backend %T", backend)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, ok := detect(nil, nil, "windows", nil).(systemBackend); !ok {
+		t.Error("Expected the system clipboard on windows")
+	}
+}
+
+func TestForName(t *testing.T) {
+	for _, name := range Names {
+		if backend, err := ForName(name); err != nil || backend == nil {
+			t.Errorf("ForName(%q) = %v, %v", name, backend, err)
+		}
+	}
+	backend, _ := ForName(Xsel)
+	if b, ok := backend.(commandBackend); !ok || b.copyCmd[0] != "xsel" {
+		t.Errorf("Expected the xsel backend, got %#v", backend)
+	}
+	if _, err := ForName("clippy"); err == nil {
+		t.Error("Expected error for an unknown backend")
+	}
+}
+
+func TestStdoutBackend(t *testing.T) {
+	var out bytes.Buffer
+	backend := stdoutBackend{w: &out}
+	if err := backend.WriteAll("the prompt"); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	if out.String() != StdoutMessage+"the prompt\n" {
+		t.Errorf("Expected the message and the text, got %q", out.String())
+	}
+	if _, err := backend.ReadAll(); err == nil {
+		t.Error("Expected ReadAll to fail without a clipboard")
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const AutosaveInterval = 10 * time.Second
+
+// AutosaveName returns the name of the autosave file of the workspace at
+// workspacePath, so workspaces open in different instances don't share one
+func AutosaveName(workspacePath string) string {
+	sum := sha256.Sum256([]byte(workspacePath))
+	return "workspace." + hex.EncodeToString(sum[:8]) + ".autosave.json"
+}
+
+// Autosave is a snapshot of a workspace written periodically while the app runs
+type Autosave struct {
+	SavedAt   time.Time      `json:"saved_at"`
+	Workspace WorkspaceState `json:"workspace"`
+}
+
+// AutosaveManager writes the open workspace to an autosave file next to the
+// config, so its state can be recovered if the app exits abnormally. The file
+// is removed on a clean exit.
+type AutosaveManager struct {
+	autosavePath  string
+	configPath    string
+	workspacePath string
+	saved         bool // Whether this manager wrote the autosave file
+}
+
+// NewAutosaveManager creates an AutosaveManager for the workspace at
+// workspacePath and the config file at configPath
+func NewAutosaveManager(configPath, workspacePath string) *AutosaveManager {
+	return &AutosaveManager{
+		autosavePath:  filepath.Join(filepath.Dir(configPath), AutosaveName(workspacePath)),
+		configPath:    configPath,
+		workspacePath: workspacePath,
+	}
+}
+
+// Save writes a snapshot of the workspace to the autosave file
+func (m *AutosaveManager) Save(workspace WorkspaceState) error {
+	data, err := json.MarshalIndent(Autosave{SavedAt: time.Now(), Workspace: workspace}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.autosavePath), 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(m.autosavePath, data, 0644); err != nil {
+		return err
+	}
+	m.saved = true
+	return nil
+}
+
+// Load reads the autosave file
+func (m *AutosaveManager) Load() (*Autosave, error) {
+	data, err := os.ReadFile(m.autosavePath)
+	if err != nil {
+		return nil, err
+	}
+	var autosave Autosave
+	if err := json.Unmarshal(data, &autosave); err != nil {
+		return nil, err
+	}
+	return &autosave, nil
+}
+
+// Remove deletes the autosave file if this manager wrote it, leaving one
+// another instance of the app wrote for the same workspace alone
+func (m *AutosaveManager) Remove() error {
+	if !m.saved {
+		return nil
+	}
+	return m.Discard()
+}
+
+// Discard deletes the autosave file, if there is one, whoever wrote it. It is
+// used once the user has declined to recover it.
+func (m *AutosaveManager) Discard() error {
+	if err := os.Remove(m.autosavePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	m.saved = false
+	return nil
+}
+
+// Recoverable returns the autosave left behind for the workspace if it is newer
+// than the config, meaning the app didn't exit cleanly after writing it. It
+// returns nil when there is nothing to recover.
+func (m *AutosaveManager) Recoverable() *Autosave {
+	autosave, err := m.Load()
+	if err != nil || autosave.Workspace.Path != m.workspacePath {
+		return nil
+	}
+	if info, err := os.Stat(m.configPath); err == nil && !autosave.SavedAt.After(info.ModTime()) {
+		return nil
+	}
+	return autosave
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAutosaveManagerSaveLoadRemove(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	manager := NewAutosaveManager(configPath, "/test/workspace")
+
+	workspace := WorkspaceState{
+		Path:           "/test/workspace",
+		SelectedFiles:  []SelectedFileState{{Path: "/test/workspace/main.go", Tags: []string{"focus"}}},
+		ChatInput:      "unsaved prompt",
+		ActivePersonas: []string{"default"},
+	}
+	if err := manager.Save(workspace); err != nil {
+		t.Fatalf("Failed to save autosave: %v", err)
+	}
+
+	autosave, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Failed to load autosave: %v", err)
+	}
+	if autosave.Workspace.ChatInput != "unsaved prompt" {
+		t.Errorf("Expected chat input 'unsaved prompt', got '%s'", autosave.Workspace.ChatInput)
+	}
+	if len(autosave.Workspace.SelectedFiles) != 1 || autosave.Workspace.SelectedFiles[0].Path != "/test/workspace/main.go" || autosave.Workspace.SelectedFiles[0].Tags[0] != "focus" {
+		t.Errorf("Selected files not restored correctly: %v", autosave.Workspace.SelectedFiles)
+	}
+	if time.Since(autosave.SavedAt) > time.Minute {
+		t.Errorf("Expected a recent save time, got %v", autosave.SavedAt)
+	}
+
+	if err := manager.Remove(); err != nil {
+		t.Fatalf("Failed to remove autosave: %v", err)
+	}
+	if _, err := manager.Load(); !os.IsNotExist(err) {
+		t.Errorf("Expected autosave to be removed, got %v", err)
+	}
+	// Removing again is not an error
+	if err := manager.Remove(); err != nil {
+		t.Errorf("Expected removing a missing autosave to succeed, got %v", err)
+	}
+	if err := manager.Discard(); err != nil {
+		t.Errorf("Expected discarding a missing autosave to succeed, got %v", err)
+	}
+}
+
+func TestAutosaveManagerKeepsOtherInstancesFiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	first := NewAutosaveManager(configPath, "/test/first")
+	second := NewAutosaveManager(configPath, "/test/second")
+	if err := first.Save(WorkspaceState{Path: "/test/first"}); err != nil {
+		t.Fatalf("Failed to save autosave: %v", err)
+	}
+	if err := second.Save(WorkspaceState{Path: "/test/second"}); err != nil {
+		t.Fatalf("Failed to save autosave: %v", err)
+	}
+
+	// Each workspace has its own file
+	if autosave, err := first.Load(); err != nil || autosave.Workspace.Path != "/test/first" {
+		t.Errorf("Expected the first workspace's autosave to be kept, got %v, %v", autosave, err)
+	}
+
+	// A clean exit of an instance that never saved leaves the file in place
+	sameWorkspace := NewAutosaveManager(configPath, "/test/first")
+	if err := sameWorkspace.Remove(); err != nil {
+		t.Fatalf("Failed to remove autosave: %v", err)
+	}
+	if _, err := first.Load(); err != nil {
+		t.Errorf("Expected the autosave written by another instance to be kept, got %v", err)
+	}
+
+	// Discarding removes it whoever wrote it
+	if err := sameWorkspace.Discard(); err != nil {
+		t.Fatalf("Failed to discard autosave: %v", err)
+	}
+	if _, err := first.Load(); !os.IsNotExist(err) {
+		t.Errorf("Expected the autosave to be discarded, got %v", err)
+	}
+	if _, err := second.Load(); err != nil {
+		t.Errorf("Expected the second workspace's autosave to be kept, got %v", err)
+	}
+}
+
+func TestAutosaveManagerRecoverable(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	workspacePath := "/test/workspace"
+	manager := NewAutosaveManager(configPath, workspacePath)
+
+	if manager.Recoverable() != nil {
+		t.Error("Expected nothing to recover without an autosave")
+	}
+
+	if err := manager.Save(WorkspaceState{Path: workspacePath, ChatInput: "unsaved"}); err != nil {
+		t.Fatalf("Failed to save autosave: %v", err)
+	}
+
+	// Config older than the autosave: the app didn't exit cleanly
+	if err := os.WriteFile(configPath, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(configPath, past, past); err != nil {
+		t.Fatal(err)
+	}
+	autosave := manager.Recoverable()
+	if autosave == nil || autosave.Workspace.ChatInput != "unsaved" {
+		t.Errorf("Expected the autosave to be recoverable, got %v", autosave)
+	}
+
+	if NewAutosaveManager(configPath, "/other/workspace").Recoverable() != nil {
+		t.Error("Expected nothing to recover for a different workspace")
+	}
+
+	// Config saved after the autosave: nothing was lost
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(configPath, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if manager.Recoverable() != nil {
+		t.Error("Expected nothing to recover when the config is newer than the autosave")
+	}
+}
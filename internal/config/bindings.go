@@ -0,0 +1,233 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Binding scopes: bindings in the same scope are active at the same time, so
+// they can't share a key
+const (
+	ScopeNormal = "normal"
+	ScopeMenu   = "menu"
+)
+
+// BindingField describes a key binding that can be changed with SetBinding
+type BindingField struct {
+	Name        string // Key under [bindings], e.g. "quick_open" or "menu_mode.exit"
+	Description string
+	Scope       string
+}
+
+// BindingFields lists the key bindings that can be changed from within the app
+var BindingFields = []BindingField{
+	{"quick_open", "Quick-open a file", ScopeNormal},
+	{"glob_select", "Select files by glob pattern", ScopeNormal},
+	{"modified_since", "Only include files modified since a time", ScopeNormal},
+	{"presets", "Select a saved selection preset", ScopeNormal},
+	{"save_preset", "Save the selection as a preset", ScopeNormal},
+	{"history", "Prompt history", ScopeNormal},
+	{"export", "Save the prompt to a file", ScopeNormal},
+	{"export_manifest", "Copy the list of selected files", ScopeNormal},
+	{"webhook", "Send the prompt to the webhook", ScopeNormal},
+	{"workspace_list", "Recent workspaces", ScopeNormal},
+	{"layout_toggle", "Cycle the panel layout", ScopeNormal},
+	{"gitignore_check", "Check the ignore patterns", ScopeNormal},
+	{"help", "Show the help", ScopeNormal},
+	{"settings", "Change key bindings", ScopeNormal},
+	{"escape_to_normal", "Leave a mode", ScopeNormal},
+	{"normal_mode.tab", "Next panel", ScopeNormal},
+	{"normal_mode.shift_tab", "Previous panel", ScopeNormal},
+	{"menu_mode.activation", "Enter menu mode", ScopeNormal},
+	{"menu_mode.exit", "Leave menu mode", ScopeMenu},
+	{"menu_mode.persona_menu", "Choose personas (menu mode)", ScopeMenu},
+	{"menu_mode.format_toggle", "Cycle XML / JSON / Markdown (menu mode)", ScopeMenu},
+}
+
+// bindingField returns the description of the binding called name
+func bindingField(name string) (BindingField, bool) {
+	for _, field := range BindingFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return BindingField{}, false
+}
+
+// bindingScope returns the scope of the binding called name; unknown bindings are in the normal scope
+func bindingScope(name string) string {
+	if field, ok := bindingField(name); ok {
+		return field.Scope
+	}
+	return ScopeNormal
+}
+
+// bindingMap returns the key of each binding in BindingFields, keyed by name
+func bindingMap(bindings *KeyBindings) map[string]string {
+	values := make(map[string]string, len(BindingFields))
+	for _, field := range BindingFields {
+		values[field.Name] = *bindingValue(bindings, field.Name)
+	}
+	return values
+}
+
+// conflictsError describes the key binding conflicts, or returns nil if there are none
+func conflictsError(conflicts []Conflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	descriptions := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		descriptions[i] = fmt.Sprintf("bindings.%s and bindings.%s both use %s", conflict.First, conflict.Second, conflict.Key)
+	}
+	return fmt.Errorf("conflicting key bindings: %s", strings.Join(descriptions, "; "))
+}
+
+// bindingValue returns a pointer to the binding called name in bindings, or nil
+func bindingValue(bindings *KeyBindings, name string) *string {
+	switch name {
+	case "escape_to_normal":
+		return &bindings.EscapeToNormal
+	case "quick_open":
+		return &bindings.QuickOpen
+	case "history":
+		return &bindings.History
+	case "export":
+		return &bindings.Export
+	case "export_manifest":
+		return &bindings.ExportManifest
+	case "glob_select":
+		return &bindings.GlobSelect
+	case "modified_since":
+		return &bindings.ModifiedSince
+	case "presets":
+		return &bindings.Presets
+	case "save_preset":
+		return &bindings.SavePreset
+	case "workspace_list":
+		return &bindings.WorkspaceList
+	case "webhook":
+		return &bindings.Webhook
+	case "help":
+		return &bindings.Help
+	case "layout_toggle":
+		return &bindings.LayoutToggle
+	case "gitignore_check":
+		return &bindings.GitignoreCheck
+	case "settings":
+		return &bindings.Settings
+	case "normal_mode.tab":
+		return &bindings.NormalMode.Tab
+	case "normal_mode.shift_tab":
+		return &bindings.NormalMode.ShiftTab
+	case "menu_mode.activation":
+		return &bindings.MenuMode.Activation
+	case "menu_mode.exit":
+		return &bindings.MenuMode.Exit
+	case "menu_mode.persona_menu":
+		return &bindings.MenuMode.PersonaMenu
+	case "menu_mode.format_toggle":
+		return &bindings.MenuMode.FormatToggle
+	}
+	return nil
+}
+
+// GetBinding returns the key bound to the binding called name (thread-safe)
+func (m *SettingsManager) GetBinding(name string) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if value := bindingValue(&m.settings.Bindings, name); value != nil {
+		return *value
+	}
+	return ""
+}
+
+// DefaultBinding returns the default key of the binding called name
+func DefaultBinding(name string) string {
+	if value := bindingValue(&getDefaultSettings().Bindings, name); value != nil {
+		return *value
+	}
+	return ""
+}
+
+// findBindingConflict returns the name of another binding in the same scope as
+// name that is bound to value, or "" when there is none
+func findBindingConflict(bindings *KeyBindings, name, value string) string {
+	field, _ := bindingField(name)
+	combo, err := ParseKeyBinding(value)
+	if err != nil {
+		return ""
+	}
+	for _, other := range BindingFields {
+		if other.Name == name || other.Scope != field.Scope {
+			continue
+		}
+		otherCombo, err := ParseKeyBinding(*bindingValue(bindings, other.Name))
+		if err == nil && otherCombo.Equals(combo) {
+			return other.Name
+		}
+	}
+	return ""
+}
+
+// SetBinding binds value to the binding called name and writes it to the global
+// settings file. The key must be valid and not used by another binding that is
+// active at the same time.
+func (m *SettingsManager) SetBinding(name, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := bindingField(name); !ok {
+		return fmt.Errorf("unknown key binding: %q", name)
+	}
+	if err := validateKeyBinding(value); err != nil {
+		return fmt.Errorf("invalid bindings.%s: %w", name, err)
+	}
+	if conflict := findBindingConflict(&m.settings.Bindings, name, value); conflict != "" {
+		return fmt.Errorf("%s is already bound to %s", value, conflict)
+	}
+	if m.configPath == "" {
+		return errNoSettingsFile
+	}
+
+	if err := writeBinding(m.configPath, name, value); err != nil {
+		return err
+	}
+	return m.loadUnsafe()
+}
+
+// writeBinding sets the binding called name in the TOML file at path, keeping
+// its other settings. Comments in the file are not kept.
+func writeBinding(path, name, value string) error {
+	settings := make(map[string]any)
+	if _, err := toml.DecodeFile(path, &settings); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// Walk down to the table holding the binding, creating missing tables
+	table := settings
+	keys := append([]string{"bindings"}, strings.Split(name, ".")...)
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := table[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			table[key] = next
+		}
+		table = next
+	}
+	table[keys[len(keys)-1]] = value
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes(), 0644)
+}
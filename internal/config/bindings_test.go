@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestSettingsManager_SetBindingConflicts(t *testing.T) {
+	manager := &SettingsManager{
+		configPath: filepath.Join(t.TempDir(), "coding_prompts.toml"),
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		binding string
+		key     string
+		wantErr string
+	}{
+		{"taken by another global binding", "history", "ctrl+p", "already bound to quick_open"},
+		{"same key spelled differently", "history", "CTRL+P", "already bound to quick_open"},
+		{"taken in menu mode", "menu_mode.persona_menu", "f", "already bound to menu_mode.format_toggle"},
+		{"invalid key", "history", "super+h", "invalid bindings.history"},
+		{"unknown binding", "launch_rockets", "ctrl+r", "unknown key binding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.SetBinding(tt.binding, tt.key)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := os.Stat(manager.configPath); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written for rejected bindings, got: %v", err)
+	}
+
+	// Menu mode keys are only active in menu mode, so they may reuse global keys
+	if err := manager.SetBinding("menu_mode.persona_menu", "ctrl+p"); err != nil {
+		t.Errorf("Expected a menu mode binding to reuse a global key, got: %v", err)
+	}
+	// Rebinding a key to itself is not a conflict
+	if err := manager.SetBinding("quick_open", "ctrl+p"); err != nil {
+		t.Errorf("Expected rebinding the same key to succeed, got: %v", err)
+	}
+}
+
+func TestSettingsManager_SetBindingRoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	content := "[ui]\nlayout_mode = \"vertical\"\n\n[bindings]\nhelp = \"f1\"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading settings, got: %v", err)
+	}
+
+	if err := manager.SetBinding("menu_mode.exit", "q"); err != nil {
+		t.Fatalf("Expected no error setting a binding, got: %v", err)
+	}
+	if err := manager.SetBinding("quick_open", "alt+o"); err != nil {
+		t.Fatalf("Expected no error setting a binding, got: %v", err)
+	}
+	if got := manager.GetBinding("quick_open"); got != "alt+o" {
+		t.Errorf("Expected the new binding to be in effect, got: %q", got)
+	}
+
+	// The file keeps the other settings
+	var saved UserSettings
+	if _, err := toml.DecodeFile(configPath, &saved); err != nil {
+		t.Fatalf("Expected valid TOML to be written, got: %v", err)
+	}
+	if saved.Bindings.MenuMode.Exit != "q" || saved.Bindings.QuickOpen != "alt+o" {
+		t.Errorf("Expected the new bindings in the file, got: %+v", saved.Bindings)
+	}
+	if saved.Bindings.Help != "f1" || saved.UI.LayoutMode != "vertical" {
+		t.Errorf("Expected the other settings to be kept, got: %+v", saved)
+	}
+
+	reloaded := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	if got := reloaded.GetMenuModeExit(); got != "q" {
+		t.Errorf("Expected menu mode exit key 'q' after reloading, got: %q", got)
+	}
+}
+
+func TestSettingsManager_SetBindingKeepsWordWrap(t *testing.T) {
+	manager := &SettingsManager{
+		configPath: filepath.Join(t.TempDir(), "coding_prompts.toml"),
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+
+	// The first remap creates a settings file holding only the binding
+	if err := manager.SetBinding("history", "alt+h"); err != nil {
+		t.Fatalf("Expected no error setting a binding, got: %v", err)
+	}
+	if !manager.IsWordWrapEnabled() {
+		t.Error("Expected word wrap to stay on after remapping a key")
+	}
+}
+
+func TestBindingFieldsHaveDefaults(t *testing.T) {
+	defaults := getDefaultSettings()
+	for _, field := range BindingFields {
+		if value := bindingValue(&defaults.Bindings, field.Name); value == nil || *value == "" {
+			t.Errorf("Expected a default key for %s", field.Name)
+		} else if conflict := findBindingConflict(&defaults.Bindings, field.Name, *value); conflict != "" {
+			t.Errorf("Expected the default of %s not to conflict, got %s", field.Name, conflict)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // AppConfig represents the complete application state
 type AppConfig struct {
@@ -11,14 +14,60 @@ type AppConfig struct {
 
 // WorkspaceState represents a previously loaded folder and its state
 type WorkspaceState struct {
-	Path           string    `json:"path"`            // Absolute path to workspace
-	LastAccessed   time.Time `json:"last_accessed"`   // When last opened
-	SelectedFiles  []string  `json:"selected_files"`  // Relative paths of selected files
-	ChatInput      string    `json:"chat_input"`      // Saved chat input
-	ActivePersonas []string  `json:"active_personas"` // Active persona names (defaults to ["default"])
-
-	// Deprecated: Use ActivePersonas instead
-	CurrentPersona string `json:"current_persona,omitempty"` // Kept for backward compatibility
+	Path                string              `json:"path"`                            // Absolute path to workspace
+	LastAccessed        time.Time           `json:"last_accessed"`                   // When last opened
+	SelectedFiles       []SelectedFileState `json:"selected_files"`                  // Selected files in prompt order
+	ChatInput           string              `json:"chat_input"`                      // Saved chat input
+	ActivePersonas      []string            `json:"active_personas"`                 // Active persona names (defaults to ["default"])
+	OutputFormat        string              `json:"output_format"`                   // Prompt output format ("xml", "json" or "markdown")
+	Buckets             map[string][]string `json:"buckets,omitempty"`               // Selected file paths by bucket name; files in none are in the first bucket
+	SelectedSortMode    string              `json:"selected_sort_mode,omitempty"`    // Order of the selected files: "order", "name" or "size"
+	ExpandedPaths       []string            `json:"expanded_paths"`                  // Directories expanded in the file tree
+	ModifiedSinceFilter *time.Time          `json:"modified_since_filter,omitempty"` // Only files modified since then are included in prompts; nil includes all
+	Presets             []SelectionPreset   `json:"presets,omitempty"`               // Named file selections, in the order they were first saved
+	CurrentPersona      string              `json:"current_persona,omitempty"`       // Replaced by ActivePersonas in config version 2; only read to migrate older configs
+}
+
+// SelectedFileState is a selected file and the tags the user gave it
+type SelectedFileState struct {
+	Path string   `json:"path"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// UnmarshalJSON also accepts a plain path, as written by versions without tags
+func (s *SelectedFileState) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*s = SelectedFileState{Path: path}
+		return nil
+	}
+	type plain SelectedFileState
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// NewSelectedFileStates returns untagged states for paths
+func NewSelectedFileStates(paths []string) []SelectedFileState {
+	states := make([]SelectedFileState, len(paths))
+	for i, path := range paths {
+		states[i] = SelectedFileState{Path: path}
+	}
+	return states
+}
+
+// SelectedPaths returns the paths of the selected files in prompt order
+func (w *WorkspaceState) SelectedPaths() []string {
+	paths := make([]string, len(w.SelectedFiles))
+	for i, file := range w.SelectedFiles {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// SelectionPreset is a named set of files that can be selected again in one go
+type SelectionPreset struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+	Tags  []string `json:"tags,omitempty"` // Comma-separated tags of the file at the same index in Files; empty if no file is tagged
 }
 
 // ConfigMetadata stores application metadata
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestFormat is a format ExportManifest can write the selected files in
+type ManifestFormat string
+
+const (
+	ManifestPlain ManifestFormat = "plain" // One path per line
+	ManifestJSON  ManifestFormat = "json"  // JSON array of strings
+	ManifestShell ManifestFormat = "shell" // Shell array literal, e.g. ('a.go' 'pkg/b.go')
+)
+
+// ManifestFormats lists the manifest formats in the order they are offered
+var ManifestFormats = []ManifestFormat{ManifestPlain, ManifestJSON, ManifestShell}
+
+// ExportManifest writes the paths of the files selected in workspace to w,
+// relative to the workspace root and in selection order, so the selection can
+// be piped into other tools
+func ExportManifest(workspace *WorkspaceState, format ManifestFormat, w io.Writer) error {
+	paths := make([]string, 0, len(workspace.SelectedFiles))
+	for _, file := range workspace.SelectedFiles {
+		path, err := filepath.Rel(workspace.Path, file.Path)
+		if err != nil {
+			path = file.Path
+		}
+		paths = append(paths, path)
+	}
+
+	var err error
+	switch format {
+	case ManifestPlain:
+		for _, path := range paths {
+			if _, err = fmt.Fprintln(w, path); err != nil {
+				break
+			}
+		}
+	case ManifestJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(paths)
+	case ManifestShell:
+		quoted := make([]string, len(paths))
+		for i, path := range paths {
+			quoted[i] = shellQuote(path)
+		}
+		_, err = fmt.Fprintf(w, "(%s)\n", strings.Join(quoted, " "))
+	default:
+		return fmt.Errorf("unknown manifest format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell, in single quotes so nothing in it is expanded
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
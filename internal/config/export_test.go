@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// manifestWorkspace returns a workspace selecting files with names that need quoting in a shell
+func manifestWorkspace() (*WorkspaceState, []string) {
+	workspace := &WorkspaceState{
+		Path: "/project",
+		SelectedFiles: []SelectedFileState{
+			{Path: "/project/main.go"},
+			{Path: "/project/pkg/it's here.go", Tags: []string{"focus"}},
+			{Path: "/project/docs/$HOME `x`.md"},
+		},
+	}
+	return workspace, []string{"main.go", "pkg/it's here.go", "docs/$HOME `x`.md"}
+}
+
+func TestExportManifestPlain(t *testing.T) {
+	workspace, expected := manifestWorkspace()
+	var buf bytes.Buffer
+	if err := ExportManifest(workspace, ManifestPlain, &buf); err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	if buf.String() != strings.Join(expected, "\n")+"\n" {
+		t.Errorf("Expected one relative path per line, got %q", buf.String())
+	}
+}
+
+func TestExportManifestJSON(t *testing.T) {
+	workspace, expected := manifestWorkspace()
+	var buf bytes.Buffer
+	if err := ExportManifest(workspace, ManifestJSON, &buf); err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	var paths []string
+	if err := json.Unmarshal(buf.Bytes(), &paths); err != nil {
+		t.Fatalf("Expected a valid JSON array, got %q: %v", buf.String(), err)
+	}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	// An empty selection is an empty array rather than null
+	buf.Reset()
+	if err := ExportManifest(&WorkspaceState{Path: "/project"}, ManifestJSON, &buf); err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty array, got %q", buf.String())
+	}
+}
+
+func TestExportManifestShell(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not available")
+	}
+
+	workspace, expected := manifestWorkspace()
+	var buf bytes.Buffer
+	if err := ExportManifest(workspace, ManifestShell, &buf); err != nil {
+		t.Fatalf("ExportManifest failed: %v", err)
+	}
+
+	// The shell sees every path as a single word, unexpanded
+	script := "files=" + buf.String() + `printf '%s\n' "${files[@]}"`
+	out, err := exec.Command(bash, "-c", script).Output()
+	if err != nil {
+		t.Fatalf("Expected a valid shell array, got %q: %v", buf.String(), err)
+	}
+	if paths := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"); !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestExportManifestUnknownFormat(t *testing.T) {
+	workspace, _ := manifestWorkspace()
+	if err := ExportManifest(workspace, "yaml", &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
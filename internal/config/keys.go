@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -194,6 +195,22 @@ func (kc *KeyCombination) matchesKey(msg tea.KeyMsg) bool {
 			return msg.Type == tea.KeyF11
 		case "f12":
 			return msg.Type == tea.KeyF12
+		case "f13":
+			return msg.Type == tea.KeyF13
+		case "f14":
+			return msg.Type == tea.KeyF14
+		case "f15":
+			return msg.Type == tea.KeyF15
+		case "f16":
+			return msg.Type == tea.KeyF16
+		case "f17":
+			return msg.Type == tea.KeyF17
+		case "f18":
+			return msg.Type == tea.KeyF18
+		case "f19":
+			return msg.Type == tea.KeyF19
+		case "f20":
+			return msg.Type == tea.KeyF20
 		}
 	}
 
@@ -239,6 +256,65 @@ func hasCtrlModifier(msg tea.KeyMsg) bool {
 	return false
 }
 
+// keyAliases maps the alternative key names ParseKeyBinding accepts to the names Hash uses
+var keyAliases = map[string]string{
+	"escape":   "esc",
+	"return":   "enter",
+	"del":      "delete",
+	"pageup":   "pgup",
+	"pagedown": "pgdn",
+}
+
+// Hash returns the key combination as a string with alternative key names
+// replaced, so combinations pressed the same way have the same hash
+func (kc *KeyCombination) Hash() string {
+	canonical := *kc
+	canonical.Key = strings.ToLower(canonical.Key)
+	if alias, ok := keyAliases[canonical.Key]; ok {
+		canonical.Key = alias
+	}
+	return canonical.String()
+}
+
+// Equals reports whether kc and other are pressed the same way
+func (kc *KeyCombination) Equals(other *KeyCombination) bool {
+	return kc != nil && other != nil && kc.Hash() == other.Hash()
+}
+
+// Conflict is a pair of key bindings that are active at the same time and share a key
+type Conflict struct {
+	First  string // Binding names, e.g. "quick_open", in sorted order
+	Second string
+	Key    string // Hash of the shared key combination
+}
+
+// DetectConflicts returns the pairs of bindings, keyed by name as in
+// BindingFields, that share a key while active at the same time. Bindings in
+// different scopes may share a key; names not in BindingFields are in the
+// normal scope. Empty or invalid keys are skipped. Conflicts are sorted by
+// binding name.
+func DetectConflicts(bindings map[string]string) []Conflict {
+	names := make([]string, 0, len(bindings))
+	combos := make(map[string]*KeyCombination, len(bindings))
+	for name, value := range bindings {
+		if combo, err := ParseKeyBinding(value); err == nil {
+			names = append(names, name)
+			combos[name] = combo
+		}
+	}
+	sort.Strings(names)
+
+	var conflicts []Conflict
+	for i, first := range names {
+		for _, second := range names[i+1:] {
+			if bindingScope(first) == bindingScope(second) && combos[first].Equals(combos[second]) {
+				conflicts = append(conflicts, Conflict{First: first, Second: second, Key: combos[first].Hash()})
+			}
+		}
+	}
+	return conflicts
+}
+
 // String returns a string representation of the key combination
 func (kc *KeyCombination) String() string {
 	parts := []string{}
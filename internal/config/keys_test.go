@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -230,3 +233,89 @@ func TestMatchesKeyMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionKeys(t *testing.T) {
+	keyTypes := []tea.KeyType{
+		tea.KeyF1, tea.KeyF2, tea.KeyF3, tea.KeyF4, tea.KeyF5,
+		tea.KeyF6, tea.KeyF7, tea.KeyF8, tea.KeyF9, tea.KeyF10,
+		tea.KeyF11, tea.KeyF12, tea.KeyF13, tea.KeyF14, tea.KeyF15,
+		tea.KeyF16, tea.KeyF17, tea.KeyF18, tea.KeyF19, tea.KeyF20,
+	}
+
+	for i, keyType := range keyTypes {
+		key := fmt.Sprintf("f%d", i+1)
+		t.Run(key, func(t *testing.T) {
+			if err := validateKeyBinding(key); err != nil {
+				t.Errorf("Expected %s to be a valid binding, got %v", key, err)
+			}
+			combo, err := ParseKeyBinding(strings.ToUpper(key))
+			if err != nil {
+				t.Fatalf("ParseKeyBinding(%q) failed: %v", key, err)
+			}
+			if combo.Key != key {
+				t.Errorf("Expected key %q, got %q", key, combo.Key)
+			}
+
+			if !combo.MatchesKeyMsg(tea.KeyMsg{Type: keyType}) {
+				t.Errorf("Expected %s to match its key", key)
+			}
+			if combo.MatchesKeyMsg(tea.KeyMsg{Type: keyTypes[(i+1)%len(keyTypes)]}) {
+				t.Errorf("Expected %s not to match another function key", key)
+			}
+			if combo.MatchesKeyMsg(tea.KeyMsg{Type: keyType, Alt: true}) {
+				t.Errorf("Expected %s not to match alt+%s", key, key)
+			}
+
+			altCombo, err := ParseKeyBinding("alt+" + key)
+			if err != nil {
+				t.Fatalf("ParseKeyBinding(alt+%s) failed: %v", key, err)
+			}
+			if !altCombo.MatchesKeyMsg(tea.KeyMsg{Type: keyType, Alt: true}) {
+				t.Errorf("Expected alt+%s to match", key)
+			}
+		})
+	}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	conflicts := DetectConflicts(map[string]string{
+		"quick_open":             "ctrl+p",
+		"history":                "Ctrl+P",
+		"export":                 "escape",
+		"help":                   "esc",
+		"glob_select":            "ctrl+g",
+		"menu_mode.persona_menu": "ctrl+g", // Menu mode bindings are active at other times
+		"menu_mode.exit":         "",
+	})
+	expected := []Conflict{
+		{First: "export", Second: "help", Key: "esc"},
+		{First: "history", Second: "quick_open", Key: "ctrl+p"},
+	}
+	if !slices.Equal(conflicts, expected) {
+		t.Errorf("Expected conflicts %v, got %v", expected, conflicts)
+	}
+
+	if conflicts := DetectConflicts(bindingMap(&getDefaultSettings().Bindings)); len(conflicts) != 0 {
+		t.Errorf("Expected the default bindings not to conflict, got %v", conflicts)
+	}
+}
+
+func TestKeyCombinationEquals(t *testing.T) {
+	parse := func(binding string) *KeyCombination {
+		combo, err := ParseKeyBinding(binding)
+		if err != nil {
+			t.Fatalf("ParseKeyBinding(%q) failed: %v", binding, err)
+		}
+		return combo
+	}
+
+	if !parse("shift+ctrl+x").Equals(parse("ctrl+shift+x")) {
+		t.Error("Expected the order of modifiers not to matter")
+	}
+	if !parse("pageup").Equals(parse("pgup")) || parse("pageup").Hash() != "pgup" {
+		t.Error("Expected alternative key names to be equal")
+	}
+	if parse("ctrl+x").Equals(parse("alt+x")) || parse("x").Equals(nil) {
+		t.Error("Expected different combinations not to be equal")
+	}
+}
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,26 +17,63 @@ import (
 const (
 	AppName    = "prompter"
 	ConfigName = "config.json"
+	HistoryDir = "history"
 	AppVersion = "0.1.0" // This should be updated with the actual app version
+
+	// DefaultPruneWorkspacesDays is how long a workspace is remembered after it was last opened
+	DefaultPruneWorkspacesDays = 30
+
+	// DefaultMaxRecentWorkspaces is how many workspaces are remembered
+	DefaultMaxRecentWorkspaces = 20
+
+	// ConfigDirEnv names the environment variable that overrides the directory
+	// the configuration and settings files are kept in
+	ConfigDirEnv = "PROMPTER_CONFIG_DIR"
 )
 
+// configDirOverride returns the directory set in ConfigDirEnv, creating it if
+// it doesn't exist, or "" when the variable isn't set
+func configDirOverride() (string, error) {
+	dir := os.Getenv(ConfigDirEnv)
+	if dir == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", ConfigDirEnv, err)
+	}
+	return dir, nil
+}
+
 // ConfigManager handles loading and saving the application configuration.
 type ConfigManager struct {
 	configPath string
 	config     *AppConfig
 	mutex      sync.RWMutex
+	// Workspaces not opened for this many days are forgotten on load; zero keeps them all
+	pruneAfterDays int
+	// Most workspaces remembered; the least recently accessed is forgotten when
+	// opening another would exceed it. Zero keeps them all.
+	maxWorkspaces int
 }
 
-// NewManager creates a new ConfigManager.
+// NewManager creates a new ConfigManager, forgetting workspaces that weren't
+// opened in the last DefaultPruneWorkspacesDays days.
 func NewManager() (*ConfigManager, error) {
-	cfgDir, err := os.UserConfigDir()
+	return NewManagerWithPruneAge(DefaultPruneWorkspacesDays)
+}
+
+// NewManagerWithPruneAge creates a new ConfigManager, forgetting workspaces that
+// weren't opened in the last maxAgeDays days. Zero or less keeps them all.
+func NewManagerWithPruneAge(maxAgeDays int) (*ConfigManager, error) {
+	configPath, err := defaultConfigPath()
 	if err != nil {
 		return nil, err
 	}
-	configPath := filepath.Join(cfgDir, AppName, ConfigName)
 
 	m := &ConfigManager{
-		configPath: configPath,
+		configPath:     configPath,
+		pruneAfterDays: maxAgeDays,
+		maxWorkspaces:  DefaultMaxRecentWorkspaces,
 	}
 
 	err = m.load()
@@ -41,11 +84,33 @@ func NewManager() (*ConfigManager, error) {
 	return m, nil
 }
 
+// NewMemoryManager creates a ConfigManager holding a new configuration that is
+// kept in memory only: Save doesn't write it anywhere and there is no history file.
+func NewMemoryManager() *ConfigManager {
+	return &ConfigManager{config: newDefaultConfig(), maxWorkspaces: DefaultMaxRecentWorkspaces}
+}
+
+// defaultConfigPath returns the path of the configuration file, in the
+// ConfigDirEnv directory if it is set
+func defaultConfigPath() (string, error) {
+	dir, err := configDirOverride()
+	if err != nil || dir != "" {
+		return filepath.Join(dir, ConfigName), err
+	}
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfgDir, AppName, ConfigName), nil
+}
+
 // load reads the configuration from disk, or creates a default one.
 func (m *ConfigManager) load() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	m.recoverTempFile()
+
 	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
 		m.config = newDefaultConfig()
 		return m.save()
@@ -62,25 +127,48 @@ func (m *ConfigManager) load() error {
 		m.config = newDefaultConfig()
 		return m.save()
 	}
-	m.config = &cfg
-	if m.config.RecentWorkspaces == nil {
-		m.config.RecentWorkspaces = make(map[string]*WorkspaceState)
+	if cfg.RecentWorkspaces == nil {
+		cfg.RecentWorkspaces = make(map[string]*WorkspaceState)
+	}
+	version := cfg.Metadata.Version
+	migrated, err := migrateConfig(&cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", m.configPath, err)
+	}
+	m.config = migrated
+	if m.config.Metadata.Version != version {
+		if err := m.save(); err != nil {
+			return err
+		}
 	}
 
 	// Initialize UI settings if not present (backward compatibility)
+	defaults := newDefaultUISettings()
 	if len(m.config.UISettings.SelectedFilesPanel.RemovalKeys) == 0 {
-		m.config.UISettings.SelectedFilesPanel.RemovalKeys = []string{" ", "delete", "backspace", "x"}
+		m.config.UISettings.SelectedFilesPanel.RemovalKeys = defaults.SelectedFilesPanel.RemovalKeys
 	}
 	if m.config.UISettings.SelectedFilesPanel.HelpText == "" {
-		m.config.UISettings.SelectedFilesPanel.HelpText = "↑/↓: navigate, %s: remove file, ctrl+c: clear all"
+		m.config.UISettings.SelectedFilesPanel.HelpText = defaults.SelectedFilesPanel.HelpText
 		m.config.UISettings.SelectedFilesPanel.ShowHelpText = true
 	}
+	if helpText := updateHelpText(m.config.UISettings.SelectedFilesPanel.HelpText, defaults.SelectedFilesPanel.HelpText); helpText != m.config.UISettings.SelectedFilesPanel.HelpText {
+		m.config.UISettings.SelectedFilesPanel.HelpText = helpText
+		if err := m.save(); err != nil {
+			return err
+		}
+	}
 
+	if m.pruneStaleWorkspaces(m.pruneAfterDays) > 0 {
+		return m.save()
+	}
 	return nil
 }
 
 // save writes the current configuration to disk.
 func (m *ConfigManager) save() error {
+	if m.configPath == "" {
+		return nil
+	}
 	m.config.Metadata.LastModified = time.Now()
 	m.config.Metadata.AppVersion = AppVersion
 
@@ -96,7 +184,54 @@ func (m *ConfigManager) save() error {
 		}
 	}
 
-	return os.WriteFile(m.configPath, data, 0644)
+	return writeFileAtomic(m.configPath, data, 0644)
+}
+
+// tempPath returns the path a file is written to before it's renamed into place
+func tempPath(path string) string {
+	return path + ".tmp"
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it over
+// path, so a crash mid-write never leaves path partially written. The temp
+// file is removed on any error.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath := tempPath(path)
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// recoverTempFile deals with a temp file left behind by a save that was
+// interrupted between writing and renaming it. A complete temp file holds the
+// newest config and replaces the config; a partial one is deleted.
+func (m *ConfigManager) recoverTempFile() {
+	tmpPath := tempPath(m.configPath)
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return
+	}
+	if json.Valid(data) && os.Rename(tmpPath, m.configPath) == nil {
+		return
+	}
+	os.Remove(tmpPath)
 }
 
 // Save saves the configuration. It's a thread-safe wrapper around save().
@@ -115,25 +250,217 @@ func (m *ConfigManager) GetWorkspace(path string) *WorkspaceState {
 	if !ok {
 		ws = &WorkspaceState{
 			Path:           path,
-			SelectedFiles:  []string{},
+			SelectedFiles:  []SelectedFileState{},
 			ActivePersonas: []string{"default"},
 		}
 		m.config.RecentWorkspaces[path] = ws
-	} else {
-		// Handle backward compatibility migration
-		if len(ws.ActivePersonas) == 0 && ws.CurrentPersona != "" {
-			ws.ActivePersonas = []string{ws.CurrentPersona}
-			ws.CurrentPersona = "" // Clear deprecated field
-		} else if len(ws.ActivePersonas) == 0 {
-			ws.ActivePersonas = []string{"default"}
-		}
+	} else if len(ws.ActivePersonas) == 0 {
+		ws.ActivePersonas = []string{"default"}
 	}
 	ws.LastAccessed = time.Now()
+	for m.maxWorkspaces > 0 && len(m.config.RecentWorkspaces) > m.maxWorkspaces {
+		m.evictOldestWorkspace(path)
+	}
 	// Save the workspace immediately to persist the new workspace or updated LastAccessed
 	m.save()
 	return ws
 }
 
+// evictOldestWorkspace forgets the workspace accessed least recently, other than keep
+func (m *ConfigManager) evictOldestWorkspace(keep string) {
+	oldest := ""
+	var oldestTime time.Time
+	for path, ws := range m.config.RecentWorkspaces {
+		if path == keep {
+			continue
+		}
+		if ws == nil {
+			oldest = path
+			break
+		}
+		if oldest == "" || ws.LastAccessed.Before(oldestTime) {
+			oldest, oldestTime = path, ws.LastAccessed
+		}
+	}
+	delete(m.config.RecentWorkspaces, oldest)
+}
+
+// SetMaxRecentWorkspaces sets how many workspaces are remembered from now on;
+// zero or less keeps them all
+func (m *ConfigManager) SetMaxRecentWorkspaces(limit int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.maxWorkspaces = max(0, limit)
+}
+
+// GetWorkspaceCount returns how many workspaces are remembered
+func (m *ConfigManager) GetWorkspaceCount() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.config.RecentWorkspaces)
+}
+
+// SavePreset saves files, with their tags keyed by path, as the selection preset
+// called name in the workspace at workspacePath, replacing any preset of that name
+func (m *ConfigManager) SavePreset(workspacePath, name string, files []string, tags map[string][]string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("preset name is empty")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ws := m.config.RecentWorkspaces[workspacePath]
+	if ws == nil {
+		return fmt.Errorf("unknown workspace %s", workspacePath)
+	}
+	preset := SelectionPreset{Name: name, Files: slices.Clone(files)}
+	if len(tags) > 0 {
+		preset.Tags = make([]string, len(files))
+		for i, file := range files {
+			preset.Tags[i] = strings.Join(tags[file], ",")
+		}
+	}
+	if i := slices.IndexFunc(ws.Presets, func(p SelectionPreset) bool { return p.Name == name }); i >= 0 {
+		ws.Presets[i] = preset
+	} else {
+		ws.Presets = append(ws.Presets, preset)
+	}
+	return m.save()
+}
+
+// GetPresets returns copies of the selection presets of the workspace at
+// workspacePath, in the order they were first saved
+func (m *ConfigManager) GetPresets(workspacePath string) []SelectionPreset {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	ws := m.config.RecentWorkspaces[workspacePath]
+	if ws == nil {
+		return nil
+	}
+	presets := make([]SelectionPreset, len(ws.Presets))
+	for i, preset := range ws.Presets {
+		presets[i] = SelectionPreset{Name: preset.Name, Files: slices.Clone(preset.Files), Tags: slices.Clone(preset.Tags)}
+	}
+	return presets
+}
+
+// GetRecentWorkspaces returns copies of the known workspaces, most recently accessed first.
+func (m *ConfigManager) GetRecentWorkspaces() []WorkspaceState {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	workspaces := make([]WorkspaceState, 0, len(m.config.RecentWorkspaces))
+	for _, ws := range m.config.RecentWorkspaces {
+		workspaces = append(workspaces, *ws)
+	}
+	return SortWorkspacesBy(workspaces, SortByAccess)
+}
+
+// WorkspaceSortMode is an order the recent workspaces can be listed in
+type WorkspaceSortMode int
+
+const (
+	SortByAccess    WorkspaceSortMode = iota // Most recently accessed first
+	SortByName                               // Path, A to Z
+	SortByFileCount                          // Most selected files first
+)
+
+// String returns the name of the sort mode as shown in the workspace list
+func (s WorkspaceSortMode) String() string {
+	switch s {
+	case SortByName:
+		return "name"
+	case SortByFileCount:
+		return "file count"
+	default:
+		return "last opened"
+	}
+}
+
+// Next returns the sort mode that follows s when cycling through them
+func (s WorkspaceSortMode) Next() WorkspaceSortMode {
+	return (s + 1) % (SortByFileCount + 1)
+}
+
+// SortWorkspacesBy sorts workspaces in place by mode and returns them.
+// Workspaces that compare equal are ordered by path.
+func SortWorkspacesBy(workspaces []WorkspaceState, mode WorkspaceSortMode) []WorkspaceState {
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		a, b := workspaces[i], workspaces[j]
+		switch mode {
+		case SortByAccess:
+			if !a.LastAccessed.Equal(b.LastAccessed) {
+				return a.LastAccessed.After(b.LastAccessed)
+			}
+		case SortByFileCount:
+			if len(a.SelectedFiles) != len(b.SelectedFiles) {
+				return len(a.SelectedFiles) > len(b.SelectedFiles)
+			}
+		}
+		return a.Path < b.Path
+	})
+	return workspaces
+}
+
+// RemoveWorkspace forgets the saved state of a workspace.
+func (m *ConfigManager) RemoveWorkspace(path string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.config.RecentWorkspaces[path]; !ok {
+		return nil
+	}
+	delete(m.config.RecentWorkspaces, path)
+	return m.save()
+}
+
+// PruneStaleWorkspaces forgets the workspaces that weren't opened in the last
+// maxAgeDays days and returns how many were removed. Zero or less removes none.
+func (m *ConfigManager) PruneStaleWorkspaces(maxAgeDays int) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	removed := m.pruneStaleWorkspaces(maxAgeDays)
+	if removed > 0 {
+		m.save()
+	}
+	return removed
+}
+
+// pruneStaleWorkspaces removes stale workspaces without locking or saving
+func (m *ConfigManager) pruneStaleWorkspaces(maxAgeDays int) int {
+	if maxAgeDays <= 0 {
+		return 0
+	}
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+	removed := 0
+	for path, ws := range m.config.RecentWorkspaces {
+		if ws == nil || ws.LastAccessed.Before(cutoff) {
+			delete(m.config.RecentWorkspaces, path)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ConfigPath returns the path of the config file
+func (m *ConfigManager) ConfigPath() string {
+	return m.configPath
+}
+
+// HistoryPath returns the path of the prompt history file for a workspace,
+// stored alongside the config file, or "" for a manager made by NewMemoryManager
+func (m *ConfigManager) HistoryPath(workspacePath string) string {
+	if m.configPath == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(workspacePath))
+	return filepath.Join(filepath.Dir(m.configPath), HistoryDir, hex.EncodeToString(sum[:8])+".json")
+}
+
 // GetSelectedFilesPanelSettings returns the selected files panel settings
 func (m *ConfigManager) GetSelectedFilesPanelSettings() SelectedFilesPanelSettings {
 	m.mutex.RLock()
@@ -149,20 +476,43 @@ func (m *ConfigManager) UpdateSelectedFilesPanelSettings(settings SelectedFilesP
 	return m.save()
 }
 
+// staleHelpTexts are the selected files panel help texts earlier versions
+// saved as defaults, which name ctrl+c for clearing the files
+var staleHelpTexts = []string{
+	"↑/↓: navigate, %s: remove file, ctrl+c: clear all",
+	"↑/↓: navigate, %s: remove file, r: line range, t: tags, 1/2/3: bucket, s: sort, ctrl+c: clear all",
+}
+
+// updateHelpText replaces a help text saved by an earlier version with the
+// current default, and in a custom help text the ctrl+c that used to clear the
+// files with the ctrl+d that does now
+func updateHelpText(helpText, defaultHelpText string) string {
+	if slices.Contains(staleHelpTexts, helpText) {
+		return defaultHelpText
+	}
+	return strings.ReplaceAll(helpText, "ctrl+c: clear all", "ctrl+c: copy paths, ctrl+d: clear all")
+}
+
+// newDefaultUISettings returns the UI settings of a new config, which also
+// fill in settings missing from older config files
+func newDefaultUISettings() UISettings {
+	return UISettings{
+		SelectedFilesPanel: SelectedFilesPanelSettings{
+			RemovalKeys:    []string{" ", "delete", "backspace", "x"}, // space, delete, backspace, x
+			ShowHelpText:   true,
+			HelpText:       "↑/↓: navigate, %s: remove file, r: line range, t: tags, 1/2/3: bucket, s: sort, ctrl+c: copy paths, ctrl+d: clear all", // %s will be replaced with key list
+			ConfirmRemoval: false,
+		},
+	}
+}
+
 // newDefaultConfig creates a new AppConfig with default values.
 func newDefaultConfig() *AppConfig {
 	return &AppConfig{
 		RecentWorkspaces: make(map[string]*WorkspaceState),
-		UISettings: UISettings{
-			SelectedFilesPanel: SelectedFilesPanelSettings{
-				RemovalKeys:    []string{" ", "delete", "backspace", "x"}, // space, delete, backspace, x
-				ShowHelpText:   true,
-				HelpText:       "↑/↓: navigate, %s: remove file, ctrl+c: clear all", // %s will be replaced with key list
-				ConfirmRemoval: false,
-			},
-		},
+		UISettings:       newDefaultUISettings(),
 		Metadata: ConfigMetadata{
-			Version:      "1",
+			Version:      CurrentConfigVersion,
 			AppVersion:   AppVersion,
 			CreatedAt:    time.Now(),
 			LastModified: time.Now(),
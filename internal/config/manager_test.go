@@ -1,9 +1,15 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfigManagerSaveRestore(t *testing.T) {
@@ -37,7 +43,7 @@ func TestConfigManagerSaveRestore(t *testing.T) {
 	}
 
 	// Modify workspace data
-	workspace.SelectedFiles = []string{"file1.go", "file2.go"}
+	workspace.SelectedFiles = NewSelectedFileStates([]string{"file1.go", "file2.go"})
 	workspace.ChatInput = "Test prompt content"
 
 	// Save the changes
@@ -62,7 +68,7 @@ func TestConfigManagerSaveRestore(t *testing.T) {
 	if len(workspace2.SelectedFiles) != 2 {
 		t.Errorf("Expected 2 selected files, got %d", len(workspace2.SelectedFiles))
 	}
-	if workspace2.SelectedFiles[0] != "file1.go" || workspace2.SelectedFiles[1] != "file2.go" {
+	if workspace2.SelectedFiles[0].Path != "file1.go" || workspace2.SelectedFiles[1].Path != "file2.go" {
 		t.Errorf("Selected files not restored correctly: %v", workspace2.SelectedFiles)
 	}
 	if workspace2.ChatInput != "Test prompt content" {
@@ -90,11 +96,11 @@ func TestConfigManagerMultipleWorkspaces(t *testing.T) {
 
 	// Create multiple workspaces
 	ws1 := manager.GetWorkspace("/workspace1")
-	ws1.SelectedFiles = []string{"file1.go"}
+	ws1.SelectedFiles = NewSelectedFileStates([]string{"file1.go"})
 	ws1.ChatInput = "Prompt 1"
 
 	ws2 := manager.GetWorkspace("/workspace2")
-	ws2.SelectedFiles = []string{"file2.go", "file3.go"}
+	ws2.SelectedFiles = NewSelectedFileStates([]string{"file2.go", "file3.go"})
 	ws2.ChatInput = "Prompt 2"
 
 	// Save
@@ -117,7 +123,7 @@ func TestConfigManagerMultipleWorkspaces(t *testing.T) {
 	if ws1_restored.ChatInput != "Prompt 1" {
 		t.Errorf("Workspace 1 chat input not restored correctly")
 	}
-	if len(ws1_restored.SelectedFiles) != 1 || ws1_restored.SelectedFiles[0] != "file1.go" {
+	if len(ws1_restored.SelectedFiles) != 1 || ws1_restored.SelectedFiles[0].Path != "file1.go" {
 		t.Errorf("Workspace 1 selected files not restored correctly")
 	}
 
@@ -130,3 +136,552 @@ func TestConfigManagerMultipleWorkspaces(t *testing.T) {
 		t.Errorf("Workspace 2 should have 2 selected files, got %d", len(ws2_restored.SelectedFiles))
 	}
 }
+
+func TestConfigManagerActivePersonasRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	manager := &ConfigManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load initial config: %v", err)
+	}
+
+	workspace := manager.GetWorkspace("/test/workspace")
+	workspace.ActivePersonas = []string{"architect", "default", "instructor"}
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	manager2 := &ConfigManager{
+		configPath: configPath,
+	}
+	if err := manager2.load(); err != nil {
+		t.Fatalf("Failed to load config from disk: %v", err)
+	}
+
+	restored := manager2.GetWorkspace("/test/workspace")
+	expected := []string{"architect", "default", "instructor"}
+	if len(restored.ActivePersonas) != len(expected) {
+		t.Fatalf("Expected active personas %v, got %v", expected, restored.ActivePersonas)
+	}
+	for i, persona := range expected {
+		if restored.ActivePersonas[i] != persona {
+			t.Errorf("Expected active persona %d to be %q, got %q", i, persona, restored.ActivePersonas[i])
+		}
+	}
+}
+
+func TestConfigManagerMigratesCurrentPersona(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	oldConfig := `{
+  "recent_workspaces": {
+    "/old/workspace": {
+      "path": "/old/workspace",
+      "selected_files": [],
+      "current_persona": "architect"
+    }
+  }
+}`
+	if err := os.WriteFile(configPath, []byte(oldConfig), 0644); err != nil {
+		t.Fatalf("Failed to write old config: %v", err)
+	}
+
+	manager := &ConfigManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load old config: %v", err)
+	}
+
+	workspace := manager.GetWorkspace("/old/workspace")
+	if len(workspace.ActivePersonas) != 1 || workspace.ActivePersonas[0] != "architect" {
+		t.Errorf("Expected current_persona to migrate to [architect], got %v", workspace.ActivePersonas)
+	}
+}
+
+func TestConfigManagerRecentWorkspaces(t *testing.T) {
+	manager := &ConfigManager{
+		configPath: filepath.Join(t.TempDir(), "config.json"),
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, path := range []string{"/old", "/newest", "/middle"} {
+		manager.GetWorkspace(path).LastAccessed = base.Add(time.Duration([]int{0, 2, 1}[i]) * time.Hour)
+	}
+
+	workspaces := manager.GetRecentWorkspaces()
+	var paths []string
+	for _, ws := range workspaces {
+		paths = append(paths, ws.Path)
+	}
+	if strings.Join(paths, ",") != "/newest,/middle,/old" {
+		t.Errorf("Expected workspaces most recent first, got %v", paths)
+	}
+
+	if err := manager.RemoveWorkspace("/middle"); err != nil {
+		t.Fatalf("RemoveWorkspace failed: %v", err)
+	}
+	if err := manager.RemoveWorkspace("/unknown"); err != nil {
+		t.Errorf("Expected removing an unknown workspace to succeed, got %v", err)
+	}
+
+	// The removal is persisted
+	reloaded := &ConfigManager{configPath: manager.configPath}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if len(reloaded.GetRecentWorkspaces()) != 2 {
+		t.Errorf("Expected 2 workspaces after removal, got %d", len(reloaded.GetRecentWorkspaces()))
+	}
+	for _, ws := range reloaded.GetRecentWorkspaces() {
+		if ws.Path == "/middle" {
+			t.Error("Expected /middle to be removed")
+		}
+	}
+}
+
+func TestSortWorkspacesBy(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	files := func(n int) []SelectedFileState {
+		return make([]SelectedFileState, n)
+	}
+	workspaces := []WorkspaceState{
+		{Path: "/beta", LastAccessed: base.Add(2 * time.Hour), SelectedFiles: files(1)},
+		{Path: "/alpha", LastAccessed: base, SelectedFiles: files(5)},
+		{Path: "/gamma", LastAccessed: base.Add(time.Hour), SelectedFiles: files(5)},
+		{Path: "/delta", LastAccessed: base.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		mode WorkspaceSortMode
+		want string
+	}{
+		{SortByAccess, "/beta,/delta,/gamma,/alpha"},
+		{SortByName, "/alpha,/beta,/delta,/gamma"},
+		{SortByFileCount, "/alpha,/gamma,/beta,/delta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			var paths []string
+			for _, ws := range SortWorkspacesBy(slices.Clone(workspaces), tt.mode) {
+				paths = append(paths, ws.Path)
+			}
+			if strings.Join(paths, ",") != tt.want {
+				t.Errorf("Expected %s, got %v", tt.want, paths)
+			}
+		})
+	}
+
+	if SortByFileCount.Next() != SortByAccess {
+		t.Error("Expected the sort modes to cycle back to the first")
+	}
+}
+
+func TestConfigManagerSaveIsAtomic(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	manager := &ConfigManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	manager.GetWorkspace("/test/workspace")
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if _, err := os.Stat(configPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected no temp file after saving, got %v", err)
+	}
+}
+
+func TestConfigManagerLoadPartialConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	// A write cut off mid-way leaves truncated JSON
+	if err := os.WriteFile(configPath, []byte(`{"recent_workspaces": {"/test": {"path": "/te`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := &ConfigManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected load to fall back to defaults, got %v", err)
+	}
+	if len(manager.GetRecentWorkspaces()) != 0 {
+		t.Errorf("Expected default config with no workspaces, got %v", manager.GetRecentWorkspaces())
+	}
+}
+
+func TestConfigManagerLoadLeftoverTempFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	manager := &ConfigManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	manager.GetWorkspace("/saved")
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	saved, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A partial temp file is discarded and the config kept
+	if err := os.WriteFile(configPath+".tmp", saved[:len(saved)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := &ConfigManager{configPath: configPath}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if _, err := os.Stat(configPath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial temp file to be removed, got %v", err)
+	}
+	if len(reloaded.GetRecentWorkspaces()) != 1 {
+		t.Errorf("Expected the saved workspace to be kept, got %v", reloaded.GetRecentWorkspaces())
+	}
+
+	// A complete temp file was about to replace the config, so it's recovered
+	complete := strings.Replace(string(saved), `"/saved"`, `"/recovered"`, -1)
+	if err := os.WriteFile(configPath+".tmp", []byte(complete), 0644); err != nil {
+		t.Fatal(err)
+	}
+	recovered := &ConfigManager{configPath: configPath}
+	if err := recovered.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	workspaces := recovered.GetRecentWorkspaces()
+	if len(workspaces) != 1 || workspaces[0].Path != "/recovered" {
+		t.Errorf("Expected the temp file to be recovered, got %v", workspaces)
+	}
+}
+
+func TestSelectedFileStateUnmarshal(t *testing.T) {
+	// Configs written before tags stored plain paths
+	var workspace WorkspaceState
+	data := `{"path": "/test", "selected_files": ["main.go", {"path": "api.go", "tags": ["focus"]}]}`
+	if err := json.Unmarshal([]byte(data), &workspace); err != nil {
+		t.Fatalf("Failed to unmarshal workspace: %v", err)
+	}
+
+	expected := []SelectedFileState{{Path: "main.go"}, {Path: "api.go", Tags: []string{"focus"}}}
+	if !reflect.DeepEqual(workspace.SelectedFiles, expected) {
+		t.Errorf("Expected %v, got %v", expected, workspace.SelectedFiles)
+	}
+	if paths := workspace.SelectedPaths(); !reflect.DeepEqual(paths, []string{"main.go", "api.go"}) {
+		t.Errorf("Expected the paths in order, got %v", paths)
+	}
+
+	saved, err := json.Marshal(workspace.SelectedFiles)
+	if err != nil {
+		t.Fatalf("Failed to marshal selected files: %v", err)
+	}
+	if string(saved) != `[{"path":"main.go"},{"path":"api.go","tags":["focus"]}]` {
+		t.Errorf("Unexpected JSON for selected files: %s", saved)
+	}
+}
+
+func TestPruneStaleWorkspacesOnLoad(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	m := &ConfigManager{configPath: configPath}
+	if err := m.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	m.GetWorkspace("/recent")
+	m.GetWorkspace("/stale")
+	m.config.RecentWorkspaces["/stale"].LastAccessed = time.Now().AddDate(0, 0, -31)
+	if err := m.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	// Pruning is disabled for a zero age
+	kept := &ConfigManager{configPath: configPath}
+	if err := kept.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(kept.GetRecentWorkspaces()) != 2 {
+		t.Errorf("Expected both workspaces to be kept without pruning, got %v", kept.GetRecentWorkspaces())
+	}
+
+	reloaded := &ConfigManager{configPath: configPath, pruneAfterDays: DefaultPruneWorkspacesDays}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	workspaces := reloaded.GetRecentWorkspaces()
+	if len(workspaces) != 1 || workspaces[0].Path != "/recent" {
+		t.Errorf("Expected only the recent workspace to be kept, got %v", workspaces)
+	}
+
+	// The pruned config was saved
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(data), "/stale") {
+		t.Errorf("Expected the stale workspace to be removed from the saved config, got:\n%s", data)
+	}
+
+	if removed := reloaded.PruneStaleWorkspaces(0); removed != 0 {
+		t.Errorf("Expected a zero age to prune nothing, removed %d", removed)
+	}
+	reloaded.config.RecentWorkspaces["/recent"].LastAccessed = time.Now().AddDate(0, 0, -8)
+	if removed := reloaded.PruneStaleWorkspaces(7); removed != 1 {
+		t.Errorf("Expected 1 workspace to be pruned, removed %d", removed)
+	}
+}
+
+func TestMaxRecentWorkspacesEvictsOldest(t *testing.T) {
+	m := &ConfigManager{configPath: filepath.Join(t.TempDir(), ConfigName)}
+	if err := m.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	m.SetMaxRecentWorkspaces(3)
+
+	// Accessed in the order /a, /c, /b, so /a is the oldest
+	base := time.Now().Add(-time.Hour)
+	for i, path := range []string{"/a", "/c", "/b"} {
+		m.GetWorkspace(path).LastAccessed = base.Add(time.Duration(i) * time.Minute)
+	}
+	if m.GetWorkspaceCount() != 3 {
+		t.Fatalf("Expected 3 workspaces, got %d", m.GetWorkspaceCount())
+	}
+
+	m.GetWorkspace("/d")
+	if m.GetWorkspaceCount() != 3 {
+		t.Errorf("Expected the count to stay at 3, got %d", m.GetWorkspaceCount())
+	}
+	var paths []string
+	for _, ws := range m.GetRecentWorkspaces() {
+		paths = append(paths, ws.Path)
+	}
+	if expected := []string{"/d", "/b", "/c"}; !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v after evicting the oldest, got %v", expected, paths)
+	}
+
+	// Opening a remembered workspace evicts nothing
+	m.GetWorkspace("/c")
+	if m.GetWorkspaceCount() != 3 {
+		t.Errorf("Expected 3 workspaces, got %d", m.GetWorkspaceCount())
+	}
+
+	// Zero keeps them all
+	m.SetMaxRecentWorkspaces(0)
+	m.GetWorkspace("/e")
+	if m.GetWorkspaceCount() != 4 {
+		t.Errorf("Expected 4 workspaces without a limit, got %d", m.GetWorkspaceCount())
+	}
+}
+
+func TestMaxRecentWorkspacesKeepsMostRecent(t *testing.T) {
+	m := &ConfigManager{configPath: filepath.Join(t.TempDir(), ConfigName)}
+	if err := m.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	m.SetMaxRecentWorkspaces(1)
+
+	// The workspace just opened is kept even though the other was accessed later
+	m.GetWorkspace("/old").LastAccessed = time.Now().Add(time.Hour)
+	m.GetWorkspace("/new")
+	if ws := m.GetRecentWorkspaces(); len(ws) != 1 || ws[0].Path != "/new" {
+		t.Errorf("Expected only /new to be kept, got %v", ws)
+	}
+}
+
+func TestSelectionPresets(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	m := &ConfigManager{configPath: configPath}
+	if err := m.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	m.GetWorkspace("/project")
+
+	if err := m.SavePreset("/project", " auth ", []string{"/project/auth.go", "/project/session.go"}, nil); err != nil {
+		t.Fatalf("SavePreset failed: %v", err)
+	}
+	if err := m.SavePreset("/project", "models", []string{"/project/user.go", "/project/role.go"}, map[string][]string{"/project/role.go": {"schema", "auth"}}); err != nil {
+		t.Fatalf("SavePreset failed: %v", err)
+	}
+	// Saving under an existing name replaces that preset in place
+	if err := m.SavePreset("/project", "auth", []string{"/project/auth.go"}, nil); err != nil {
+		t.Fatalf("SavePreset failed: %v", err)
+	}
+	expected := []SelectionPreset{
+		{Name: "auth", Files: []string{"/project/auth.go"}},
+		{Name: "models", Files: []string{"/project/user.go", "/project/role.go"}, Tags: []string{"", "schema,auth"}},
+	}
+	presets := m.GetPresets("/project")
+	if !slices.EqualFunc(presets, expected, func(a, b SelectionPreset) bool {
+		return a.Name == b.Name && slices.Equal(a.Files, b.Files) && slices.Equal(a.Tags, b.Tags)
+	}) {
+		t.Errorf("Expected presets %v, got %v", expected, presets)
+	}
+
+	// The presets returned are copies
+	presets[0].Files[0] = "/project/changed.go"
+	if m.GetPresets("/project")[0].Files[0] != "/project/auth.go" {
+		t.Error("Expected changes to the returned presets not to affect the saved ones")
+	}
+
+	if err := m.SavePreset("/project", "  ", nil, nil); err == nil {
+		t.Error("Expected an error for an empty preset name")
+	}
+	if err := m.SavePreset("/other", "auth", nil, nil); err == nil {
+		t.Error("Expected an error for an unknown workspace")
+	}
+	if presets := m.GetPresets("/other"); len(presets) != 0 {
+		t.Errorf("Expected no presets for an unknown workspace, got %v", presets)
+	}
+
+	// Presets are saved with the workspace
+	reloaded := &ConfigManager{configPath: configPath}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if presets := reloaded.GetPresets("/project"); len(presets) != 2 || presets[1].Name != "models" || presets[1].Tags[1] != "schema,auth" {
+		t.Errorf("Expected the presets to be saved, got %v", presets)
+	}
+}
+
+func TestConfigDirEnvOverridesDefaultLocation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	configDir := filepath.Join(t.TempDir(), "shared", "prompter")
+	t.Setenv(ConfigDirEnv, configDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() returned an error: %v", err)
+	}
+	manager.GetWorkspace("/test/workspace")
+	if err := manager.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, ConfigName)); err != nil {
+		t.Errorf("Expected the config file in %s: %v", configDir, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", AppName, ConfigName)); !os.IsNotExist(err) {
+		t.Error("Expected no config file in the default location")
+	}
+
+	settings := "[ui]\nnotification_ttl = 7\n"
+	if err := os.WriteFile(filepath.Join(configDir, SettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	settingsManager, err := NewSettingsManager("")
+	if err != nil {
+		t.Fatalf("NewSettingsManager() returned an error: %v", err)
+	}
+	if ttl := settingsManager.GetNotificationTTL(); ttl != 7 {
+		t.Errorf("Expected the settings to be read from %s, got a TTL of %d", configDir, ttl)
+	}
+
+	// A path that can't be a directory is rejected
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	t.Setenv(ConfigDirEnv, file)
+	if _, err := NewManager(); err == nil {
+		t.Error("Expected an error for a config directory that is a file")
+	}
+	if _, err := NewSettingsManager(""); err == nil {
+		t.Error("Expected an error for a config directory that is a file")
+	}
+}
+
+func TestConfigManagerSelectedFilesPanelSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	manager := &ConfigManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	defaults := newDefaultUISettings().SelectedFilesPanel
+	if settings := manager.GetSelectedFilesPanelSettings(); !reflect.DeepEqual(settings, defaults) {
+		t.Errorf("Expected default settings %+v, got %+v", defaults, settings)
+	}
+
+	custom := SelectedFilesPanelSettings{RemovalKeys: []string{"d"}, ShowHelpText: false, HelpText: "%s: remove", ConfirmRemoval: true}
+	if err := manager.UpdateSelectedFilesPanelSettings(custom); err != nil {
+		t.Fatalf("Failed to update settings: %v", err)
+	}
+	reloaded := &ConfigManager{configPath: configPath}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if settings := reloaded.GetSelectedFilesPanelSettings(); !reflect.DeepEqual(settings, custom) {
+		t.Errorf("Expected saved settings %+v, got %+v", custom, settings)
+	}
+
+	// Config files written before the panel settings existed get the defaults
+	if err := os.WriteFile(configPath, []byte(`{"recent_workspaces": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	older := &ConfigManager{configPath: configPath}
+	if err := older.load(); err != nil {
+		t.Fatalf("Failed to load older config: %v", err)
+	}
+	if settings := older.GetSelectedFilesPanelSettings(); !reflect.DeepEqual(settings, defaults) {
+		t.Errorf("Expected default settings for an older config, got %+v", settings)
+	}
+
+	// Help texts naming ctrl+c for clearing the files are updated and saved
+	for helpText, expected := range map[string]string{
+		"↑/↓: navigate, %s: remove file, ctrl+c: clear all": defaults.HelpText,
+		"%s: remove, ctrl+c: clear all":                     "%s: remove, ctrl+c: copy paths, ctrl+d: clear all",
+	} {
+		data := fmt.Sprintf(`{"ui_settings": {"selected_files_panel": {"help_text": %q}}}`, helpText)
+		if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stale := &ConfigManager{configPath: configPath}
+		if err := stale.load(); err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if got := stale.GetSelectedFilesPanelSettings().HelpText; got != expected {
+			t.Errorf("Expected help text %q to become %q, got %q", helpText, expected, got)
+		}
+		saved, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(saved), "ctrl+c: clear all") {
+			t.Errorf("Expected the updated help text to be saved, got:\n%s", saved)
+		}
+	}
+}
+
+func TestNewMemoryManager(t *testing.T) {
+	// Nothing may be written to the working directory either
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	m := NewMemoryManager()
+	workspace := m.GetWorkspace("/project")
+	workspace.ChatInput = "review this"
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected nothing to be written, got %v", entries)
+	}
+	if m.GetWorkspace("/project").ChatInput != "review this" {
+		t.Error("Expected the workspace to be kept in memory")
+	}
+	if m.HistoryPath("/project") != "" {
+		t.Errorf("Expected no history file, got %q", m.HistoryPath("/project"))
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import "fmt"
+
+// CurrentConfigVersion is the schema version of the configuration written by
+// this version of the app
+const CurrentConfigVersion = "2"
+
+// migration upgrades a configuration from one schema version to the next
+type migration struct {
+	from    string
+	to      string
+	migrate func(cfg *AppConfig) error
+}
+
+// migrations lists the schema migrations in order, each starting at the
+// version the previous one ends at. Add new ones at the end, bumping
+// CurrentConfigVersion.
+var migrations = []migration{
+	{from: "1", to: "2", migrate: migrateV1ToV2},
+}
+
+// migrateConfig applies the migrations from the configuration's version up to
+// CurrentConfigVersion in turn. Configurations without a version predate
+// versioning and are treated as version 1. It returns an error for versions it
+// doesn't know, such as ones written by a newer version of the app.
+func migrateConfig(cfg *AppConfig) (*AppConfig, error) {
+	if cfg.Metadata.Version == "" {
+		cfg.Metadata.Version = "1"
+	}
+	for _, m := range migrations {
+		if cfg.Metadata.Version != m.from {
+			continue
+		}
+		if err := m.migrate(cfg); err != nil {
+			return nil, fmt.Errorf("failed to migrate config from version %s to %s: %w", m.from, m.to, err)
+		}
+		cfg.Metadata.Version = m.to
+	}
+	if cfg.Metadata.Version != CurrentConfigVersion {
+		return nil, fmt.Errorf("unsupported config version %q", cfg.Metadata.Version)
+	}
+	return cfg, nil
+}
+
+// migrateV1ToV2 replaces the single current persona of each workspace with
+// the list of active personas
+func migrateV1ToV2(cfg *AppConfig) error {
+	for _, ws := range cfg.RecentWorkspaces {
+		if ws == nil {
+			continue
+		}
+		if ws.CurrentPersona != "" && len(ws.ActivePersonas) == 0 {
+			ws.ActivePersonas = []string{ws.CurrentPersona}
+		}
+		ws.CurrentPersona = ""
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateV1ToV2(t *testing.T) {
+	cfg := &AppConfig{
+		Metadata: ConfigMetadata{Version: "1"},
+		RecentWorkspaces: map[string]*WorkspaceState{
+			"/legacy":   {Path: "/legacy", CurrentPersona: "architect"},
+			"/both":     {Path: "/both", CurrentPersona: "architect", ActivePersonas: []string{"reviewer"}},
+			"/personas": {Path: "/personas", ActivePersonas: []string{"default", "reviewer"}},
+		},
+	}
+
+	migrated, err := migrateConfig(cfg)
+	if err != nil {
+		t.Fatalf("migrateConfig() returned an error: %v", err)
+	}
+	if migrated.Metadata.Version != "2" {
+		t.Errorf("Expected version 2, got %q", migrated.Metadata.Version)
+	}
+	expected := map[string][]string{
+		"/legacy":   {"architect"},
+		"/both":     {"reviewer"}, // Active personas already set win
+		"/personas": {"default", "reviewer"},
+	}
+	for path, personas := range expected {
+		ws := migrated.RecentWorkspaces[path]
+		if !reflect.DeepEqual(ws.ActivePersonas, personas) {
+			t.Errorf("%s: expected active personas %v, got %v", path, personas, ws.ActivePersonas)
+		}
+		if ws.CurrentPersona != "" {
+			t.Errorf("%s: expected the current persona to be cleared, got %q", path, ws.CurrentPersona)
+		}
+	}
+}
+
+func TestMigrateConfigVersions(t *testing.T) {
+	// Configs written before versioning start at version 1
+	cfg, err := migrateConfig(&AppConfig{RecentWorkspaces: map[string]*WorkspaceState{}})
+	if err != nil || cfg.Metadata.Version != CurrentConfigVersion {
+		t.Errorf("Expected an unversioned config to be migrated to %s, got %+v, %v", CurrentConfigVersion, cfg, err)
+	}
+
+	// The current version is left alone
+	cfg, err = migrateConfig(&AppConfig{Metadata: ConfigMetadata{Version: CurrentConfigVersion}})
+	if err != nil || cfg.Metadata.Version != CurrentConfigVersion {
+		t.Errorf("Expected the current version to be kept, got %+v, %v", cfg, err)
+	}
+
+	if _, err := migrateConfig(&AppConfig{Metadata: ConfigMetadata{Version: "99"}}); err == nil {
+		t.Error("Expected an error for an unknown version")
+	}
+}
+
+func TestConfigManagerSavesMigratedConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigName)
+	old := `{"metadata": {"version": "1"}, "recent_workspaces": {"/ws": {"path": "/ws", "current_persona": "architect"}}}`
+	if err := os.WriteFile(configPath, []byte(old), 0644); err != nil {
+		t.Fatalf("Failed to write old config: %v", err)
+	}
+
+	manager := &ConfigManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Failed to load old config: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var saved struct {
+		Metadata         ConfigMetadata             `json:"metadata"`
+		RecentWorkspaces map[string]json.RawMessage `json:"recent_workspaces"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Saved config is not valid JSON: %v", err)
+	}
+	if saved.Metadata.Version != CurrentConfigVersion {
+		t.Errorf("Expected the migrated config to be saved at version %s, got %q", CurrentConfigVersion, saved.Metadata.Version)
+	}
+	var ws map[string]any
+	json.Unmarshal(saved.RecentWorkspaces["/ws"], &ws)
+	if _, ok := ws["current_persona"]; ok {
+		t.Errorf("Expected current_persona to be dropped, got %s", saved.RecentWorkspaces["/ws"])
+	}
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/toml"
@@ -11,21 +14,41 @@ import (
 )
 
 const (
-	SettingsDir  = "coding-prompts"
-	SettingsFile = "coding_prompts.toml"
+	SettingsDir         = "coding-prompts"
+	SettingsFile        = "coding_prompts.toml"
+	ProjectSettingsFile = ".coding-prompts.toml" // Per-project overrides at the workspace root
 )
 
 // UserSettings represents user-configurable settings loaded from TOML
 type UserSettings struct {
-	Bindings KeyBindings    `toml:"bindings"`
-	UI       UserUISettings `toml:"ui"`
-	Debug    DebugSettings  `toml:"debug"`
+	// Recent workspaces not opened for this many days are forgotten; negative keeps them all
+	PruneWorkspacesOlderThan int `toml:"prune_workspaces_older_than_days"`
+
+	Bindings KeyBindings     `toml:"bindings"`
+	UI       UserUISettings  `toml:"ui"`
+	Debug    DebugSettings   `toml:"debug"`
+	Webhook  WebhookSettings `toml:"webhook"`
+	Storage  StorageSettings `toml:"storage"`
 }
 
 // KeyBindings contains all key binding configurations
 type KeyBindings struct {
 	// Global bindings (always active)
 	EscapeToNormal string `toml:"escape_to_normal"`
+	QuickOpen      string `toml:"quick_open"`
+	History        string `toml:"history"`
+	Export         string `toml:"export"`
+	ExportManifest string `toml:"export_manifest"`
+	GlobSelect     string `toml:"glob_select"`
+	WorkspaceList  string `toml:"workspace_list"`
+	Webhook        string `toml:"webhook"`
+	Help           string `toml:"help"`
+	LayoutToggle   string `toml:"layout_toggle"`
+	GitignoreCheck string `toml:"gitignore_check"`
+	Settings       string `toml:"settings"`
+	ModifiedSince  string `toml:"modified_since"`
+	Presets        string `toml:"presets"`
+	SavePreset     string `toml:"save_preset"`
 
 	// Mode-specific bindings
 	MenuMode   ModeBindings `toml:"menu_mode"`
@@ -39,47 +62,107 @@ type KeyBindings struct {
 
 // ModeBindings represents key bindings for a specific interaction mode
 type ModeBindings struct {
-	Activation  string `toml:"activation,omitempty"`
-	Exit        string `toml:"exit,omitempty"`
-	PersonaMenu string `toml:"persona_menu,omitempty"`
-	Tab         string `toml:"tab,omitempty"`
-	ShiftTab    string `toml:"shift_tab,omitempty"`
+	Activation   string `toml:"activation,omitempty"`
+	Exit         string `toml:"exit,omitempty"`
+	PersonaMenu  string `toml:"persona_menu,omitempty"`
+	FormatToggle string `toml:"format_toggle,omitempty"`
+	Tab          string `toml:"tab,omitempty"`
+	ShiftTab     string `toml:"shift_tab,omitempty"`
 }
 
 // UserUISettings represents user interface configuration options from TOML
 type UserUISettings struct {
-	NotificationTTL int `toml:"notification_ttl"`
+	NotificationTTL         int            `toml:"notification_ttl"`
+	TokenWarningThreshold   int            `toml:"token_warning_threshold"`    // Warn when a prompt's estimated tokens exceed this
+	ShowGitStatus           bool           `toml:"show_git_status"`            // Annotate files in the tree with their git status
+	FollowSymlinks          bool           `toml:"follow_symlinks"`            // Expand symlinked directories in the file tree
+	AllowBinaryFiles        bool           `toml:"allow_binary_files"`         // Allow selecting binary files, which are included as placeholders
+	MaxFileSizeKB           int            `toml:"max_file_size_kb"`           // Larger files can't be selected; negative disables the limit
+	LayoutMode              string         `toml:"layout_mode"`                // Panel arrangement on startup: default, vertical or compact
+	ShowPreview             bool           `toml:"show_preview"`               // Show the file under the tree cursor below the file tree
+	PreviewLines            int            `toml:"preview_lines"`              // Number of lines shown in the file preview
+	ClipboardBackend        string         `toml:"clipboard_backend"`          // Clipboard tool to copy with: auto, wl-clipboard, xclip, xsel, pbcopy or stdout
+	StripComments           bool           `toml:"strip_comments"`             // Remove comments from source files in generated prompts
+	StripCommentLanguages   []string       `toml:"strip_comment_languages"`    // Languages to strip comments from; empty means all supported
+	PromptCharLimit         int            `toml:"prompt_char_limit"`          // Most characters the user prompt may hold; negative removes the limit
+	WordWrap                bool           `toml:"word_wrap"`                  // Wrap the user prompt at the chat panel width instead of a fixed width
+	MaxBulkSelect           int            `toml:"max_bulk_select"`            // Most files ctrl+a selects at once; negative removes the limit
+	NormalizeEncoding       bool           `toml:"normalize_encoding"`         // Transcode selected files that aren't UTF-8 to UTF-8 in generated prompts
+	OverviewFiles           []string       `toml:"overview_files"`             // Files in the project root checked in order for the project overview
+	IncludeAllOverviewFiles bool           `toml:"include_all_overview_files"` // Include every overview file found rather than only the first
+	Layout                  LayoutSettings `toml:"layout"`
+	Theme                   ThemeSettings  `toml:"theme"`
 }
 
+// ThemeSettings picks the colors of the interface. Colors are terminal color
+// codes such as "69" or hex values such as "#5f87ff"; empty ones come from the preset.
+type ThemeSettings struct {
+	Preset       string `toml:"preset"`        // Built-in theme the colors start from: default or high-contrast
+	FocusBorder  string `toml:"focus_border"`  // Border of the focused panel and the chat title
+	NormalBorder string `toml:"normal_border"` // Border of the other panels
+	CursorFG     string `toml:"cursor_fg"`     // Item under the cursor in lists
+	SelectedFG   string `toml:"selected_fg"`   // Selected files and the preview title
+	TitleFG      string `toml:"title_fg"`      // Dialog titles and borders
+}
+
+// LayoutSettings controls how the screen is split between panels
+type LayoutSettings struct {
+	TopHeightRatio float64 `toml:"top_height_ratio"` // Share of the height given to the top panels
+	LeftPanelRatio float64 `toml:"left_panel_ratio"` // Share of the width given to the left panels
+}
+
+const (
+	minLayoutRatio = 0.1
+	maxLayoutRatio = 0.9
+)
+
 // DebugSettings represents debug configuration options from TOML
 type DebugSettings struct {
-	Enabled     bool   `toml:"enabled"`      // Enable debug mode on startup
-	ToggleKey   string `toml:"toggle_key"`   // Key binding to toggle debug mode
-	FileLogging bool   `toml:"file_logging"` // Enable file logging for debug messages
-	LogFile     string `toml:"log_file"`     // Log file path relative to workspace
+	Enabled               bool   `toml:"enabled"`                 // Enable debug mode on startup
+	ToggleKey             string `toml:"toggle_key"`              // Key binding to toggle debug mode
+	FileLogging           bool   `toml:"file_logging"`            // Enable file logging for debug messages
+	LogFile               string `toml:"log_file"`                // Log file path relative to workspace
+	InvariantCheckEnabled bool   `toml:"invariant_check_enabled"` // Check the app state after every update while debug mode is on
+}
+
+// WebhookSettings configures delivery of generated prompts to an HTTP endpoint
+type WebhookSettings struct {
+	URL            string            `toml:"url"`             // Endpoint the prompt is sent to; delivery is disabled when empty
+	Method         string            `toml:"method"`          // GET or POST
+	Headers        map[string]string `toml:"headers"`         // Extra request headers, e.g. Authorization
+	TimeoutSeconds int               `toml:"timeout_seconds"` // Give up on the request after this many seconds
+}
+
+// StorageSettings limits what is kept in the configuration file
+type StorageSettings struct {
+	MaxRecentWorkspaces int `toml:"max_recent_workspaces"` // Most workspaces remembered; negative keeps them all
 }
 
 // SettingsManager handles loading and validation of user settings from TOML
 type SettingsManager struct {
 	configPath string
+	localPath  string // Project-local overrides, empty when not used
 	settings   *UserSettings
 	mutex      sync.RWMutex
 	watcher    *fsnotify.Watcher
 	onChange   func(*UserSettings) // Callback when settings change
 }
 
-// NewSettingsManager creates a new SettingsManager
-func NewSettingsManager() (*SettingsManager, error) {
-	homeDir, err := os.UserHomeDir()
+// NewSettingsManager creates a new SettingsManager. The settings are read from
+// the ConfigDirEnv directory if it is set. If workspaceDir is not empty, a
+// .coding-prompts.toml at its root overrides the global settings.
+func NewSettingsManager(workspaceDir string) (*SettingsManager, error) {
+	configPath, err := defaultSettingsPath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	configPath := filepath.Join(homeDir, ".config", SettingsDir, SettingsFile)
-
 	m := &SettingsManager{
 		configPath: configPath,
 	}
+	if workspaceDir != "" {
+		m.localPath = filepath.Join(workspaceDir, ProjectSettingsFile)
+	}
 
 	if err := m.load(); err != nil {
 		return nil, fmt.Errorf("failed to load settings: %w", err)
@@ -88,6 +171,30 @@ func NewSettingsManager() (*SettingsManager, error) {
 	return m, nil
 }
 
+// NewDefaultSettingsManager creates a SettingsManager holding the default
+// settings, without a settings file to read them from or write bindings to
+func NewDefaultSettingsManager() *SettingsManager {
+	return &SettingsManager{settings: getDefaultSettings()}
+}
+
+// errNoSettingsFile is returned when writing or watching the settings of a
+// manager made by NewDefaultSettingsManager
+var errNoSettingsFile = errors.New("settings are not backed by a file")
+
+// defaultSettingsPath returns the path of the global settings file, in the
+// ConfigDirEnv directory if it is set
+func defaultSettingsPath() (string, error) {
+	dir, err := configDirOverride()
+	if err != nil || dir != "" {
+		return filepath.Join(dir, SettingsFile), err
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", SettingsDir, SettingsFile), nil
+}
+
 // load reads the TOML configuration file with validation (thread-safe)
 func (m *SettingsManager) load() error {
 	m.mutex.Lock()
@@ -95,46 +202,116 @@ func (m *SettingsManager) load() error {
 	return m.loadUnsafe()
 }
 
-// loadUnsafe reads the TOML configuration file with validation (not thread-safe)
+// loadUnsafe reads the TOML configuration files with validation (not thread-safe).
+// The global file is decoded onto the default settings and the project-local
+// file onto the result, so keys missing from a file keep their earlier value
+// and a project file can set a value back to false, 0 or "".
 func (m *SettingsManager) loadUnsafe() error {
-	// Check if config file exists
-	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
-		// Use default settings if file doesn't exist
-		m.settings = getDefaultSettings()
-		return nil
-	}
-
-	// Read the TOML file
-	data, err := os.ReadFile(m.configPath)
+	settings := getDefaultSettings()
+	found, err := decodeSettingsFile(m.configPath, settings)
 	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", m.configPath, err)
+		return err
+	}
+	if m.localPath != "" {
+		foundLocal, err := decodeSettingsFile(m.localPath, settings)
+		if err != nil {
+			return err
+		}
+		found = found || foundLocal
 	}
 
-	var settings UserSettings
-	if err := toml.Unmarshal(data, &settings); err != nil {
-		return fmt.Errorf("invalid TOML format in %s: %w", m.configPath, err)
+	// Use default settings if neither file exists
+	if !found {
+		m.settings = settings
+		return nil
 	}
 
-	// Apply defaults for missing values
-	m.applyDefaults(&settings)
+	// Apply defaults for values set to zero
+	m.applyDefaults(settings)
 
 	// Validate the loaded settings
-	if err := m.validate(&settings); err != nil {
+	if err := m.validate(settings); err != nil {
 		return fmt.Errorf("invalid configuration in %s: %w", m.configPath, err)
 	}
 
-	m.settings = &settings
+	m.settings = settings
 	return nil
 }
 
+// decodeSettingsFile decodes the TOML settings file at path onto settings,
+// returning false if it doesn't exist
+func decodeSettingsFile(path string, settings *UserSettings) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if err := toml.Unmarshal(data, settings); err != nil {
+		return false, fmt.Errorf("invalid TOML format in %s: %w", path, err)
+	}
+	return true, nil
+}
+
 // applyDefaults applies default values for any missing configuration
 func (m *SettingsManager) applyDefaults(settings *UserSettings) {
 	defaults := getDefaultSettings()
 
+	if settings.PruneWorkspacesOlderThan == 0 {
+		settings.PruneWorkspacesOlderThan = defaults.PruneWorkspacesOlderThan
+	}
+	if settings.Storage.MaxRecentWorkspaces == 0 {
+		settings.Storage.MaxRecentWorkspaces = defaults.Storage.MaxRecentWorkspaces
+	}
+
 	// Apply binding defaults
 	if settings.Bindings.EscapeToNormal == "" {
 		settings.Bindings.EscapeToNormal = defaults.Bindings.EscapeToNormal
 	}
+	if settings.Bindings.QuickOpen == "" {
+		settings.Bindings.QuickOpen = defaults.Bindings.QuickOpen
+	}
+	if settings.Bindings.History == "" {
+		settings.Bindings.History = defaults.Bindings.History
+	}
+	if settings.Bindings.Export == "" {
+		settings.Bindings.Export = defaults.Bindings.Export
+	}
+	if settings.Bindings.ExportManifest == "" {
+		settings.Bindings.ExportManifest = defaults.Bindings.ExportManifest
+	}
+	if settings.Bindings.GlobSelect == "" {
+		settings.Bindings.GlobSelect = defaults.Bindings.GlobSelect
+	}
+	if settings.Bindings.ModifiedSince == "" {
+		settings.Bindings.ModifiedSince = defaults.Bindings.ModifiedSince
+	}
+	if settings.Bindings.Presets == "" {
+		settings.Bindings.Presets = defaults.Bindings.Presets
+	}
+	if settings.Bindings.SavePreset == "" {
+		settings.Bindings.SavePreset = defaults.Bindings.SavePreset
+	}
+	if settings.Bindings.WorkspaceList == "" {
+		settings.Bindings.WorkspaceList = defaults.Bindings.WorkspaceList
+	}
+	if settings.Bindings.Webhook == "" {
+		settings.Bindings.Webhook = defaults.Bindings.Webhook
+	}
+	if settings.Bindings.Help == "" {
+		settings.Bindings.Help = defaults.Bindings.Help
+	}
+	if settings.Bindings.LayoutToggle == "" {
+		settings.Bindings.LayoutToggle = defaults.Bindings.LayoutToggle
+	}
+	if settings.Bindings.GitignoreCheck == "" {
+		settings.Bindings.GitignoreCheck = defaults.Bindings.GitignoreCheck
+	}
+	if settings.Bindings.Settings == "" {
+		settings.Bindings.Settings = defaults.Bindings.Settings
+	}
 
 	// Apply menu mode defaults
 	if settings.Bindings.MenuMode.Activation == "" {
@@ -146,6 +323,9 @@ func (m *SettingsManager) applyDefaults(settings *UserSettings) {
 	if settings.Bindings.MenuMode.PersonaMenu == "" {
 		settings.Bindings.MenuMode.PersonaMenu = defaults.Bindings.MenuMode.PersonaMenu
 	}
+	if settings.Bindings.MenuMode.FormatToggle == "" {
+		settings.Bindings.MenuMode.FormatToggle = defaults.Bindings.MenuMode.FormatToggle
+	}
 
 	// Apply normal mode defaults
 	if settings.Bindings.NormalMode.Tab == "" {
@@ -159,6 +339,47 @@ func (m *SettingsManager) applyDefaults(settings *UserSettings) {
 	if settings.UI.NotificationTTL <= 0 {
 		settings.UI.NotificationTTL = defaults.UI.NotificationTTL
 	}
+	if settings.UI.TokenWarningThreshold <= 0 {
+		settings.UI.TokenWarningThreshold = defaults.UI.TokenWarningThreshold
+	}
+	if settings.UI.MaxFileSizeKB == 0 {
+		settings.UI.MaxFileSizeKB = defaults.UI.MaxFileSizeKB
+	}
+	if settings.UI.PromptCharLimit == 0 {
+		settings.UI.PromptCharLimit = defaults.UI.PromptCharLimit
+	}
+	if settings.UI.MaxBulkSelect == 0 {
+		settings.UI.MaxBulkSelect = defaults.UI.MaxBulkSelect
+	}
+	if len(settings.UI.OverviewFiles) == 0 {
+		settings.UI.OverviewFiles = defaults.UI.OverviewFiles
+	}
+	if settings.UI.LayoutMode == "" {
+		settings.UI.LayoutMode = defaults.UI.LayoutMode
+	}
+	if settings.UI.PreviewLines <= 0 {
+		settings.UI.PreviewLines = defaults.UI.PreviewLines
+	}
+	if settings.UI.ClipboardBackend == "" {
+		settings.UI.ClipboardBackend = defaults.UI.ClipboardBackend
+	}
+	if settings.UI.Layout.TopHeightRatio == 0 {
+		settings.UI.Layout.TopHeightRatio = defaults.UI.Layout.TopHeightRatio
+	}
+	if settings.UI.Layout.LeftPanelRatio == 0 {
+		settings.UI.Layout.LeftPanelRatio = defaults.UI.Layout.LeftPanelRatio
+	}
+	if settings.UI.Theme.Preset == "" {
+		settings.UI.Theme.Preset = defaults.UI.Theme.Preset
+	}
+
+	// Apply webhook defaults
+	if settings.Webhook.Method == "" {
+		settings.Webhook.Method = defaults.Webhook.Method
+	}
+	if settings.Webhook.TimeoutSeconds <= 0 {
+		settings.Webhook.TimeoutSeconds = defaults.Webhook.TimeoutSeconds
+	}
 
 	// Apply debug defaults
 	if settings.Debug.ToggleKey == "" {
@@ -173,13 +394,76 @@ func (m *SettingsManager) applyDefaults(settings *UserSettings) {
 
 // validate performs validation on the loaded settings
 func (m *SettingsManager) validate(settings *UserSettings) error {
+	// Validate layout ratios
+	if err := validateLayoutRatio("ui.layout.top_height_ratio", settings.UI.Layout.TopHeightRatio); err != nil {
+		return err
+	}
+	if err := validateLayoutRatio("ui.layout.left_panel_ratio", settings.UI.Layout.LeftPanelRatio); err != nil {
+		return err
+	}
+
+	// Validate layout mode
+	switch settings.UI.LayoutMode {
+	case "default", "vertical", "compact":
+	default:
+		return fmt.Errorf("ui.layout_mode must be default, vertical or compact, got: %q", settings.UI.LayoutMode)
+	}
+
+	// Validate theme preset
+	switch settings.UI.Theme.Preset {
+	case "default", "high-contrast":
+	default:
+		return fmt.Errorf("ui.theme.preset must be default or high-contrast, got: %q", settings.UI.Theme.Preset)
+	}
+
+	// Validate clipboard backend
+	switch settings.UI.ClipboardBackend {
+	case "auto", "wl-clipboard", "xclip", "xsel", "pbcopy", "stdout":
+	default:
+		return fmt.Errorf("ui.clipboard_backend must be auto, wl-clipboard, xclip, xsel, pbcopy or stdout, got: %q", settings.UI.ClipboardBackend)
+	}
+
+	// Validate the languages to strip comments from
+	for _, lang := range settings.UI.StripCommentLanguages {
+		switch lang {
+		case "go", "python", "javascript", "shell":
+		default:
+			return fmt.Errorf("ui.strip_comment_languages may only contain go, python, javascript or shell, got: %q", lang)
+		}
+	}
+
+	// Validate the project overview files, which are looked up in the project root
+	for _, name := range settings.UI.OverviewFiles {
+		if strings.TrimSpace(name) == "" || filepath.IsAbs(name) {
+			return fmt.Errorf("ui.overview_files must contain paths relative to the project root, got: %q", name)
+		}
+	}
+
+	// Validate webhook method
+	if method := strings.ToUpper(settings.Webhook.Method); method != "GET" && method != "POST" {
+		return fmt.Errorf("webhook.method must be GET or POST, got: %q", settings.Webhook.Method)
+	}
+
 	// Check for backward compatibility mode (legacy single-character bindings)
 	if settings.Bindings.MenuActivation != "" || settings.Bindings.PersonaMenu != "" {
 		return m.validateLegacyBindings(settings)
 	}
 
 	// Validate new mode-based bindings
-	return m.validateModeBindings(settings)
+	if err := m.validateModeBindings(settings); err != nil {
+		return err
+	}
+
+	// Bindings active at the same time can't share a key
+	return conflictsError(DetectConflicts(bindingMap(&settings.Bindings)))
+}
+
+// validateLayoutRatio checks that a panel split ratio leaves room for both panels
+func validateLayoutRatio(name string, ratio float64) error {
+	if ratio < minLayoutRatio || ratio > maxLayoutRatio {
+		return fmt.Errorf("%s must be between %.1f and %.1f, got %g", name, minLayoutRatio, maxLayoutRatio, ratio)
+	}
+	return nil
 }
 
 // validateLegacyBindings validates the old single-character binding format
@@ -205,6 +489,74 @@ func (m *SettingsManager) validateLegacyBindings(settings *UserSettings) error {
 
 // validateModeBindings validates the new mode-based binding format
 func (m *SettingsManager) validateModeBindings(settings *UserSettings) error {
+	// Validate quick open key
+	if err := validateKeyBinding(settings.Bindings.QuickOpen); err != nil {
+		return fmt.Errorf("invalid bindings.quick_open: %w", err)
+	}
+
+	// Validate history key
+	if err := validateKeyBinding(settings.Bindings.History); err != nil {
+		return fmt.Errorf("invalid bindings.history: %w", err)
+	}
+
+	// Validate export key
+	if err := validateKeyBinding(settings.Bindings.Export); err != nil {
+		return fmt.Errorf("invalid bindings.export: %w", err)
+	}
+
+	// Validate manifest export key
+	if err := validateKeyBinding(settings.Bindings.ExportManifest); err != nil {
+		return fmt.Errorf("invalid bindings.export_manifest: %w", err)
+	}
+
+	// Validate glob select key
+	if err := validateKeyBinding(settings.Bindings.GlobSelect); err != nil {
+		return fmt.Errorf("invalid bindings.glob_select: %w", err)
+	}
+
+	// Validate modified since filter key
+	if err := validateKeyBinding(settings.Bindings.ModifiedSince); err != nil {
+		return fmt.Errorf("invalid bindings.modified_since: %w", err)
+	}
+
+	// Validate selection preset keys
+	if err := validateKeyBinding(settings.Bindings.Presets); err != nil {
+		return fmt.Errorf("invalid bindings.presets: %w", err)
+	}
+	if err := validateKeyBinding(settings.Bindings.SavePreset); err != nil {
+		return fmt.Errorf("invalid bindings.save_preset: %w", err)
+	}
+
+	// Validate workspace list key
+	if err := validateKeyBinding(settings.Bindings.WorkspaceList); err != nil {
+		return fmt.Errorf("invalid bindings.workspace_list: %w", err)
+	}
+
+	// Validate webhook delivery key
+	if err := validateKeyBinding(settings.Bindings.Webhook); err != nil {
+		return fmt.Errorf("invalid bindings.webhook: %w", err)
+	}
+
+	// Validate help overlay key
+	if err := validateKeyBinding(settings.Bindings.Help); err != nil {
+		return fmt.Errorf("invalid bindings.help: %w", err)
+	}
+
+	// Validate layout toggle key
+	if err := validateKeyBinding(settings.Bindings.LayoutToggle); err != nil {
+		return fmt.Errorf("invalid bindings.layout_toggle: %w", err)
+	}
+
+	// Validate ignore pattern check key
+	if err := validateKeyBinding(settings.Bindings.GitignoreCheck); err != nil {
+		return fmt.Errorf("invalid bindings.gitignore_check: %w", err)
+	}
+
+	// Validate settings panel key
+	if err := validateKeyBinding(settings.Bindings.Settings); err != nil {
+		return fmt.Errorf("invalid bindings.settings: %w", err)
+	}
+
 	// Validate menu mode activation key
 	if settings.Bindings.MenuMode.Activation == "" {
 		return fmt.Errorf("bindings.menu_mode.activation cannot be empty")
@@ -228,6 +580,13 @@ func (m *SettingsManager) validateModeBindings(settings *UserSettings) error {
 		}
 	}
 
+	// Validate format toggle key (if specified)
+	if settings.Bindings.MenuMode.FormatToggle != "" {
+		if err := validateKeyBinding(settings.Bindings.MenuMode.FormatToggle); err != nil {
+			return fmt.Errorf("invalid bindings.menu_mode.format_toggle: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -306,6 +665,111 @@ func (m *SettingsManager) GetMenuModePersonaMenu() string {
 	return m.settings.Bindings.MenuMode.PersonaMenu
 }
 
+// GetMenuModeFormatToggle returns the output format toggle key for menu mode (thread-safe)
+func (m *SettingsManager) GetMenuModeFormatToggle() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.MenuMode.FormatToggle
+}
+
+// GetQuickOpenKey returns the key binding for the quick-open file search dialog (thread-safe)
+func (m *SettingsManager) GetQuickOpenKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.QuickOpen
+}
+
+// GetHistoryKey returns the key binding for the prompt history dialog (thread-safe)
+func (m *SettingsManager) GetHistoryKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.History
+}
+
+// GetExportKey returns the key binding for the export-to-file dialog (thread-safe)
+func (m *SettingsManager) GetExportKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.Export
+}
+
+// GetGlobSelectKey returns the key binding for the glob pattern selection input (thread-safe)
+func (m *SettingsManager) GetGlobSelectKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.GlobSelect
+}
+
+// GetWorkspaceListKey returns the key binding for the recent workspaces list (thread-safe)
+func (m *SettingsManager) GetWorkspaceListKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.WorkspaceList
+}
+
+// GetWebhookKey returns the key binding for delivering the prompt to the webhook (thread-safe)
+func (m *SettingsManager) GetWebhookKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.Webhook
+}
+
+// GetHelpKey returns the key binding for the key binding help overlay (thread-safe)
+func (m *SettingsManager) GetHelpKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.Help
+}
+
+// GetLayoutToggleKey returns the key binding that cycles through the panel layouts (thread-safe)
+func (m *SettingsManager) GetLayoutToggleKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.LayoutToggle
+}
+
+// GetExportManifestKey returns the key binding that exports the list of selected files (thread-safe)
+func (m *SettingsManager) GetExportManifestKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.ExportManifest
+}
+
+// GetModifiedSinceKey returns the key binding that sets the time files must be modified since to be included (thread-safe)
+func (m *SettingsManager) GetModifiedSinceKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.ModifiedSince
+}
+
+// GetPresetsKey returns the key binding that opens the saved selection presets (thread-safe)
+func (m *SettingsManager) GetPresetsKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.Presets
+}
+
+// GetSavePresetKey returns the key binding that saves the selection as a preset (thread-safe)
+func (m *SettingsManager) GetSavePresetKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.SavePreset
+}
+
+// GetGitignoreCheckKey returns the key binding that shows what each ignore pattern matches (thread-safe)
+func (m *SettingsManager) GetGitignoreCheckKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.GitignoreCheck
+}
+
+// GetSettingsKey returns the key binding that opens the key binding settings panel (thread-safe)
+func (m *SettingsManager) GetSettingsKey() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Bindings.Settings
+}
+
 // IsLegacyMode returns true if using legacy single-character bindings
 func (m *SettingsManager) IsLegacyMode() bool {
 	m.mutex.RLock()
@@ -323,6 +787,198 @@ func (m *SettingsManager) GetNotificationTTL() int {
 	return m.settings.UI.NotificationTTL
 }
 
+// GetTokenWarningThreshold returns the estimated token count above which a warning is shown
+func (m *SettingsManager) GetTokenWarningThreshold() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.UI.TokenWarningThreshold <= 0 {
+		return 100000 // Default 100k tokens
+	}
+	return m.settings.UI.TokenWarningThreshold
+}
+
+// GetMaxFileSizeBytes returns the size in bytes above which files can't be selected, or zero for no limit
+func (m *SettingsManager) GetMaxFileSizeBytes() int64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.UI.MaxFileSizeKB < 0 {
+		return 0
+	}
+	if m.settings.UI.MaxFileSizeKB == 0 {
+		return 512 * 1024 // Default 512 KB
+	}
+	return int64(m.settings.UI.MaxFileSizeKB) * 1024
+}
+
+// GetPromptCharLimit returns the most characters the user prompt may hold, or zero for no limit
+func (m *SettingsManager) GetPromptCharLimit() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.UI.PromptCharLimit < 0 {
+		return 0
+	}
+	if m.settings.UI.PromptCharLimit == 0 {
+		return 10000 // Default 10 000 characters
+	}
+	return m.settings.UI.PromptCharLimit
+}
+
+// GetOverviewFiles returns a copy of the files checked in order for the project overview
+func (m *SettingsManager) GetOverviewFiles() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]string{}, m.settings.UI.OverviewFiles...)
+}
+
+// IsIncludeAllOverviewFilesEnabled returns whether every overview file found is included rather than only the first
+func (m *SettingsManager) IsIncludeAllOverviewFilesEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.IncludeAllOverviewFiles
+}
+
+// GetMaxBulkSelect returns the most files selecting every visible file may select, or zero for no limit
+func (m *SettingsManager) GetMaxBulkSelect() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.UI.MaxBulkSelect < 0 {
+		return 0
+	}
+	if m.settings.UI.MaxBulkSelect == 0 {
+		return 100 // Default 100 files
+	}
+	return m.settings.UI.MaxBulkSelect
+}
+
+// IsWordWrapEnabled returns whether the user prompt wraps at the chat panel width
+func (m *SettingsManager) IsWordWrapEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.WordWrap
+}
+
+// IsNormalizeEncodingEnabled returns whether files that aren't UTF-8 are transcoded in generated prompts
+func (m *SettingsManager) IsNormalizeEncodingEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.NormalizeEncoding
+}
+
+// GetPruneWorkspacesDays returns how many days a workspace is remembered after it was last opened, or zero to keep them all
+func (m *SettingsManager) GetPruneWorkspacesDays() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.PruneWorkspacesOlderThan < 0 {
+		return 0
+	}
+	if m.settings.PruneWorkspacesOlderThan == 0 {
+		return DefaultPruneWorkspacesDays
+	}
+	return m.settings.PruneWorkspacesOlderThan
+}
+
+// GetMaxRecentWorkspaces returns how many workspaces are remembered, or zero to keep them all
+func (m *SettingsManager) GetMaxRecentWorkspaces() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.settings.Storage.MaxRecentWorkspaces < 0 {
+		return 0
+	}
+	if m.settings.Storage.MaxRecentWorkspaces == 0 {
+		return DefaultMaxRecentWorkspaces
+	}
+	return m.settings.Storage.MaxRecentWorkspaces
+}
+
+// GetLayoutMode returns the panel arrangement to start with (thread-safe)
+func (m *SettingsManager) GetLayoutMode() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.LayoutMode
+}
+
+// GetLayoutSettings returns the panel split ratios (thread-safe)
+func (m *SettingsManager) GetLayoutSettings() LayoutSettings {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.Layout
+}
+
+// GetThemeSettings returns the configured interface colors (thread-safe)
+func (m *SettingsManager) GetThemeSettings() ThemeSettings {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.Theme
+}
+
+// IsPreviewEnabled returns whether the file preview is shown below the file tree
+func (m *SettingsManager) IsPreviewEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.ShowPreview
+}
+
+// GetPreviewLines returns the number of lines shown in the file preview (thread-safe)
+func (m *SettingsManager) GetPreviewLines() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.PreviewLines
+}
+
+// GetClipboardBackend returns the name of the clipboard backend to copy with (thread-safe)
+func (m *SettingsManager) GetClipboardBackend() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.ClipboardBackend
+}
+
+// IsFollowSymlinksEnabled returns whether symlinked directories are expanded in the file tree
+func (m *SettingsManager) IsFollowSymlinksEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.FollowSymlinks
+}
+
+// IsBinaryFilesAllowed returns whether binary files can be selected
+func (m *SettingsManager) IsBinaryFilesAllowed() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.AllowBinaryFiles
+}
+
+// IsGitStatusEnabled returns whether files in the tree are annotated with their git status
+func (m *SettingsManager) IsGitStatusEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.ShowGitStatus
+}
+
+// IsStripCommentsEnabled returns whether comments are removed from source files in generated prompts
+func (m *SettingsManager) IsStripCommentsEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.UI.StripComments
+}
+
+// GetStripCommentLanguages returns a copy of the languages to strip comments from; empty means all
+func (m *SettingsManager) GetStripCommentLanguages() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]string{}, m.settings.UI.StripCommentLanguages...)
+}
+
+// GetWebhookSettings returns a copy of the webhook delivery settings (thread-safe)
+func (m *SettingsManager) GetWebhookSettings() WebhookSettings {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	webhook := m.settings.Webhook
+	webhook.Headers = make(map[string]string, len(m.settings.Webhook.Headers))
+	for name, value := range m.settings.Webhook.Headers {
+		webhook.Headers[name] = value
+	}
+	return webhook
+}
+
 // Debug settings accessors
 
 // IsDebugEnabled returns whether debug mode should be enabled on startup
@@ -349,6 +1005,13 @@ func (m *SettingsManager) IsDebugFileLoggingEnabled() bool {
 	return m.settings.Debug.FileLogging
 }
 
+// IsInvariantCheckEnabled returns whether the app state is checked after every update in debug mode
+func (m *SettingsManager) IsInvariantCheckEnabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settings.Debug.InvariantCheckEnabled
+}
+
 // GetDebugLogFile returns the log file path for debug messages
 func (m *SettingsManager) GetDebugLogFile() string {
 	m.mutex.RLock()
@@ -381,6 +1044,9 @@ func (m *SettingsManager) StartWatching() error {
 	if m.watcher != nil {
 		return fmt.Errorf("already watching config file")
 	}
+	if m.configPath == "" {
+		return errNoSettingsFile
+	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -399,6 +1065,14 @@ func (m *SettingsManager) StartWatching() error {
 		return fmt.Errorf("failed to watch config directory: %w", err)
 	}
 
+	// Also watch the workspace for project-local overrides
+	if m.localPath != "" {
+		if err := watcher.Add(filepath.Dir(m.localPath)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch workspace directory: %w", err)
+		}
+	}
+
 	m.watcher = watcher
 
 	// Start watching in a goroutine
@@ -430,8 +1104,8 @@ func (m *SettingsManager) watchLoop() {
 				return
 			}
 
-			// Only care about writes to our config file
-			if event.Name == m.configPath && (event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create) {
+			// Only care about writes to our config files
+			if (event.Name == m.configPath || (m.localPath != "" && event.Name == m.localPath)) && (event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create) {
 				if err := m.reloadAndNotify(); err != nil {
 					// In a real app, you might want to log this error
 					// For now, we'll silently continue
@@ -480,14 +1154,15 @@ func (m *SettingsManager) hasBindingsChanged(old, new *KeyBindings) bool {
 	}
 
 	// Check global bindings
-	if old.EscapeToNormal != new.EscapeToNormal {
+	if old.EscapeToNormal != new.EscapeToNormal || old.QuickOpen != new.QuickOpen || old.History != new.History || old.Export != new.Export || old.ExportManifest != new.ExportManifest || old.GlobSelect != new.GlobSelect || old.ModifiedSince != new.ModifiedSince || old.Presets != new.Presets || old.SavePreset != new.SavePreset || old.WorkspaceList != new.WorkspaceList || old.Webhook != new.Webhook || old.Help != new.Help || old.LayoutToggle != new.LayoutToggle || old.GitignoreCheck != new.GitignoreCheck || old.Settings != new.Settings {
 		return true
 	}
 
 	// Check menu mode bindings
 	if old.MenuMode.Activation != new.MenuMode.Activation ||
 		old.MenuMode.Exit != new.MenuMode.Exit ||
-		old.MenuMode.PersonaMenu != new.MenuMode.PersonaMenu {
+		old.MenuMode.PersonaMenu != new.MenuMode.PersonaMenu ||
+		old.MenuMode.FormatToggle != new.MenuMode.FormatToggle {
 		return true
 	}
 
@@ -502,7 +1177,26 @@ func (m *SettingsManager) hasBindingsChanged(old, new *KeyBindings) bool {
 
 // hasUIChanged checks if any UI settings have changed
 func (m *SettingsManager) hasUIChanged(old, new *UserUISettings) bool {
-	return old.NotificationTTL != new.NotificationTTL
+	return old.NotificationTTL != new.NotificationTTL ||
+		old.TokenWarningThreshold != new.TokenWarningThreshold ||
+		old.ShowGitStatus != new.ShowGitStatus ||
+		old.FollowSymlinks != new.FollowSymlinks ||
+		old.AllowBinaryFiles != new.AllowBinaryFiles ||
+		old.MaxFileSizeKB != new.MaxFileSizeKB ||
+		old.LayoutMode != new.LayoutMode ||
+		old.ShowPreview != new.ShowPreview ||
+		old.PreviewLines != new.PreviewLines ||
+		old.ClipboardBackend != new.ClipboardBackend ||
+		old.StripComments != new.StripComments ||
+		!slices.Equal(old.StripCommentLanguages, new.StripCommentLanguages) ||
+		!slices.Equal(old.OverviewFiles, new.OverviewFiles) ||
+		old.IncludeAllOverviewFiles != new.IncludeAllOverviewFiles ||
+		old.PromptCharLimit != new.PromptCharLimit ||
+		old.WordWrap != new.WordWrap ||
+		old.MaxBulkSelect != new.MaxBulkSelect ||
+		old.NormalizeEncoding != new.NormalizeEncoding ||
+		old.Layout != new.Layout ||
+		old.Theme != new.Theme
 }
 
 // hasDebugChanged checks if any debug settings have changed
@@ -510,18 +1204,36 @@ func (m *SettingsManager) hasDebugChanged(old, new *DebugSettings) bool {
 	return old.Enabled != new.Enabled ||
 		old.ToggleKey != new.ToggleKey ||
 		old.FileLogging != new.FileLogging ||
-		old.LogFile != new.LogFile
+		old.LogFile != new.LogFile ||
+		old.InvariantCheckEnabled != new.InvariantCheckEnabled
 }
 
 // getDefaultSettings returns the default settings
 func getDefaultSettings() *UserSettings {
 	return &UserSettings{
+		PruneWorkspacesOlderThan: DefaultPruneWorkspacesDays,
+		Storage:                  StorageSettings{MaxRecentWorkspaces: DefaultMaxRecentWorkspaces},
 		Bindings: KeyBindings{
 			EscapeToNormal: "esc",
+			QuickOpen:      "ctrl+p",
+			History:        "ctrl+h",
+			Export:         "ctrl+e",
+			ExportManifest: "alt+e",
+			GlobSelect:     "ctrl+g",
+			ModifiedSince:  "alt+t",
+			Presets:        "ctrl+t",
+			SavePreset:     "alt+p",
+			WorkspaceList:  "ctrl+w",
+			Webhook:        "alt+y",
+			Help:           "?",
+			LayoutToggle:   "ctrl+l",
+			GitignoreCheck: "alt+i",
+			Settings:       "alt+,",
 			MenuMode: ModeBindings{
-				Activation:  "alt+m",
-				Exit:        "esc",
-				PersonaMenu: "p",
+				Activation:   "alt+m",
+				Exit:         "esc",
+				PersonaMenu:  "p",
+				FormatToggle: "f",
 			},
 			NormalMode: ModeBindings{
 				Tab:      "tab",
@@ -532,13 +1244,35 @@ func getDefaultSettings() *UserSettings {
 			PersonaMenu:    "",
 		},
 		UI: UserUISettings{
-			NotificationTTL: 3, // Default 3 seconds
+			NotificationTTL:       3,      // Default 3 seconds
+			TokenWarningThreshold: 100000, // Default 100k tokens
+			MaxFileSizeKB:         512,    // Default 512 KB
+			LayoutMode:            "default",
+			PreviewLines:          50,
+			ClipboardBackend:      "auto",
+			PromptCharLimit:       10000,
+			WordWrap:              true,
+			MaxBulkSelect:         100,
+			NormalizeEncoding:     true,
+			OverviewFiles:         []string{"CLAUDE.md", "GEMINI.md", "README.md", "AGENTS.md"},
+			Layout: LayoutSettings{
+				TopHeightRatio: 0.66,
+				LeftPanelRatio: 0.5,
+			},
+			Theme: ThemeSettings{
+				Preset: "default",
+			},
+		},
+		Webhook: WebhookSettings{
+			Method:         "POST",
+			TimeoutSeconds: 10,
 		},
 		Debug: DebugSettings{
-			Enabled:     false,            // Debug disabled by default
-			ToggleKey:   "f11",            // F11 to toggle
-			FileLogging: true,             // Enable file logging when debug is on
-			LogFile:     "logs/error.log", // Default log file path
+			Enabled:               false,            // Debug disabled by default
+			ToggleKey:             "f11",            // F11 to toggle
+			FileLogging:           true,             // Enable file logging when debug is on
+			LogFile:               "logs/error.log", // Default log file path
+			InvariantCheckEnabled: true,             // Check state invariants when debug is on
 		},
 	}
 }
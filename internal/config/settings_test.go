@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -304,3 +306,582 @@ persona_menu = "q"`
 		t.Errorf("Expected callback settings to have menu_activation 'b', got: %v", callbackSettings)
 	}
 }
+
+func TestSettingsManager_TokenWarningThreshold(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "coding_prompts.toml")
+
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if got := manager.GetTokenWarningThreshold(); got != 100000 {
+		t.Errorf("Expected default token_warning_threshold to be 100000, got: %d", got)
+	}
+
+	err := os.WriteFile(configPath, []byte("[ui]\ntoken_warning_threshold = 8000"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	if got := manager.GetTokenWarningThreshold(); got != 8000 {
+		t.Errorf("Expected token_warning_threshold to be 8000, got: %d", got)
+	}
+}
+
+func TestSettingsManager_ProjectLocalOverride(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "coding_prompts.toml")
+	workspaceDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+		t.Fatalf("Failed to create workspace directory: %v", err)
+	}
+
+	globalTOML := `[bindings]
+quick_open = "ctrl+o"
+
+[bindings.menu_mode]
+activation = "alt+m"
+
+[ui]
+notification_ttl = 7`
+	if err := os.WriteFile(configPath, []byte(globalTOML), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	localTOML := `[bindings.menu_mode]
+activation = "f12"`
+	if err := os.WriteFile(filepath.Join(workspaceDir, ProjectSettingsFile), []byte(localTOML), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	manager := &SettingsManager{
+		configPath: configPath,
+		localPath:  filepath.Join(workspaceDir, ProjectSettingsFile),
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading merged settings, got: %v", err)
+	}
+
+	if got := manager.GetMenuModeActivation(); got != "f12" {
+		t.Errorf("Expected project-local menu_mode.activation 'f12', got: %q", got)
+	}
+	// Keys not set locally keep their global values
+	if got := manager.GetQuickOpenKey(); got != "ctrl+o" {
+		t.Errorf("Expected global quick_open 'ctrl+o', got: %q", got)
+	}
+	if got := manager.GetNotificationTTL(); got != 7 {
+		t.Errorf("Expected global notification_ttl 7, got: %d", got)
+	}
+}
+
+func TestSettingsManager_ProjectLocalWithoutGlobal(t *testing.T) {
+	tempDir := t.TempDir()
+	localPath := filepath.Join(tempDir, ProjectSettingsFile)
+	if err := os.WriteFile(localPath, []byte("[bindings.menu_mode]\nactivation = \"f12\""), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	manager := &SettingsManager{
+		configPath: filepath.Join(tempDir, "missing", "coding_prompts.toml"),
+		localPath:  localPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading project-local settings, got: %v", err)
+	}
+
+	if got := manager.GetMenuModeActivation(); got != "f12" {
+		t.Errorf("Expected project-local menu_mode.activation 'f12', got: %q", got)
+	}
+	if got := manager.GetQuickOpenKey(); got != "ctrl+p" {
+		t.Errorf("Expected default quick_open 'ctrl+p', got: %q", got)
+	}
+}
+
+func TestSettingsManager_MissingKeysKeepDefaults(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "coding_prompts.toml")
+	if err := os.WriteFile(configPath, []byte("[bindings]\nquick_open = \"ctrl+p\""), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	manager := &SettingsManager{configPath: configPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading settings, got: %v", err)
+	}
+
+	// Settings that default to true stay on when the file doesn't mention them
+	if !manager.IsNormalizeEncodingEnabled() {
+		t.Error("Expected normalize_encoding to default to true")
+	}
+	if !manager.IsWordWrapEnabled() {
+		t.Error("Expected word_wrap to default to true")
+	}
+	if !manager.IsInvariantCheckEnabled() {
+		t.Error("Expected invariant_check_enabled to default to true")
+	}
+}
+
+func TestSettingsManager_ProjectLocalTurnsOffGlobal(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "coding_prompts.toml")
+	localPath := filepath.Join(tempDir, ProjectSettingsFile)
+	globalTOML := "[ui]\nword_wrap = true\nshow_git_status = true\n\n[debug]\ninvariant_check_enabled = true"
+	if err := os.WriteFile(configPath, []byte(globalTOML), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+	localTOML := "[ui]\nword_wrap = false\n\n[debug]\ninvariant_check_enabled = false"
+	if err := os.WriteFile(localPath, []byte(localTOML), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	manager := &SettingsManager{configPath: configPath, localPath: localPath}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading merged settings, got: %v", err)
+	}
+	if manager.IsWordWrapEnabled() || manager.IsInvariantCheckEnabled() {
+		t.Error("Expected the project file to turn off settings the global file turns on")
+	}
+	if !manager.IsGitStatusEnabled() {
+		t.Error("Expected settings the project file doesn't mention to keep their global values")
+	}
+}
+
+func TestSettingsManager_LayoutSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "coding_prompts.toml")
+
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	layout := manager.GetLayoutSettings()
+	if layout.TopHeightRatio != 0.66 || layout.LeftPanelRatio != 0.5 {
+		t.Errorf("Expected default layout 0.66/0.5, got: %+v", layout)
+	}
+	if manager.GetLayoutMode() != "default" || manager.GetLayoutToggleKey() != "ctrl+l" {
+		t.Errorf("Expected the default layout mode toggled with ctrl+l, got %q toggled with %q", manager.GetLayoutMode(), manager.GetLayoutToggleKey())
+	}
+
+	// A partial section keeps the default for the missing ratio
+	if err := os.WriteFile(configPath, []byte("[ui.layout]\nleft_panel_ratio = 0.4"), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	layout = manager.GetLayoutSettings()
+	if layout.TopHeightRatio != 0.66 || layout.LeftPanelRatio != 0.4 {
+		t.Errorf("Expected layout 0.66/0.4, got: %+v", layout)
+	}
+}
+
+func TestSettingsManager_Validate_LayoutRatios(t *testing.T) {
+	tests := []struct {
+		name   string
+		toml   string
+		errMsg string
+	}{
+		{"top ratio too large", "[ui.layout]\ntop_height_ratio = 0.95", "ui.layout.top_height_ratio must be between 0.1 and 0.9"},
+		{"left ratio too small", "[ui.layout]\nleft_panel_ratio = 0.05", "ui.layout.left_panel_ratio must be between 0.1 and 0.9"},
+		{"negative ratio", "[ui.layout]\nleft_panel_ratio = -0.5", "ui.layout.left_panel_ratio must be between 0.1 and 0.9"},
+		{"unknown layout mode", "[ui]\nlayout_mode = \"grid\"", "ui.layout_mode must be default, vertical or compact"},
+		{"unknown clipboard backend", "[ui]\nclipboard_backend = \"clippy\"", "ui.clipboard_backend must be auto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+			if err := os.WriteFile(configPath, []byte(tt.toml), 0644); err != nil {
+				t.Fatalf("Failed to write test config file: %v", err)
+			}
+
+			manager := &SettingsManager{
+				configPath: configPath,
+			}
+			err := manager.load()
+			if err == nil {
+				t.Fatal("Expected validation error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestSettingsManager_WebhookSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	content := `[webhook]
+url = "http://localhost:8080/prompts"
+
+[webhook.headers]
+Authorization = "Bearer token"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading settings, got: %v", err)
+	}
+
+	webhook := manager.GetWebhookSettings()
+	if webhook.URL != "http://localhost:8080/prompts" || webhook.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Expected configured url and headers, got: %+v", webhook)
+	}
+	if webhook.Method != "POST" || webhook.TimeoutSeconds != 10 {
+		t.Errorf("Expected default method POST and timeout 10, got: %+v", webhook)
+	}
+	if manager.GetWebhookKey() != "alt+y" {
+		t.Errorf("Expected default webhook key alt+y, got: %s", manager.GetWebhookKey())
+	}
+
+	// The returned headers are a copy
+	webhook.Headers["Authorization"] = "changed"
+	if manager.GetWebhookSettings().Headers["Authorization"] != "Bearer token" {
+		t.Error("Expected GetWebhookSettings to return a copy of the headers")
+	}
+
+	if err := os.WriteFile(configPath, []byte("[webhook]\nmethod = \"PUT\""), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err == nil || !strings.Contains(err.Error(), "webhook.method must be GET or POST") {
+		t.Errorf("Expected webhook method validation error, got: %v", err)
+	}
+}
+
+func TestSettingsManager_MaxFileSize(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if got := manager.GetMaxFileSizeBytes(); got != 512*1024 {
+		t.Errorf("Expected default limit of 512 KB, got: %d bytes", got)
+	}
+
+	for content, want := range map[string]int64{
+		"[ui]\nmax_file_size_kb = 64": 64 * 1024,
+		"[ui]\nmax_file_size_kb = -1": 0,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Expected no error reloading settings, got: %v", err)
+		}
+		if got := manager.GetMaxFileSizeBytes(); got != want {
+			t.Errorf("Expected %d bytes for %q, got: %d", want, content, got)
+		}
+	}
+}
+
+func TestSettingsManager_PruneWorkspacesDays(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if got := manager.GetPruneWorkspacesDays(); got != DefaultPruneWorkspacesDays {
+		t.Errorf("Expected default of %d days, got: %d", DefaultPruneWorkspacesDays, got)
+	}
+
+	for content, want := range map[string]int{
+		"prune_workspaces_older_than_days = 90": 90,
+		"prune_workspaces_older_than_days = -1": 0,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Expected no error reloading settings, got: %v", err)
+		}
+		if got := manager.GetPruneWorkspacesDays(); got != want {
+			t.Errorf("Expected %d days for %q, got: %d", want, content, got)
+		}
+	}
+}
+
+func TestSettingsManager_MaxRecentWorkspaces(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if got := manager.GetMaxRecentWorkspaces(); got != DefaultMaxRecentWorkspaces {
+		t.Errorf("Expected default of %d workspaces, got: %d", DefaultMaxRecentWorkspaces, got)
+	}
+
+	for content, want := range map[string]int{
+		"[storage]\nmax_recent_workspaces = 50": 50,
+		"[storage]\nmax_recent_workspaces = -1": 0,
+		"[ui]\nword_wrap = true":                DefaultMaxRecentWorkspaces,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Expected no error reloading settings, got: %v", err)
+		}
+		if got := manager.GetMaxRecentWorkspaces(); got != want {
+			t.Errorf("Expected %d workspaces for %q, got: %d", want, content, got)
+		}
+	}
+}
+
+func TestSettingsManager_StripComments(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if manager.IsStripCommentsEnabled() || len(manager.GetStripCommentLanguages()) != 0 {
+		t.Error("Expected comments to be kept by default")
+	}
+
+	content := "[ui]\nstrip_comments = true\nstrip_comment_languages = [\"go\", \"shell\"]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	if !manager.IsStripCommentsEnabled() {
+		t.Error("Expected comment stripping to be enabled")
+	}
+	if langs := manager.GetStripCommentLanguages(); len(langs) != 2 || langs[0] != "go" || langs[1] != "shell" {
+		t.Errorf("Expected go and shell, got: %v", langs)
+	}
+
+	content = "[ui]\nstrip_comment_languages = [\"rust\"]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err == nil {
+		t.Error("Expected an error for an unsupported language")
+	}
+}
+
+func TestSettingsManager_PromptInput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if !manager.IsNormalizeEncodingEnabled() {
+		t.Error("Expected encoding normalization to be on by default")
+	}
+	if manager.GetPromptCharLimit() != 10000 || !manager.IsWordWrapEnabled() {
+		t.Errorf("Expected a 10000 character limit and word wrap by default, got %d and %v",
+			manager.GetPromptCharLimit(), manager.IsWordWrapEnabled())
+	}
+
+	for content, expected := range map[string]int{
+		"[ui]\nprompt_char_limit = 2000\n": 2000,
+		"[ui]\nprompt_char_limit = -1\n":   0,
+		"[ui]\nword_wrap = false\n":        10000,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Expected no error reloading settings, got: %v", err)
+		}
+		if limit := manager.GetPromptCharLimit(); limit != expected {
+			t.Errorf("%q: expected a limit of %d, got %d", content, expected, limit)
+		}
+		if wrap := !strings.Contains(content, "word_wrap = false"); manager.IsWordWrapEnabled() != wrap {
+			t.Errorf("%q: expected word wrap %v, got %v", content, wrap, manager.IsWordWrapEnabled())
+		}
+	}
+}
+
+func TestSettingsManager_MaxBulkSelect(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if limit := manager.GetMaxBulkSelect(); limit != 100 {
+		t.Errorf("Expected a bulk selection limit of 100 by default, got %d", limit)
+	}
+
+	for content, expected := range map[string]int{
+		"[ui]\nmax_bulk_select = 500\n": 500,
+		"[ui]\nmax_bulk_select = -1\n":  0,
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Expected no error reloading settings, got: %v", err)
+		}
+		if limit := manager.GetMaxBulkSelect(); limit != expected {
+			t.Errorf("%q: expected a limit of %d, got %d", content, expected, limit)
+		}
+	}
+}
+
+func TestSettingsManager_Theme(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	if theme := manager.GetThemeSettings(); theme != (ThemeSettings{Preset: "default"}) {
+		t.Errorf("Expected the default preset without overrides, got %+v", theme)
+	}
+
+	content := "[ui.theme]\npreset = \"high-contrast\"\nfocus_border = \"#ff0000\"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	if theme := manager.GetThemeSettings(); theme.Preset != "high-contrast" || theme.FocusBorder != "#ff0000" {
+		t.Errorf("Expected the configured theme, got %+v", theme)
+	}
+
+	if err := os.WriteFile(configPath, []byte("[ui.theme]\npreset = \"neon\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err == nil {
+		t.Error("Expected an error for an unknown theme preset")
+	}
+}
+
+func TestSettingsManager_OverviewFiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected no error loading default settings, got: %v", err)
+	}
+	expected := []string{"CLAUDE.md", "GEMINI.md", "README.md", "AGENTS.md"}
+	if files := manager.GetOverviewFiles(); !slices.Equal(files, expected) {
+		t.Errorf("Expected default overview files %v, got %v", expected, files)
+	}
+	if manager.IsIncludeAllOverviewFilesEnabled() {
+		t.Error("Expected only the first overview file to be included by default")
+	}
+
+	content := "[ui]\noverview_files = [\"AGENTS.md\", \"docs/OVERVIEW.md\"]\ninclude_all_overview_files = true\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Expected no error reloading settings, got: %v", err)
+	}
+	expected = []string{"AGENTS.md", "docs/OVERVIEW.md"}
+	if files := manager.GetOverviewFiles(); !slices.Equal(files, expected) {
+		t.Errorf("Expected overview files %v, got %v", expected, files)
+	}
+	if !manager.IsIncludeAllOverviewFilesEnabled() {
+		t.Error("Expected every overview file to be included when enabled")
+	}
+
+	for _, content := range []string{
+		"[ui]\noverview_files = [\"\"]\n",
+		"[ui]\noverview_files = [\"/etc/passwd\"]\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test config file: %v", err)
+		}
+		if err := manager.Reload(); err == nil {
+			t.Errorf("%q: expected a validation error", content)
+		}
+	}
+}
+
+func TestSettingsManager_BindingConflicts(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "coding_prompts.toml")
+	manager := &SettingsManager{
+		configPath: configPath,
+	}
+
+	// The example settings file must load as shipped
+	example, err := os.ReadFile(filepath.Join("..", "..", "configs", "coding_prompts.toml"))
+	if err != nil {
+		t.Fatalf("Failed to read the example settings: %v", err)
+	}
+	if err := os.WriteFile(configPath, example, 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	if err := manager.load(); err != nil {
+		t.Fatalf("Expected the example settings to load, got: %v", err)
+	}
+
+	conflicting := `[bindings]
+quick_open = "ctrl+h"
+help = "ctrl+r"
+layout_toggle = "ctrl+r"
+
+[bindings.menu_mode]
+persona_menu = "ctrl+h"
+`
+	if err := os.WriteFile(configPath, []byte(conflicting), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	err = manager.load()
+	if err == nil {
+		t.Fatal("Expected an error for conflicting bindings")
+	}
+	for _, expected := range []string{
+		"bindings.history and bindings.quick_open both use ctrl+h",
+		"bindings.help and bindings.layout_toggle both use ctrl+r",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to mention %q, got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "persona_menu") {
+		t.Errorf("Expected a menu mode binding not to conflict with normal mode ones, got: %v", err)
+	}
+}
+
+func TestNewDefaultSettingsManager(t *testing.T) {
+	manager := NewDefaultSettingsManager()
+	if manager.GetNotificationTTL() != 3 {
+		t.Errorf("Expected the default notification TTL, got %d", manager.GetNotificationTTL())
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if manager.GetSettings().Bindings.MenuMode.Activation != "alt+m" {
+		t.Error("Expected the default settings after reloading")
+	}
+	if err := manager.SetBinding("quick_open", "f2"); !errors.Is(err, errNoSettingsFile) {
+		t.Errorf("Expected an error writing a binding without a settings file, got %v", err)
+	}
+	if err := manager.StartWatching(); err == nil {
+		manager.StopWatching()
+		t.Error("Expected an error watching without a settings file")
+	}
+}
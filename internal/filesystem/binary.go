@@ -0,0 +1,27 @@
+package filesystem
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// binarySniffLen is how much of a file is inspected to decide whether it is binary
+const binarySniffLen = 512
+
+// IsBinaryFile reports whether the file at path looks binary. Like git, it
+// treats a file as binary if its first 512 bytes contain a null byte.
+func IsBinaryFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0, nil
+}
@@ -0,0 +1,43 @@
+package filesystem
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsBinaryFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{"text", []byte("package main\n\nfunc main() {}\n"), false},
+		{"empty", nil, false},
+		{"utf8", []byte("héllo wörld ✓\n"), false},
+		{"null byte", []byte("GIF89a\x00\x01\x02"), true},
+		{"null byte past sniff length", append(bytes.Repeat([]byte("a"), 600), 0), false},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			got, err := IsBinaryFile(path)
+			if err != nil {
+				t.Fatalf("IsBinaryFile failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected IsBinaryFile to be %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := IsBinaryFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
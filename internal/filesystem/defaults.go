@@ -0,0 +1,50 @@
+package filesystem
+
+import "strings"
+
+// defaultIgnoredDirs are directory names that never belong in a prompt:
+// version control data, dependencies and build output
+var defaultIgnoredDirs = []string{
+	".git",
+	".svn",
+	".hg",
+	"node_modules",
+	"vendor",
+	"target",
+	"build",
+	"dist",
+	"__pycache__",
+}
+
+// defaultIgnoredFiles are file names created by operating systems
+var defaultIgnoredFiles = []string{
+	".DS_Store",
+	"Thumbs.db",
+}
+
+// defaultIgnoredGlobs are only applied by GitignoreMatcher, which can match
+// patterns rather than whole names
+var defaultIgnoredGlobs = []string{
+	"*.tmp",
+	"*.log",
+}
+
+// ShouldIgnore reports whether a file or directory name is hidden (starts
+// with a dot) or one of the default ignored names. It doesn't look at
+// .gitignore or .promptignore rules; use NewProjectMatcher for those.
+func ShouldIgnore(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, ignored := range defaultIgnoredDirs {
+		if name == ignored {
+			return true
+		}
+	}
+	for _, ignored := range defaultIgnoredFiles {
+		if name == ignored {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestShouldIgnore(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		// Default ignored names
+		{".git", true},
+		{".svn", true},
+		{".hg", true},
+		{"node_modules", true},
+		{"vendor", true},
+		{"target", true},
+		{"build", true},
+		{"dist", true},
+		{"__pycache__", true},
+		{".DS_Store", true},
+		{"Thumbs.db", true},
+		// Hidden files and directories
+		{".env", true},
+		{".github", true},
+		// Normal names, including ones resembling the defaults
+		{"main.go", false},
+		{"README.md", false},
+		{"src", false},
+		{"builder.go", false},
+		{"my_node_modules", false},
+		{"debug.log", false}, // Globs are only applied by GitignoreMatcher
+	}
+
+	for _, tt := range tests {
+		if got := ShouldIgnore(tt.name); got != tt.expected {
+			t.Errorf("ShouldIgnore(%q) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestShouldIgnoreCoversDefaultPatterns(t *testing.T) {
+	// Every default name ShouldIgnore checks is also a default of GitignoreMatcher
+	root := t.TempDir()
+	matcher, err := NewGitignoreMatcher(root)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	for _, dir := range defaultIgnoredDirs {
+		if !ShouldIgnore(dir) || !matcher.ShouldIgnore(filepath.Join(root, dir), true) {
+			t.Errorf("Expected directory %s to be ignored by both", dir)
+		}
+	}
+	for _, file := range defaultIgnoredFiles {
+		if !ShouldIgnore(file) || !matcher.ShouldIgnore(filepath.Join(root, file), false) {
+			t.Errorf("Expected file %s to be ignored by both", file)
+		}
+	}
+}
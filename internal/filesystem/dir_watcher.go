@@ -0,0 +1,176 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DirChangedMsg reports that files or directories were created, removed or
+// renamed somewhere under a watched directory tree
+type DirChangedMsg struct {
+	Root  string
+	Paths []string // Entries that changed, sorted
+}
+
+// DirWatcher watches a directory tree for added and removed entries and
+// reports debounced changes to it
+type DirWatcher struct {
+	watcher  *fsnotify.Watcher
+	root     string
+	matcher  Matcher // nil falls back to ShouldIgnore
+	debounce time.Duration
+	events   chan DirChangedMsg
+	done     chan struct{}
+
+	mutex   sync.Mutex
+	timer   *time.Timer
+	changed map[string]bool // Paths changed since the last reported change
+}
+
+// NewDirWatcher starts watching root and every directory below it that isn't
+// ignored. A change is reported once the tree has been quiet for the debounce
+// duration.
+func NewDirWatcher(root string, debounce time.Duration) (*DirWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory watcher: %w", err)
+	}
+
+	w := &DirWatcher{
+		watcher:  watcher,
+		root:     filepath.Clean(root),
+		debounce: debounce,
+		events:   make(chan DirChangedMsg, 1),
+		done:     make(chan struct{}),
+		changed:  make(map[string]bool),
+	}
+	if matcher, err := NewProjectMatcher(root); err == nil {
+		w.matcher = matcher
+	}
+
+	if err := watcher.Add(w.root); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch directory %s: %w", w.root, err)
+	}
+	w.addTree(w.root)
+
+	go w.watchLoop()
+
+	return w, nil
+}
+
+// addTree watches dir and the directories below it, except the root, which is
+// watched by NewDirWatcher. Directories that can't be watched are skipped
+// rather than failing the whole tree.
+func (w *DirWatcher) addTree(dir string) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == w.root {
+			return nil
+		}
+		if w.ignored(path, true) {
+			return filepath.SkipDir
+		}
+		w.watcher.Add(path)
+		return nil
+	})
+}
+
+// ignored reports whether the entry at path is left out of the tree
+func (w *DirWatcher) ignored(path string, isDir bool) bool {
+	if w.matcher != nil {
+		return w.matcher.ShouldIgnore(path, isDir)
+	}
+	return ShouldIgnore(filepath.Base(path))
+}
+
+// Events returns the channel debounced changes are delivered on
+func (w *DirWatcher) Events() <-chan DirChangedMsg {
+	return w.events
+}
+
+// Close stops watching. No further events are delivered after Close returns.
+func (w *DirWatcher) Close() error {
+	w.mutex.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mutex.Unlock()
+
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// watchLoop runs the directory watcher loop
+func (w *DirWatcher) watchLoop() {
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Writes don't change the tree; only entries coming and going do
+			if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			path := filepath.Clean(event.Name)
+			info, err := os.Lstat(path)
+			isDir := err == nil && info.IsDir()
+			if w.ignored(path, isDir) {
+				continue
+			}
+			// New directories are watched too, along with anything created in them already
+			if event.Op&fsnotify.Create != 0 && isDir {
+				w.addTree(path)
+			}
+			w.schedule(path)
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// schedule reports a change to path once the tree has been quiet for the
+// debounce duration, along with any other paths changed in the meantime
+func (w *DirWatcher) schedule(path string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.changed[path] = true
+
+	if w.timer != nil {
+		w.timer.Reset(w.debounce)
+		return
+	}
+
+	w.timer = time.AfterFunc(w.debounce, func() {
+		w.mutex.Lock()
+		w.timer = nil
+		paths := make([]string, 0, len(w.changed))
+		for changed := range w.changed {
+			paths = append(paths, changed)
+		}
+		clear(w.changed)
+		w.mutex.Unlock()
+		sort.Strings(paths)
+
+		// Wait for the previous change to be taken, so no changed path is lost
+		select {
+		case w.events <- DirChangedMsg{Root: w.root, Paths: paths}:
+		case <-w.done:
+		}
+	})
+}
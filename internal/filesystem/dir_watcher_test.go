@@ -0,0 +1,104 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// expectDirChange waits for a DirChangedMsg from watcher
+func expectDirChange(t *testing.T, watcher *DirWatcher, what string) DirChangedMsg {
+	t.Helper()
+	select {
+	case msg := <-watcher.Events():
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Timed out waiting for DirChangedMsg after %s", what)
+	}
+	return DirChangedMsg{}
+}
+
+func TestDirWatcher_ReportsAddedAndRemovedEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	existing := filepath.Join(tempDir, "pkg")
+	if err := os.Mkdir(existing, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	watcher, err := NewDirWatcher(tempDir, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	// Several new files are debounced into a single change
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(existing, name), []byte("package pkg"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	msg := expectDirChange(t, watcher, "creating files in a subdirectory")
+	expected := []string{filepath.Join(existing, "a.go"), filepath.Join(existing, "b.go"), filepath.Join(existing, "c.go")}
+	if !reflect.DeepEqual(msg.Paths, expected) {
+		t.Errorf("Expected changed paths %v, got %v", expected, msg.Paths)
+	}
+	select {
+	case <-watcher.Events():
+		t.Error("Expected a single debounced change")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Directories created later are watched as well
+	added := filepath.Join(tempDir, "added")
+	if err := os.Mkdir(added, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	expectDirChange(t, watcher, "creating a directory")
+	if err := os.WriteFile(filepath.Join(added, "new.go"), []byte("package added"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	expectDirChange(t, watcher, "creating a file in a new directory")
+
+	if err := os.Remove(filepath.Join(existing, "a.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	expectDirChange(t, watcher, "removing a file")
+
+	// Writing to an existing file doesn't change the tree
+	if err := os.WriteFile(filepath.Join(existing, "b.go"), []byte("package pkg // edit"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	select {
+	case <-watcher.Events():
+		t.Error("Expected no change for a write to an existing file")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestDirWatcher_SkipsIgnoredDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("build/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .gitignore: %v", err)
+	}
+	build := filepath.Join(tempDir, "build")
+	if err := os.Mkdir(build, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	watcher, err := NewDirWatcher(tempDir, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	if err := os.WriteFile(filepath.Join(build, "out.bin"), []byte("output"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	select {
+	case <-watcher.Events():
+		t.Error("Expected no change for files in an ignored directory")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,53 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// DetectAndNormalize reads the file at path and returns its content as UTF-8.
+// Content in another encoding is transcoded and preceded by a comment naming
+// the original encoding, e.g. <!-- original encoding: windows-1252 -->.
+func DetectAndNormalize(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	content, encoding := NormalizeEncoding(data)
+	if encoding != "" {
+		content = EncodingNote(encoding) + content
+	}
+	return content, nil
+}
+
+// NormalizeEncoding returns data as UTF-8, along with the name of the encoding
+// it was transcoded from, or "" if it already was UTF-8. Data that isn't valid
+// UTF-8 is taken to be Windows-1252 if it uses bytes 0x80-0x9F, which are
+// printable there but control codes in ISO-8859-1, and ISO-8859-1 otherwise.
+func NormalizeEncoding(data []byte) (string, string) {
+	if utf8.Valid(data) {
+		return string(data), ""
+	}
+
+	decoder, encoding := charmap.ISO8859_1.NewDecoder(), "iso-8859-1"
+	for _, b := range data {
+		if b >= 0x80 && b <= 0x9f {
+			decoder, encoding = charmap.Windows1252.NewDecoder(), "windows-1252"
+			break
+		}
+	}
+	content, err := decoder.Bytes(data)
+	if err != nil {
+		// Single byte encodings decode every byte, so this isn't expected
+		return string(data), ""
+	}
+	return string(content), encoding
+}
+
+// EncodingNote returns the comment put before content transcoded from encoding
+func EncodingNote(encoding string) string {
+	return fmt.Sprintf("<!-- original encoding: %s -->\n", encoding)
+}
@@ -0,0 +1,40 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectAndNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"utf8", []byte("café ✓\n"), "café ✓\n"},
+		{"iso-8859-1", []byte("caf\xe9 na\xefve\n"), "<!-- original encoding: iso-8859-1 -->\ncafé naïve\n"},
+		{"windows-1252", []byte("\x93quoted\x94 \x80 caf\xe9\n"), "<!-- original encoding: windows-1252 -->\n“quoted” € café\n"},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".txt")
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			got, err := DetectAndNormalize(path)
+			if err != nil {
+				t.Fatalf("DetectAndNormalize failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := DetectAndNormalize(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
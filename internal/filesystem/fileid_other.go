@@ -0,0 +1,14 @@
+//go:build !unix
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileID identifies the file at path by its path with symlinks resolved, as
+// inode numbers aren't available on this platform
+func fileID(path string, info os.FileInfo) (string, error) {
+	return filepath.EvalSymlinks(path)
+}
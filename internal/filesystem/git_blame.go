@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrNotGitRepository is returned when blaming a file outside a git repository
+var ErrNotGitRepository = errors.New("not a git repository")
+
+// BlameLine is a line of a file with the commit that last changed it
+type BlameLine struct {
+	Line    int // Line number in the file, starting at 1
+	Author  string
+	Commit  string
+	Summary string // First line of the commit message
+	Content string
+}
+
+// GetBlame runs `git blame` on filePath in repoRoot and returns who last changed
+// each line. It returns ErrNotGitRepository if repoRoot isn't in a git repository.
+func GetBlame(repoRoot, filePath string) ([]BlameLine, error) {
+	if _, err := runGit(repoRoot, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, ErrNotGitRepository
+	}
+
+	output, err := runGit(repoRoot, "blame", "--line-porcelain", "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlame(output), nil
+}
+
+// parseBlame parses `git blame --line-porcelain` output, where each line of the
+// file is preceded by a header with its commit and the commit's details
+func parseBlame(output string) []BlameLine {
+	var lines []BlameLine
+	var current BlameLine
+	inHeader := false
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The file's line ends the entry
+			current.Content = line[1:]
+			lines = append(lines, current)
+			inHeader = false
+		case !inHeader:
+			// "<commit> <original line> <final line> [<lines in group>]"
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			current = BlameLine{Commit: fields[0]}
+			current.Line, _ = strconv.Atoi(fields[2])
+			inHeader = true
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "summary "):
+			current.Summary = strings.TrimPrefix(line, "summary ")
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,99 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBlame(t *testing.T) {
+	output := "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d 1 1 2\n" +
+		"author Alice\n" +
+		"author-mail <alice@example.com>\n" +
+		"summary Add main\n" +
+		"filename main.go\n" +
+		"\tpackage main\n" +
+		"1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d 2 2\n" +
+		"author Alice\n" +
+		"summary Add main\n" +
+		"filename main.go\n" +
+		"\t\n" +
+		"0000000000000000000000000000000000000000 3 3 1\n" +
+		"author Not Committed Yet\n" +
+		"summary Version of main.go from main.go\n" +
+		"filename main.go\n" +
+		"\tfunc main() {}\n"
+
+	lines := parseBlame(output)
+
+	expected := []BlameLine{
+		{Line: 1, Author: "Alice", Commit: "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d", Summary: "Add main", Content: "package main"},
+		{Line: 2, Author: "Alice", Commit: "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d", Summary: "Add main", Content: ""},
+		{Line: 3, Author: "Not Committed Yet", Commit: "0000000000000000000000000000000000000000", Summary: "Version of main.go from main.go", Content: "func main() {}"},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(expected), len(lines), lines)
+	}
+	for i, want := range expected {
+		if lines[i] != want {
+			t.Errorf("Line %d = %+v, expected %+v", i+1, lines[i], want)
+		}
+	}
+}
+
+func TestGetBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	path := filepath.Join(repo, "main.go")
+
+	git("init", "-q")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "Add main")
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+
+	lines, err := GetBlame(repo, path)
+	if err != nil {
+		t.Fatalf("GetBlame failed: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %+v", len(lines), lines)
+	}
+	if first := lines[0]; first.Line != 1 || first.Author != "Alice" || first.Summary != "Add main" || first.Content != "package main" {
+		t.Errorf("Unexpected blame for the committed line: %+v", first)
+	}
+	if last := lines[2]; last.Line != 3 || last.Author != "Not Committed Yet" || last.Content != "func main() {}" {
+		t.Errorf("Unexpected blame for the uncommitted line: %+v", last)
+	}
+}
+
+func TestGetBlame_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if _, err := GetBlame(dir, path); !errors.Is(err, ErrNotGitRepository) {
+		t.Errorf("Expected ErrNotGitRepository, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package filesystem
+
+import "errors"
+
+// ErrNotTracked is returned when diffing a file git doesn't track
+var ErrNotTracked = errors.New("file is not tracked by git")
+
+// GetFileDiff runs `git diff HEAD` on filePath in repoRoot and returns the
+// changes to the file since the last commit, which are empty if it is
+// unchanged. It returns ErrNotGitRepository if repoRoot isn't in a git
+// repository and ErrNotTracked if the file isn't tracked.
+func GetFileDiff(repoRoot, filePath string) (string, error) {
+	if _, err := runGit(repoRoot, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return "", ErrNotGitRepository
+	}
+	if _, err := runGit(repoRoot, "ls-files", "--error-unmatch", "--", filePath); err != nil {
+		return "", ErrNotTracked
+	}
+
+	return runGit(repoRoot, "diff", "HEAD", "--", filePath)
+}
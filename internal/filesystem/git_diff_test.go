@@ -0,0 +1,74 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetFileDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	modified := filepath.Join(repo, "main.go")
+	unchanged := filepath.Join(repo, "util.go")
+	untracked := filepath.Join(repo, "new.go")
+
+	git("init", "-q")
+	for _, path := range []string{modified, unchanged} {
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "Add main")
+	if err := os.WriteFile(modified, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if err := os.WriteFile(untracked, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write new.go: %v", err)
+	}
+
+	diff, err := GetFileDiff(repo, modified)
+	if err != nil {
+		t.Fatalf("GetFileDiff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+func main() {}") || !strings.Contains(diff, "--- a/main.go") {
+		t.Errorf("Expected the change to main.go, got:\n%s", diff)
+	}
+
+	if diff, err := GetFileDiff(repo, unchanged); err != nil || diff != "" {
+		t.Errorf("Expected no changes to util.go, got %q, %v", diff, err)
+	}
+
+	if _, err := GetFileDiff(repo, untracked); !errors.Is(err, ErrNotTracked) {
+		t.Errorf("Expected ErrNotTracked, got %v", err)
+	}
+}
+
+func TestGetFileDiff_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if _, err := GetFileDiff(dir, path); !errors.Is(err, ErrNotGitRepository) {
+		t.Errorf("Expected ErrNotGitRepository, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@ package filesystem
 
 import (
 	"bufio"
+	"fmt"
+	"io/fs"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -16,42 +19,96 @@ type GitignorePattern struct {
 	Regex      *regexp.Regexp
 }
 
+// gitignoreLevel holds the patterns from a single .gitignore file together
+// with the directory it governs
+type gitignoreLevel struct {
+	baseDir  string
+	patterns []GitignorePattern
+}
+
 // GitignoreMatcher handles .gitignore pattern matching
 type GitignoreMatcher struct {
-	patterns []GitignorePattern
+	levels   []gitignoreLevel
 	rootPath string
 }
 
-// NewGitignoreMatcher creates a new gitignore matcher for the given root path
+// NewGitignoreMatcher creates a new gitignore matcher for the given root path.
+// It loads the root .gitignore and every nested .gitignore found in directories
+// that are not themselves ignored.
 func NewGitignoreMatcher(rootPath string) (*GitignoreMatcher, error) {
 	matcher := &GitignoreMatcher{
 		rootPath: rootPath,
 	}
 
 	// Try to load .gitignore from the root path
-	gitignorePath := filepath.Join(rootPath, ".gitignore")
-	if _, err := os.Stat(gitignorePath); err == nil {
-		if err := matcher.loadGitignore(gitignorePath); err != nil {
-			return nil, err
-		}
+	if err := matcher.LoadGitignoreAt(rootPath); err != nil {
+		return nil, err
 	}
 
 	// If no .gitignore or it's empty, add some sensible defaults
-	if len(matcher.patterns) == 0 {
+	if len(matcher.levels) == 0 {
 		matcher.addDefaultPatterns()
 	}
 
+	// Collect .gitignore files from subdirectories. Parents are visited before
+	// their children, so ignored directories are skipped using the patterns
+	// loaded so far.
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip directories we can't read
+			if d != nil && d.IsDir() && path != rootPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() || path == rootPath {
+			return nil
+		}
+		if matcher.ShouldIgnore(path, true) {
+			return filepath.SkipDir
+		}
+		return matcher.LoadGitignoreAt(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return matcher, nil
 }
 
-// loadGitignore parses a .gitignore file and loads the patterns
-func (gm *GitignoreMatcher) loadGitignore(gitignorePath string) error {
-	file, err := os.Open(gitignorePath)
+// LoadGitignoreAt loads the .gitignore file in dir, if one exists, as a new
+// level governing dir and everything below it
+func (gm *GitignoreMatcher) LoadGitignoreAt(dir string) error {
+	return gm.loadIgnoreFileAt(dir, ".gitignore")
+}
+
+// loadIgnoreFileAt loads the named ignore file in dir, if one exists, as a new
+// level governing dir and everything below it
+func (gm *GitignoreMatcher) loadIgnoreFileAt(dir, filename string) error {
+	ignorePath := filepath.Join(dir, filename)
+	if _, err := os.Stat(ignorePath); err != nil {
+		return nil
+	}
+
+	patterns, err := gm.loadGitignore(ignorePath)
 	if err != nil {
 		return err
 	}
+	if len(patterns) > 0 {
+		gm.levels = append(gm.levels, gitignoreLevel{baseDir: dir, patterns: patterns})
+	}
+	return nil
+}
+
+// loadGitignore parses a .gitignore file and returns its patterns
+func (gm *GitignoreMatcher) loadGitignore(gitignorePath string) ([]GitignorePattern, error) {
+	file, err := os.Open(gitignorePath)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
 
+	var patterns []GitignorePattern
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -63,18 +120,34 @@ func (gm *GitignoreMatcher) loadGitignore(gitignorePath string) error {
 
 		pattern := gm.parsePattern(line)
 		if pattern != nil {
-			gm.patterns = append(gm.patterns, *pattern)
+			patterns = append(patterns, *pattern)
 		}
 	}
 
-	return scanner.Err()
+	return patterns, scanner.Err()
 }
 
-// parsePattern converts a gitignore pattern line into a GitignorePattern
+// parsePattern converts a gitignore pattern line into a GitignorePattern,
+// skipping comments and invalid patterns
 func (gm *GitignoreMatcher) parsePattern(line string) *GitignorePattern {
+	pattern, err := gm.compilePattern(line)
+	if err != nil {
+		return nil
+	}
+	return pattern
+}
+
+// compilePattern converts a gitignore pattern line into a GitignorePattern. It
+// returns nil for empty lines and comments, and an error for invalid patterns.
+func (gm *GitignoreMatcher) compilePattern(line string) (*GitignorePattern, error) {
 	// Skip empty lines and comments
 	if line == "" || strings.HasPrefix(line, "#") {
-		return nil
+		return nil, nil
+	}
+
+	// Like git, a pattern ending in an unescaped backslash never matches
+	if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+		return nil, fmt.Errorf("pattern %q ends with a backslash", line)
 	}
 
 	pattern := GitignorePattern{}
@@ -100,12 +173,11 @@ func (gm *GitignoreMatcher) parsePattern(line string) *GitignorePattern {
 	// Compile the regex
 	regex, err := regexp.Compile(regexPattern)
 	if err != nil {
-		// If regex compilation fails, skip this pattern
-		return nil
+		return nil, fmt.Errorf("pattern %q: %w", line, err)
 	}
 
 	pattern.Regex = regex
-	return &pattern
+	return &pattern, nil
 }
 
 // gitignoreToRegex converts gitignore patterns to regex patterns
@@ -135,69 +207,113 @@ func (gm *GitignoreMatcher) gitignoreToRegex(pattern string) string {
 
 // addDefaultPatterns adds sensible default ignore patterns when no .gitignore exists
 func (gm *GitignoreMatcher) addDefaultPatterns() {
-	defaultPatterns := []string{
-		".git/",
-		".svn/",
-		".hg/",
-		"node_modules/",
-		"vendor/",
-		"target/",
-		"build/",
-		"dist/",
-		"__pycache__/",
-		".DS_Store",
-		"Thumbs.db",
-		"*.tmp",
-		"*.log",
+	var defaultPatterns []string
+	for _, dir := range defaultIgnoredDirs {
+		defaultPatterns = append(defaultPatterns, dir+"/")
 	}
+	defaultPatterns = append(defaultPatterns, defaultIgnoredFiles...)
+	defaultPatterns = append(defaultPatterns, defaultIgnoredGlobs...)
 
+	var patterns []GitignorePattern
 	for _, pattern := range defaultPatterns {
 		parsed := gm.parsePattern(pattern)
 		if parsed != nil {
-			gm.patterns = append(gm.patterns, *parsed)
+			patterns = append(patterns, *parsed)
 		}
 	}
+	gm.levels = append(gm.levels, gitignoreLevel{baseDir: gm.rootPath, patterns: patterns})
 }
 
-// ShouldIgnore determines if a file or directory should be ignored based on .gitignore patterns
+// ShouldIgnore determines if a file or directory should be ignored based on .gitignore patterns.
+// Only .gitignore files in ancestor directories of path apply; deeper files
+// are checked last so their patterns override those of their parents.
 func (gm *GitignoreMatcher) ShouldIgnore(path string, isDir bool) bool {
-	// Convert absolute path to relative path from root
-	relPath, err := filepath.Rel(gm.rootPath, path)
-	if err != nil {
-		// If we can't get relative path, use the basename
-		relPath = filepath.Base(path)
-	}
+	ignored := false
+	for _, level := range gm.levels {
+		// Convert absolute path to relative path from the level's directory
+		relPath, err := filepath.Rel(level.baseDir, path)
+		if err != nil {
+			// If we can't get relative path, use the basename
+			relPath = filepath.Base(path)
+		}
 
-	// Normalize path separators for cross-platform compatibility
-	relPath = filepath.ToSlash(relPath)
+		// Normalize path separators for cross-platform compatibility
+		relPath = filepath.ToSlash(relPath)
 
-	// Don't ignore the root directory itself
-	if relPath == "." {
-		return false
+		// Don't ignore the level's directory itself, and skip levels that
+		// don't govern this path
+		if relPath == "." || relPath == ".." || strings.HasPrefix(relPath, "../") {
+			continue
+		}
+
+		for _, pattern := range level.patterns {
+			// Directory-only patterns never match a file by name, but they
+			// still match files inside an ignored directory
+			target := relPath
+			if pattern.IsDir && !isDir {
+				target = pathpkg.Dir(relPath)
+				if target == "." {
+					continue
+				}
+			}
+
+			if pattern.Regex.MatchString(target) {
+				// Negation pattern overrides previous ignore
+				ignored = !pattern.IsNegative
+			}
+		}
 	}
 
-	// Check against all patterns
-	ignored := false
-	for _, pattern := range gm.patterns {
-		// Check if pattern matches
-		matches := pattern.Regex.MatchString(relPath)
-
-		// For directory-only patterns, only apply to actual directories
-		// But if a file is inside an ignored directory, it should also be ignored
-		if pattern.IsDir && !isDir {
-			// Check if this file is inside the ignored directory
-			// The regex should handle this with the (/.*)?$ suffix
-			matches = matches // Keep the matches as-is since regex handles subdirectories
+	return ignored
+}
+
+// MayReinclude reports whether a negation pattern could re-include something
+// below dir, so an ignored dir has to be walked rather than skipped whole. It
+// errs on the side of true: a negation pattern without a slash, such as
+// "!*.txt", may match a file at any depth.
+func (gm *GitignoreMatcher) MayReinclude(dir string) bool {
+	for _, level := range gm.levels {
+		relDir, err := filepath.Rel(level.baseDir, dir)
+		if err != nil {
+			continue
+		}
+		relDir = filepath.ToSlash(relDir)
+		if relDir == ".." || strings.HasPrefix(relDir, "../") {
+			continue
 		}
 
-		if matches {
-			if pattern.IsNegative {
-				ignored = false // Negation pattern overrides previous ignore
-			} else {
-				ignored = true
+		for _, pattern := range level.patterns {
+			if pattern.IsNegative && negationMayMatchBelow(pattern.Pattern, relDir) {
+				return true
 			}
 		}
 	}
+	return false
+}
+
+// negationMayMatchBelow reports whether pattern could match a path below
+// relDir, comparing the literal part of the pattern before its first wildcard
+func negationMayMatchBelow(pattern, relDir string) bool {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored && !strings.Contains(pattern, "/") {
+		return true
+	}
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+		prefix = pattern[:i]
+	}
 
-	return ignored
+	// Unanchored patterns match at any level, so try relDir and each of its suffixes
+	candidate := relDir
+	for {
+		if relDir == "." || strings.HasPrefix(prefix, candidate+"/") || strings.HasPrefix(candidate+"/", prefix) {
+			return true
+		}
+		i := strings.Index(candidate, "/")
+		if anchored || i < 0 {
+			return false
+		}
+		candidate = candidate[i+1:]
+	}
 }
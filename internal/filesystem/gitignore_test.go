@@ -136,3 +136,143 @@ func TestGitignorePatternParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestGitignoreMatcherNestedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Root .gitignore ignores all log files and the generated/ directory
+	err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("*.log\ngenerated/\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create root .gitignore: %v", err)
+	}
+
+	// packages/api re-includes its audit log and ignores its own tmp directory
+	apiDir := filepath.Join(tmpDir, "packages", "api")
+	if err := os.MkdirAll(apiDir, 0755); err != nil {
+		t.Fatalf("Failed to create package dir: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(apiDir, ".gitignore"), []byte("!audit.log\ntmp/\n/local.txt\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create nested .gitignore: %v", err)
+	}
+
+	// A .gitignore inside an ignored directory must not be loaded
+	generatedDir := filepath.Join(tmpDir, "generated")
+	if err := os.MkdirAll(generatedDir, 0755); err != nil {
+		t.Fatalf("Failed to create generated dir: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(generatedDir, ".gitignore"), []byte("*.go\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create ignored .gitignore: %v", err)
+	}
+
+	matcher, err := NewGitignoreMatcher(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create gitignore matcher: %v", err)
+	}
+
+	tests := []struct {
+		path         string
+		isDir        bool
+		shouldIgnore bool
+		description  string
+	}{
+		{filepath.Join(tmpDir, "server.log"), false, true, "root pattern applies at root"},
+		{filepath.Join(apiDir, "server.log"), false, true, "root pattern applies in subdirectory"},
+		{filepath.Join(apiDir, "audit.log"), false, false, "subdirectory negation overrides root pattern"},
+		{filepath.Join(tmpDir, "audit.log"), false, true, "subdirectory negation does not apply outside its directory"},
+		{filepath.Join(apiDir, "tmp"), true, true, "subdirectory directory pattern applies"},
+		{filepath.Join(apiDir, "tmp", "cache.txt"), false, true, "files in directory ignored by subdirectory pattern"},
+		{filepath.Join(tmpDir, "tmp"), true, false, "subdirectory pattern does not apply to root"},
+		{filepath.Join(apiDir, "local.txt"), false, true, "anchored pattern is relative to its .gitignore"},
+		{filepath.Join(apiDir, "nested", "local.txt"), false, false, "anchored pattern does not match deeper paths"},
+		{filepath.Join(tmpDir, "packages", "web", "main.go"), false, false, "sibling package is unaffected"},
+	}
+
+	for _, test := range tests {
+		result := matcher.ShouldIgnore(test.path, test.isDir)
+		if result != test.shouldIgnore {
+			t.Errorf("%s: expected %t, got %t for path %s",
+				test.description, test.shouldIgnore, result, test.path)
+		}
+	}
+
+	for _, level := range matcher.levels {
+		if level.baseDir == generatedDir {
+			t.Error("Expected .gitignore inside an ignored directory not to be loaded")
+		}
+	}
+}
+
+func TestLoadGitignoreAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	matcher := &GitignoreMatcher{rootPath: tmpDir}
+
+	// Loading a directory without a .gitignore is a no-op
+	if err := matcher.LoadGitignoreAt(tmpDir); err != nil {
+		t.Fatalf("LoadGitignoreAt returned an unexpected error: %v", err)
+	}
+	if len(matcher.levels) != 0 {
+		t.Errorf("Expected no levels, got %d", len(matcher.levels))
+	}
+
+	subDir := filepath.Join(tmpDir, "sub")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatalf("Failed to create subdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subDir, ".gitignore"), []byte("*.bak\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	if err := matcher.LoadGitignoreAt(subDir); err != nil {
+		t.Fatalf("LoadGitignoreAt returned an unexpected error: %v", err)
+	}
+
+	if len(matcher.levels) != 1 || matcher.levels[0].baseDir != subDir {
+		t.Fatalf("Expected one level for %s, got %+v", subDir, matcher.levels)
+	}
+	if !matcher.ShouldIgnore(filepath.Join(subDir, "notes.bak"), false) {
+		t.Error("Expected notes.bak in sub to be ignored")
+	}
+	if matcher.ShouldIgnore(filepath.Join(tmpDir, "notes.bak"), false) {
+		t.Error("Expected notes.bak outside sub not to be ignored")
+	}
+}
+
+func TestGitignoreMatcherMayReinclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	gitignore := "build/\n!build/important.txt\nvendor/\n!/vendor/keep/*.go\nlogs/\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(gitignore), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	matcher, err := NewGitignoreMatcher(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	tests := []struct {
+		dir      string
+		expected bool
+	}{
+		{"build", true},
+		{"pkg/build", true}, // Unanchored patterns match at any level
+		{"build/cache", false},
+		{"vendor", true},
+		{"vendor/keep", true},
+		{"vendor/other", false},
+		{"pkg/vendor", false},
+		{"logs", false},
+	}
+	for _, tt := range tests {
+		if got := matcher.MayReinclude(filepath.Join(tmpDir, tt.dir)); got != tt.expected {
+			t.Errorf("MayReinclude(%s) = %v, expected %v", tt.dir, got, tt.expected)
+		}
+	}
+
+	// The re-included file itself is not ignored, unlike its siblings
+	if matcher.ShouldIgnore(filepath.Join(tmpDir, "build", "important.txt"), false) {
+		t.Error("Expected build/important.txt to be re-included")
+	}
+	if !matcher.ShouldIgnore(filepath.Join(tmpDir, "build", "output.bin"), false) {
+		t.Error("Expected build/output.bin to stay ignored")
+	}
+}
@@ -0,0 +1,147 @@
+package filesystem
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+)
+
+// maxValidationExamples is how many matched paths are kept per pattern
+const maxValidationExamples = 3
+
+// ValidationResult reports what a single ignore pattern matches in the project
+type ValidationResult struct {
+	File     string   // Ignore file the pattern comes from, relative to the root
+	Line     int      // Line number of the pattern in File
+	Pattern  string   // The pattern as written
+	Matches  int      // Number of files and directories the pattern matches
+	Examples []string // Some of the matched paths, relative to the root
+	Err      error    // Set when the pattern is invalid and never matches
+}
+
+// Matched reports whether the pattern matched anything
+func (r ValidationResult) Matched() bool {
+	return r.Matches > 0
+}
+
+// ValidateGitignore tests every pattern of the .gitignore files under rootPath,
+// and of its .promptignore, against the files actually present. Patterns in a
+// nested .gitignore are only tested against the files below it. Directories
+// whose contents a pattern matches are counted once rather than per file.
+func ValidateGitignore(rootPath string) ([]ValidationResult, error) {
+	matcher, err := NewGitignoreMatcher(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the ignore files that apply, skipping directories that are ignored
+	ignoreFiles := []string{filepath.Join(rootPath, PromptIgnoreFile)}
+	err = filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != rootPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && path != rootPath && matcher.ShouldIgnore(path, true) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == ".gitignore" {
+			ignoreFiles = append(ignoreFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ValidationResult
+	for _, ignoreFile := range ignoreFiles {
+		fileResults, err := validateIgnoreFile(rootPath, ignoreFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+	return results, nil
+}
+
+// validateIgnoreFile tests the patterns of one ignore file against the files below its directory
+func validateIgnoreFile(rootPath, ignoreFile string) ([]ValidationResult, error) {
+	file, err := os.Open(ignoreFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	relFile, err := filepath.Rel(rootPath, ignoreFile)
+	if err != nil {
+		relFile = ignoreFile
+	}
+
+	gm := &GitignoreMatcher{rootPath: rootPath}
+	var results []ValidationResult
+	var patterns []*GitignorePattern
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		pattern, err := gm.compilePattern(line)
+		if pattern == nil && err == nil {
+			continue
+		}
+		results = append(results, ValidationResult{
+			File:    filepath.ToSlash(relFile),
+			Line:    lineNum,
+			Pattern: line,
+			Err:     err,
+		})
+		patterns = append(patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	baseDir := filepath.Dir(ignoreFile)
+	err = filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == baseDir {
+			return nil
+		}
+		relPath, err := filepath.Rel(baseDir, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		parent := pathpkg.Dir(relPath)
+
+		for i, pattern := range patterns {
+			if pattern == nil || (pattern.IsDir && !d.IsDir()) || !pattern.Regex.MatchString(relPath) {
+				continue
+			}
+			// Count a matched directory once, not every path inside it
+			if parent != "." && pattern.Regex.MatchString(parent) {
+				continue
+			}
+			results[i].Matches++
+			if len(results[i].Examples) < maxValidationExamples {
+				rootRel, _ := filepath.Rel(rootPath, path)
+				results[i].Examples = append(results[i].Examples, filepath.ToSlash(rootRel))
+			}
+		}
+
+		// The repository metadata is large and never interesting
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		".gitignore":         "*.log\nbuild/\n*.bak\nfoo\\\n",
+		"app.log":            "",
+		"logs/debug.log":     "",
+		"build/out.bin":      "",
+		"build/sub/more.bin": "",
+		"main.go":            "",
+		"docs/.gitignore":    "*.md\n",
+		"docs/guide.md":      "",
+		"README.md":          "",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	results, err := ValidateGitignore(tmpDir)
+	if err != nil {
+		t.Fatalf("ValidateGitignore failed: %v", err)
+	}
+
+	byPattern := make(map[string]ValidationResult)
+	for _, result := range results {
+		byPattern[result.File+":"+result.Pattern] = result
+	}
+
+	if r := byPattern[".gitignore:*.log"]; r.Matches != 2 || len(r.Examples) != 2 || r.Err != nil {
+		t.Errorf("Expected *.log to match 2 files, got %+v", r)
+	}
+	// The directory is counted once, not the files inside it
+	if r := byPattern[".gitignore:build/"]; r.Matches != 1 || r.Examples[0] != "build" {
+		t.Errorf("Expected build/ to match the build directory, got %+v", r)
+	}
+	if r := byPattern[".gitignore:*.bak"]; r.Matched() || r.Err != nil {
+		t.Errorf("Expected *.bak to match nothing, got %+v", r)
+	}
+	if r := byPattern[`.gitignore:foo\`]; r.Err == nil || r.Line != 4 {
+		t.Errorf("Expected an error for the trailing backslash on line 4, got %+v", r)
+	}
+	// Nested patterns only apply below their directory
+	if r := byPattern["docs/.gitignore:*.md"]; r.Matches != 1 || r.Examples[0] != "docs/guide.md" {
+		t.Errorf("Expected docs/*.md to match only docs/guide.md, got %+v", r)
+	}
+}
@@ -0,0 +1,111 @@
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitStatus is the state of a file in the git working tree
+type GitStatus int
+
+const (
+	GitClean GitStatus = iota
+	GitModified
+	GitStaged
+	GitUntracked
+)
+
+// Sigil returns the short marker shown for the status, or "" for clean files
+func (s GitStatus) Sigil() string {
+	switch s {
+	case GitModified:
+		return "M"
+	case GitStaged:
+		return "A"
+	case GitUntracked:
+		return "?"
+	default:
+		return ""
+	}
+}
+
+// GetGitStatus runs `git status` in repoRoot and returns the status of every
+// changed file keyed by absolute path. Files not in the map are clean.
+func GetGitStatus(repoRoot string) (map[string]GitStatus, error) {
+	topLevel, err := runGit(repoRoot, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	topLevel = strings.TrimSpace(topLevel)
+
+	// git reports the top level with symlinks resolved; express it relative to
+	// repoRoot as given so the keys match paths produced by ScanDirectory
+	if absRoot, err := filepath.Abs(repoRoot); err == nil {
+		if resolvedRoot, err := filepath.EvalSymlinks(absRoot); err == nil && resolvedRoot != absRoot {
+			if rel, err := filepath.Rel(resolvedRoot, topLevel); err == nil {
+				topLevel = filepath.Join(absRoot, rel)
+			}
+		}
+	}
+
+	output, err := runGit(repoRoot, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGitStatus(topLevel, output), nil
+}
+
+// runGit runs a git command in dir and returns its standard output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// parseGitStatus parses `git status --porcelain -z` output. Paths in the
+// output are relative to the repository's top level directory.
+func parseGitStatus(topLevel, output string) map[string]GitStatus {
+	statuses := make(map[string]GitStatus)
+
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		index, worktree, path := entry[0], entry[1], entry[3:]
+
+		// Renames and copies are followed by the original path, which we skip
+		if index == 'R' || index == 'C' {
+			i++
+		}
+
+		var status GitStatus
+		switch {
+		case index == '?' && worktree == '?':
+			status = GitUntracked
+		case worktree != ' ':
+			// Unstaged changes take precedence over staged ones
+			status = GitModified
+		case index != ' ':
+			status = GitStaged
+		default:
+			continue
+		}
+
+		statuses[filepath.Join(topLevel, filepath.FromSlash(path))] = status
+	}
+
+	return statuses
+}
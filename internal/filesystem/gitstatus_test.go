@@ -0,0 +1,92 @@
+package filesystem
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitStatus(t *testing.T) {
+	output := " M src/main.go\x00M  staged.go\x00MM both.go\x00?? new dir/file.go\x00R  renamed.go\x00original.go\x00"
+
+	statuses := parseGitStatus("/repo", output)
+
+	expected := map[string]GitStatus{
+		"/repo/src/main.go":     GitModified,
+		"/repo/staged.go":       GitStaged,
+		"/repo/both.go":         GitModified,
+		"/repo/new dir/file.go": GitUntracked,
+		"/repo/renamed.go":      GitStaged,
+	}
+	if len(statuses) != len(expected) {
+		t.Errorf("Expected %d entries, got %d: %v", len(expected), len(statuses), statuses)
+	}
+	for path, want := range expected {
+		if got := statuses[path]; got != want {
+			t.Errorf("Status of %s = %v, expected %v", path, got, want)
+		}
+	}
+	if _, ok := statuses["/repo/original.go"]; ok {
+		t.Error("Expected original path of a rename to be skipped")
+	}
+}
+
+func TestGetGitStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	git("init", "-q")
+	write("clean.go", "package main")
+	write("modified.go", "package main")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	write("modified.go", "package main // changed")
+	write("staged.go", "package main")
+	git("add", "staged.go")
+	write("untracked.go", "package main")
+
+	statuses, err := GetGitStatus(repo)
+	if err != nil {
+		t.Fatalf("GetGitStatus failed: %v", err)
+	}
+
+	expected := map[string]GitStatus{
+		"clean.go":     GitClean,
+		"modified.go":  GitModified,
+		"staged.go":    GitStaged,
+		"untracked.go": GitUntracked,
+	}
+	for name, want := range expected {
+		if got := statuses[filepath.Join(repo, name)]; got != want {
+			t.Errorf("Status of %s = %v, expected %v", name, got, want)
+		}
+	}
+}
+
+func TestGetGitStatus_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if _, err := GetGitStatus(t.TempDir()); err == nil {
+		t.Error("Expected error for a directory outside a git repository")
+	}
+}
@@ -0,0 +1,146 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MatchGlob reports whether a slash-separated relative path matches pattern.
+// In addition to the filepath.Match syntax, a "**" path segment matches zero
+// or more directories.
+func MatchGlob(pattern, name string) (bool, error) {
+	patternSegments := strings.Split(pattern, "/")
+	for _, segment := range patternSegments {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return false, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return matchSegments(patternSegments, strings.Split(name, "/")), nil
+}
+
+// matchSegments matches path segments against pattern segments, expanding "**"
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try letting "**" consume each possible number of segments
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// GlobFiles returns the absolute paths of the files under rootPath matching
+// pattern, which is relative to rootPath. Files ignored by matcher, or inside
+// an ignored directory, are left out.
+func GlobFiles(rootPath, pattern string, matcher Matcher) ([]string, error) {
+	pattern = filepath.ToSlash(filepath.Clean(pattern))
+	if pattern == "." || pattern == "" {
+		return nil, fmt.Errorf("empty glob pattern")
+	}
+	if strings.HasPrefix(pattern, "../") || pattern == ".." {
+		return nil, fmt.Errorf("glob pattern %q must be inside %s", pattern, rootPath)
+	}
+
+	var matches []string
+	if strings.Contains(pattern, "**") {
+		var err error
+		if matches, err = globRecursive(rootPath, pattern, matcher); err != nil {
+			return nil, err
+		}
+	} else {
+		candidates, err := filepath.Glob(filepath.Join(rootPath, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+		for _, candidate := range candidates {
+			if isIgnoredFile(rootPath, candidate, matcher) {
+				continue
+			}
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globRecursive walks rootPath and matches every file against a "**" pattern
+func globRecursive(rootPath, pattern string, matcher Matcher) ([]string, error) {
+	if _, err := MatchGlob(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err := filepath.WalkDir(rootPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == rootPath {
+			return nil
+		}
+		if matcher != nil && matcher.ShouldIgnore(p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, p)
+		if err != nil {
+			return err
+		}
+		if ok, _ := MatchGlob(pattern, filepath.ToSlash(relPath)); ok {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// isIgnoredFile reports whether filePath is not a regular file, or is ignored by
+// matcher either itself or through one of its parent directories
+func isIgnoredFile(rootPath, filePath string, matcher Matcher) bool {
+	info, err := os.Stat(filePath)
+	if err != nil || !info.Mode().IsRegular() {
+		return true
+	}
+	if matcher == nil {
+		return false
+	}
+	if matcher.ShouldIgnore(filePath, false) {
+		return true
+	}
+	for dir := filepath.Dir(filePath); dir != rootPath && strings.HasPrefix(dir, rootPath); dir = filepath.Dir(dir) {
+		if matcher.ShouldIgnore(dir, true) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		// ** matches zero or more directories
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/pkg/util/util.go", true},
+		{"src/**/*.go", "main.go", false},
+		{"**/*_test.go", "internal/tui/app_test.go", true},
+		{"**/*_test.go", "app_test.go", true},
+		{"docs/**", "docs/guide/intro.md", true},
+		{"a/**/b/*.go", "a/x/y/b/c.go", true},
+		{"a/**/b/*.go", "a/x/y/c.go", false},
+		// * stays within a path segment
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/main.go", false},
+		{"pkg/*.go", "pkg/a.go", true},
+		// ? matches a single character
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"**/v?/*.go", "api/v1/handler.go", true},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchGlob(tt.pattern, tt.name)
+		if err != nil {
+			t.Errorf("MatchGlob(%q, %q) returned error: %v", tt.pattern, tt.name, err)
+			continue
+		}
+		if got != tt.matches {
+			t.Errorf("MatchGlob(%q, %q) = %t, expected %t", tt.pattern, tt.name, got, tt.matches)
+		}
+	}
+
+	if _, err := MatchGlob("src/**/[.go", "src/a.go"); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+}
+
+func TestGlobFiles(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"main.go",
+		"file1.txt",
+		"file22.txt",
+		"src/app.go",
+		"src/pkg/util.go",
+		"src/pkg/util.md",
+		"build/out.go",
+	}
+	for _, name := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .gitignore: %v", err)
+	}
+
+	matcher, err := NewProjectMatcher(root)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"**/*.go", []string{"main.go", "src/app.go", "src/pkg/util.go"}},
+		{"src/**/*.go", []string{"src/app.go", "src/pkg/util.go"}},
+		{"*.go", []string{"main.go"}},
+		{"file?.txt", []string{"file1.txt"}},
+		{"build/*.go", nil},
+		{"src/*", []string{"src/app.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			matches, err := GlobFiles(root, tt.pattern, matcher)
+			if err != nil {
+				t.Fatalf("GlobFiles failed: %v", err)
+			}
+			if len(matches) != len(tt.expected) {
+				t.Fatalf("Expected %d matches, got %d: %v", len(tt.expected), len(matches), matches)
+			}
+			for i, name := range tt.expected {
+				if matches[i] != filepath.Join(root, name) {
+					t.Errorf("Match %d: expected %s, got %s", i, filepath.Join(root, name), matches[i])
+				}
+			}
+		})
+	}
+
+	if _, err := GlobFiles(root, "../*.go", matcher); err == nil {
+		t.Error("Expected error for pattern outside the root")
+	}
+}
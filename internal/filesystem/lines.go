@@ -0,0 +1,90 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadLines returns lines start through end (1-based, inclusive) of the file at
+// path, keeping their line endings. An end of zero, or past the end of the file,
+// reads to the end of the file.
+func ReadLines(path string, start, end int) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return ReadLinesFrom(file, path, start, end)
+}
+
+// ReadLinesFrom returns lines start through end of r like ReadLines, for
+// content that was already read. name identifies it in errors.
+func ReadLinesFrom(r io.Reader, name string, start, end int) (string, error) {
+	if start < 1 {
+		return "", fmt.Errorf("invalid start line %d: lines are numbered from 1", start)
+	}
+	if end != 0 && end < start {
+		return "", fmt.Errorf("invalid line range %d-%d: end is before start", start, end)
+	}
+
+	var b strings.Builder
+	reader := bufio.NewReader(r)
+	lineNumber := 0
+	for end == 0 || lineNumber < end {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			lineNumber++
+			if lineNumber >= start {
+				b.WriteString(line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if lineNumber < start {
+		return "", fmt.Errorf("start line %d is past the end of %s (%d lines)", start, name, lineNumber)
+	}
+	return b.String(), nil
+}
+
+// GetLineCount returns the number of lines in the file at path, reading it in
+// chunks so large files aren't held in memory. A final line without a trailing
+// newline still counts, so "a\nb" and "a\nb\n" both have 2 lines, and an empty
+// file has none.
+func GetLineCount(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	count := 0
+	var last byte = '\n'
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		count++
+	}
+	return count, nil
+}
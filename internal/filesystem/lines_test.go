@@ -0,0 +1,101 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\nfour\nfive"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		expected   string
+		wantErr    bool
+	}{
+		{name: "middle range", start: 2, end: 4, expected: "two\nthree\nfour\n"},
+		{name: "single line", start: 3, end: 3, expected: "three\n"},
+		{name: "zero end reads to end of file", start: 4, end: 0, expected: "four\nfive"},
+		{name: "end past last line is clamped", start: 4, end: 100, expected: "four\nfive"},
+		{name: "whole file", start: 1, end: 5, expected: "one\ntwo\nthree\nfour\nfive"},
+		{name: "start past last line", start: 6, end: 10, wantErr: true},
+		{name: "start of zero", start: 0, end: 3, wantErr: true},
+		{name: "negative start", start: -1, end: 0, wantErr: true},
+		{name: "end before start", start: 4, end: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := ReadLines(path, tt.start, tt.end)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got content %q", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadLines failed: %v", err)
+			}
+			if content != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestReadLines_MissingFile(t *testing.T) {
+	if _, err := ReadLines(filepath.Join(t.TempDir(), "missing.txt"), 1, 2); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestReadLines_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, err := ReadLines(path, 1, 0); err == nil {
+		t.Error("Expected error reading lines from an empty file")
+	}
+}
+
+func TestGetLineCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{name: "empty file", content: "", expected: 0},
+		{name: "single line without newline", content: "one", expected: 1},
+		{name: "single line with newline", content: "one\n", expected: 1},
+		{name: "no trailing newline", content: "one\ntwo\nthree", expected: 3},
+		{name: "trailing newline", content: "one\ntwo\nthree\n", expected: 3},
+		{name: "blank lines", content: "\n\n\n", expected: 3},
+		{name: "larger than the read buffer", content: strings.Repeat("line\n", 20000), expected: 20000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lines.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+			count, err := GetLineCount(path)
+			if err != nil {
+				t.Fatalf("GetLineCount failed: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected %d lines, got %d", tt.expected, count)
+			}
+		})
+	}
+
+	if _, err := GetLineCount(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,95 @@
+package filesystem
+
+// PromptIgnoreFile is the name of the file listing paths to keep out of prompts
+const PromptIgnoreFile = ".promptignore"
+
+// Matcher decides whether a path should be left out of the file tree
+type Matcher interface {
+	ShouldIgnore(path string, isDir bool) bool
+}
+
+// ReincludingMatcher is a Matcher with negation patterns, which can re-include
+// entries inside a directory it ignores
+type ReincludingMatcher interface {
+	Matcher
+	// MayReinclude reports whether something below the ignored dir may not be ignored
+	MayReinclude(dir string) bool
+}
+
+// PromptIgnoreMatcher handles .promptignore pattern matching. It uses the same
+// pattern format as .gitignore and lets users hide files that Git tracks, such
+// as large generated files or secrets.
+type PromptIgnoreMatcher struct {
+	matcher *GitignoreMatcher
+}
+
+// NewPromptIgnoreMatcher creates a matcher for the .promptignore file in rootPath.
+// A missing .promptignore results in a matcher that ignores nothing.
+func NewPromptIgnoreMatcher(rootPath string) (*PromptIgnoreMatcher, error) {
+	matcher := &GitignoreMatcher{
+		rootPath: rootPath,
+	}
+	if err := matcher.loadIgnoreFileAt(rootPath, PromptIgnoreFile); err != nil {
+		return nil, err
+	}
+	return &PromptIgnoreMatcher{matcher: matcher}, nil
+}
+
+// ShouldIgnore determines if a file or directory should be ignored based on .promptignore patterns
+func (pm *PromptIgnoreMatcher) ShouldIgnore(path string, isDir bool) bool {
+	return pm.matcher.ShouldIgnore(path, isDir)
+}
+
+// MayReinclude reports whether a .promptignore negation pattern could re-include something below dir
+func (pm *PromptIgnoreMatcher) MayReinclude(dir string) bool {
+	return pm.matcher.MayReinclude(dir)
+}
+
+// CompositeMatcher ignores a path if any of its matchers ignores it
+type CompositeMatcher struct {
+	matchers []Matcher
+}
+
+// NewCompositeMatcher combines matchers so that a path ignored by any one of them is ignored
+func NewCompositeMatcher(matchers ...Matcher) *CompositeMatcher {
+	return &CompositeMatcher{matchers: matchers}
+}
+
+// NewProjectMatcher creates the matcher used for a project tree, combining
+// .gitignore and .promptignore rules
+func NewProjectMatcher(rootPath string) (*CompositeMatcher, error) {
+	gitignore, err := NewGitignoreMatcher(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	promptignore, err := NewPromptIgnoreMatcher(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewCompositeMatcher(gitignore, promptignore), nil
+}
+
+// ShouldIgnore determines if a file or directory is ignored by any of the matchers
+func (cm *CompositeMatcher) ShouldIgnore(path string, isDir bool) bool {
+	for _, matcher := range cm.matchers {
+		if matcher.ShouldIgnore(path, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// MayReinclude reports whether something below dir may be left in, which
+// requires every matcher ignoring dir to be able to re-include it
+func (cm *CompositeMatcher) MayReinclude(dir string) bool {
+	for _, matcher := range cm.matchers {
+		if !matcher.ShouldIgnore(dir, true) {
+			continue
+		}
+		reincluding, ok := matcher.(ReincludingMatcher)
+		if !ok || !reincluding.MayReinclude(dir) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPromptIgnoreMatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("*.log\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(tmpDir, PromptIgnoreFile), []byte("secrets.env\ngenerated/\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create .promptignore: %v", err)
+	}
+
+	promptignore, err := NewPromptIgnoreMatcher(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create promptignore matcher: %v", err)
+	}
+	matcher, err := NewProjectMatcher(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create project matcher: %v", err)
+	}
+
+	tests := []struct {
+		path               string
+		isDir              bool
+		promptignoreIgnore bool
+		compositeIgnore    bool
+		description        string
+	}{
+		{filepath.Join(tmpDir, "secrets.env"), false, true, true, "pattern only in .promptignore"},
+		{filepath.Join(tmpDir, "generated"), true, true, true, "directory only in .promptignore"},
+		{filepath.Join(tmpDir, "generated", "api.go"), false, true, true, "file inside .promptignore directory"},
+		{filepath.Join(tmpDir, "debug.log"), false, false, true, "pattern only in .gitignore"},
+		{filepath.Join(tmpDir, "main.go"), false, false, false, "file in neither ignore file"},
+	}
+
+	for _, test := range tests {
+		if result := promptignore.ShouldIgnore(test.path, test.isDir); result != test.promptignoreIgnore {
+			t.Errorf("%s: PromptIgnoreMatcher expected %t, got %t for path %s",
+				test.description, test.promptignoreIgnore, result, test.path)
+		}
+		if result := matcher.ShouldIgnore(test.path, test.isDir); result != test.compositeIgnore {
+			t.Errorf("%s: CompositeMatcher expected %t, got %t for path %s",
+				test.description, test.compositeIgnore, result, test.path)
+		}
+	}
+}
+
+func TestPromptIgnoreMatcherWithoutFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	matcher, err := NewPromptIgnoreMatcher(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create promptignore matcher: %v", err)
+	}
+
+	// Without a .promptignore nothing is ignored, not even gitignore defaults
+	if matcher.ShouldIgnore(filepath.Join(tmpDir, "node_modules"), true) {
+		t.Error("Expected nothing to be ignored without a .promptignore file")
+	}
+}
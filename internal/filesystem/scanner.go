@@ -4,45 +4,121 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // FileNode represents a file or directory in the filesystem
 type FileNode struct {
-	Name     string
-	Path     string
-	IsDir    bool
-	Children []*FileNode
+	Name      string
+	Path      string
+	IsDir     bool
+	IsSymlink bool
+	Unscanned bool      // Directory whose children haven't been scanned yet
+	SizeBytes int64     // Size of a file; zero for directories
+	ModTime   time.Time // When a file was last modified
+	Children  []*FileNode
 }
 
-// ScanDirectory recursively scans a directory and returns a tree structure
-func ScanDirectory(rootPath string) (*FileNode, error) {
-	return scanDirectoryWithMatcher(rootPath, rootPath)
+// ScanOptions controls how ScanDirectory traverses the filesystem
+type ScanOptions struct {
+	// FollowSymlinks expands symlinked directories. Symlinks that lead back to
+	// a directory being scanned are shown but not expanded, to avoid cycles.
+	FollowSymlinks bool
+	// Depth limits how many levels below the scanned directory are read.
+	// Directories at the limit are marked Unscanned. Zero scans everything.
+	Depth int
+	// Matcher decides which files are ignored. When nil, the ignore files of
+	// the scanned directory are used.
+	Matcher Matcher
 }
 
-// scanDirectoryWithMatcher is the internal implementation that maintains the original root path
-func scanDirectoryWithMatcher(currentPath, originalRoot string) (*FileNode, error) {
-	info, err := os.Stat(currentPath)
-	if err != nil {
-		return nil, err
+// ScanDirectory recursively scans a directory and returns a tree structure.
+// Symlinked directories are only expanded when opts.FollowSymlinks is set.
+func ScanDirectory(rootPath string, opts ScanOptions) (*FileNode, error) {
+	s := &scanner{opts: opts, ancestors: make(map[string]bool)}
+
+	// The directories above rootPath count as being scanned, so symlinks back
+	// to them are detected when scanning a subdirectory on its own
+	for dir := filepath.Dir(rootPath); ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(dir); err == nil {
+			if id, err := fileID(dir, info); err == nil {
+				s.ancestors[id] = true
+			}
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
 	}
 
-	// Create gitignore matcher for the original root if we don't have one
-	matcher, err := NewGitignoreMatcher(originalRoot)
-	if err != nil {
-		// Fall back to simple name-based ignore if gitignore fails
-		return scanDirectoryLegacy(currentPath)
+	s.matcher = opts.Matcher
+	if s.matcher == nil {
+		matcher, err := NewProjectMatcher(rootPath)
+		if err != nil {
+			// Fall back to simple name-based ignore if gitignore fails
+			return s.scanDirectoryLegacy(rootPath, false, 0)
+		}
+		s.matcher = matcher
 	}
+	return s.scanDirectoryWithMatcher(rootPath, false, 0)
+}
 
-	root := &FileNode{
-		Name:     filepath.Base(currentPath),
-		Path:     currentPath,
-		IsDir:    info.IsDir(),
-		Children: []*FileNode{},
+// scanner holds the state shared across a single directory scan
+type scanner struct {
+	opts    ScanOptions
+	matcher Matcher
+	// file IDs of the directories currently being scanned, used to detect symlink cycles
+	ancestors map[string]bool
+}
+
+// newNode stats path and creates its node at the given level below the scanned
+// directory. For a directory whose children should be scanned it also returns
+// the directory's file ID, which the caller must remove from ancestors once the
+// children are scanned.
+func (s *scanner) newNode(path string, isSymlink bool, level int) (*FileNode, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
 	}
 
+	node := &FileNode{
+		Name:      filepath.Base(path),
+		Path:      path,
+		IsDir:     info.IsDir(),
+		IsSymlink: isSymlink,
+		Children:  []*FileNode{},
+	}
 	if !info.IsDir() {
+		node.SizeBytes = info.Size()
+		node.ModTime = info.ModTime()
+	}
+
+	if !info.IsDir() || (isSymlink && !s.opts.FollowSymlinks) {
+		return node, "", nil
+	}
+
+	id, err := fileID(path, info)
+	if err != nil || s.ancestors[id] {
+		// Either unidentifiable or a symlink back to a directory we're inside of
+		return node, "", nil
+	}
+	if s.opts.Depth > 0 && level >= s.opts.Depth {
+		node.Unscanned = true
+		return node, "", nil
+	}
+	s.ancestors[id] = true
+	return node, id, nil
+}
+
+// scanDirectoryWithMatcher is the internal implementation that shares a single matcher across the tree
+func (s *scanner) scanDirectoryWithMatcher(currentPath string, isSymlink bool, level int) (*FileNode, error) {
+	root, id, err := s.newNode(currentPath, isSymlink, level)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
 		return root, nil
 	}
+	defer delete(s.ancestors, id)
 
 	entries, err := os.ReadDir(currentPath)
 	if err != nil {
@@ -52,12 +128,12 @@ func scanDirectoryWithMatcher(currentPath, originalRoot string) (*FileNode, erro
 	for _, entry := range entries {
 		childPath := filepath.Join(currentPath, entry.Name())
 
-		// Skip files based on gitignore patterns
-		if matcher.ShouldIgnore(childPath, entry.IsDir()) {
+		// Skip files based on .gitignore and .promptignore patterns
+		if s.matcher.ShouldIgnore(childPath, entry.IsDir()) {
 			continue
 		}
 
-		child, err := scanDirectoryWithMatcher(childPath, originalRoot)
+		child, err := s.scanDirectoryWithMatcher(childPath, entry.Type()&os.ModeSymlink != 0, level+1)
 		if err != nil {
 			// Skip files we can't read
 			continue
@@ -70,22 +146,15 @@ func scanDirectoryWithMatcher(currentPath, originalRoot string) (*FileNode, erro
 }
 
 // scanDirectoryLegacy is a fallback that uses the old simple ignore logic
-func scanDirectoryLegacy(rootPath string) (*FileNode, error) {
-	info, err := os.Stat(rootPath)
+func (s *scanner) scanDirectoryLegacy(rootPath string, isSymlink bool, level int) (*FileNode, error) {
+	root, id, err := s.newNode(rootPath, isSymlink, level)
 	if err != nil {
 		return nil, err
 	}
-
-	root := &FileNode{
-		Name:     filepath.Base(rootPath),
-		Path:     rootPath,
-		IsDir:    info.IsDir(),
-		Children: []*FileNode{},
-	}
-
-	if !info.IsDir() {
+	if id == "" {
 		return root, nil
 	}
+	defer delete(s.ancestors, id)
 
 	entries, err := os.ReadDir(rootPath)
 	if err != nil {
@@ -99,7 +168,7 @@ func scanDirectoryLegacy(rootPath string) (*FileNode, error) {
 		}
 
 		childPath := filepath.Join(rootPath, entry.Name())
-		child, err := scanDirectoryLegacy(childPath)
+		child, err := s.scanDirectoryLegacy(childPath, entry.Type()&os.ModeSymlink != 0, level+1)
 		if err != nil {
 			// Skip files we can't read
 			continue
@@ -111,48 +180,30 @@ func scanDirectoryLegacy(rootPath string) (*FileNode, error) {
 	return root, nil
 }
 
-// ShouldIgnore determines if a file or directory should be ignored
-func ShouldIgnore(name string) bool {
-	// Common ignore patterns
-	ignorePatterns := []string{
-		"node_modules",
-		".git",
-		".svn",
-		".hg",
-		"vendor",
-		"target",
-		"build",
-		"dist",
-		"__pycache__",
-		".DS_Store",
-		"Thumbs.db",
-	}
-
-	// Check if starts with dot (hidden files)
-	if strings.HasPrefix(name, ".") {
-		return true
-	}
-
-	// Check against ignore patterns
-	for _, pattern := range ignorePatterns {
-		if name == pattern {
-			return true
-		}
-	}
-
-	return false
-}
-
 // FlattenTree converts a tree structure to a flat list for display
 func FlattenTree(root *FileNode, level int, expanded map[string]bool) []FileTreeItem {
 	var items []FileTreeItem
 
+	childCount := -1
+	if root.IsDir && !root.Unscanned {
+		childCount = 0
+		for _, child := range root.Children {
+			if !child.IsDir {
+				childCount++
+			}
+		}
+	}
+
 	item := FileTreeItem{
-		Name:     root.Name,
-		Path:     root.Path,
-		IsDir:    root.IsDir,
-		Level:    level,
-		Expanded: expanded[root.Path],
+		Name:       root.Name,
+		Path:       root.Path,
+		IsDir:      root.IsDir,
+		IsSymlink:  root.IsSymlink,
+		Level:      level,
+		Expanded:   expanded[root.Path],
+		SizeBytes:  root.SizeBytes,
+		ModTime:    root.ModTime,
+		ChildCount: childCount,
 	}
 	items = append(items, item)
 
@@ -169,12 +220,19 @@ func FlattenTree(root *FileNode, level int, expanded map[string]bool) []FileTree
 
 // FileTreeItem represents an item in the flattened tree view
 type FileTreeItem struct {
-	Name     string
-	Path     string
-	IsDir    bool
-	Level    int
-	Expanded bool
-	Selected bool
+	Name       string
+	Path       string
+	IsDir      bool
+	IsSymlink  bool
+	Level      int
+	Expanded   bool
+	Selected   bool
+	GitStatus  GitStatus
+	SizeBytes  int64
+	ModTime    time.Time
+	LineCount  int  // Lines in the file, or -1 until they have been counted
+	ChildCount int  // Files directly in a directory, or -1 if it hasn't been scanned
+	Hidden     bool // Filtered out of the tree view
 }
 
 // GetFileContent reads and returns the content of a file
@@ -195,7 +253,7 @@ func IsTextFile(filePath string) bool {
 		".html", ".css", ".scss", ".sass", ".xml", ".json", ".yaml", ".yml", ".toml",
 		".sh", ".bash", ".zsh", ".fish",
 		".sql", ".dockerfile", ".makefile",
-		".vue", ".jsx", ".tsx",
+		".vue", ".jsx", ".tsx", ".mjs", ".cjs",
 		".rs", ".elm", ".hs", ".ml", ".fs",
 	}
 
@@ -221,3 +279,22 @@ func IsTextFile(filePath string) bool {
 
 	return false
 }
+
+// DetectLanguage returns the language of a source file from its extension:
+// "go", "python", "javascript" or "shell", or "" for other files
+func DetectLanguage(filePath string) string {
+	if !IsTextFile(filePath) {
+		return ""
+	}
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs":
+		return "javascript"
+	case ".sh", ".bash", ".zsh":
+		return "shell"
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// findNode returns the node at the slash-separated path below root, or nil
+func findNode(root *FileNode, path ...string) *FileNode {
+	node := root
+	for _, name := range path {
+		var next *FileNode
+		for _, child := range node.Children {
+			if child.Name == name {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// newSymlinkTree creates root/{real/file.go, link.go -> real/file.go,
+// linkdir -> real, real/loop -> root}
+func newSymlinkTree(t *testing.T) string {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "real"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "real", "file.go"), []byte("package real"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	links := map[string]string{
+		filepath.Join(root, "link.go"):         filepath.Join(root, "real", "file.go"),
+		filepath.Join(root, "linkdir"):         filepath.Join(root, "real"),
+		filepath.Join(root, "real", "loop"):    root,
+		filepath.Join(root, "real", "missing"): filepath.Join(root, "does-not-exist"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+	return root
+}
+
+func TestScanDirectory_SymlinksNotFollowed(t *testing.T) {
+	root := newSymlinkTree(t)
+
+	tree, err := ScanDirectory(root, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+
+	linkFile := findNode(tree, "link.go")
+	if linkFile == nil || !linkFile.IsSymlink || linkFile.IsDir {
+		t.Errorf("Expected link.go to be a symlinked file, got %+v", linkFile)
+	}
+
+	linkDir := findNode(tree, "linkdir")
+	if linkDir == nil || !linkDir.IsSymlink || !linkDir.IsDir {
+		t.Fatalf("Expected linkdir to be a symlinked directory, got %+v", linkDir)
+	}
+	if len(linkDir.Children) != 0 {
+		t.Errorf("Expected symlinked directory not to be expanded, got %d children", len(linkDir.Children))
+	}
+
+	if file := findNode(tree, "real", "file.go"); file == nil || file.IsSymlink {
+		t.Errorf("Expected real/file.go to be a regular file, got %+v", file)
+	}
+	if findNode(tree, "real", "missing") != nil {
+		t.Error("Expected broken symlink to be skipped")
+	}
+}
+
+func TestScanDirectory_FollowSymlinksDetectsCycles(t *testing.T) {
+	root := newSymlinkTree(t)
+
+	// real/loop points back at root; following it must not recurse forever
+	tree, err := ScanDirectory(root, ScanOptions{FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+
+	if findNode(tree, "linkdir", "file.go") == nil {
+		t.Error("Expected symlinked directory to be expanded when following symlinks")
+	}
+
+	loop := findNode(tree, "real", "loop")
+	if loop == nil || !loop.IsSymlink {
+		t.Fatalf("Expected real/loop to be shown as a symlink, got %+v", loop)
+	}
+	if len(loop.Children) != 0 {
+		t.Errorf("Expected cyclic symlink not to be expanded, got %d children", len(loop.Children))
+	}
+
+	// linkdir/loop also leads back to root, which is an ancestor there too
+	if nested := findNode(tree, "linkdir", "loop"); nested == nil || len(nested.Children) != 0 {
+		t.Errorf("Expected linkdir/loop to be shown without children, got %+v", nested)
+	}
+}
+
+func TestScanDirectory_Depth(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/sub/b.go"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package x"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tree, err := ScanDirectory(root, ScanOptions{Depth: 1})
+	if err != nil {
+		t.Fatalf("ScanDirectory failed: %v", err)
+	}
+	pkg := findNode(tree, "pkg")
+	if pkg == nil || !pkg.Unscanned || len(pkg.Children) != 0 {
+		t.Fatalf("Expected pkg to be left unscanned, got %+v", pkg)
+	}
+	if main := findNode(tree, "main.go"); main == nil || main.Unscanned || main.SizeBytes != int64(len("package x")) {
+		t.Errorf("Expected main.go to be scanned as a file with its size, got %+v", main)
+	}
+
+	// Scanning the directory later with the project's matcher fills in one more level
+	matcher, err := NewProjectMatcher(root)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	scanned, err := ScanDirectory(pkg.Path, ScanOptions{Depth: 1, Matcher: matcher})
+	if err != nil {
+		t.Fatalf("ScanDirectory of subdirectory failed: %v", err)
+	}
+	if findNode(scanned, "a.go") == nil {
+		t.Error("Expected pkg/a.go in the subdirectory scan")
+	}
+	if sub := findNode(scanned, "sub"); sub == nil || !sub.Unscanned {
+		t.Errorf("Expected pkg/sub to be left unscanned, got %+v", sub)
+	}
+}
+
+func TestFlattenTree_ChildCount(t *testing.T) {
+	file := func(name string) *FileNode { return &FileNode{Name: name, Path: name} }
+	sub := &FileNode{Name: "sub", Path: "pkg/sub", IsDir: true, Children: []*FileNode{file("c.go")}}
+	pkg := &FileNode{Name: "pkg", Path: "pkg", IsDir: true, Children: []*FileNode{file("a.go"), file("b.go"), sub}}
+	unscanned := &FileNode{Name: "vendor", Path: "vendor", IsDir: true, Unscanned: true}
+
+	counts := map[string]int{}
+	for _, root := range []*FileNode{pkg, unscanned, file("main.go")} {
+		for _, item := range FlattenTree(root, 0, map[string]bool{"pkg": true}) {
+			counts[item.Path] = item.ChildCount
+		}
+	}
+
+	// Only files directly in a directory are counted, not its subdirectories
+	// or the files in them
+	want := map[string]int{"pkg": 2, "pkg/sub": 1, "vendor": -1, "main.go": -1}
+	for path, count := range want {
+		if counts[path] != count {
+			t.Errorf("Expected %s to have a child count of %d, got %d", path, count, counts[path])
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"main.go":          "go",
+		"script.PY":        "python",
+		"app.tsx":          "javascript",
+		"lib/module.mjs":   "javascript",
+		"deploy.sh":        "shell",
+		"README.md":        "",
+		"Makefile":         "",
+		"image.png":        "",
+		"dir/no_extension": "",
+	}
+	for path, want := range tests {
+		if got := DetectLanguage(path); got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
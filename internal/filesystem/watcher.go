@@ -0,0 +1,159 @@
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long a file must stay quiet before a change is reported
+const DefaultDebounce = 500 * time.Millisecond
+
+// FileChangedMsg reports that a watched file was written
+type FileChangedMsg struct {
+	Path string
+}
+
+// FileWatcher watches a set of files and reports debounced changes to them
+type FileWatcher struct {
+	watcher  *fsnotify.Watcher
+	debounce time.Duration
+	events   chan FileChangedMsg
+	done     chan struct{}
+
+	mutex  sync.Mutex
+	files  map[string]bool
+	dirs   map[string]bool
+	timers map[string]*time.Timer
+}
+
+// NewFileWatcher creates a watcher that reports a change once a file has
+// been quiet for the debounce duration
+func NewFileWatcher(debounce time.Duration) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	w := &FileWatcher{
+		watcher:  watcher,
+		debounce: debounce,
+		events:   make(chan FileChangedMsg, 16),
+		done:     make(chan struct{}),
+		files:    make(map[string]bool),
+		dirs:     make(map[string]bool),
+		timers:   make(map[string]*time.Timer),
+	}
+
+	go w.watchLoop()
+
+	return w, nil
+}
+
+// SetFiles replaces the set of watched files
+func (w *FileWatcher) SetFiles(paths []string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	files := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		files[path] = true
+		dirs[filepath.Dir(path)] = true
+	}
+
+	// Watch parent directories rather than the files themselves, so files
+	// replaced by editors through rename keep being watched
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			w.watcher.Remove(dir)
+		}
+	}
+	for dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			delete(dirs, dir)
+			w.files = files
+			w.dirs = dirs
+			return fmt.Errorf("failed to watch directory %s: %w", dir, err)
+		}
+	}
+
+	w.files = files
+	w.dirs = dirs
+	return nil
+}
+
+// Events returns the channel debounced file changes are delivered on
+func (w *FileWatcher) Events() <-chan FileChangedMsg {
+	return w.events
+}
+
+// Close stops watching. No further events are delivered after Close returns.
+func (w *FileWatcher) Close() error {
+	w.mutex.Lock()
+	for _, timer := range w.timers {
+		timer.Stop()
+	}
+	w.mutex.Unlock()
+
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// watchLoop runs the file watcher loop
+func (w *FileWatcher) watchLoop() {
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Only care about writes to watched files
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				w.schedule(filepath.Clean(event.Name))
+			}
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// schedule reports a change to path once it has been quiet for the debounce duration
+func (w *FileWatcher) schedule(path string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.files[path] {
+		return
+	}
+
+	if timer, ok := w.timers[path]; ok {
+		timer.Reset(w.debounce)
+		return
+	}
+
+	w.timers[path] = time.AfterFunc(w.debounce, func() {
+		w.mutex.Lock()
+		delete(w.timers, path)
+		w.mutex.Unlock()
+
+		select {
+		case w.events <- FileChangedMsg{Path: path}:
+		case <-w.done:
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher_ReportsWrites(t *testing.T) {
+	tempDir := t.TempDir()
+	watched := filepath.Join(tempDir, "watched.go")
+	other := filepath.Join(tempDir, "other.go")
+	for _, path := range []string{watched, other} {
+		if err := os.WriteFile(path, []byte("package main"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+	}
+
+	watcher, err := NewFileWatcher(50 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.SetFiles([]string{watched}); err != nil {
+		t.Fatalf("Failed to set watched files: %v", err)
+	}
+
+	// Unwatched files in the same directory must not be reported
+	if err := os.WriteFile(other, []byte("package other"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", other, err)
+	}
+	// Rapid writes should be debounced into a single change
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(watched, []byte("package main // edit"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", watched, err)
+		}
+	}
+
+	select {
+	case msg := <-watcher.Events():
+		if msg.Path != watched {
+			t.Errorf("Expected change for %s, got %s", watched, msg.Path)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for FileChangedMsg")
+	}
+
+	select {
+	case msg := <-watcher.Events():
+		t.Errorf("Expected a single debounced change, got another for %s", msg.Path)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // Manager handles persona discovery and management
 type Manager struct {
 	personasDir string
-	personas    []string
+
+	mutex    sync.RWMutex // Guards personas, which WatchPersonasDir rediscovers in the background
+	personas []string
 }
 
 // NewManager creates a new persona manager
@@ -21,6 +24,38 @@ func NewManager(rootDir string) *Manager {
 	}
 }
 
+// defaultTemplate is the content of the default persona created for projects without personas
+const defaultTemplate = `# Default Persona
+
+You are an experienced software engineer helping with the project described below.
+
+- Follow the conventions of the existing code
+- Keep changes small and explain the reasoning behind them
+- Point out risks, edge cases and missing tests
+
+Edit this file to describe how the assistant should work on this project.
+`
+
+// GetPersonasDir returns the directory personas are read from
+func (m *Manager) GetPersonasDir() string {
+	return m.personasDir
+}
+
+// EnsurePersonasDir creates the personas directory with a default persona to
+// start from when it doesn't exist. An existing directory is left alone.
+func (m *Manager) EnsurePersonasDir() error {
+	if _, err := os.Stat(m.personasDir); !os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(m.personasDir, 0755); err != nil {
+		return fmt.Errorf("failed to create personas directory: %w", err)
+	}
+	if err := os.WriteFile(m.GetPersonaPath("default"), []byte(defaultTemplate), 0644); err != nil {
+		return fmt.Errorf("failed to create default persona: %w", err)
+	}
+	return nil
+}
+
 // DiscoverPersonas scans the personas directory for available personas
 func (m *Manager) DiscoverPersonas() error {
 	// Check if personas directory exists
@@ -49,18 +84,24 @@ func (m *Manager) DiscoverPersonas() error {
 
 	// Sort personas alphabetically
 	sort.Strings(personas)
+	m.mutex.Lock()
 	m.personas = personas
+	m.mutex.Unlock()
 
 	return nil
 }
 
 // GetAvailablePersonas returns the list of discovered personas
 func (m *Manager) GetAvailablePersonas() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	return append([]string{}, m.personas...) // Return a copy
 }
 
 // ValidatePersonas checks if the given personas exist
 func (m *Manager) ValidatePersonas(personas []string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
 	var valid []string
 	personaSet := make(map[string]bool)
 
@@ -102,12 +143,195 @@ func (m *Manager) PersonaExists(persona string) bool {
 	return err == nil
 }
 
-// ReadPersonaContent reads the content of a persona file
+// MaxNameLength is the longest persona name ValidateName accepts
+const MaxNameLength = 64
+
+// ValidateName checks that name can be used as a persona file name: it must be
+// made of letters, digits, hyphens and underscores, and at most MaxNameLength long
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("persona name cannot be empty")
+	}
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("persona name cannot be longer than %d characters", MaxNameLength)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("persona name may only contain letters, digits, hyphens and underscores")
+		}
+	}
+	return nil
+}
+
+// CreatePersona writes a new persona file with the given content, creating the
+// personas directory if needed, and rediscovers the personas. It fails if the
+// name is invalid or the persona already exists.
+func (m *Manager) CreatePersona(name, content string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.personasDir, 0755); err != nil {
+		return fmt.Errorf("failed to create personas directory: %w", err)
+	}
+
+	f, err := os.OpenFile(m.GetPersonaPath(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("persona %s already exists", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create persona %s: %w", name, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write persona %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write persona %s: %w", name, err)
+	}
+	return m.DiscoverPersonas()
+}
+
+// UpdatePersona replaces the content of an existing persona. It fails if the
+// name is invalid or the persona doesn't exist.
+func (m *Manager) UpdatePersona(name, content string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if !m.PersonaExists(name) {
+		return fmt.Errorf("persona %s does not exist", name)
+	}
+	if err := os.WriteFile(m.GetPersonaPath(name), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write persona %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeletePersona removes a persona file and rediscovers the personas. It fails
+// if the name is invalid or the persona doesn't exist.
+func (m *Manager) DeletePersona(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := os.Remove(m.GetPersonaPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("persona %s does not exist", name)
+		}
+		return fmt.Errorf("failed to delete persona %s: %w", name, err)
+	}
+	return m.DiscoverPersonas()
+}
+
+// frontMatter holds the metadata a persona declares in its YAML front-matter
+type frontMatter struct {
+	Extends string // Name of the parent persona
+}
+
+// ReadPersonaContent reads the content of a persona file without its front-matter
 func (m *Manager) ReadPersonaContent(persona string) (string, error) {
+	_, body, err := m.readPersona(persona)
+	return body, err
+}
+
+// ResolvePersonaChain returns the inheritance chain of a persona, starting with
+// the root ancestor and ending with the persona itself. An error is returned if
+// a persona in the chain can't be read or the chain contains a cycle.
+func (m *Manager) ResolvePersonaChain(name string) ([]string, error) {
+	var chain []string
+	visited := make(map[string]bool)
+
+	for current := name; current != ""; {
+		if visited[current] {
+			return nil, fmt.Errorf("persona inheritance cycle: %s -> %s", strings.Join(chain, " -> "), current)
+		}
+		visited[current] = true
+		chain = append(chain, current)
+
+		meta, _, err := m.readPersona(current)
+		if err != nil {
+			return nil, err
+		}
+		current = meta.Extends
+	}
+
+	return reverse(chain), nil
+}
+
+// ResolvePersonaContent returns the content of a persona with the content of
+// its ancestors before it, each separated by a "---" line
+func (m *Manager) ResolvePersonaContent(name string) (string, error) {
+	chain, err := m.ResolvePersonaChain(name)
+	if err != nil {
+		return "", err
+	}
+	if len(chain) == 1 {
+		return m.ReadPersonaContent(name)
+	}
+
+	parts := make([]string, 0, len(chain))
+	for _, persona := range chain {
+		body, err := m.ReadPersonaContent(persona)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, strings.TrimSpace(body))
+	}
+	return strings.Join(parts, "\n\n---\n\n"), nil
+}
+
+// readPersona reads a persona file and splits it into front-matter and body
+func (m *Manager) readPersona(persona string) (frontMatter, string, error) {
 	path := m.GetPersonaPath(persona)
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to read persona %s: %w", persona, err)
+		return frontMatter{}, "", fmt.Errorf("failed to read persona %s: %w", persona, err)
+	}
+	meta, body := parseFrontMatter(string(content))
+	return meta, body, nil
+}
+
+// parseFrontMatter splits a leading "---" delimited front-matter block from
+// content. Only simple "key: value" lines are supported.
+func parseFrontMatter(content string) (frontMatter, string) {
+	var meta frontMatter
+
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return meta, content
+	}
+
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end == -1 {
+		return meta, content
+	}
+
+	for _, line := range strings.Split(rest[:end], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "extends":
+			meta.Extends = value
+		}
+	}
+
+	// Skip the closing delimiter line
+	body := rest[end+len("\n---"):]
+	if i := strings.Index(body, "\n"); i != -1 {
+		body = body[i+1:]
+	} else {
+		body = ""
+	}
+	return meta, body
+}
+
+// reverse returns a reversed copy of names
+func reverse(names []string) []string {
+	reversed := make([]string, len(names))
+	for i, name := range names {
+		reversed[len(names)-1-i] = name
 	}
-	return string(content), nil
+	return reversed
 }
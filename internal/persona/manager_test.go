@@ -0,0 +1,242 @@
+package persona
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writePersonas(t *testing.T, personas map[string]string) *Manager {
+	t.Helper()
+	rootDir := t.TempDir()
+	personasDir := filepath.Join(rootDir, "personas")
+	if err := os.MkdirAll(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas directory: %v", err)
+	}
+	for name, content := range personas {
+		if err := os.WriteFile(filepath.Join(personasDir, name+".md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write persona %s: %v", name, err)
+		}
+	}
+	return NewManager(rootDir)
+}
+
+func TestResolvePersonaChain_MultiLevel(t *testing.T) {
+	m := writePersonas(t, map[string]string{
+		"base":     "Base content",
+		"engineer": "---\nextends: base\n---\nEngineer content",
+		"reviewer": "---\nextends: \"engineer\"\n---\nReviewer content\n",
+	})
+
+	chain, err := m.ResolvePersonaChain("reviewer")
+	if err != nil {
+		t.Fatalf("ResolvePersonaChain failed: %v", err)
+	}
+	if strings.Join(chain, ",") != "base,engineer,reviewer" {
+		t.Errorf("Expected chain base,engineer,reviewer, got %v", chain)
+	}
+
+	content, err := m.ResolvePersonaContent("reviewer")
+	if err != nil {
+		t.Fatalf("ResolvePersonaContent failed: %v", err)
+	}
+	expected := "Base content\n\n---\n\nEngineer content\n\n---\n\nReviewer content"
+	if content != expected {
+		t.Errorf("Expected content %q, got %q", expected, content)
+	}
+}
+
+func TestResolvePersonaChain_Standalone(t *testing.T) {
+	m := writePersonas(t, map[string]string{
+		"default": "# Default\n\nDefault content\n",
+	})
+
+	chain, err := m.ResolvePersonaChain("default")
+	if err != nil {
+		t.Fatalf("ResolvePersonaChain failed: %v", err)
+	}
+	if len(chain) != 1 || chain[0] != "default" {
+		t.Errorf("Expected chain [default], got %v", chain)
+	}
+
+	content, err := m.ResolvePersonaContent("default")
+	if err != nil {
+		t.Fatalf("ResolvePersonaContent failed: %v", err)
+	}
+	if content != "# Default\n\nDefault content\n" {
+		t.Errorf("Expected standalone persona content unchanged, got %q", content)
+	}
+}
+
+func TestResolvePersonaChain_Cycle(t *testing.T) {
+	m := writePersonas(t, map[string]string{
+		"a": "---\nextends: b\n---\nA",
+		"b": "---\nextends: c\n---\nB",
+		"c": "---\nextends: a\n---\nC",
+	})
+
+	_, err := m.ResolvePersonaChain("a")
+	if err == nil {
+		t.Fatal("Expected error for inheritance cycle")
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected error to describe the cycle, got: %v", err)
+	}
+
+	if _, err := m.ResolvePersonaContent("b"); err == nil {
+		t.Error("Expected ResolvePersonaContent to fail for a cyclic persona")
+	}
+}
+
+func TestResolvePersonaChain_MissingParent(t *testing.T) {
+	m := writePersonas(t, map[string]string{
+		"child": "---\nextends: missing\n---\nChild",
+	})
+
+	if _, err := m.ResolvePersonaChain("child"); err == nil {
+		t.Error("Expected error for missing parent persona")
+	}
+}
+
+func TestReadPersonaContent_StripsFrontMatter(t *testing.T) {
+	m := writePersonas(t, map[string]string{
+		"child": "---\r\nextends: base\r\n---\r\nChild content",
+		"plain": "--- not front-matter\nContent",
+	})
+
+	content, err := m.ReadPersonaContent("child")
+	if err != nil {
+		t.Fatalf("ReadPersonaContent failed: %v", err)
+	}
+	if content != "Child content" {
+		t.Errorf("Expected front-matter to be stripped, got %q", content)
+	}
+
+	content, err = m.ReadPersonaContent("plain")
+	if err != nil {
+		t.Fatalf("ReadPersonaContent failed: %v", err)
+	}
+	if content != "--- not front-matter\nContent" {
+		t.Errorf("Expected content without front-matter to be unchanged, got %q", content)
+	}
+}
+
+func TestCreatePersona(t *testing.T) {
+	rootDir := t.TempDir()
+	m := NewManager(rootDir)
+
+	// The personas directory is created on demand
+	if err := m.CreatePersona("code-reviewer_2", "Review the code.\n"); err != nil {
+		t.Fatalf("CreatePersona failed: %v", err)
+	}
+	content, err := m.ReadPersonaContent("code-reviewer_2")
+	if err != nil || content != "Review the code.\n" {
+		t.Errorf("Expected the persona content to be written, got %q (err %v)", content, err)
+	}
+	if err := m.DiscoverPersonas(); err != nil {
+		t.Fatalf("DiscoverPersonas failed: %v", err)
+	}
+	if personas := m.GetAvailablePersonas(); len(personas) != 1 || personas[0] != "code-reviewer_2" {
+		t.Errorf("Expected the new persona to be discovered, got %v", personas)
+	}
+
+	if err := m.CreatePersona("code-reviewer_2", "Other"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error for an existing persona, got %v", err)
+	}
+	for _, name := range []string{"", "../escape", "has space", "dot.md"} {
+		if err := m.CreatePersona(name, "content"); err == nil {
+			t.Errorf("Expected an error for persona name %q", name)
+		}
+	}
+}
+
+func TestCreatePersona_NameTooLong(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if err := m.CreatePersona(strings.Repeat("a", MaxNameLength), "content"); err != nil {
+		t.Errorf("Expected a %d character name to be accepted, got %v", MaxNameLength, err)
+	}
+	if err := m.CreatePersona(strings.Repeat("a", MaxNameLength+1), "content"); err == nil {
+		t.Errorf("Expected an error for a name longer than %d characters", MaxNameLength)
+	}
+}
+
+func TestUpdatePersona(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if err := m.CreatePersona("reviewer", "Review the code.\n"); err != nil {
+		t.Fatalf("CreatePersona failed: %v", err)
+	}
+
+	if err := m.UpdatePersona("reviewer", "Review the tests.\n"); err != nil {
+		t.Fatalf("UpdatePersona failed: %v", err)
+	}
+	content, err := m.ReadPersonaContent("reviewer")
+	if err != nil || content != "Review the tests.\n" {
+		t.Errorf("Expected the persona content to be replaced, got %q (err %v)", content, err)
+	}
+
+	if err := m.UpdatePersona("missing", "content"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected an error for a missing persona, got %v", err)
+	}
+	if err := m.UpdatePersona("../escape", "content"); err == nil {
+		t.Error("Expected an error for an invalid name")
+	}
+}
+
+func TestDeletePersona(t *testing.T) {
+	m := NewManager(t.TempDir())
+	for _, name := range []string{"default", "reviewer"} {
+		if err := m.CreatePersona(name, "content\n"); err != nil {
+			t.Fatalf("CreatePersona failed: %v", err)
+		}
+	}
+	if personas := m.GetAvailablePersonas(); len(personas) != 2 {
+		t.Fatalf("Expected creating to rediscover the personas, got %v", personas)
+	}
+
+	if err := m.DeletePersona("reviewer"); err != nil {
+		t.Fatalf("DeletePersona failed: %v", err)
+	}
+	if m.PersonaExists("reviewer") {
+		t.Error("Expected the persona file to be removed")
+	}
+	if personas := m.GetAvailablePersonas(); len(personas) != 1 || personas[0] != "default" {
+		t.Errorf("Expected the deleted persona to be dropped from the list, got %v", personas)
+	}
+
+	if err := m.DeletePersona("reviewer"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("Expected an error deleting a missing persona, got %v", err)
+	}
+	if err := m.DeletePersona("../default"); err == nil {
+		t.Error("Expected an error for an invalid name")
+	}
+}
+
+func TestEnsurePersonasDir(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if err := m.EnsurePersonasDir(); err != nil {
+		t.Fatalf("EnsurePersonasDir failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(m.GetPersonasDir(), "default.md"))
+	if err != nil {
+		t.Fatalf("Expected the default persona to be created: %v", err)
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		t.Error("Expected the default persona to have content")
+	}
+	if err := m.DiscoverPersonas(); err != nil || !slices.Equal(m.GetAvailablePersonas(), []string{"default"}) {
+		t.Errorf("Expected the default persona to be discovered, got %v (err %v)", m.GetAvailablePersonas(), err)
+	}
+}
+
+func TestEnsurePersonasDir_KeepsExistingDir(t *testing.T) {
+	m := writePersonas(t, map[string]string{"reviewer": "Review code"})
+	if err := m.EnsurePersonasDir(); err != nil {
+		t.Fatalf("EnsurePersonasDir failed: %v", err)
+	}
+	if m.PersonaExists("default") {
+		t.Error("Expected no default persona in an existing personas directory")
+	}
+}
@@ -0,0 +1,71 @@
+package persona
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchPersonasDir watches the personas directory for persona files being
+// written, created, removed or renamed. After each such change the personas are
+// discovered again and onChange is called with the new list, from the
+// watcher's goroutine. The returned function stops watching; onChange isn't
+// called once it returns, and calling it again does nothing.
+func (m *Manager) WatchPersonasDir(onChange func([]string)) (func(), error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create personas watcher: %w", err)
+	}
+	if err := watcher.Add(m.personasDir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch personas directory %s: %w", m.personasDir, err)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !strings.HasSuffix(event.Name, ".md") {
+					continue
+				}
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) &&
+					!event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+				if err := m.DiscoverPersonas(); err != nil {
+					continue
+				}
+				select {
+				case <-done:
+					return
+				default:
+					onChange(m.GetAvailablePersonas())
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+			<-stopped
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,83 @@
+package persona
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// waitForPersonas returns the next persona list sent on changes, failing the test after a timeout
+func waitForPersonas(t *testing.T, changes <-chan []string) []string {
+	t.Helper()
+	select {
+	case personas := <-changes:
+		return personas
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a personas change")
+		return nil
+	}
+}
+
+func TestWatchPersonasDir(t *testing.T) {
+	m := writePersonas(t, map[string]string{"default": "Default content"})
+	if err := m.DiscoverPersonas(); err != nil {
+		t.Fatalf("DiscoverPersonas failed: %v", err)
+	}
+
+	changes := make(chan []string, 10)
+	stop, err := m.WatchPersonasDir(func(personas []string) {
+		changes <- personas
+	})
+	if err != nil {
+		t.Fatalf("WatchPersonasDir failed: %v", err)
+	}
+	defer stop()
+
+	// Other files in the directory don't count as changes
+	if err := os.WriteFile(m.GetPersonaPath("notes")+".txt", []byte("notes"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(m.GetPersonaPath("reviewer"), []byte("Reviewer content"), 0644); err != nil {
+		t.Fatalf("Failed to write persona: %v", err)
+	}
+	if personas := waitForPersonas(t, changes); !slices.Equal(personas, []string{"default", "reviewer"}) {
+		t.Errorf("Expected the created persona to be discovered, got %v", personas)
+	}
+	if !slices.Equal(m.GetAvailablePersonas(), []string{"default", "reviewer"}) {
+		t.Errorf("Expected the manager's personas to be updated, got %v", m.GetAvailablePersonas())
+	}
+
+	// Writing the persona may report more changes; wait for the removal
+	if err := os.Remove(m.GetPersonaPath("reviewer")); err != nil {
+		t.Fatalf("Failed to remove persona: %v", err)
+	}
+	for {
+		personas := waitForPersonas(t, changes)
+		if slices.Equal(personas, []string{"default"}) {
+			break
+		}
+	}
+
+	stop()
+	stop() // Stopping again does nothing
+	// Drop changes reported before stopping
+	for len(changes) > 0 {
+		<-changes
+	}
+	if err := os.WriteFile(m.GetPersonaPath("late"), []byte("Late content"), 0644); err != nil {
+		t.Fatalf("Failed to write persona: %v", err)
+	}
+	select {
+	case personas := <-changes:
+		t.Errorf("Expected no changes after stopping, got %v", personas)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatchPersonasDirMissing(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if _, err := m.WatchPersonasDir(func([]string) {}); err == nil {
+		t.Error("Expected an error watching a missing personas directory")
+	}
+}
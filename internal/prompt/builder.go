@@ -1,66 +1,337 @@
 package prompt
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"coding-prompts-tui/internal/filesystem"
+	"coding-prompts-tui/internal/persona"
 )
 
+// OutputFormat selects how a built prompt is serialized
+type OutputFormat string
+
+const (
+	OutputXML      OutputFormat = "xml"
+	OutputJSON     OutputFormat = "json"
+	OutputMarkdown OutputFormat = "markdown"
+)
+
+// Extension returns the file extension, without the dot, of prompts saved in the format
+func (f OutputFormat) Extension() string {
+	if f == OutputMarkdown {
+		return "md"
+	}
+	return string(f)
+}
+
 type cdata struct {
 	Text string `xml:",cdata"`
 }
 
 type File struct {
-	XMLName xml.Name `xml:"file"`
-	Name    string   `xml:"name,attr"`
-	Content string   `xml:",cdata"`
+	XMLName   xml.Name `xml:"file" json:"-"`
+	Name      string   `xml:"name,attr" json:"name"`
+	Lines     string   `xml:"lines,attr,omitempty" json:"lines,omitempty"`
+	Tag       string   `xml:"tag,attr,omitempty" json:"tag,omitempty"`             // Comma-separated tags the user gave the file
+	Binary    bool     `xml:"binary,attr,omitempty" json:"binary,omitempty"`       // Placeholder for a binary file; Content is empty
+	Diff      bool     `xml:"diff,attr,omitempty" json:"diff,omitempty"`           // Content holds the changes since the git HEAD
+	Untracked bool     `xml:"untracked,attr,omitempty" json:"untracked,omitempty"` // Included in full in diff mode as git doesn't track it
+	Bucket    string   `xml:"-" json:"bucket,omitempty"`                           // In XML, files are grouped by bucket instead
+	Content   string   `xml:",cdata" json:"content"`
+}
+
+// FileBucket groups the files the user put in the same bucket, e.g. "focus"
+type FileBucket struct {
+	Name  string `xml:"bucket,attr"`
+	Files []File `xml:"file"`
+}
+
+// LineRange limits a file to lines Start through End (1-based, inclusive).
+// A zero Start includes the whole file; a zero End reads to the end of the file.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// IsSet reports whether the range limits the file to a subset of its lines
+func (r LineRange) IsSet() bool {
+	return r.Start > 0
+}
+
+// String formats the range as "10-50", or "10-" when it reads to the end of the file
+func (r LineRange) String() string {
+	if !r.IsSet() {
+		return ""
+	}
+	if r.End == 0 {
+		return fmt.Sprintf("%d-", r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
 }
 
 type SystemPrompt struct {
-	XMLName xml.Name `xml:"SystemPrompt"`
-	Type    string   `xml:"type,attr,omitempty"`
-	Content string   `xml:",cdata"`
+	XMLName xml.Name `xml:"SystemPrompt" json:"-"`
+	Type    string   `xml:"type,attr,omitempty" json:"type"`
+	Content string   `xml:",cdata" json:"content"`
 }
 
 type Prompt struct {
 	XMLName      xml.Name       `xml:"prompt"`
 	FileTree     cdata          `xml:"filetree"`
 	Files        []File         `xml:"file"`
+	Buckets      []FileBucket   `xml:"files"`
 	SystemPrompt []SystemPrompt `xml:"SystemPrompt"`
 	UserPrompt   cdata          `xml:"UserPrompt"`
 }
 
+// jsonPrompt is the JSON representation of a Prompt
+type jsonPrompt struct {
+	FileTree      string         `json:"file_tree"`
+	Files         []File         `json:"files"`
+	SystemPrompts []SystemPrompt `json:"system_prompts"`
+	UserPrompt    string         `json:"user_prompt"`
+}
+
+// Build generates the prompt as XML
 func Build(rootPath string, selectedFiles map[string]bool, userPrompt string, activePersonas []string) (string, error) {
+	return BuildWithFormat(rootPath, selectedFiles, userPrompt, activePersonas, OutputXML)
+}
+
+// BuildWithFormat generates the prompt and serializes it in the requested format.
+// Selected files are included in path order.
+func BuildWithFormat(rootPath string, selectedFiles map[string]bool, userPrompt string, activePersonas []string, format OutputFormat) (string, error) {
+	var orderedFiles []string
+	for path, selected := range selectedFiles {
+		if selected {
+			orderedFiles = append(orderedFiles, path)
+		}
+	}
+	sort.Strings(orderedFiles)
+	return BuildOrdered(rootPath, orderedFiles, userPrompt, activePersonas, format)
+}
+
+// BuildOrdered generates the prompt with the selected files included in the given order
+func BuildOrdered(rootPath string, selectedFiles []string, userPrompt string, activePersonas []string, format OutputFormat) (string, error) {
+	return BuildOrderedWithRanges(rootPath, selectedFiles, nil, userPrompt, activePersonas, format)
+}
+
+// BuildOrderedWithRanges generates the prompt with the selected files included in the
+// given order. Files with an entry in lineRanges only include those lines.
+func BuildOrderedWithRanges(rootPath string, selectedFiles []string, lineRanges map[string]LineRange, userPrompt string, activePersonas []string, format OutputFormat) (string, error) {
+	return BuildWithOptions(rootPath, selectedFiles, userPrompt, activePersonas, format, BuildOptions{LineRanges: lineRanges})
+}
+
+// BuildOptions controls how the content of selected files is included in a prompt
+type BuildOptions struct {
+	// LineRanges limits the files with an entry to those lines
+	LineRanges map[string]LineRange
+	// Tags labels the files with an entry, e.g. as context or focus
+	Tags map[string][]string
+	// Buckets groups the files with an entry by bucket name, in order of each
+	// bucket's first file. Files without one are included ungrouped.
+	Buckets map[string]string
+	// StripComments removes comments from source files in the languages
+	// StripComments supports, to save tokens
+	StripComments bool
+	// CommentLanguages limits StripComments to these languages, e.g. "go";
+	// empty strips every supported language
+	CommentLanguages []string
+	// Cache serves the content of files that haven't changed since an earlier
+	// build; nil reads every file
+	Cache *FileCache
+	// MaxFileSize skips files larger than this many bytes. Zero means no limit.
+	MaxFileSize int64
+	// ModifiedSince skips files last modified before this time; nil includes
+	// files however old they are
+	ModifiedSince *time.Time
+	// Logger receives a warning for each skipped file; nil discards them
+	Logger *slog.Logger
+	// NormalizeEncoding transcodes files that aren't UTF-8, such as
+	// Windows-1252 ones, to UTF-8, noting their original encoding
+	NormalizeEncoding bool
+	// DiffMode includes the changes to files tracked by git since HEAD instead
+	// of their content. Files git doesn't track are included in full, marked
+	// untracked; files outside a repository are included in full unmarked.
+	DiffMode bool
+	// OverviewFiles are the files in the project root checked, in order, for
+	// the project overview; the first one found is included. Nil checks
+	// DefaultOverviewFiles.
+	OverviewFiles []string
+	// IncludeAllOverviewFiles includes every one of OverviewFiles found rather
+	// than only the first, separated by "---" lines
+	IncludeAllOverviewFiles bool
+}
+
+// DefaultOverviewFiles are the project overview files checked when no others are configured
+var DefaultOverviewFiles = []string{"CLAUDE.md", "GEMINI.md", "README.md", "AGENTS.md"}
+
+// BuildWithOptions generates the prompt with the selected files included in the given order
+func BuildWithOptions(rootPath string, selectedFiles []string, userPrompt string, activePersonas []string, format OutputFormat, opts BuildOptions) (string, error) {
+	prompt, err := assemble(rootPath, selectedFiles, userPrompt, activePersonas, opts)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case OutputXML, "":
+		xmlOutput, err := xml.MarshalIndent(prompt, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error marshalling to xml: %w", err)
+		}
+		return string(xmlOutput), nil
+	case OutputJSON:
+		jsonOutput, err := json.MarshalIndent(toJSONPrompt(prompt), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error marshalling to json: %w", err)
+		}
+		return string(jsonOutput), nil
+	case OutputMarkdown:
+		return toMarkdown(prompt), nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %q", format)
+	}
+}
+
+// toJSONPrompt converts a Prompt into its JSON representation. Files in a
+// bucket are listed after the others, labeled with their bucket.
+func toJSONPrompt(p Prompt) jsonPrompt {
+	files := append([]File{}, p.Files...)
+	for _, bucket := range p.Buckets {
+		for _, file := range bucket.Files {
+			file.Bucket = bucket.Name
+			files = append(files, file)
+		}
+	}
+	systemPrompts := p.SystemPrompt
+	if systemPrompts == nil {
+		systemPrompts = []SystemPrompt{}
+	}
+	return jsonPrompt{
+		FileTree:      p.FileTree.Text,
+		Files:         files,
+		SystemPrompts: systemPrompts,
+		UserPrompt:    p.UserPrompt.Text,
+	}
+}
+
+// assemble gathers the file tree, file contents and system prompts into a Prompt
+func assemble(rootPath string, selectedFiles []string, userPrompt string, activePersonas []string, opts BuildOptions) (Prompt, error) {
+	// Files unchanged since the filter time are left out, and marked in the file tree
+	unmodified := make(map[string]bool)
+	if opts.ModifiedSince != nil {
+		for _, path := range selectedFiles {
+			if info, err := os.Stat(path); err == nil && info.ModTime().Before(*opts.ModifiedSince) {
+				unmodified[filepath.Clean(path)] = true
+			}
+		}
+	}
+
 	// 1. Generate file tree
-	fileTree, err := generateFileTree(rootPath)
+	fileTree, err := generateFileTree(rootPath, unmodified)
 	if err != nil {
-		return "", fmt.Errorf("error generating file tree: %w", err)
+		return Prompt{}, fmt.Errorf("error generating file tree: %w", err)
 	}
 
 	// 2. Get selected file contents
 	var files []File
-	for path, selected := range selectedFiles {
-		if selected {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return "", fmt.Errorf("error reading file %s: %w", path, err)
+	var buckets []FileBucket
+	// add includes a file ungrouped or in the bucket path was put in
+	add := func(path string, file File) {
+		name := opts.Buckets[path]
+		if name == "" {
+			files = append(files, file)
+			return
+		}
+		for i := range buckets {
+			if buckets[i].Name == name {
+				buckets[i].Files = append(buckets[i].Files, file)
+				return
 			}
-			relativePath, err := filepath.Rel(rootPath, path)
-			if err != nil {
-				return "", fmt.Errorf("error getting relative path for %s: %w", path, err)
+		}
+		buckets = append(buckets, FileBucket{Name: name, Files: []File{file}})
+	}
+	lineRanges := opts.LineRanges
+	for _, path := range selectedFiles {
+		relativePath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return Prompt{}, fmt.Errorf("error getting relative path for %s: %w", path, err)
+		}
+
+		// Files over the size limit are left out
+		if opts.MaxFileSize > 0 {
+			if info, err := os.Stat(path); err == nil && info.Size() > opts.MaxFileSize {
+				if opts.Logger != nil {
+					opts.Logger.Warn("skipping file over the size limit", "component", "prompt", "event", "file_skipped",
+						"file", relativePath, "size", info.Size(), "limit", opts.MaxFileSize)
+				}
+				continue
+			}
+		}
+
+		if unmodified[filepath.Clean(path)] {
+			if opts.Logger != nil {
+				opts.Logger.Warn("skipping file not modified since the filter time", "component", "prompt", "event", "file_skipped",
+					"file", relativePath, "since", *opts.ModifiedSince)
 			}
-			files = append(files, File{Name: relativePath, Content: string(content)})
+			continue
+		}
+
+		// Binary files are included as placeholders rather than garbage content
+		binary, err := filesystem.IsBinaryFile(path)
+		if err != nil {
+			return Prompt{}, fmt.Errorf("error reading file %s: %w", path, err)
+		}
+		tag := strings.Join(opts.Tags[path], ",")
+		if binary {
+			add(path, File{Name: relativePath, Tag: tag, Binary: true})
+			continue
 		}
+
+		untracked := false
+		if opts.DiffMode {
+			diff, err := filesystem.GetFileDiff(rootPath, path)
+			if err == nil {
+				add(path, File{Name: relativePath, Tag: tag, Diff: true, Content: diff})
+				continue
+			}
+			// Only files git doesn't track are marked; files outside a repository
+			// or that can't be diffed are included in full as without diff mode
+			untracked = errors.Is(err, filesystem.ErrNotTracked)
+		}
+
+		content, err := readFileContent(path, lineRanges[path], opts.Cache)
+		if err != nil {
+			return Prompt{}, fmt.Errorf("error reading file %s: %w", path, err)
+		}
+		encoding := ""
+		if opts.NormalizeEncoding {
+			content, encoding = filesystem.NormalizeEncoding([]byte(content))
+		}
+		content = stripFileComments(path, relativePath, content, opts)
+		if encoding != "" {
+			content = filesystem.EncodingNote(encoding) + content
+		}
+		add(path, File{Name: relativePath, Lines: lineRanges[path].String(), Tag: tag, Untracked: untracked, Content: content})
 	}
 
 	var systemPrompts []SystemPrompt
 
 	// 3. Get project overview
-	overviewContent, err := getProjectOverview(rootPath)
+	overviewFiles := opts.OverviewFiles
+	if overviewFiles == nil {
+		overviewFiles = DefaultOverviewFiles
+	}
+	overviewContent, err := getProjectOverview(rootPath, overviewFiles, opts.IncludeAllOverviewFiles)
 	if err == nil && overviewContent != "" {
 		systemPrompts = append(systemPrompts, SystemPrompt{
 			Type:    "project-overview",
@@ -68,43 +339,100 @@ func Build(rootPath string, selectedFiles map[string]bool, userPrompt string, ac
 		})
 	}
 
-	// 4. Get system prompts for active personas
+	// 4. Get system prompts for active personas, expanding template variables
+	templateContext := NewTemplateContext(rootPath, selectedFiles, time.Now())
 	if len(activePersonas) == 0 {
 		activePersonas = []string{"default"}
 	}
 
-	for _, persona := range activePersonas {
-		personaPath := filepath.Join(rootPath, "personas", persona+".md")
-		systemPromptContent, err := os.ReadFile(personaPath)
-		if err != nil {
+	personaManager := persona.NewManager(rootPath)
+	for _, name := range activePersonas {
+		var systemPromptContent string
+		if !personaManager.PersonaExists(name) {
 			// If persona file doesn't exist, use a fallback
-			systemPromptContent = []byte(fmt.Sprintf("You are a helpful AI assistant with the %s persona.", persona))
+			systemPromptContent = fmt.Sprintf("You are a helpful AI assistant with the %s persona.", name)
+		} else {
+			// Include the content of any personas this one extends
+			systemPromptContent, err = personaManager.ResolvePersonaContent(name)
+			if err != nil {
+				return Prompt{}, fmt.Errorf("error resolving persona %s: %w", name, err)
+			}
+			systemPromptContent, err = RenderTemplate(systemPromptContent, templateContext)
+			if err != nil {
+				return Prompt{}, fmt.Errorf("error in persona %s: %w", name, err)
+			}
 		}
 		systemPrompts = append(systemPrompts, SystemPrompt{
-			Type:    persona,
-			Content: string(systemPromptContent),
+			Type:    name,
+			Content: systemPromptContent,
 		})
 	}
 
+	userPrompt, err = RenderTemplate(userPrompt, templateContext)
+	if err != nil {
+		return Prompt{}, fmt.Errorf("error in user prompt: %w", err)
+	}
+
 	// 5. Construct the prompt struct
-	prompt := Prompt{
+	return Prompt{
 		FileTree:     cdata{Text: fileTree},
 		Files:        files,
+		Buckets:      buckets,
 		SystemPrompt: systemPrompts,
 		UserPrompt:   cdata{Text: userPrompt},
-	}
+	}, nil
+}
 
-	// 6. Marshal to XML
-	xmlOutput, err := xml.MarshalIndent(prompt, "", "  ")
+// stripFileComments removes the comments from content when opts asks for it
+// and the language of path is one to strip. Files that can't be stripped are
+// included as they are.
+func stripFileComments(path, relativePath, content string, opts BuildOptions) string {
+	if !opts.StripComments {
+		return content
+	}
+	lang := filesystem.DetectLanguage(path)
+	if lang == "" || (len(opts.CommentLanguages) > 0 && !slices.Contains(opts.CommentLanguages, lang)) {
+		return content
+	}
+	stripped, err := StripComments(content, lang)
 	if err != nil {
-		return "", fmt.Errorf("error marshalling to xml: %w", err)
+		if opts.Logger != nil {
+			opts.Logger.Warn("keeping comments", "component", "prompt", "event", "strip_comments_failed",
+				"file", relativePath, "error", err)
+		}
+		return content
 	}
+	return stripped
+}
 
-	return string(xmlOutput), nil
+// readFileContent reads the whole file, or only the lines in lineRange when it
+// is set, through cache when there is one
+func readFileContent(path string, lineRange LineRange, cache *FileCache) (string, error) {
+	if cache != nil {
+		content, err := cache.Get(path)
+		if err != nil || !lineRange.IsSet() {
+			return content, err
+		}
+		return filesystem.ReadLinesFrom(strings.NewReader(content), path, lineRange.Start, lineRange.End)
+	}
+	if lineRange.IsSet() {
+		return filesystem.ReadLines(path, lineRange.Start, lineRange.End)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
 }
 
-func getProjectOverview(rootPath string) (string, error) {
-	overviewFiles := []string{"CLAUDE.md", "GEMINI.md", "README.md"}
+// overviewSeparator goes between overview files when all of them are included
+const overviewSeparator = "\n\n---\n\n"
+
+// getProjectOverview returns the content of the first of overviewFiles found in
+// rootPath, or "" if there are none. With includeAll, the content of every one
+// found is returned instead, in order and separated by "---" lines.
+func getProjectOverview(rootPath string, overviewFiles []string, includeAll bool) (string, error) {
+	var contents []string
 	for _, filename := range overviewFiles {
 		path := filepath.Join(rootPath, filename)
 		if _, err := os.Stat(path); err == nil {
@@ -112,63 +440,78 @@ func getProjectOverview(rootPath string) (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("error reading overview file %s: %w", path, err)
 			}
-			return string(content), nil
+			if !includeAll {
+				return string(content), nil
+			}
+			contents = append(contents, string(content))
 		}
 	}
-	return "", nil // No overview file found
+	if len(contents) == 1 {
+		return contents[0], nil
+	}
+	for i, content := range contents {
+		contents[i] = strings.TrimRight(content, "\n")
+	}
+	return strings.Join(contents, overviewSeparator), nil // Empty if no overview file was found
 }
 
-func generateFileTree(rootPath string) (string, error) {
+// generateFileTree lists the project under rootPath, marking the unmodified
+// files left out of the prompt by the modified-since filter
+func generateFileTree(rootPath string, unmodified map[string]bool) (string, error) {
 	// Try to use gitignore-aware generation
-	tree, err := generateFileTreeWithGitignore(rootPath)
+	tree, err := generateFileTreeWithGitignore(rootPath, unmodified)
 	if err != nil {
 		// Fall back to legacy generation if gitignore fails
-		return generateFileTreeLegacy(rootPath)
+		return generateFileTreeLegacy(rootPath, unmodified)
 	}
 	return tree, nil
 }
 
-func generateFileTreeWithGitignore(rootPath string) (string, error) {
-	// Create gitignore matcher
-	matcher, err := filesystem.NewGitignoreMatcher(rootPath)
+func generateFileTreeWithGitignore(rootPath string, unmodified map[string]bool) (string, error) {
+	// Create matcher combining .gitignore and .promptignore rules
+	matcher, err := filesystem.NewProjectMatcher(rootPath)
 	if err != nil {
 		return "", err
 	}
 
 	var tree strings.Builder
+	// Ignored directories walked because negation patterns may re-include
+	// something in them; they are listed once something in them is
+	var pendingDirs []string
 	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Don't apply ignore rules to the root directory itself
-		if path != rootPath {
-			// Skip ignored files and directories using gitignore patterns
-			if matcher.ShouldIgnore(path, info.IsDir()) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
+		if path == rootPath {
+			return nil
 		}
 
-		relPath, err := filepath.Rel(rootPath, path)
-		if err != nil {
-			return err
+		// Forget the ignored directories the walk has left
+		for len(pendingDirs) > 0 && !strings.HasPrefix(path, pendingDirs[len(pendingDirs)-1]+string(os.PathSeparator)) {
+			pendingDirs = pendingDirs[:len(pendingDirs)-1]
 		}
 
-		if relPath == "." {
-			return nil
+		// Skip ignored files and directories using .gitignore and .promptignore patterns
+		if matcher.ShouldIgnore(path, info.IsDir()) {
+			if !info.IsDir() {
+				return nil
+			}
+			if matcher.MayReinclude(path) {
+				pendingDirs = append(pendingDirs, path)
+				return nil
+			}
+			return filepath.SkipDir
 		}
 
-		depth := strings.Count(relPath, string(os.PathSeparator))
-		indent := strings.Repeat("  ", depth)
-		if info.IsDir() {
-			fmt.Fprintf(&tree, "%s- %s/\n", indent, info.Name())
-		} else {
-			fmt.Fprintf(&tree, "%s- %s\n", indent, info.Name())
+		for _, dir := range pendingDirs {
+			if err := writeTreeEntry(&tree, rootPath, dir, true, false); err != nil {
+				return err
+			}
 		}
-		return nil
+		pendingDirs = pendingDirs[:0]
+		return writeTreeEntry(&tree, rootPath, path, info.IsDir(), unmodified[path])
 	})
 	if err != nil {
 		return "", err
@@ -176,7 +519,27 @@ func generateFileTreeWithGitignore(rootPath string) (string, error) {
 	return tree.String(), nil
 }
 
-func generateFileTreeLegacy(rootPath string) (string, error) {
+// writeTreeEntry writes the line of the file tree for path, indented by its depth below rootPath
+func writeTreeEntry(tree *strings.Builder, rootPath, path string, isDir, unmodified bool) error {
+	relPath, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return err
+	}
+
+	depth := strings.Count(relPath, string(os.PathSeparator))
+	indent := strings.Repeat("  ", depth)
+	switch {
+	case isDir:
+		fmt.Fprintf(tree, "%s- %s/\n", indent, filepath.Base(path))
+	case unmodified:
+		fmt.Fprintf(tree, "%s- %s (unmodified)\n", indent, filepath.Base(path))
+	default:
+		fmt.Fprintf(tree, "%s- %s\n", indent, filepath.Base(path))
+	}
+	return nil
+}
+
+func generateFileTreeLegacy(rootPath string, unmodified map[string]bool) (string, error) {
 	var tree strings.Builder
 	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -194,23 +557,10 @@ func generateFileTreeLegacy(rootPath string) (string, error) {
 			}
 		}
 
-		relPath, err := filepath.Rel(rootPath, path)
-		if err != nil {
-			return err
-		}
-
-		if relPath == "." {
+		if path == rootPath {
 			return nil
 		}
-
-		depth := strings.Count(relPath, string(os.PathSeparator))
-		indent := strings.Repeat("  ", depth)
-		if info.IsDir() {
-			fmt.Fprintf(&tree, "%s- %s/\n", indent, info.Name())
-		} else {
-			fmt.Fprintf(&tree, "%s- %s\n", indent, info.Name())
-		}
-		return nil
+		return writeTreeEntry(&tree, rootPath, path, info.IsDir(), unmodified[path])
 	})
 	if err != nil {
 		return "", err
@@ -0,0 +1,131 @@
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// extLanguages maps file extensions to the language names Markdown renderers
+// use to highlight fenced code
+var extLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".jsx":   "jsx",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".swift": "swift",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".sh":    "bash",
+	".bash":  "bash",
+	".zsh":   "zsh",
+	".sql":   "sql",
+	".html":  "html",
+	".css":   "css",
+	".scss":  "scss",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".md":    "markdown",
+	".lua":   "lua",
+}
+
+// ExtToLanguage returns the code fence language for a file extension such as
+// ".go", or "" when it isn't known
+func ExtToLanguage(ext string) string {
+	return extLanguages[strings.ToLower(ext)]
+}
+
+// BuildMarkdown generates the prompt as Markdown, for pasting into
+// Markdown-aware tools. Selected files are included in path order.
+func BuildMarkdown(rootPath string, selectedFiles map[string]bool, userPrompt string, activePersonas []string) (string, error) {
+	return BuildWithFormat(rootPath, selectedFiles, userPrompt, activePersonas, OutputMarkdown)
+}
+
+// toMarkdown renders a Prompt as Markdown sections: system prompts, the file
+// tree, the files (a section per bucket after the ungrouped ones) and the user prompt
+func toMarkdown(p Prompt) string {
+	var b strings.Builder
+
+	b.WriteString("## System Prompts\n")
+	for _, systemPrompt := range p.SystemPrompt {
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", systemPrompt.Type, strings.TrimSpace(systemPrompt.Content))
+	}
+
+	b.WriteString("\n## File Tree\n\n")
+	writeCodeBlock(&b, "", p.FileTree.Text)
+
+	b.WriteString("\n## Files\n")
+	writeMarkdownFiles(&b, p.Files)
+	for _, bucket := range p.Buckets {
+		fmt.Fprintf(&b, "\n## Files (%s)\n", bucket.Name)
+		writeMarkdownFiles(&b, bucket.Files)
+	}
+
+	fmt.Fprintf(&b, "\n## Prompt\n\n%s\n", strings.TrimSpace(p.UserPrompt.Text))
+	return b.String()
+}
+
+// writeMarkdownFiles writes a heading and a fenced code block per file
+func writeMarkdownFiles(b *strings.Builder, files []File) {
+	for _, file := range files {
+		heading := file.Name
+		if file.Lines != "" {
+			heading += " (lines " + file.Lines + ")"
+		}
+		if file.Tag != "" {
+			heading += " [" + file.Tag + "]"
+		}
+		if file.Diff {
+			heading += " (changes since HEAD)"
+		} else if file.Untracked {
+			heading += " (untracked)"
+		}
+		fmt.Fprintf(b, "\n### %s\n\n", heading)
+		if file.Binary {
+			b.WriteString("_Binary file, content omitted._\n")
+			continue
+		}
+		lang := ExtToLanguage(filepath.Ext(file.Name))
+		if file.Diff {
+			lang = "diff"
+		}
+		writeCodeBlock(b, lang, file.Content)
+	}
+}
+
+// writeCodeBlock writes content in a code fence longer than any run of
+// backticks inside it, so the content can't close the fence early
+func writeCodeBlock(b *strings.Builder, lang, content string) {
+	longest, run := 0, 0
+	for _, c := range content {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+
+	b.WriteString(fence + lang + "\n")
+	b.WriteString(content)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(fence + "\n")
+}
@@ -0,0 +1,100 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildMarkdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	personasDir := filepath.Join(tmpDir, "personas")
+	if err := os.Mkdir(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(personasDir, "default.md"), []byte("You are a test assistant."), 0644); err != nil {
+		t.Fatalf("Failed to write persona: %v", err)
+	}
+	files := map[string]string{
+		"main.go":   "package main\n",
+		"notes.txt": "no trailing newline",
+		"README.md": "Example:\n\n```go\nfmt.Println()\n```\n",
+	}
+	selected := make(map[string]bool)
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		selected[path] = true
+	}
+
+	output, err := BuildMarkdown(tmpDir, selected, "Explain this.", []string{"default"})
+	if err != nil {
+		t.Fatalf("BuildMarkdown() returned an unexpected error: %v", err)
+	}
+
+	// The sections come in order
+	var headings []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			headings = append(headings, line)
+		}
+	}
+	expected := []string{"## System Prompts", "## File Tree", "## Files", "## Prompt"}
+	if strings.Join(headings, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected sections %v, got %v", expected, headings)
+	}
+	for _, want := range []string{
+		"### default\n\nYou are a test assistant.\n",
+		"### main.go\n\n```go\npackage main\n```\n",
+		"### notes.txt\n\n```\nno trailing newline\n```\n",
+		"### README.md\n\n````markdown\nExample:\n\n```go\nfmt.Println()\n```\n````\n",
+		"## Prompt\n\nExplain this.\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Every fence that opens a block is closed by the same fence
+	var open string
+	for _, line := range strings.Split(output, "\n") {
+		fence := strings.TrimRight(line, "abcdefghijklmnopqrstuvwxyz")
+		if fence == "" || strings.Trim(fence, "`") != "" || len(fence) < 3 {
+			continue
+		}
+		switch {
+		case open == "":
+			open = fence
+		case line == open:
+			open = ""
+		}
+	}
+	if open != "" {
+		t.Errorf("Expected every code fence to be closed, %s is left open in:\n%s", open, output)
+	}
+}
+
+func TestExtToLanguage(t *testing.T) {
+	for ext, want := range map[string]string{".go": "go", ".PY": "python", ".tsx": "tsx", ".unknown": "", "": ""} {
+		if got := ExtToLanguage(ext); got != want {
+			t.Errorf("ExtToLanguage(%q) = %q, want %q", ext, got, want)
+		}
+	}
+}
+
+func TestMarkdownDiffFiles(t *testing.T) {
+	output := toMarkdown(Prompt{Files: []File{
+		{Name: "main.go", Diff: true, Content: "+func main() {}\n"},
+		{Name: "new.go", Untracked: true, Content: "package main\n"},
+	}})
+
+	if !strings.Contains(output, "### main.go (changes since HEAD)\n\n```diff\n+func main() {}\n```") {
+		t.Errorf("Expected the diff of main.go in a diff block, got:\n%s", output)
+	}
+	if !strings.Contains(output, "### new.go (untracked)\n\n```go\npackage main\n```") {
+		t.Errorf("Expected new.go in full, marked untracked, got:\n%s", output)
+	}
+}
@@ -1,11 +1,16 @@
 package prompt
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestBuild(t *testing.T) {
@@ -601,7 +606,7 @@ func TestGetProjectOverview(t *testing.T) {
 			t.Fatalf("Failed to write README.md: %v", err)
 		}
 
-		content, err := getProjectOverview(tmpDir)
+		content, err := getProjectOverview(tmpDir, DefaultOverviewFiles, false)
 		if err != nil {
 			t.Fatalf("getProjectOverview returned error: %v", err)
 		}
@@ -626,7 +631,7 @@ func TestGetProjectOverview(t *testing.T) {
 			t.Fatalf("Failed to write README.md: %v", err)
 		}
 
-		content, err := getProjectOverview(tmpDir)
+		content, err := getProjectOverview(tmpDir, DefaultOverviewFiles, false)
 		if err != nil {
 			t.Fatalf("getProjectOverview returned error: %v", err)
 		}
@@ -645,7 +650,7 @@ func TestGetProjectOverview(t *testing.T) {
 			t.Fatalf("Failed to write README.md: %v", err)
 		}
 
-		content, err := getProjectOverview(tmpDir)
+		content, err := getProjectOverview(tmpDir, DefaultOverviewFiles, false)
 		if err != nil {
 			t.Fatalf("getProjectOverview returned error: %v", err)
 		}
@@ -658,7 +663,7 @@ func TestGetProjectOverview(t *testing.T) {
 	t.Run("no overview files exist", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
-		content, err := getProjectOverview(tmpDir)
+		content, err := getProjectOverview(tmpDir, DefaultOverviewFiles, false)
 		if err != nil {
 			t.Fatalf("getProjectOverview returned error: %v", err)
 		}
@@ -684,13 +689,110 @@ func TestGetProjectOverview(t *testing.T) {
 		}
 		defer os.Chmod(claudeFile, 0644) // Restore for cleanup
 
-		_, err = getProjectOverview(tmpDir)
+		_, err = getProjectOverview(tmpDir, DefaultOverviewFiles, false)
 		if err == nil {
 			t.Error("Expected error for unreadable overview file, got nil")
 		}
 	})
 }
 
+func TestGetProjectOverviewCustomFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"CLAUDE.md":        "This is CLAUDE.md",
+		"AGENTS.md":        "This is AGENTS.md",
+		"docs/OVERVIEW.md": "This is docs/OVERVIEW.md",
+		"CONTRIBUTING.md":  "This is CONTRIBUTING.md",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		overviewFiles []string
+		expected      string
+	}{
+		{"first match wins", []string{"MISSING.md", "AGENTS.md", "CLAUDE.md"}, "This is AGENTS.md"},
+		{"nested file", []string{"docs/OVERVIEW.md", "CLAUDE.md"}, "This is docs/OVERVIEW.md"},
+		{"default order", DefaultOverviewFiles, "This is CLAUDE.md"},
+		{"no match", []string{"MISSING.md"}, ""},
+		{"empty list", []string{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := getProjectOverview(tmpDir, tt.overviewFiles, false)
+			if err != nil {
+				t.Fatalf("getProjectOverview returned error: %v", err)
+			}
+			if content != tt.expected {
+				t.Errorf("Expected content '%s', got '%s'", tt.expected, content)
+			}
+		})
+	}
+
+	// The configured files are used when building prompts
+	output, err := BuildWithOptions(tmpDir, nil, "", []string{"default"}, OutputXML, BuildOptions{OverviewFiles: []string{"CONTRIBUTING.md"}})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "This is CONTRIBUTING.md") || strings.Contains(output, "This is CLAUDE.md") {
+		t.Errorf("Expected only CONTRIBUTING.md as the overview, got:\n%s", output)
+	}
+}
+
+func TestGetProjectOverviewIncludeAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"CLAUDE.md": "Claude instructions\n",
+		"GEMINI.md": "Gemini instructions",
+		"AGENTS.md": "Agent instructions\n\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		overviewFiles []string
+		expected      string
+	}{
+		{"every file in order", DefaultOverviewFiles, "Claude instructions\n\n---\n\nGemini instructions\n\n---\n\nAgent instructions"},
+		{"configured order", []string{"AGENTS.md", "MISSING.md", "CLAUDE.md"}, "Agent instructions\n\n---\n\nClaude instructions"},
+		{"single file unchanged", []string{"CLAUDE.md", "MISSING.md"}, "Claude instructions\n"},
+		{"no match", []string{"MISSING.md"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := getProjectOverview(tmpDir, tt.overviewFiles, true)
+			if err != nil {
+				t.Fatalf("getProjectOverview returned error: %v", err)
+			}
+			if content != tt.expected {
+				t.Errorf("Expected content %q, got %q", tt.expected, content)
+			}
+		})
+	}
+
+	output, err := BuildWithOptions(tmpDir, nil, "", []string{"default"}, OutputMarkdown, BuildOptions{IncludeAllOverviewFiles: true})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	for _, content := range files {
+		if !strings.Contains(output, strings.TrimSpace(content)) {
+			t.Errorf("Expected %q in the prompt, got:\n%s", strings.TrimSpace(content), output)
+		}
+	}
+}
+
 func TestFileTreeRespectsGitignore(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -856,3 +958,550 @@ test_*.go
 		t.Errorf("Generated XML is not well-formed: %v\nXML:\n%s", err, xmlOutput)
 	}
 }
+
+func TestFileTreeReincludesNegatedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "build/\n!build/important.txt\ndist/\n",
+		"build/important.txt": "keep me",
+		"build/output.bin":    "generated",
+		"build/cache/a.o":     "generated",
+		"dist/app.js":         "bundled",
+		"main.go":             "package main",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tree, err := generateFileTreeWithGitignore(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("generateFileTreeWithGitignore() returned an unexpected error: %v", err)
+	}
+
+	expected := "- .gitignore\n- build/\n  - important.txt\n- main.go\n"
+	if tree != expected {
+		t.Errorf("Expected only the re-included file of build/, got:\n%s", tree)
+	}
+}
+
+func TestBuildWithFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	personasDir := filepath.Join(tmpDir, "personas")
+	if err := os.Mkdir(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(personasDir, "default.md"), []byte("You are a test assistant."), 0644); err != nil {
+		t.Fatalf("Failed to write dummy system prompt: %v", err)
+	}
+	filePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(filePath, []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to write dummy file: %v", err)
+	}
+	selectedFiles := map[string]bool{filePath: true}
+
+	t.Run("json output", func(t *testing.T) {
+		output, err := BuildWithFormat(tmpDir, selectedFiles, "Explain this.", []string{"default"}, OutputJSON)
+		if err != nil {
+			t.Fatalf("BuildWithFormat() returned an unexpected error: %v", err)
+		}
+
+		var decoded struct {
+			FileTree string `json:"file_tree"`
+			Files    []struct {
+				Name    string `json:"name"`
+				Content string `json:"content"`
+			} `json:"files"`
+			SystemPrompts []struct {
+				Type    string `json:"type"`
+				Content string `json:"content"`
+			} `json:"system_prompts"`
+			UserPrompt string `json:"user_prompt"`
+		}
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\nOutput:\n%s", err, output)
+		}
+
+		if !strings.Contains(decoded.FileTree, "- main.go") {
+			t.Errorf("Expected file_tree to contain main.go, got %q", decoded.FileTree)
+		}
+		if len(decoded.Files) != 1 || decoded.Files[0].Name != "main.go" || decoded.Files[0].Content != "package main" {
+			t.Errorf("Unexpected files: %+v", decoded.Files)
+		}
+		if len(decoded.SystemPrompts) != 1 || decoded.SystemPrompts[0].Type != "default" {
+			t.Errorf("Unexpected system_prompts: %+v", decoded.SystemPrompts)
+		}
+		if decoded.UserPrompt != "Explain this." {
+			t.Errorf("Expected user_prompt 'Explain this.', got %q", decoded.UserPrompt)
+		}
+	})
+
+	t.Run("xml output matches Build", func(t *testing.T) {
+		xmlOutput, err := BuildWithFormat(tmpDir, selectedFiles, "Explain this.", []string{"default"}, OutputXML)
+		if err != nil {
+			t.Fatalf("BuildWithFormat() returned an unexpected error: %v", err)
+		}
+		buildOutput, err := Build(tmpDir, selectedFiles, "Explain this.", []string{"default"})
+		if err != nil {
+			t.Fatalf("Build() returned an unexpected error: %v", err)
+		}
+		if xmlOutput != buildOutput {
+			t.Error("Expected BuildWithFormat with OutputXML to match Build output")
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := BuildWithFormat(tmpDir, selectedFiles, "", nil, OutputFormat("yaml"))
+		if err == nil {
+			t.Error("Expected error for unsupported output format, got nil")
+		}
+	})
+}
+
+func TestFileTreeRespectsPromptignore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("*.log\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".promptignore"), []byte("schema.generated.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .promptignore: %v", err)
+	}
+	for _, name := range []string{"main.go", "schema.generated.go", "debug.log"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	fileTree, err := generateFileTree(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("generateFileTree() returned an unexpected error: %v", err)
+	}
+
+	if !strings.Contains(fileTree, "- main.go") {
+		t.Errorf("Expected file tree to contain main.go, got:\n%s", fileTree)
+	}
+	if strings.Contains(fileTree, "schema.generated.go") {
+		t.Errorf("Expected file tree to exclude .promptignore pattern, got:\n%s", fileTree)
+	}
+	if strings.Contains(fileTree, "debug.log") {
+		t.Errorf("Expected file tree to exclude .gitignore pattern, got:\n%s", fileTree)
+	}
+}
+
+func TestBuildOrdered(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var paths []string
+	for _, name := range []string{"b.go", "a.go", "c.go"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte("package "+strings.TrimSuffix(name, ".go")), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	output, err := BuildOrdered(tmpDir, paths, "", []string{"default"}, OutputXML)
+	if err != nil {
+		t.Fatalf("BuildOrdered() returned an unexpected error: %v", err)
+	}
+
+	b := strings.Index(output, `<file name="b.go">`)
+	a := strings.Index(output, `<file name="a.go">`)
+	c := strings.Index(output, `<file name="c.go">`)
+	if b < 0 || a < 0 || c < 0 {
+		t.Fatalf("Expected all files in output, got:\n%s", output)
+	}
+	if !(b < a && a < c) {
+		t.Errorf("Expected files in selection order b, a, c; got positions b=%d a=%d c=%d", b, a, c)
+	}
+}
+
+func TestBuildOrderedWithRanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	ranged := filepath.Join(tmpDir, "ranged.go")
+	whole := filepath.Join(tmpDir, "whole.go")
+	if err := os.WriteFile(ranged, []byte("line1\nline2\nline3\nline4\n"), 0644); err != nil {
+		t.Fatalf("Failed to write ranged.go: %v", err)
+	}
+	if err := os.WriteFile(whole, []byte("all of it\n"), 0644); err != nil {
+		t.Fatalf("Failed to write whole.go: %v", err)
+	}
+
+	lineRanges := map[string]LineRange{ranged: {Start: 2, End: 3}}
+	output, err := BuildOrderedWithRanges(tmpDir, []string{ranged, whole}, lineRanges, "", []string{"default"}, OutputXML)
+	if err != nil {
+		t.Fatalf("BuildOrderedWithRanges() returned an unexpected error: %v", err)
+	}
+
+	if !strings.Contains(output, `<file name="ranged.go" lines="2-3"><![CDATA[line2`+"\n"+`line3`+"\n"+`]]></file>`) {
+		t.Errorf("Expected only lines 2-3 of ranged.go, got:\n%s", output)
+	}
+	if !strings.Contains(output, `<file name="whole.go"><![CDATA[all of it`) {
+		t.Errorf("Expected whole.go without a line range, got:\n%s", output)
+	}
+
+	// A range starting past the end of the file is an error
+	lineRanges[ranged] = LineRange{Start: 10}
+	if _, err := BuildOrderedWithRanges(tmpDir, []string{ranged}, lineRanges, "", nil, OutputXML); err == nil {
+		t.Error("Expected error for a line range past the end of the file")
+	}
+}
+
+func TestBuildBinaryFilePlaceholder(t *testing.T) {
+	tmpDir := t.TempDir()
+	image := filepath.Join(tmpDir, "logo.png")
+	if err := os.WriteFile(image, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644); err != nil {
+		t.Fatalf("Failed to write logo.png: %v", err)
+	}
+
+	output, err := BuildOrdered(tmpDir, []string{image}, "", []string{"default"}, OutputXML)
+	if err != nil {
+		t.Fatalf("BuildOrdered() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="logo.png" binary="true"></file>`) {
+		t.Errorf("Expected a placeholder for the binary file, got:\n%s", output)
+	}
+	if strings.Contains(output, "IHDR") {
+		t.Errorf("Expected no binary content in the prompt, got:\n%s", output)
+	}
+
+	output, err = BuildOrdered(tmpDir, []string{image}, "", []string{"default"}, OutputJSON)
+	if err != nil {
+		t.Fatalf("BuildOrdered() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"binary": true`) {
+		t.Errorf("Expected a binary placeholder in the JSON prompt, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsSkipsLargeFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	small := filepath.Join(tmpDir, "small.go")
+	large := filepath.Join(tmpDir, "large.json")
+	if err := os.WriteFile(small, []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to write small.go: %v", err)
+	}
+	if err := os.WriteFile(large, []byte(strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatalf("Failed to write large.json: %v", err)
+	}
+
+	var logged strings.Builder
+	opts := BuildOptions{MaxFileSize: 1024, Logger: slog.New(slog.NewJSONHandler(&logged, nil))}
+	output, err := BuildWithOptions(tmpDir, []string{small, large}, "", []string{"default"}, OutputXML, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="small.go">`) {
+		t.Errorf("Expected small.go in the prompt, got:\n%s", output)
+	}
+	if strings.Contains(output, `<file name="large.json">`) {
+		t.Errorf("Expected large.json to be skipped, got:\n%s", output)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(logged.String()), &record); err != nil {
+		t.Fatalf("Expected a JSON log record, got %q: %v", logged.String(), err)
+	}
+	expected := map[string]any{"level": "WARN", "component": "prompt", "event": "file_skipped", "file": "large.json", "size": float64(2048), "limit": float64(1024)}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v in the log record, got %v", key, value, record[key])
+		}
+	}
+}
+
+func TestBuildWithOptionsModifiedSince(t *testing.T) {
+	tmpDir := t.TempDir()
+	since := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	modified := map[string]time.Time{
+		"older.go":  since.Add(-time.Second),
+		"exact.go":  since,
+		"newer.go":  since.Add(time.Second),
+		"later.go":  since.Add(48 * time.Hour),
+		"remote.go": since.In(time.FixedZone("UTC+5", 5*60*60)).Add(-time.Microsecond),
+	}
+	var files []string
+	for name, modTime := range modified {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte("package main"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set the times of %s: %v", name, err)
+		}
+		files = append(files, path)
+	}
+
+	output, err := BuildWithOptions(tmpDir, files, "", []string{"default"}, OutputXML, BuildOptions{ModifiedSince: &since})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+
+	// Files modified at exactly the filter time are kept, and time zones don't
+	// change the comparison
+	for name, included := range map[string]bool{"older.go": false, "exact.go": true, "newer.go": true, "later.go": true, "remote.go": false} {
+		if got := strings.Contains(output, `<file name="`+name+`">`); got != included {
+			t.Errorf("Expected %s included=%v, got:\n%s", name, included, output)
+		}
+		// Files left out stay in the file tree, marked as unmodified
+		if got := strings.Contains(output, "- "+name+" (unmodified)\n"); got == included {
+			t.Errorf("Expected %s marked unmodified=%v in the file tree, got:\n%s", name, !included, output)
+		}
+	}
+
+	// Without the filter every file is included
+	output, err = BuildWithOptions(tmpDir, files, "", []string{"default"}, OutputXML, BuildOptions{})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="older.go">`) || strings.Contains(output, "(unmodified)") {
+		t.Errorf("Expected older.go unmarked without a filter, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsTagsFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	tagged := filepath.Join(tmpDir, "tagged.go")
+	plain := filepath.Join(tmpDir, "plain.go")
+	for _, path := range []string{tagged, plain} {
+		if err := os.WriteFile(path, []byte("package main"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	opts := BuildOptions{Tags: map[string][]string{tagged: {"focus", "context"}}}
+	output, err := BuildWithOptions(tmpDir, []string{tagged, plain}, "", []string{"default"}, OutputXML, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="tagged.go" tag="focus,context">`) {
+		t.Errorf("Expected the tags of tagged.go as an attribute, got:\n%s", output)
+	}
+	if !strings.Contains(output, `<file name="plain.go">`) {
+		t.Errorf("Expected plain.go without a tag attribute, got:\n%s", output)
+	}
+
+	output, err = BuildWithOptions(tmpDir, []string{tagged}, "", []string{"default"}, OutputJSON, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"tag": "focus,context"`) {
+		t.Errorf("Expected the tags in the JSON prompt, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsDiffMode(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	tracked := filepath.Join(repo, "main.go")
+	untracked := filepath.Join(repo, "new.go")
+
+	git("init", "-q")
+	if err := os.WriteFile(tracked, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "Add main")
+	if err := os.WriteFile(tracked, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if err := os.WriteFile(untracked, []byte("package main // new\n"), 0644); err != nil {
+		t.Fatalf("Failed to write new.go: %v", err)
+	}
+
+	opts := BuildOptions{DiffMode: true}
+	output, err := BuildWithOptions(repo, []string{tracked, untracked}, "", []string{"default"}, OutputXML, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="main.go" diff="true"><![CDATA[diff --git a/main.go b/main.go`) ||
+		!strings.Contains(output, "+func main() {}") {
+		t.Errorf("Expected the diff of main.go instead of its content, got:\n%s", output)
+	}
+	if !strings.Contains(output, `<file name="new.go" untracked="true"><![CDATA[package main // new`) {
+		t.Errorf("Expected new.go in full, marked untracked, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsDiffModeOutsideGit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+
+	output, err := BuildWithOptions(dir, []string{path}, "", []string{"default"}, OutputXML, BuildOptions{DiffMode: true})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `<file name="main.go"><![CDATA[package main`) {
+		t.Errorf("Expected main.go in full without the untracked attribute, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsGroupsBuckets(t *testing.T) {
+	tmpDir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte("package main"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		paths = append(paths, path)
+	}
+
+	opts := BuildOptions{Buckets: map[string]string{
+		paths[0]: "focus",
+		paths[1]: "context",
+		paths[2]: "focus",
+	}}
+	output, err := BuildWithOptions(tmpDir, paths, "", []string{"default"}, OutputXML, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	var p struct {
+		Files []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"file"`
+		Buckets []struct {
+			Name  string `xml:"bucket,attr"`
+			Files []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"file"`
+		} `xml:"files"`
+	}
+	if err := xml.Unmarshal([]byte(output), &p); err != nil {
+		t.Fatalf("Output is not valid XML: %v\n%s", err, output)
+	}
+	if len(p.Files) != 1 || p.Files[0].Name != "d.go" {
+		t.Errorf("Expected d.go outside any bucket, got %+v", p.Files)
+	}
+	if len(p.Buckets) != 2 || p.Buckets[0].Name != "focus" || p.Buckets[1].Name != "context" {
+		t.Fatalf("Expected focus and context buckets in order of their first file, got %+v", p.Buckets)
+	}
+	if files := p.Buckets[0].Files; len(files) != 2 || files[0].Name != "a.go" || files[1].Name != "c.go" {
+		t.Errorf("Expected a.go and c.go in the focus bucket, got %+v", files)
+	}
+
+	output, err = BuildWithOptions(tmpDir, paths[:1], "", []string{"default"}, OutputJSON, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"bucket": "focus"`) {
+		t.Errorf("Expected the bucket in the JSON prompt, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsStripsComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	goFile := filepath.Join(tmpDir, "main.go")
+	shFile := filepath.Join(tmpDir, "run.sh")
+	files := map[string]string{
+		goFile: "package main // the main package\n",
+		shFile: "echo hi # greet\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	build := func(opts BuildOptions) string {
+		t.Helper()
+		output, err := BuildWithOptions(tmpDir, []string{goFile, shFile}, "", []string{"default"}, OutputXML, opts)
+		if err != nil {
+			t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+		}
+		return output
+	}
+
+	if output := build(BuildOptions{}); !strings.Contains(output, "the main package") {
+		t.Errorf("Expected comments to be kept by default, got:\n%s", output)
+	}
+
+	output := build(BuildOptions{StripComments: true})
+	if strings.Contains(output, "the main package") || strings.Contains(output, "greet") {
+		t.Errorf("Expected comments to be stripped, got:\n%s", output)
+	}
+	if !strings.Contains(output, "package main") || !strings.Contains(output, "echo hi") {
+		t.Errorf("Expected the code to be kept, got:\n%s", output)
+	}
+
+	output = build(BuildOptions{StripComments: true, CommentLanguages: []string{"shell"}})
+	if !strings.Contains(output, "the main package") || strings.Contains(output, "greet") {
+		t.Errorf("Expected only shell comments to be stripped, got:\n%s", output)
+	}
+}
+
+func TestBuildWithOptionsNormalizesEncoding(t *testing.T) {
+	tmpDir := t.TempDir()
+	latinFile := filepath.Join(tmpDir, "latin1.txt")
+	if err := os.WriteFile(latinFile, []byte("caf\xe9 cr\xe8me\n"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", latinFile, err)
+	}
+
+	output, err := BuildWithOptions(tmpDir, []string{latinFile}, "", []string{"default"}, OutputXML, BuildOptions{NormalizeEncoding: true})
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an unexpected error: %v", err)
+	}
+	if !utf8.ValidString(output) {
+		t.Fatalf("Expected the prompt to be valid UTF-8, got %q", output)
+	}
+	if !strings.Contains(output, "<!-- original encoding: iso-8859-1 -->\ncafé crème") {
+		t.Errorf("Expected the transcoded content after an encoding note, got:\n%s", output)
+	}
+	if err := xml.Unmarshal([]byte(output), new(struct{})); err != nil {
+		t.Errorf("Expected valid XML, got %v:\n%s", err, output)
+	}
+}
+
+func TestBuildResolvesPersonaInheritance(t *testing.T) {
+	tmpDir := t.TempDir()
+	personasDir := filepath.Join(tmpDir, "personas")
+	if err := os.Mkdir(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas dir: %v", err)
+	}
+	personas := map[string]string{
+		"base":     "Base rules",
+		"reviewer": "---\nextends: base\n---\nReview rules",
+		"loop":     "---\nextends: loop\n---\nLoop",
+	}
+	for name, content := range personas {
+		if err := os.WriteFile(filepath.Join(personasDir, name+".md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write persona %s: %v", name, err)
+		}
+	}
+
+	output, err := BuildOrdered(tmpDir, nil, "", []string{"reviewer"}, OutputJSON)
+	if err != nil {
+		t.Fatalf("BuildOrdered failed: %v", err)
+	}
+	var result jsonPrompt
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	if len(result.SystemPrompts) != 1 || result.SystemPrompts[0].Content != "Base rules\n\n---\n\nReview rules" {
+		t.Errorf("Expected inherited persona content, got %+v", result.SystemPrompts)
+	}
+
+	if _, err := BuildOrdered(tmpDir, nil, "", []string{"loop"}, OutputXML); err == nil {
+		t.Error("Expected error for a persona inheritance cycle")
+	}
+}
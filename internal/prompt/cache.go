@@ -0,0 +1,63 @@
+package prompt
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// cacheEntry is the content of a file as it was when it was read
+type cacheEntry struct {
+	content string
+	modTime time.Time
+	size    int64
+}
+
+// FileCache keeps the content of files read while building prompts, so files
+// that haven't changed aren't read again on every build. It is safe for
+// concurrent use.
+type FileCache struct {
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewFileCache creates an empty file cache
+func NewFileCache() *FileCache {
+	return &FileCache{entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the content of the file at path. The file is only read again
+// when it has a newer modification time, or a different size, than when it
+// was last read.
+func (c *FileCache) Get(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		c.Invalidate(path)
+		return "", err
+	}
+
+	c.mutex.Lock()
+	entry, ok := c.entries[path]
+	c.mutex.Unlock()
+	if ok && !info.ModTime().After(entry.modTime) && info.Size() == entry.size {
+		return entry.content, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.Invalidate(path)
+		return "", err
+	}
+
+	c.mutex.Lock()
+	c.entries[path] = cacheEntry{content: string(data), modTime: info.ModTime(), size: info.Size()}
+	c.mutex.Unlock()
+	return string(data), nil
+}
+
+// Invalidate drops the cached content of path, so the next Get reads it again
+func (c *FileCache) Invalidate(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, path)
+}
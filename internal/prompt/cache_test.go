@@ -0,0 +1,87 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	modTime := time.Now().Add(-time.Hour)
+	writeFile := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set the modification time: %v", err)
+		}
+	}
+	cache := NewFileCache()
+
+	writeFile("one", modTime)
+	if content, err := cache.Get(path); err != nil || content != "one" {
+		t.Fatalf("Get() = %q, %v; want \"one\"", content, err)
+	}
+
+	// Same modification time and size: the cached content is returned
+	writeFile("two", modTime)
+	if content, _ := cache.Get(path); content != "one" {
+		t.Errorf("Expected an unchanged file not to be read again, got %q", content)
+	}
+
+	// A newer modification time reads the file again
+	writeFile("two", modTime.Add(time.Second))
+	if content, _ := cache.Get(path); content != "two" {
+		t.Errorf("Expected a modified file to be read again, got %q", content)
+	}
+
+	// Invalidate forces a read even without a change
+	writeFile("six", modTime.Add(time.Second))
+	cache.Invalidate(path)
+	if content, _ := cache.Get(path); content != "six" {
+		t.Errorf("Expected an invalidated file to be read again, got %q", content)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if _, err := cache.Get(path); err == nil {
+		t.Error("Expected an error for a removed file")
+	}
+}
+
+func TestBuildWithOptionsUsesCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	modTime := time.Now().Add(-time.Hour)
+	if err := os.WriteFile(path, []byte("line 1\nline 2\nline 3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set the modification time: %v", err)
+	}
+
+	opts := BuildOptions{Cache: NewFileCache(), LineRanges: map[string]LineRange{path: {Start: 2, End: 2}}}
+	if _, err := BuildWithOptions(dir, []string{path}, "", nil, OutputXML, opts); err != nil {
+		t.Fatalf("BuildWithOptions() returned an error: %v", err)
+	}
+
+	// Line ranges are cut from the cached content
+	if err := os.WriteFile(path, []byte("LINE 1\nLINE 2\nLINE 3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set the modification time: %v", err)
+	}
+	prompt, err := BuildWithOptions(dir, []string{path}, "", nil, OutputXML, opts)
+	if err != nil {
+		t.Fatalf("BuildWithOptions() returned an error: %v", err)
+	}
+	if !strings.Contains(prompt, "line 2") || strings.Contains(prompt, "line 1") {
+		t.Errorf("Expected line 2 of the cached content, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,166 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is the kind of edit a diff line represents
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+// diffLine is a line of a diff together with its position in the old and new text
+type diffLine struct {
+	op      diffOp
+	text    string
+	oldLine int // 1-based line in the old text, zero for insertions
+	newLine int // 1-based line in the new text, zero for deletions
+}
+
+// DiffPrompts returns a line-level diff from prompt a to prompt b. Removed lines
+// are prefixed with "- ", added lines with "+ " and unchanged context lines with
+// two spaces. Each group of changes starts with a "@@ -old +new @@" header giving
+// its first line in a and b. It returns an empty string when the prompts are equal.
+func DiffPrompts(a, b string) string {
+	lines := myersDiff(splitLines(a), splitLines(b))
+
+	// Mark the lines to show: every change and the context around it
+	show := make([]bool, len(lines))
+	changed := false
+	for i, line := range lines {
+		if line.op == diffEqual {
+			continue
+		}
+		changed = true
+		for j := max(0, i-diffContext); j <= min(len(lines)-1, i+diffContext); j++ {
+			show[j] = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	for i, line := range lines {
+		if !show[i] {
+			continue
+		}
+		if i == 0 || !show[i-1] {
+			fmt.Fprintf(&out, "@@ -%d +%d @@\n", hunkStart(lines, i, true), hunkStart(lines, i, false))
+		}
+		switch line.op {
+		case diffDelete:
+			out.WriteString("- ")
+		case diffInsert:
+			out.WriteString("+ ")
+		default:
+			out.WriteString("  ")
+		}
+		out.WriteString(line.text)
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// hunkStart returns the first line number in the old (or new) text of the hunk starting at index i
+func hunkStart(lines []diffLine, i int, old bool) int {
+	for ; i < len(lines); i++ {
+		if old && lines[i].oldLine > 0 {
+			return lines[i].oldLine
+		}
+		if !old && lines[i].newLine > 0 {
+			return lines[i].newLine
+		}
+	}
+	return 0
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// myersDiff computes the shortest edit script turning a into b with Myers'
+// O(ND) algorithm, returned as the full sequence of equal, deleted and
+// inserted lines
+func myersDiff(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	maxEdits := n + m
+	offset := maxEdits + 1
+	v := make([]int, 2*maxEdits+3)
+
+	// trace[d] holds the furthest reaching x for each diagonal k before step d
+	var trace [][]int
+	for d := 0; d <= maxEdits; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Move down: insertion
+			} else {
+				x = v[offset+k-1] + 1 // Move right: deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackDiff walks the trace of myersDiff back from the end of both texts
+// to recover the edits
+func backtrackDiff(trace [][]int, a, b []string, offset int) []diffLine {
+	var reversed []diffLine
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffLine{op: diffEqual, text: a[x-1], oldLine: x, newLine: y})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffLine{op: diffInsert, text: b[y-1], newLine: y})
+			} else {
+				reversed = append(reversed, diffLine{op: diffDelete, text: a[x-1], oldLine: x})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
@@ -0,0 +1,53 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffPrompts(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"both empty", "", "", ""},
+		{"added to empty", "", "a\n", "@@ -0 +1 @@\n+ a\n"},
+		{"line changed", "a\nb\nc\n", "a\nx\nc\n", "@@ -1 +1 @@\n  a\n- b\n+ x\n  c\n"},
+		{"line added", "a\nc\n", "a\nb\nc\n", "@@ -1 +1 @@\n  a\n+ b\n  c\n"},
+		{"line removed", "a\nb\nc\n", "a\nc\n", "@@ -1 +1 @@\n  a\n- b\n  c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffPrompts(tt.a, tt.b); got != tt.expected {
+				t.Errorf("DiffPrompts(%q, %q) =\n%q\nexpected\n%q", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffPromptsHunks(t *testing.T) {
+	var old, updated []string
+	for i := 1; i <= 20; i++ {
+		line := strings.Repeat("x", i)
+		old = append(old, line)
+		if i == 2 || i == 18 {
+			line = "changed"
+		}
+		updated = append(updated, line)
+	}
+
+	diff := DiffPrompts(strings.Join(old, "\n"), strings.Join(updated, "\n"))
+	// Distant changes get their own hunks, and unchanged lines far from a change are left out
+	if strings.Count(diff, "@@ ") != 2 || !strings.Contains(diff, "@@ -15 +15 @@") {
+		t.Errorf("Expected two hunks, got:\n%s", diff)
+	}
+	if strings.Contains(diff, "\n  "+strings.Repeat("x", 10)+"\n") {
+		t.Errorf("Expected line 10 to be left out, got:\n%s", diff)
+	}
+	if strings.Count(diff, "- ") != 2 || strings.Count(diff, "+ changed") != 2 {
+		t.Errorf("Expected two changed lines, got:\n%s", diff)
+	}
+}
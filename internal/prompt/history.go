@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHistorySize is the number of generated prompts kept in history
+	DefaultHistorySize = 20
+	// historyLabelLength is the maximum length of an entry's label
+	historyLabelLength = 60
+)
+
+// HistoryEntry is a previously generated prompt
+type HistoryEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Label      string    `json:"label"`       // First characters of the user prompt
+	UserPrompt string    `json:"user_prompt"` // User prompt the entry was generated from
+	Content    string    `json:"content"`     // Full generated prompt
+}
+
+// PromptHistory keeps the most recently generated prompts, dropping the
+// oldest entry once the capacity is reached
+type PromptHistory struct {
+	path     string
+	capacity int
+	entries  []HistoryEntry // Oldest first
+}
+
+// NewPromptHistory creates an empty history persisted at path
+func NewPromptHistory(path string, capacity int) *PromptHistory {
+	if capacity <= 0 {
+		capacity = DefaultHistorySize
+	}
+	return &PromptHistory{
+		path:     path,
+		capacity: capacity,
+	}
+}
+
+// LoadPromptHistory reads the history persisted at path. A missing file results in an empty history.
+func LoadPromptHistory(path string, capacity int) (*PromptHistory, error) {
+	h := NewPromptHistory(path, capacity)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		return h, err
+	}
+	h.trim()
+	return h, nil
+}
+
+// Push records a generated prompt, evicting the oldest entry if the history is full
+func (h *PromptHistory) Push(userPrompt, content string) {
+	h.entries = append(h.entries, HistoryEntry{
+		Timestamp:  time.Now(),
+		Label:      historyLabel(userPrompt),
+		UserPrompt: userPrompt,
+		Content:    content,
+	})
+	h.trim()
+}
+
+// Entries returns the history newest first
+func (h *PromptHistory) Entries() []HistoryEntry {
+	entries := make([]HistoryEntry, len(h.entries))
+	for i, entry := range h.entries {
+		entries[len(h.entries)-1-i] = entry
+	}
+	return entries
+}
+
+// Len returns the number of entries in the history
+func (h *PromptHistory) Len() int {
+	return len(h.entries)
+}
+
+// Save writes the history to disk
+func (h *PromptHistory) Save() error {
+	data, err := json.MarshalIndent(h.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, data, 0o644)
+}
+
+// trim drops the oldest entries beyond the capacity
+func (h *PromptHistory) trim() {
+	if len(h.entries) > h.capacity {
+		h.entries = append([]HistoryEntry{}, h.entries[len(h.entries)-h.capacity:]...)
+	}
+}
+
+// historyLabel derives a single-line label from the first characters of the user prompt
+func historyLabel(userPrompt string) string {
+	label := strings.Join(strings.Fields(userPrompt), " ")
+	if label == "" {
+		return "(empty prompt)"
+	}
+	runes := []rune(label)
+	if len(runes) > historyLabelLength {
+		return string(runes[:historyLabelLength])
+	}
+	return label
+}
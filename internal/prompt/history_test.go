@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptHistoryCapacity(t *testing.T) {
+	history := NewPromptHistory(filepath.Join(t.TempDir(), "history.json"), 3)
+
+	for i := 1; i <= 5; i++ {
+		history.Push(fmt.Sprintf("prompt %d", i), fmt.Sprintf("content %d", i))
+	}
+
+	if history.Len() != 3 {
+		t.Fatalf("Expected history to be capped at 3 entries, got %d", history.Len())
+	}
+
+	entries := history.Entries()
+	expected := []string{"prompt 5", "prompt 4", "prompt 3"}
+	for i, label := range expected {
+		if entries[i].Label != label {
+			t.Errorf("Expected entry %d to be %q, got %q", i, label, entries[i].Label)
+		}
+	}
+}
+
+func TestPromptHistoryLabel(t *testing.T) {
+	history := NewPromptHistory(filepath.Join(t.TempDir(), "history.json"), 0)
+
+	history.Push(strings.Repeat("a", 100), "content")
+	history.Push("  multi\nline   prompt ", "content")
+	history.Push("", "content")
+
+	entries := history.Entries()
+	if entries[0].Label != "(empty prompt)" {
+		t.Errorf("Expected empty prompt label, got %q", entries[0].Label)
+	}
+	if entries[1].Label != "multi line prompt" {
+		t.Errorf("Expected whitespace to be collapsed, got %q", entries[1].Label)
+	}
+	if len(entries[2].Label) != 60 {
+		t.Errorf("Expected label to be truncated to 60 characters, got %d", len(entries[2].Label))
+	}
+}
+
+func TestPromptHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "workspace.json")
+
+	history, err := LoadPromptHistory(path, DefaultHistorySize)
+	if err != nil {
+		t.Fatalf("Expected no error loading missing history, got: %v", err)
+	}
+	if history.Len() != 0 {
+		t.Fatalf("Expected empty history, got %d entries", history.Len())
+	}
+
+	history.Push("first", "<prompt>first</prompt>")
+	history.Push("second", "<prompt>second</prompt>")
+	if err := history.Save(); err != nil {
+		t.Fatalf("Failed to save history: %v", err)
+	}
+
+	restored, err := LoadPromptHistory(path, DefaultHistorySize)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	entries := restored.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 restored entries, got %d", len(entries))
+	}
+	if entries[0].UserPrompt != "second" || entries[0].Content != "<prompt>second</prompt>" {
+		t.Errorf("Unexpected newest entry: %+v", entries[0])
+	}
+	if entries[1].Timestamp.IsZero() {
+		t.Error("Expected timestamp to be restored")
+	}
+}
@@ -0,0 +1,254 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commentSyntax describes the comments and string literals of a language, so
+// comment markers inside strings are left alone
+type commentSyntax struct {
+	lineComment   string // Starts a comment running to the end of the line
+	blockComments bool   // /* ... */ comments
+	quotes        string // Characters that open and close a string
+	rawQuotes     string // Quotes whose strings have no escapes
+	multiline     string // Quotes whose strings may span lines
+	tripleQuotes  bool   // Python """ and ''' strings; statement-level ones are docstrings
+	wordComments  bool   // The line comment only starts at the beginning of a word, as in shell
+	regexLiterals bool   // JavaScript /regex/ literals
+}
+
+// commentSyntaxes lists the languages StripComments supports, by the names
+// filesystem.DetectLanguage returns
+var commentSyntaxes = map[string]commentSyntax{
+	"go": {
+		lineComment:   "//",
+		blockComments: true,
+		quotes:        "\"'`",
+		rawQuotes:     "`",
+		multiline:     "`",
+	},
+	"python": {
+		lineComment:  "#",
+		quotes:       "\"'",
+		tripleQuotes: true,
+	},
+	"javascript": {
+		lineComment:   "//",
+		blockComments: true,
+		quotes:        "\"'`",
+		multiline:     "`",
+		regexLiterals: true,
+	},
+	"shell": {
+		lineComment:  "#",
+		quotes:       "\"'",
+		rawQuotes:    "'",
+		multiline:    "\"'",
+		wordComments: true,
+	},
+}
+
+// StripComments removes the comments from src, written in lang ("go",
+// "python", "javascript" or "shell"). Python docstrings are removed as well,
+// and a leading #! line is kept. Lines left empty by a removed comment are
+// dropped; other blank lines are kept. It returns an error for other
+// languages and for unterminated block comments and docstrings.
+func StripComments(src, lang string) (string, error) {
+	syntax, ok := commentSyntaxes[lang]
+	if !ok {
+		return "", fmt.Errorf("unsupported language %q", lang)
+	}
+
+	var out strings.Builder
+	stripped := make(map[int]bool) // Output lines a comment was removed from
+	line := 0
+	n := len(src)
+
+	for i := 0; i < n; {
+		c := src[i]
+		rest := src[i:]
+
+		switch {
+		case c == '\n':
+			out.WriteByte(c)
+			line++
+			i++
+
+		case i == 0 && strings.HasPrefix(src, "#!"):
+			// Keep the interpreter line
+			end := lineEnd(src, i)
+			out.WriteString(src[i:end])
+			i = end
+
+		case strings.HasPrefix(rest, syntax.lineComment) && (!syntax.wordComments || atWordStart(out.String())):
+			stripped[line] = true
+			i = lineEnd(src, i)
+
+		case syntax.blockComments && strings.HasPrefix(rest, "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return "", fmt.Errorf("unterminated block comment")
+			}
+			comment := src[i : i+2+end+2]
+			stripped[line] = true
+			// A comment spanning lines still separates the code around it
+			if strings.Contains(comment, "\n") {
+				out.WriteByte('\n')
+				line++
+				stripped[line] = true
+			}
+			i += len(comment)
+
+		case syntax.tripleQuotes && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''")):
+			end := closingQuote(src, i+3, rest[:3], false)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated string")
+			}
+			end += 3
+			if atLineStart(out.String()) && onlyCommentFollows(src, end, syntax.lineComment) {
+				// A docstring: an expression statement of its own
+				stripped[line] = true
+			} else {
+				out.WriteString(src[i:end])
+				line += strings.Count(src[i:end], "\n")
+			}
+			i = end
+
+		case strings.IndexByte(syntax.quotes, c) >= 0:
+			quote := src[i : i+1]
+			raw := strings.Contains(syntax.rawQuotes, quote)
+			end := closingQuote(src, i+1, quote, raw)
+			if end < 0 || (!strings.Contains(syntax.multiline, quote) && strings.Contains(src[i:end], "\n")) {
+				// Unterminated: leave the rest of the line as it is
+				end = lineEnd(src, i)
+			} else {
+				end++
+			}
+			out.WriteString(src[i:end])
+			line += strings.Count(src[i:end], "\n")
+			i = end
+
+		case syntax.regexLiterals && c == '/' && regexAllowed(out.String()):
+			end := regexEnd(src, i)
+			out.WriteString(src[i:end])
+			i = end
+
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+
+	// Drop the lines that held nothing but a comment
+	lines := strings.Split(out.String(), "\n")
+	kept := lines[:0]
+	for n, text := range lines {
+		if stripped[n] {
+			text = strings.TrimRight(text, " \t\r")
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+		}
+		kept = append(kept, text)
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+// lineEnd returns the index of the newline ending the line at i, or len(src)
+func lineEnd(src string, i int) int {
+	if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(src)
+}
+
+// closingQuote returns the index of the quote closing a string whose content
+// starts at i, skipping escaped characters unless raw, or -1 if there is none
+func closingQuote(src string, i int, quote string, raw bool) int {
+	for i < len(src) {
+		if !raw && src[i] == '\\' {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(src[i:], quote) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// atWordStart reports whether the next character begins a shell word
+func atWordStart(out string) bool {
+	if out == "" {
+		return true
+	}
+	return strings.ContainsRune(" \t\n;&|()", rune(out[len(out)-1]))
+}
+
+// atLineStart reports whether only whitespace was written since the last newline
+func atLineStart(out string) bool {
+	return strings.TrimLeft(out[strings.LastIndexByte(out, '\n')+1:], " \t") == ""
+}
+
+// onlyCommentFollows reports whether the line holds nothing after i but
+// whitespace and possibly a comment
+func onlyCommentFollows(src string, i int, lineComment string) bool {
+	rest := strings.TrimLeft(src[i:lineEnd(src, i)], " \t\r")
+	return rest == "" || strings.HasPrefix(rest, lineComment)
+}
+
+// regexAllowed reports whether a / following out starts a regular expression
+// literal rather than a division, judging by the token before it
+func regexAllowed(out string) bool {
+	trimmed := strings.TrimRight(out, " \t\n\r")
+	if trimmed == "" {
+		return true
+	}
+	if strings.ContainsRune("(,=:[!&|?{};+-*%<>~^", rune(trimmed[len(trimmed)-1])) {
+		return true
+	}
+	for _, keyword := range []string{"return", "typeof", "case", "else"} {
+		if strings.HasSuffix(trimmed, keyword) {
+			before := strings.TrimSuffix(trimmed, keyword)
+			if before == "" || !isIdentChar(before[len(before)-1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regexEnd returns the index just past the regular expression literal at i,
+// including its flags. An unterminated literal ends at the end of the line.
+func regexEnd(src string, i int) int {
+	inClass := false
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			return j
+		case '/':
+			if inClass {
+				continue
+			}
+			j++
+			for j < len(src) && isIdentChar(src[j]) {
+				j++
+			}
+			return j
+		}
+	}
+	return len(src)
+}
+
+// isIdentChar reports whether c can be part of an identifier
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
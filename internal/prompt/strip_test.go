@@ -0,0 +1,141 @@
+package prompt
+
+import "testing"
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+		want string
+	}{
+		// Go
+		{
+			name: "go line comments",
+			lang: "go",
+			src:  "package main\n\n// Doc comment\nfunc main() {\n\tx := 1 // trailing\n\t// whole line\n}\n",
+			want: "package main\n\nfunc main() {\n\tx := 1\n}\n",
+		},
+		{
+			name: "go block comments",
+			lang: "go",
+			src:  "/*\n * License\n */\npackage main\n\nvar x = 1 /* inline */ + 2\n",
+			want: "package main\n\nvar x = 1  + 2\n",
+		},
+		{
+			name: "go block comment spanning lines keeps the code apart",
+			lang: "go",
+			src:  "a() /* one\ntwo */ b()\n",
+			want: "a()\n b()\n",
+		},
+		{
+			name: "go comment markers in strings",
+			lang: "go",
+			src:  "s := \"// not a comment\" // comment\nr := `/* raw\n// still raw */`\nq := \"esc \\\" // quote\"\nc := '/'\n",
+			want: "s := \"// not a comment\"\nr := `/* raw\n// still raw */`\nq := \"esc \\\" // quote\"\nc := '/'\n",
+		},
+		{
+			name: "go blank lines are kept",
+			lang: "go",
+			src:  "a\n\n\nb\n",
+			want: "a\n\n\nb\n",
+		},
+
+		// Python
+		{
+			name: "python hash comments",
+			lang: "python",
+			src:  "#!/usr/bin/env python3\n# comment\nx = 1  # trailing\n",
+			want: "#!/usr/bin/env python3\nx = 1\n",
+		},
+		{
+			name: "python docstrings",
+			lang: "python",
+			src:  "def f():\n    \"\"\"Docstring\n    over lines.\"\"\"\n    return 1\n\nclass C:\n    '''Single line.'''\n",
+			want: "def f():\n    return 1\n\nclass C:\n",
+		},
+		{
+			name: "python triple-quoted strings that aren't docstrings",
+			lang: "python",
+			src:  "sql = \"\"\"\nSELECT 1 -- # not a comment\n\"\"\"\n",
+			want: "sql = \"\"\"\nSELECT 1 -- # not a comment\n\"\"\"\n",
+		},
+		{
+			name: "python hash in strings",
+			lang: "python",
+			src:  "s = \"#hash\" + '#also' # comment\nt = \"it's \\\"#\\\"\"\n",
+			want: "s = \"#hash\" + '#also'\nt = \"it's \\\"#\\\"\"\n",
+		},
+
+		// JavaScript
+		{
+			name: "javascript comments",
+			lang: "javascript",
+			src:  "/** JSDoc */\nconst a = 1; // trailing\n/* block */ const b = 2;\n",
+			want: "const a = 1;\n const b = 2;\n",
+		},
+		{
+			name: "javascript strings and templates",
+			lang: "javascript",
+			src:  "const url = \"http://example.com\";\nconst t = `line // one\n/* two */`;\nconst s = 'it\\'s // fine';\n",
+			want: "const url = \"http://example.com\";\nconst t = `line // one\n/* two */`;\nconst s = 'it\\'s // fine';\n",
+		},
+		{
+			name: "javascript regex literals",
+			lang: "javascript",
+			src:  "const re = /\\/\\/|[/*]/g; // comment\nconst half = total / 2; // division\nreturn /a\\/b/.test(x);\n",
+			want: "const re = /\\/\\/|[/*]/g;\nconst half = total / 2;\nreturn /a\\/b/.test(x);\n",
+		},
+
+		// Shell
+		{
+			name: "shell comments",
+			lang: "shell",
+			src:  "#!/bin/sh\n# comment\necho hi # trailing\necho done;# after semicolon\n",
+			want: "#!/bin/sh\necho hi\necho done;\n",
+		},
+		{
+			name: "shell hash that isn't a comment",
+			lang: "shell",
+			src:  "echo $# ${#arr[@]} a#b\necho \"# quoted\" '# single'\n",
+			want: "echo $# ${#arr[@]} a#b\necho \"# quoted\" '# single'\n",
+		},
+		{
+			name: "shell single quotes have no escapes",
+			lang: "shell",
+			src:  "echo 'back\\' # comment\n",
+			want: "echo 'back\\'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StripComments(tt.src, tt.lang)
+			if err != nil {
+				t.Fatalf("StripComments() returned an unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("StripComments() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripCommentsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		src  string
+	}{
+		{"unsupported language", "rust", "// comment\n"},
+		{"unterminated block comment", "go", "x := 1 /* never closed\n"},
+		{"unterminated docstring", "python", "def f():\n    \"\"\"never closed\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := StripComments(tt.src, tt.lang); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
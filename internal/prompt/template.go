@@ -0,0 +1,75 @@
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TemplateContext holds the variables available to persona and user prompt templates
+type TemplateContext struct {
+	ProjectName   string // Name of the project directory
+	Today         string // Current date as YYYY-MM-DD
+	FileCount     int    // Number of selected files
+	SelectedFiles string // Comma-separated relative paths of the selected files
+}
+
+// NewTemplateContext derives the template variables from the project root and selected files
+func NewTemplateContext(rootPath string, selectedFiles []string, now time.Time) TemplateContext {
+	names := make([]string, 0, len(selectedFiles))
+	for _, path := range selectedFiles {
+		if relPath, err := filepath.Rel(rootPath, path); err == nil {
+			path = relPath
+		}
+		names = append(names, path)
+	}
+
+	return TemplateContext{
+		ProjectName:   filepath.Base(rootPath),
+		Today:         now.Format("2006-01-02"),
+		FileCount:     len(selectedFiles),
+		SelectedFiles: strings.Join(names, ", "),
+	}
+}
+
+// templateVariable matches a reference to a variable such as {{.ProjectName}}
+var templateVariable = regexp.MustCompile(`{{\s*\.(\w+)\s*}}`)
+
+// values returns the template variables by name
+func (ctx TemplateContext) values() map[string]string {
+	return map[string]string{
+		"ProjectName":   ctx.ProjectName,
+		"Today":         ctx.Today,
+		"FileCount":     strconv.Itoa(ctx.FileCount),
+		"SelectedFiles": ctx.SelectedFiles,
+	}
+}
+
+// RenderTemplate expands template variables such as {{.ProjectName}} in content.
+// Other text, including template syntax of other languages such as Jinja or
+// Vue snippets, is returned unchanged. Referencing an unknown variable is an
+// error rather than expanding to an empty value.
+func RenderTemplate(content string, ctx TemplateContext) (string, error) {
+	if !strings.Contains(content, "{{") {
+		return content, nil
+	}
+
+	values := ctx.values()
+	var unknown []string
+	rendered := templateVariable.ReplaceAllStringFunc(content, func(ref string) string {
+		name := templateVariable.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok {
+			unknown = append(unknown, name)
+			return ref
+		}
+		return value
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown template variable %s, expected one of .ProjectName, .Today, .FileCount or .SelectedFiles", strings.Join(unknown, ", "))
+	}
+	return rendered, nil
+}
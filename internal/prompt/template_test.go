@@ -0,0 +1,107 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTemplateContext(t *testing.T) {
+	now := time.Date(2024, 3, 9, 15, 4, 5, 0, time.UTC)
+	ctx := NewTemplateContext("/work/myproject", []string{"/work/myproject/main.go", "/work/myproject/pkg/util.go"}, now)
+
+	if ctx.ProjectName != "myproject" {
+		t.Errorf("Expected ProjectName 'myproject', got %q", ctx.ProjectName)
+	}
+	if ctx.Today != "2024-03-09" {
+		t.Errorf("Expected Today '2024-03-09', got %q", ctx.Today)
+	}
+	if ctx.FileCount != 2 {
+		t.Errorf("Expected FileCount 2, got %d", ctx.FileCount)
+	}
+	if ctx.SelectedFiles != "main.go, pkg/util.go" {
+		t.Errorf("Expected SelectedFiles 'main.go, pkg/util.go', got %q", ctx.SelectedFiles)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	ctx := TemplateContext{ProjectName: "demo", Today: "2024-03-09", FileCount: 3, SelectedFiles: "a.go, b.go, c.go"}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"all variables", "{{.ProjectName}} on {{.Today}}: {{.FileCount}} files ({{.SelectedFiles}})", "demo on 2024-03-09: 3 files (a.go, b.go, c.go)"},
+		{"no template actions", "Plain text with { braces }", "Plain text with { braces }"},
+		{"empty content", "", ""},
+		{"spaces inside the braces", "{{ .ProjectName }}", "demo"},
+		{"other template syntax", "{% if user %}{{ user.name }}{% endif %} {{#each items}}{{this}}{{/each}} {{ .Values.port | quote }}", "{% if user %}{{ user.name }}{% endif %} {{#each items}}{{this}}{{/each}} {{ .Values.port | quote }}"},
+		{"unclosed action", "Hello {{.ProjectName", "Hello {{.ProjectName"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.content, ctx)
+			if err != nil {
+				t.Fatalf("RenderTemplate failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate_Errors(t *testing.T) {
+	ctx := TemplateContext{ProjectName: "demo"}
+
+	_, err := RenderTemplate("Hello {{.Author}}", ctx)
+	if err == nil {
+		t.Fatal("Expected error for missing variable")
+	}
+	if !strings.Contains(err.Error(), "Author") {
+		t.Errorf("Expected error to name the missing variable, got: %v", err)
+	}
+}
+
+func TestBuildRendersTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	personasDir := filepath.Join(tmpDir, "personas")
+	if err := os.Mkdir(personasDir, 0755); err != nil {
+		t.Fatalf("Failed to create personas dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(personasDir, "default.md"), []byte("You work on {{.ProjectName}}."), 0644); err != nil {
+		t.Fatalf("Failed to write persona: %v", err)
+	}
+	mainFile := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(mainFile, []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	output, err := BuildOrdered(tmpDir, []string{mainFile}, "Review {{.SelectedFiles}}", nil, OutputXML)
+	if err != nil {
+		t.Fatalf("BuildOrdered failed: %v", err)
+	}
+	if !strings.Contains(output, "You work on "+filepath.Base(tmpDir)+".") {
+		t.Errorf("Expected persona template to be rendered, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Review main.go") {
+		t.Errorf("Expected user prompt template to be rendered, got:\n%s", output)
+	}
+
+	if _, err := BuildOrdered(tmpDir, nil, "Hello {{.Unknown}}", nil, OutputXML); err == nil || !strings.Contains(err.Error(), "Unknown") {
+		t.Errorf("Expected an error naming the unknown template variable in the user prompt, got %v", err)
+	}
+
+	// Pasted snippets of other template languages are kept as they are
+	output, err = BuildOrdered(tmpDir, nil, "Fix <p>{{ user.name }}</p> in {{.ProjectName}}", nil, OutputXML)
+	if err != nil {
+		t.Fatalf("Expected a pasted template snippet not to fail the build, got %v", err)
+	}
+	if !strings.Contains(output, "Fix <p>{{ user.name }}</p> in "+filepath.Base(tmpDir)) {
+		t.Errorf("Expected the snippet to be kept, got:\n%s", output)
+	}
+}
@@ -0,0 +1,37 @@
+package prompt
+
+import "unicode"
+
+// charsPerToken approximates how many characters of a word make up one token
+const charsPerToken = 4
+
+// EstimateTokens approximates the number of tokens a GPT-style tokenizer would
+// produce for text. Words are split on whitespace and punctuation, each word
+// counts as roughly one token per four characters, and each punctuation mark
+// counts as a token of its own.
+func EstimateTokens(text string) int {
+	tokens := 0
+	wordLen := 0
+
+	flushWord := func() {
+		if wordLen > 0 {
+			tokens += (wordLen + charsPerToken - 1) / charsPerToken
+			wordLen = 0
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flushWord()
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			flushWord()
+			tokens++
+		default:
+			wordLen++
+		}
+	}
+	flushWord()
+
+	return tokens
+}
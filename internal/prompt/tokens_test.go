@@ -0,0 +1,28 @@
+package prompt
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{"empty", "", 0},
+		{"whitespace only", " \n\t ", 0},
+		{"short word", "go", 1},
+		{"four characters", "test", 1},
+		{"long word", "tokenisation", 3},
+		{"words and spaces", "hello big world", 5},
+		{"punctuation", "fmt.Println(x)", 7},
+		{"xml tag", "<file>", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.text); got != tt.expected {
+				t.Errorf("EstimateTokens(%q) = %d, expected %d", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
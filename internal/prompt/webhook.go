@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultWebhookTimeout is used when a WebhookConfig has no timeout
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookConfig describes where and how a generated prompt is delivered
+type WebhookConfig struct {
+	URL     string
+	Method  string // GET or POST, defaults to POST
+	Headers map[string]string
+	Timeout time.Duration
+	Format  OutputFormat // Selects the Content-Type of the request
+}
+
+// DeliverPrompt sends prompt as the body of an HTTP request to cfg.URL. It
+// returns an error if the request fails or the server doesn't respond with a
+// 2xx status.
+func DeliverPrompt(prompt string, cfg WebhookConfig) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("no webhook url configured")
+	}
+
+	method := strings.ToUpper(cfg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	if method != http.MethodGet && method != http.MethodPost {
+		return fmt.Errorf("unsupported webhook method %q", cfg.Method)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, cfg.URL, strings.NewReader(prompt))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(cfg.Format))
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// contentType returns the MIME type of prompts in the given format
+func contentType(format OutputFormat) string {
+	switch format {
+	case OutputJSON:
+		return "application/json"
+	case OutputMarkdown:
+		return "text/markdown"
+	default:
+		return "application/xml"
+	}
+}
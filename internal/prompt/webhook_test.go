@@ -0,0 +1,99 @@
+package prompt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeliverPrompt(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		format      OutputFormat
+		wantMethod  string
+		contentType string
+	}{
+		{"default post xml", "", OutputXML, http.MethodPost, "application/xml"},
+		{"post json", "post", OutputJSON, http.MethodPost, "application/json"},
+		{"get", "GET", OutputXML, http.MethodGet, "application/xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotContentType, gotAuth, gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotMethod = r.Method
+				gotContentType = r.Header.Get("Content-Type")
+				gotAuth = r.Header.Get("Authorization")
+				gotBody = string(body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			err := DeliverPrompt("<prompt/>", WebhookConfig{
+				URL:     server.URL,
+				Method:  tt.method,
+				Headers: map[string]string{"Authorization": "Bearer token"},
+				Format:  tt.format,
+			})
+			if err != nil {
+				t.Fatalf("DeliverPrompt failed: %v", err)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("Expected method %s, got %s", tt.wantMethod, gotMethod)
+			}
+			if gotContentType != tt.contentType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, gotContentType)
+			}
+			if gotAuth != "Bearer token" {
+				t.Errorf("Expected configured Authorization header, got %q", gotAuth)
+			}
+			if gotBody != "<prompt/>" {
+				t.Errorf("Expected the prompt as the body, got %q", gotBody)
+			}
+		})
+	}
+}
+
+func TestDeliverPromptErrors(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	tests := []struct {
+		name   string
+		cfg    WebhookConfig
+		errMsg string
+	}{
+		{"no url", WebhookConfig{}, "no webhook url"},
+		{"bad method", WebhookConfig{URL: failing.URL, Method: "PUT"}, "unsupported webhook method"},
+		{"server error", WebhookConfig{URL: failing.URL}, "500"},
+		{"timeout", WebhookConfig{URL: slow.URL, Timeout: 50 * time.Millisecond}, "webhook request failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DeliverPrompt("prompt", tt.cfg)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
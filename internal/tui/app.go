@@ -1,22 +1,27 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"coding-prompts-tui/internal/clipboard"
 	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/filesystem"
 	"coding-prompts-tui/internal/persona"
 	"coding-prompts-tui/internal/prompt"
 
-	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	lipglossv2 "github.com/charmbracelet/lipgloss/v2"
-	"go.dalton.dog/bubbleup"
 )
 
 // FocusedPanel represents which panel currently has focus
@@ -47,6 +52,19 @@ type LayoutChangeMsg struct {
 	Height int
 }
 
+// WebhookDeliveredMsg reports the result of sending the prompt to the webhook
+type WebhookDeliveredMsg struct {
+	Err error
+}
+
+// PromptBuiltMsg delivers a prompt built in the background
+type PromptBuiltMsg struct {
+	Content    string
+	Err        error
+	UserPrompt string // User prompt the prompt was built from, recorded in the history
+	Copy       bool   // Copy the prompt to the clipboard instead of showing it
+}
+
 // App represents the main application model
 type App struct {
 	targetDir       string
@@ -59,73 +77,396 @@ type App struct {
 	chat            *ChatModel
 	promptDialog    *PromptDialogModel
 	personaDialog   *PersonaDialogModel
-	alertModel      bubbleup.AlertModel
+	personaWizard   *PersonaCreateWizard
+	searchDialog    *SearchDialogModel
+	historyDialog   *HistoryDialogModel
+	saveDialog      *SaveDialogModel
+	manifestDialog  *ManifestDialogModel
+	globInput       *GlobInputModel
+	timeInput       *TimeInputModel
+	presetDialog    *PresetDialogModel
+	nameInput       *NameInputModel
+	lineRangeDialog *LineRangeDialogModel
+	tagInput        *TagInputModel
+	settingsPanel   *SettingsPanelModel
+	helpOverlay     *HelpOverlayModel
+	preview         *FilePreviewModel
+	textDialog      *TextDialogModel
+	recoveryDialog  *RecoveryDialogModel
+	dialogs         DialogStack // Visible dialogs, the one opened last on top
+	history         *prompt.PromptHistory
+	lastPrompt      string                  // Prompt generated most recently in this session
+	promptCache     *prompt.FileCache       // Content of selected files, reused while they are unchanged
+	buildingPrompt  bool                    // A prompt is being built in the background
+	spinner         spinner.Model           // Shown in the header while a prompt is built
+	watcher         *filesystem.FileWatcher // Set in watch mode
+	dirWatcher      *filesystem.DirWatcher  // Refreshes the file tree when files are added or removed
+	personaChanges  chan []string           // Personas found by the personas directory watcher, started by Init
+	stopPersonas    func()                  // Stops the personas directory watcher
+	clipboard       clipboard.Backend
+	autosave        *config.AutosaveManager // Set by EnableAutosave
+	notifications   *NotificationModel
 	configManager   *config.ConfigManager
 	settingsManager *config.SettingsManager
 	personaManager  *persona.Manager
+	theme           Theme
+	personasCreated bool   // The personas directory was created on startup, announced once by Init
+	startupNotice   string // Announced once by Init, set by AnnounceOnStart
 	workspace       *config.WorkspaceState
 	debugMode       bool
 	lastDebugInfo   string
-	debugLogger     *log.Logger
+	invariantError  string // Invariant violation alerted most recently, so it isn't alerted on every update
+	debugLogger     *slog.Logger
 	layoutConfig    *LayoutConfig
 	mode            string
 }
 
-// NewApp creates a new application instance
-func NewApp(targetDir string, cfgManager *config.ConfigManager, settingsManager *config.SettingsManager, workspace *config.WorkspaceState) *App {
-	fileTree := NewFileTreeModel(targetDir, workspace.SelectedFiles)
-	selectedFiles := NewSelectedFilesModel(cfgManager)
-	chat := NewChatModel(workspace.ChatInput)
+// AppOption configures an App created by NewApp
+type AppOption func(*App)
+
+// WithConfigManager sets the manager the workspaces are saved with
+func WithConfigManager(cfgManager *config.ConfigManager) AppOption {
+	return func(a *App) {
+		a.configManager = cfgManager
+	}
+}
+
+// WithSettingsManager sets the manager the user settings are read from
+func WithSettingsManager(settingsManager *config.SettingsManager) AppOption {
+	return func(a *App) {
+		a.settingsManager = settingsManager
+	}
+}
+
+// WithWorkspace sets the state the app opens with and saves its state to
+func WithWorkspace(workspace *config.WorkspaceState) AppOption {
+	return func(a *App) {
+		a.workspace = workspace
+	}
+}
+
+// WithDebugLogger sets the logger debug events are written to
+func WithDebugLogger(logger *slog.Logger) AppOption {
+	return func(a *App) {
+		a.debugLogger = logger
+	}
+}
+
+// WithPersonaManager sets the manager personas are discovered with
+func WithPersonaManager(personaManager *persona.Manager) AppOption {
+	return func(a *App) {
+		a.personaManager = personaManager
+	}
+}
+
+// NewApp creates a new application instance for targetDir. Anything not set by
+// an option is created the way the command line does: the config and settings
+// are loaded from their files, falling back to in-memory defaults if they can't
+// be, and the workspace is the one saved for targetDir.
+func NewApp(targetDir string, opts ...AppOption) *App {
+	app := &App{}
+	for _, opt := range opts {
+		opt(app)
+	}
+
+	cfgManager := app.configManager
+	if cfgManager == nil {
+		var err error
+		if cfgManager, err = config.NewManager(); err != nil {
+			cfgManager = config.NewMemoryManager()
+		}
+	}
+	settingsManager := app.settingsManager
+	if settingsManager == nil {
+		var err error
+		if settingsManager, err = config.NewSettingsManager(targetDir); err != nil {
+			settingsManager = config.NewDefaultSettingsManager()
+		}
+	}
+	workspace := app.workspace
+	if workspace == nil {
+		workspace = cfgManager.GetWorkspace(targetDir)
+	}
+
+	theme := ThemeFromSettings(settingsManager.GetThemeSettings())
+	fileTree := NewFileTreeModel(targetDir, workspace.SelectedPaths(), theme)
+	fileTree.SetFollowSymlinks(settingsManager.IsFollowSymlinksEnabled())
+	fileTree.SetShowGitStatus(settingsManager.IsGitStatusEnabled())
+	fileTree.SetMaxFileSize(settingsManager.GetMaxFileSizeBytes())
+	fileTree.SetMaxBulkSelect(settingsManager.GetMaxBulkSelect())
+	fileTree.SetExpandedPaths(workspace.ExpandedPaths)
+	fileTree.SetModifiedSince(workspace.ModifiedSinceFilter)
+	selectedFiles := NewSelectedFilesModel(cfgManager, theme)
+	selectedFiles.SetAllowBinaryFiles(settingsManager.IsBinaryFilesAllowed())
+	chat := NewChatModel(workspace.ChatInput, settingsManager.GetPromptCharLimit(), settingsManager.IsWordWrapEnabled(), theme)
 
 	// Initialize persona manager and discover personas
-	personaManager := persona.NewManager(targetDir)
+	personaManager := app.personaManager
+	if personaManager == nil {
+		personaManager = persona.NewManager(targetDir)
+	}
+	_, statErr := os.Stat(personaManager.GetPersonasDir())
+	personasCreated := os.IsNotExist(statErr) && personaManager.EnsurePersonasDir() == nil
 	personaManager.DiscoverPersonas()
 
 	// Initialize debug logger
-	debugLogger := initializeDebugLogger(targetDir, settingsManager)
+	debugLogger := app.debugLogger
+	if debugLogger == nil {
+		debugLogger = initializeDebugLogger(targetDir, settingsManager)
+	}
+
+	// Use the configured clipboard tool, detecting one if the setting is unusable
+	clip, err := clipboard.ForName(settingsManager.GetClipboardBackend())
+	if err != nil {
+		clip = clipboard.NewBackend()
+	}
 
 	// Initialize persona dialog
-	personaDialog := NewPersonaDialogModel()
+	personaDialog := NewPersonaDialogModel(personaManager, theme)
 	personaDialog.SetAvailablePersonas(personaManager.GetAvailablePersonas())
 	personaDialog.SetActivePersonas(workspace.ActivePersonas)
 	personaDialog.SetDebugLogger(debugLogger)
+	fileTree.SetDebugLogger(debugLogger)
+
+	// Load the prompt history for this workspace, starting empty if it can't be read
+	history, err := prompt.LoadPromptHistory(cfgManager.HistoryPath(targetDir), prompt.DefaultHistorySize)
+	if err != nil && debugLogger != nil {
+		debugLogger.Error("failed to load prompt history", "component", "app", "event", "history_load_failed", "error", err)
+	}
 
-	app := &App{
+	*app = App{
 		targetDir:       targetDir,
 		focused:         FileTreePanel,
 		fileTree:        fileTree,
 		selectedFiles:   selectedFiles,
 		chat:            chat,
-		promptDialog:    NewPromptDialogModel(),
+		promptDialog:    NewPromptDialogModel(theme),
 		personaDialog:   personaDialog,
-		alertModel:      *bubbleup.NewAlertModel(40, true), // Will be updated dynamically on window resize
+		personaWizard:   NewPersonaCreateWizard(personaManager, theme),
+		searchDialog:    NewSearchDialogModel(targetDir, theme),
+		historyDialog:   NewHistoryDialogModel(theme),
+		saveDialog:      NewSaveDialogModel(theme),
+		manifestDialog:  NewManifestDialogModel(theme),
+		globInput:       NewGlobInputModel(theme),
+		timeInput:       NewTimeInputModel(theme),
+		presetDialog:    NewPresetDialogModel(theme),
+		nameInput:       NewNameInputModel(theme),
+		lineRangeDialog: NewLineRangeDialogModel(theme),
+		tagInput:        NewTagInputModel(theme),
+		settingsPanel:   NewSettingsPanelModel(theme),
+		helpOverlay:     NewHelpOverlayModel(theme),
+		preview:         NewFilePreviewModel(theme),
+		textDialog:      NewTextDialogModel(theme),
+		recoveryDialog:  NewRecoveryDialogModel(theme),
+		history:         history,
+		promptCache:     prompt.NewFileCache(),
+		spinner:         spinner.New(spinner.WithSpinner(spinner.Dot)),
+		notifications:   NewNotificationModel(40, time.Duration(settingsManager.GetNotificationTTL())*time.Second), // Width is updated on window resize
 		configManager:   cfgManager,
 		settingsManager: settingsManager,
 		personaManager:  personaManager,
+		theme:           theme,
 		workspace:       workspace,
 		debugMode:       settingsManager.IsDebugEnabled(), // Set from config
 		debugLogger:     debugLogger,
+		clipboard:       clip,
 		layoutConfig:    NewLayoutConfig(),
 		mode:            "normal",
+		personasCreated: personasCreated,
+	}
+	app.layoutConfig.Mode = ParseLayoutMode(settingsManager.GetLayoutMode())
+	// Restore the saved file order, tags and buckets before syncing with the tree selection
+	for _, file := range workspace.SelectedFiles {
+		selectedFiles.AddFile(filepath.Base(file.Path), file.Path)
+		selectedFiles.SetTags(file.Path, file.Tags)
+	}
+	for bucket, paths := range workspace.Buckets {
+		for _, path := range paths {
+			selectedFiles.SetBucket(path, bucket)
+		}
+	}
+	selectedFiles.sortFiles(ParseSortMode(workspace.SelectedSortMode))
+	// Binary files saved with the workspace are dropped if they're no longer allowed
+	if refused := app.updateSelectedFilesFromSelection(fileTree.selected); len(refused) > 0 {
+		fileTree.deselectFiles(refused)
 	}
-	app.updateSelectedFilesFromSelection(fileTree.selected)
 
 	return app
 }
 
+// EnableWatchMode regenerates and copies the prompt whenever a selected file changes on disk
+func (a *App) EnableWatchMode(watcher *filesystem.FileWatcher) {
+	a.watcher = watcher
+	a.syncWatchedFiles()
+}
+
+// EnableDirWatch refreshes the file tree whenever files are added to or removed from the target directory
+func (a *App) EnableDirWatch(watcher *filesystem.DirWatcher) {
+	a.dirWatcher = watcher
+}
+
+// AnnounceOnStart shows message in an info alert once the app starts
+func (a *App) AnnounceOnStart(message string) {
+	a.startupNotice = message
+}
+
+// watchPersonas starts watching the personas directory so the persona dialog
+// lists persona files added or removed while the app runs. The returned command
+// delivers the first change; it is nil if the directory can't be watched.
+func (a *App) watchPersonas() tea.Cmd {
+	changes := make(chan []string, 1)
+	stop, err := a.personaManager.WatchPersonasDir(func(personas []string) {
+		// Only the latest list matters, so replace one that wasn't picked up yet
+		select {
+		case <-changes:
+		default:
+		}
+		changes <- personas
+	})
+	if err != nil {
+		if a.debugLogger != nil {
+			a.debugLogger.Warn("failed to watch personas", "component", "app", "event", "persona_watch_failed", "error", err)
+		}
+		return nil
+	}
+	a.personaChanges = changes
+	a.stopPersonas = stop
+	return waitForPersonasChange(changes)
+}
+
+// Close stops the watch mode file watcher, the directory watcher and the personas watcher, if any
+func (a *App) Close() error {
+	// A clean exit leaves nothing to recover
+	if a.autosave != nil {
+		a.autosave.Remove()
+	}
+	if a.stopPersonas != nil {
+		// No changes are sent once the watcher has stopped
+		a.stopPersonas()
+		a.stopPersonas = nil
+		close(a.personaChanges)
+	}
+	var err error
+	if a.dirWatcher != nil {
+		err = a.dirWatcher.Close()
+	}
+	if a.watcher != nil {
+		if closeErr := a.watcher.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// EnableAutosave periodically writes the workspace state with autosave so it can be
+// recovered after a crash. If recovered holds state left behind by an earlier crash
+// that differs from the workspace, the user is asked whether to restore it.
+func (a *App) EnableAutosave(autosave *config.AutosaveManager, recovered *config.Autosave) {
+	a.autosave = autosave
+	if recovered == nil {
+		return
+	}
+	if workspaceDiffers(recovered.Workspace, *a.workspace) {
+		a.dialogs.Push(a.recoveryDialog, func() tea.Cmd {
+			a.recoveryDialog.Show(recovered)
+			return nil
+		})
+	} else {
+		autosave.Discard()
+	}
+}
+
+// autosaveTick schedules the next autosave
+func autosaveTick() tea.Cmd {
+	return tea.Tick(config.AutosaveInterval, func(time.Time) tea.Msg {
+		return AutosaveTickMsg{}
+	})
+}
+
+// workspaceDiffers reports whether two workspace states would restore differently
+func workspaceDiffers(a, b config.WorkspaceState) bool {
+	return !slices.EqualFunc(a.SelectedFiles, b.SelectedFiles, func(x, y config.SelectedFileState) bool {
+		return x.Path == y.Path && slices.Equal(x.Tags, y.Tags)
+	}) ||
+		!maps.EqualFunc(a.Buckets, b.Buckets, slices.Equal) ||
+		a.SelectedSortMode != b.SelectedSortMode ||
+		a.ChatInput != b.ChatInput ||
+		!slices.Equal(a.ActivePersonas, b.ActivePersonas) ||
+		a.OutputFormat != b.OutputFormat
+}
+
+// restoreWorkspace replaces the selection, chat input, personas and output format with those of ws
+func (a *App) restoreWorkspace(ws config.WorkspaceState) {
+	// Rebuild the selection in the saved order
+	a.selectedFiles.files = []SelectedFile{}
+	selected := make(map[string]bool)
+	for _, file := range ws.SelectedFiles {
+		a.selectedFiles.AddFile(filepath.Base(file.Path), file.Path)
+		a.selectedFiles.SetTags(file.Path, file.Tags)
+		selected[file.Path] = true
+	}
+	for bucket, paths := range ws.Buckets {
+		for _, path := range paths {
+			a.selectedFiles.SetBucket(path, bucket)
+		}
+	}
+	a.selectedFiles.sortFiles(ParseSortMode(ws.SelectedSortMode))
+	a.fileTree.selected = selected
+	a.fileTree.pushSelection()
+	a.fileTree.refreshItems()
+	if refused := a.updateSelectedFilesFromSelection(selected); len(refused) > 0 {
+		a.fileTree.deselectFiles(refused)
+	}
+
+	a.chat.SetPrompt(ws.ChatInput)
+	a.storeSelection()
+	a.workspace.ChatInput = ws.ChatInput
+	if len(ws.ActivePersonas) > 0 {
+		a.workspace.ActivePersonas = ws.ActivePersonas
+	}
+	a.workspace.OutputFormat = ws.OutputFormat
+	a.configManager.Save()
+	a.syncWatchedFiles()
+}
+
 // Init initializes the application
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		a.fileTree.Init(),
 		a.selectedFiles.Init(),
 		a.chat.Init(),
 		a.personaDialog.Init(),
-		a.alertModel.Init(),
-	)
+	}
+	if a.watcher != nil {
+		cmds = append(cmds, waitForFileChange(a.watcher))
+	}
+	if a.dirWatcher != nil {
+		cmds = append(cmds, waitForDirChange(a.dirWatcher))
+	}
+	if a.autosave != nil {
+		cmds = append(cmds, autosaveTick())
+	}
+	if a.stopPersonas == nil {
+		cmds = append(cmds, a.watchPersonas())
+	}
+	if a.personasCreated {
+		cmds = append(cmds, a.createAlert(InfoAlert, "Created personas/default.md — customize it!"))
+	}
+	if a.startupNotice != "" {
+		cmds = append(cmds, a.createAlert(InfoAlert, a.startupNotice))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the application state
-func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (a *App) Update(msg tea.Msg) (_ tea.Model, cmd tea.Cmd) {
+	// Catch inconsistent state as soon as an update causes it while debugging
+	if a.debugMode && a.settingsManager.IsInvariantCheckEnabled() {
+		defer func() {
+			cmd = tea.Batch(cmd, a.checkStateInvariants())
+		}()
+	}
+
 	var cmds []tea.Cmd
 
 	// Handle state change messages first with centralized validation
@@ -163,15 +504,75 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, tea.Batch(cmds...)
 
+	case PromptBuiltMsg:
+		a.buildingPrompt = false
+		if msg.Err != nil {
+			return a, a.createAlert(ErrorAlert, "error building prompt: "+msg.Err.Error())
+		}
+		previousPrompt := a.lastPrompt
+		a.recordPrompt(msg.UserPrompt, msg.Content)
+		if msg.Copy {
+			return a, a.copyPrompt(msg.Content)
+		}
+		a.dialogs.Push(a.promptDialog, func() tea.Cmd {
+			a.promptDialog.ShowWithPrevious(msg.Content, previousPrompt)
+			return nil
+		})
+		a.promptDialog.SetTokenEstimate(prompt.EstimateTokens(msg.Content))
+		return a, a.tokenWarning(msg.Content)
+
+	case spinner.TickMsg:
+		// Stop ticking once the build is done
+		if !a.buildingPrompt {
+			return a, nil
+		}
+		var cmd tea.Cmd
+		a.spinner, cmd = a.spinner.Update(msg)
+		return a, cmd
+
+	case DirScannedMsg, LineCountMsg:
+		// Background directory scans and line counts complete whichever panel has focus
+		model, cmd := a.fileTree.Update(msg)
+		a.fileTree = model.(*FileTreeModel)
+		return a, cmd
+
+	case filesystem.DirChangedMsg:
+		for _, path := range msg.Paths {
+			a.promptCache.Invalidate(path)
+		}
+		// Rescan in the background and keep listening for further changes
+		return a, tea.Batch(a.fileTree.RefreshCmd(), waitForDirChange(a.dirWatcher))
+
+	case TreeRefreshedMsg:
+		// Rescans complete whichever panel has focus
+		model, cmd := a.fileTree.Update(msg)
+		a.fileTree = model.(*FileTreeModel)
+		return a, cmd
+
 	case FileSelectionMsg:
 		// Update selected files panel when file selection changes
-		a.updateSelectedFilesFromSelection(msg.SelectedFiles)
-		a.workspace.SelectedFiles = []string{}
-		for path, selected := range msg.SelectedFiles {
-			if selected {
-				a.workspace.SelectedFiles = append(a.workspace.SelectedFiles, path)
+		var cmd tea.Cmd
+		if refused := a.updateSelectedFilesFromSelection(msg.SelectedFiles); len(refused) > 0 {
+			// Binary files can't be selected, so take them out of the tree selection again
+			a.fileTree.deselectFiles(refused)
+			message := filepath.Base(refused[0]) + " is a binary file, not selected"
+			if len(refused) > 1 {
+				message = fmt.Sprintf("%d binary files not selected", len(refused))
 			}
+			cmd = a.createAlert(WarnAlert, message)
 		}
+		a.storeSelection()
+		a.configManager.Save()
+		a.syncWatchedFiles()
+		return a, tea.Batch(cmd, a.selectedFiles.estimateTokens())
+
+	case TokenEstimateMsg:
+		a.selectedFiles.SetTokenEstimate(msg)
+		return a, nil
+
+	case FileOrderChangedMsg:
+		// Persist the new order of selected files
+		a.storeSelection()
 		a.configManager.Save()
 		return a, nil
 
@@ -183,16 +584,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case FileDeselectionMsg:
 		// Update file tree selection state when file is removed from selected files
 		a.fileTree.selected[msg.FilePath] = false
+		a.fileTree.pushSelection()
 		a.fileTree.refreshItems()
 		// Also update workspace state
-		var newSelected []string
-		for _, f := range a.workspace.SelectedFiles {
-			if f != msg.FilePath {
-				newSelected = append(newSelected, f)
-			}
-		}
-		a.workspace.SelectedFiles = newSelected
+		a.storeSelection()
 		a.configManager.Save()
+		a.syncWatchedFiles()
 		return a, nil
 
 	case ClearAllFilesMsg:
@@ -200,67 +597,376 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for filePath := range a.fileTree.selected {
 			a.fileTree.selected[filePath] = false
 		}
+		a.fileTree.pushSelection()
 		a.fileTree.refreshItems()
 		// Clear workspace state
-		a.workspace.SelectedFiles = []string{}
+		a.workspace.SelectedFiles = []config.SelectedFileState{}
 		a.configManager.Save()
+		a.syncWatchedFiles()
 		return a, nil
 
+	case SearchSelectMsg:
+		// Reveal and select the chosen file in the tree, then focus it
+		return a, tea.Batch(a.fileTree.RevealAndSelect(msg.Path), a.setFocus(FileTreePanel))
+
+	case JumpToFileMsg:
+		if !a.fileTree.JumpToPath(msg.Path) {
+			return a, a.createAlert(WarnAlert, "file not found in tree")
+		}
+		return a, a.setFocus(FileTreePanel)
+
+	case HistoryRestoreMsg:
+		// Restore a past user prompt into the chat input
+		a.chat.SetPrompt(msg.UserPrompt)
+		a.workspace.ChatInput = msg.UserPrompt
+		a.configManager.Save()
+		return a, tea.Batch(a.createAlert(InfoAlert, "prompt restored"), a.setFocus(ChatPanel))
+
+	case HistoryCopyMsg:
+		if err := a.clipboard.WriteAll(msg.Content); err != nil {
+			return a, a.createAlert(ErrorAlert, "clipboard error")
+		}
+		return a, a.createAlert(InfoAlert, "prompt copied")
+
+	case PromptCopyMsg:
+		return a, a.copyPrompt(msg.Content)
+
+	case FileFocusedMsg:
+		if a.settingsManager.IsPreviewEnabled() {
+			a.preview.SetFile(msg.Path, a.settingsManager.GetPreviewLines())
+		}
+		// Count the lines of the file for the status line below the tree
+		return a, a.fileTree.countLinesCmd()
+
+	case AutosaveTickMsg:
+		if a.autosave == nil {
+			return a, nil
+		}
+		// Keep the autosave being offered for recovery until the user has answered
+		if !a.recoveryDialog.IsVisible() {
+			workspace := *a.workspace
+			workspace.ChatInput = a.chat.textarea.Value()
+			if err := a.autosave.Save(workspace); err != nil && a.debugLogger != nil {
+				a.debugLogger.Error("failed to autosave workspace", "component", "app", "event", "autosave_failed", "error", err)
+			}
+		}
+		return a, autosaveTick()
+
+	case RecoveryMsg:
+		if !msg.Restore {
+			a.autosave.Discard()
+			return a, nil
+		}
+		a.restoreWorkspace(msg.Autosave.Workspace)
+		return a, tea.Batch(a.selectedFiles.estimateTokens(), a.createAlert(InfoAlert, "autosaved session restored"))
+
+	case BlameFinishedMsg:
+		title := "Blame: " + filepath.Base(msg.Path)
+		switch {
+		case errors.Is(msg.Err, filesystem.ErrNotGitRepository):
+			a.dialogs.Push(a.textDialog, func() tea.Cmd {
+				a.textDialog.Show(title, "Not a git repository, so there is no history to show.")
+				return nil
+			})
+		case msg.Err != nil:
+			return a, a.createAlert(ErrorAlert, "git blame failed: "+msg.Err.Error())
+		default:
+			a.dialogs.Push(a.textDialog, func() tea.Cmd {
+				a.textDialog.Show(title, formatBlame(msg.Lines))
+				return nil
+			})
+		}
+		return a, nil
+
+	case EditorFinishedMsg:
+		cmd := a.RefreshFile(msg.Path)
+		if msg.Err != nil {
+			return a, tea.Batch(cmd, a.createAlert(ErrorAlert, "editor failed: "+msg.Err.Error()))
+		}
+		return a, cmd
+
+	case filesystem.FileChangedMsg:
+		a.promptCache.Invalidate(msg.Path)
+		// Regenerate in the background and keep listening for further changes
+		return a, tea.Batch(
+			regeneratePrompt(msg.Path, a.targetDir, a.selectedFiles.GetPaths(), a.chat.textarea.Value(), a.workspace.ActivePersonas, a.outputFormat(), a.buildOptions(), a.clipboard),
+			waitForFileChange(a.watcher),
+		)
+
+	case WebhookDeliveredMsg:
+		if msg.Err != nil {
+			return a, a.createAlert(ErrorAlert, "webhook failed: "+msg.Err.Error())
+		}
+		return a, a.createAlert(InfoAlert, "prompt delivered to webhook")
+
+	case PromptRegeneratedMsg:
+		if msg.Err != nil {
+			return a, a.createAlert(ErrorAlert, "error regenerating prompt")
+		}
+		if msg.Tokens > a.settingsManager.GetTokenWarningThreshold() {
+			return a, a.createAlert(WarnAlert, fmt.Sprintf("prompt is ~%s tokens", formatTokenCount(msg.Tokens)))
+		}
+		return a, a.createAlert(InfoAlert, filepath.Base(msg.Path)+" changed, prompt copied")
+
+	case GlobSelectMsg:
+		selected, err := a.fileTree.SelectByGlob(msg.Pattern)
+		if err != nil {
+			return a, a.createAlert(ErrorAlert, err.Error())
+		}
+		if len(selected) == 0 {
+			return a, a.createAlert(WarnAlert, "no files match "+msg.Pattern)
+		}
+		return a, tea.Batch(
+			a.fileTree.sendFileSelectionUpdate(),
+			a.createAlert(InfoAlert, fmt.Sprintf("selected %d files", len(selected))),
+		)
+
+	case PresetApplyMsg:
+		return a, a.applyPreset(msg.Preset)
+
+	case PresetNameMsg:
+		files := a.selectedFiles.GetPaths()
+		if err := a.configManager.SavePreset(a.workspace.Path, msg.Name, files, a.selectedFiles.GetTags()); err != nil {
+			return a, a.createAlert(ErrorAlert, "error saving preset: "+err.Error())
+		}
+		return a, a.createAlert(InfoAlert, fmt.Sprintf("saved %d files as preset %s", len(files), msg.Name))
+
+	case ModifiedSinceMsg:
+		return a, a.setModifiedSince(msg.Since)
+
+	case SaveConfirmMsg:
+		return a, a.exportPrompt(msg.Path)
+
+	case ManifestExportMsg:
+		return a, a.exportManifest(msg.Format)
+
+	case CopyPathsMsg:
+		return a, a.copyPaths(msg.Paths)
+
+	case LineRangeRequestMsg:
+		return a, a.dialogs.Push(a.lineRangeDialog, func() tea.Cmd { return a.lineRangeDialog.Show(msg.Path, msg.StartLine, msg.EndLine) })
+
+	case TagRequestMsg:
+		return a, a.dialogs.Push(a.tagInput, func() tea.Cmd { return a.tagInput.Show(msg.Path, msg.Tags) })
+
+	case BindingChangedMsg:
+		if err := a.settingsManager.SetBinding(msg.Name, msg.Key); err != nil {
+			return a, a.createAlert(ErrorAlert, err.Error())
+		}
+		a.settingsPanel.SetBindings(a.bindingValues())
+		return a, a.createAlert(InfoAlert, fmt.Sprintf("%s bound to %s", msg.Name, msg.Key))
+
+	case TagsMsg:
+		a.selectedFiles.SetTags(msg.Path, msg.Tags)
+		a.storeSelection()
+		a.configManager.Save()
+		if len(msg.Tags) == 0 {
+			return a, a.createAlert(InfoAlert, "cleared tags of "+filepath.Base(msg.Path))
+		}
+		return a, a.createAlert(InfoAlert, fmt.Sprintf("tagged %s: %s", filepath.Base(msg.Path), strings.Join(msg.Tags, ", ")))
+
+	case LineRangeMsg:
+		a.selectedFiles.SetLineRange(msg.Path, msg.StartLine, msg.EndLine)
+		estimate := a.selectedFiles.estimateTokens()
+		if msg.StartLine == 0 {
+			return a, tea.Batch(estimate, a.createAlert(InfoAlert, "including all of "+filepath.Base(msg.Path)))
+		}
+		lineRange := prompt.LineRange{Start: msg.StartLine, End: msg.EndLine}
+		return a, tea.Batch(estimate, a.createAlert(InfoAlert, fmt.Sprintf("including lines %s of %s", lineRange.String(), filepath.Base(msg.Path))))
+
 	case PersonaSelectionMsg:
 		// Update workspace state with new active personas
 		a.workspace.ActivePersonas = msg.ActivePersonas
 		a.configManager.Save()
 		return a, nil
 
+	case ShowPersonaWizardMsg:
+		return a, a.dialogs.Push(a.personaWizard, a.personaWizard.Show)
+
+	case PersonaCreatedMsg:
+		// List the new persona and make it active straight away
+		a.personaDialog.SetAvailablePersonas(a.personaManager.GetAvailablePersonas())
+		a.workspace.ActivePersonas = append(a.workspace.ActivePersonas, msg.Name)
+		a.configManager.Save()
+		a.personaDialog.selectedPersonas[msg.Name] = true
+		a.personaDialog.updateDialogContent()
+		return a, a.createAlert(InfoAlert, "created persona "+msg.Name)
+
+	case PersonasChangedMsg:
+		a.personaDialog.SetAvailablePersonas(msg.Personas)
+
+		// Drop active personas whose files were removed
+		active := slices.DeleteFunc(slices.Clone(a.workspace.ActivePersonas), func(name string) bool {
+			return !slices.Contains(msg.Personas, name)
+		})
+		if len(active) == 0 {
+			active = []string{"default"}
+		}
+		if !slices.Equal(active, a.workspace.ActivePersonas) {
+			a.workspace.ActivePersonas = active
+			a.configManager.Save()
+			a.personaDialog.SetActivePersonas(active)
+		}
+		a.personaDialog.updateDialogContent()
+		return a, waitForPersonasChange(a.personaChanges)
+
+	case PersonaDeleteMsg:
+		if err := a.personaManager.DeletePersona(msg.Name); err != nil {
+			return a, a.createAlert(ErrorAlert, err.Error())
+		}
+		a.personaDialog.SetAvailablePersonas(a.personaManager.GetAvailablePersonas())
+		delete(a.personaDialog.selectedPersonas, msg.Name)
+		a.personaDialog.updateDialogContent()
+
+		// Drop the deleted persona from the active ones
+		active := slices.DeleteFunc(slices.Clone(a.workspace.ActivePersonas), func(name string) bool {
+			return name == msg.Name
+		})
+		if len(active) == 0 {
+			active = []string{"default"}
+		}
+		a.workspace.ActivePersonas = active
+		a.configManager.Save()
+		return a, a.createAlert(InfoAlert, "deleted persona "+msg.Name)
+
 	// Bindings
 	case tea.KeyMsg:
 		// Handle global clipboard copy first
 		if msg.String() == "ctrl+y" {
-			var promptToCopy string
 			if a.promptDialog.IsVisible() && a.promptDialog.GetContent() != "" {
-				promptToCopy = a.promptDialog.GetContent()
-			} else {
-				generatedPrompt, err := prompt.Build(a.targetDir, a.fileTree.selected, a.chat.textarea.Value(), a.workspace.ActivePersonas)
-				if err != nil {
-					// Show error notification
-					alertCmd := a.createAlert(bubbleup.ErrorKey, "error building prompt")
-					return a, alertCmd
-				}
-				promptToCopy = generatedPrompt
+				return a, a.copyPrompt(a.promptDialog.GetContent())
 			}
+			return a, a.GeneratePromptAsync(true)
+		}
 
-			err := clipboard.WriteAll(promptToCopy)
+		// Save a screenshot from any dialog; alt+s for terminals that can't send ctrl+shift+s
+		if msg.String() == "ctrl+shift+s" || msg.String() == "alt+s" {
+			path, err := a.captureScreenshot()
 			if err != nil {
-				// Show error notification
-				alertCmd := a.createAlert(bubbleup.ErrorKey, "clipboard error")
-				return a, alertCmd
+				return a, a.createAlert(ErrorAlert, err.Error())
 			}
-
-			// Show success notification
-			alertCmd := a.createAlert(bubbleup.InfoKey, "prompt copied")
-			return a, alertCmd
+			return a, a.createAlert(InfoAlert, "screenshot saved to "+path)
 		}
 
-		// Handle persona dialog input if visible
-		if a.personaDialog.IsVisible() {
-			if a.debugLogger != nil {
-				a.debugLogger.Printf("APP: Persona dialog is visible, forwarding key: %q", msg.String())
-			}
-			model, cmd := a.personaDialog.Update(msg)
-			a.personaDialog = model
-			if a.debugLogger != nil {
-				a.debugLogger.Printf("APP: After persona dialog update, visible: %v", a.personaDialog.IsVisible())
+		// Send keys to the dialog opened last, taking it off the stack once its
+		// own keys close it
+		if dialog := a.dialogs.Current(); dialog != nil {
+			cmd := a.updateDialog(dialog, msg)
+			if !dialog.IsVisible() {
+				a.dialogs.Pop()
 			}
 			return a, cmd
 		}
 
-		// Handle prompt dialog input if visible
-		if a.promptDialog.IsVisible() {
-			model, cmd := a.promptDialog.Update(msg)
-			a.promptDialog = model
+		// Send keys to the file tree filter while it is being typed
+		if a.focused == FileTreePanel && a.fileTree.IsFiltering() {
+			model, cmd := a.fileTree.Update(msg)
+			a.fileTree = model.(*FileTreeModel)
 			return a, cmd
 		}
 
+		// Open the quick-open file search, except while typing in the chat, where
+		// ctrl+p moves to the previous line
+		if quickOpenKey, err := config.ParseKeyBinding(a.settingsManager.GetQuickOpenKey()); err == nil && quickOpenKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			a.searchDialog.SetFiles(a.fileTree.AllFilePaths())
+			return a, a.dialogs.Push(a.searchDialog, a.searchDialog.Show)
+		}
+
+		// Open the prompt history, except while typing in the chat, where terminals
+		// may send ctrl+h for backspace
+		if historyKey, err := config.ParseKeyBinding(a.settingsManager.GetHistoryKey()); err == nil && historyKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			a.dialogs.Push(a.historyDialog, func() tea.Cmd {
+				a.historyDialog.Show(a.history.Entries())
+				return nil
+			})
+			return a, nil
+		}
+
+		// Open the export dialog, except while typing in the chat, where ctrl+e
+		// moves to the end of the line
+		if exportKey, err := config.ParseKeyBinding(a.settingsManager.GetExportKey()); err == nil && exportKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			defaultPath := filepath.Join(a.targetDir, "prompt."+a.outputFormat().Extension())
+			return a, a.dialogs.Push(a.saveDialog, func() tea.Cmd { return a.saveDialog.Show(defaultPath) })
+		}
+
+		// Copy the list of selected files from any panel
+		if manifestKey, err := config.ParseKeyBinding(a.settingsManager.GetExportManifestKey()); err == nil && manifestKey.MatchesKeyMsg(msg) {
+			a.dialogs.Push(a.manifestDialog, func() tea.Cmd {
+				a.manifestDialog.Show()
+				return nil
+			})
+			return a, nil
+		}
+
+		// Switch to another recently opened workspace, except while typing in the
+		// chat, where ctrl+w deletes a word
+		if workspaceListKey, err := config.ParseKeyBinding(a.settingsManager.GetWorkspaceListKey()); err == nil && workspaceListKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			return a, func() tea.Msg {
+				return ShowWorkspaceListMsg{}
+			}
+		}
+
+		// Open the glob pattern selection from any panel
+		if globKey, err := config.ParseKeyBinding(a.settingsManager.GetGlobSelectKey()); err == nil && globKey.MatchesKeyMsg(msg) {
+			return a, a.dialogs.Push(a.globInput, a.globInput.Show)
+		}
+
+		// Set the modified-since filter from any panel
+		if modifiedSinceKey, err := config.ParseKeyBinding(a.settingsManager.GetModifiedSinceKey()); err == nil && modifiedSinceKey.MatchesKeyMsg(msg) {
+			return a, a.dialogs.Push(a.timeInput, func() tea.Cmd { return a.timeInput.Show(a.workspace.ModifiedSinceFilter) })
+		}
+
+		// Select the files of a saved preset, except while typing in the chat, where
+		// ctrl+t transposes characters
+		if presetsKey, err := config.ParseKeyBinding(a.settingsManager.GetPresetsKey()); err == nil && presetsKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			a.dialogs.Push(a.presetDialog, func() tea.Cmd {
+				a.presetDialog.Show(a.configManager.GetPresets(a.workspace.Path))
+				return nil
+			})
+			return a, nil
+		}
+
+		// Save the selected files as a preset from any panel
+		if savePresetKey, err := config.ParseKeyBinding(a.settingsManager.GetSavePresetKey()); err == nil && savePresetKey.MatchesKeyMsg(msg) {
+			if len(a.selectedFiles.GetPaths()) == 0 {
+				return a, a.createAlert(WarnAlert, "no files selected to save as a preset")
+			}
+			return a, a.dialogs.Push(a.nameInput, a.nameInput.Show)
+		}
+
+		// Send the generated prompt to the configured webhook from any panel
+		if webhookKey, err := config.ParseKeyBinding(a.settingsManager.GetWebhookKey()); err == nil && webhookKey.MatchesKeyMsg(msg) {
+			return a, a.deliverPrompt()
+		}
+
+		// Show what each ignore pattern matches from any panel
+		if gitignoreKey, err := config.ParseKeyBinding(a.settingsManager.GetGitignoreCheckKey()); err == nil && gitignoreKey.MatchesKeyMsg(msg) {
+			return a, a.showGitignoreReport()
+		}
+
+		// Change the key bindings from any panel
+		if settingsKey, err := config.ParseKeyBinding(a.settingsManager.GetSettingsKey()); err == nil && settingsKey.MatchesKeyMsg(msg) {
+			a.dialogs.Push(a.settingsPanel, func() tea.Cmd {
+				a.settingsPanel.Show(a.bindingValues())
+				return nil
+			})
+			return a, nil
+		}
+
+		// Cycle through the panel layouts
+		if layoutKey, err := config.ParseKeyBinding(a.settingsManager.GetLayoutToggleKey()); err == nil && layoutKey.MatchesKeyMsg(msg) {
+			return a, a.toggleLayout()
+		}
+
+		// Show the key bindings for the focused panel, except while typing in the chat
+		if helpKey, err := config.ParseKeyBinding(a.settingsManager.GetHelpKey()); err == nil && helpKey.MatchesKeyMsg(msg) && a.focused != ChatPanel {
+			a.dialogs.Push(a.helpOverlay, func() tea.Cmd {
+				a.helpOverlay.Show(BuildHelpTable(a.settingsManager, a.focused))
+				return nil
+			})
+			return a, nil
+		}
+
 		// Handle menu activation first (supports both legacy and new modes)
 		if menuCmd := a.handleMenuActivation(msg); menuCmd != nil {
 			return a, menuCmd
@@ -276,11 +982,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Log to file
 			if a.debugLogger != nil {
-				a.debugLogger.Printf("DEBUG: %s", debugInfo)
+				a.debugLogger.Debug("key pressed", "component", "app", "event", "key_pressed",
+					"key", msg.String(), "type", msg.Type.String(), "alt", msg.Alt, "runes", string(msg.Runes))
 			}
 
 			// Also show as notification in TUI (but don't return immediately - let other handlers run)
-			alertCmd := a.createAlert(bubbleup.InfoKey, debugInfo)
+			alertCmd := a.createAlert(InfoAlert, debugInfo)
 			cmds = append(cmds, alertCmd)
 		}
 
@@ -294,9 +1001,11 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle other key commands
 		switch msg.String() {
 		case "ctrl+c":
-			// If selected files panel is focused, clear all files instead of quitting
+			// The selected files panel copies their paths instead of quitting
 			if a.focused == SelectedFilesPanel {
-				return a, a.selectedFiles.ClearAllFiles()
+				model, cmd := a.selectedFiles.Update(msg)
+				a.selectedFiles = model.(*SelectedFilesModel)
+				return a, cmd
 			}
 			return a, tea.Quit
 		case "q":
@@ -311,15 +1020,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, a.exitMenuMode()
 			}
 		case "ctrl+s":
-			generatedPrompt, err := prompt.Build(a.targetDir, a.fileTree.selected, a.chat.textarea.Value(), a.workspace.ActivePersonas)
-			if err != nil {
-				// Handle error, maybe show an error message
-				// For now, we'll just log it
-				// log.Printf("Error building prompt: %v", err)
-			} else {
-				a.promptDialog.Show(generatedPrompt)
-			}
-			return a, nil
+			return a, a.GeneratePromptAsync(false)
 		}
 
 		// Handle menu-specific commands (only active in menu binding mode)
@@ -328,16 +1029,63 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case a.settingsManager.GetPersonaMenuKey():
 				// Show persona selection dialog
 				a.personaDialog.SetActivePersonas(a.workspace.ActivePersonas)
-				a.personaDialog.Show()
+				a.dialogs.Push(a.personaDialog, func() tea.Cmd {
+					a.personaDialog.Show()
+					return nil
+				})
 				return a, nil
+			case a.settingsManager.GetMenuModeFormatToggle():
+				return a, a.toggleOutputFormat()
 			}
 		}
 	}
 
-	// Update the alert model
-	outAlert, outCmd := a.alertModel.Update(msg)
-	a.alertModel = outAlert.(bubbleup.AlertModel)
-	cmds = append(cmds, outCmd)
+	// Keep the search input's cursor blinking while the dialog is open
+	if a.searchDialog.IsVisible() {
+		model, cmd := a.searchDialog.Update(msg)
+		a.searchDialog = model
+		cmds = append(cmds, cmd)
+	}
+	if a.saveDialog.IsVisible() {
+		model, cmd := a.saveDialog.Update(msg)
+		a.saveDialog = model
+		cmds = append(cmds, cmd)
+	}
+	if a.globInput.IsVisible() {
+		model, cmd := a.globInput.Update(msg)
+		a.globInput = model
+		cmds = append(cmds, cmd)
+	}
+	if a.timeInput.IsVisible() {
+		model, cmd := a.timeInput.Update(msg)
+		a.timeInput = model
+		cmds = append(cmds, cmd)
+	}
+	if a.nameInput.IsVisible() {
+		model, cmd := a.nameInput.Update(msg)
+		a.nameInput = model
+		cmds = append(cmds, cmd)
+	}
+	if a.lineRangeDialog.IsVisible() {
+		model, cmd := a.lineRangeDialog.Update(msg)
+		a.lineRangeDialog = model
+		cmds = append(cmds, cmd)
+	}
+	if a.tagInput.IsVisible() {
+		model, cmd := a.tagInput.Update(msg)
+		a.tagInput = model
+		cmds = append(cmds, cmd)
+	}
+	if a.personaWizard.IsVisible() {
+		model, cmd := a.personaWizard.Update(msg)
+		a.personaWizard = model
+		cmds = append(cmds, cmd)
+	}
+
+	// Update the notifications
+	notifications, notifyCmd := a.notifications.Update(msg)
+	a.notifications = notifications
+	cmds = append(cmds, notifyCmd)
 
 	// Update the focused panel
 	switch a.focused {
@@ -380,83 +1128,256 @@ func (a *App) View() string {
 	// Main layout
 	mainLayout := a.mainLayout()
 
-	// Show persona dialog if visible (takes priority over prompt dialog)
-	if a.personaDialog.IsVisible() {
-		dialogView := a.personaDialog.View()
-		// Render dialog over the background using Lipgloss v2 Place
-		backgroundStyle := lipglossv2.NewStyle().SetString(mainLayout)
-		overlayView := lipglossv2.Place(a.width, a.height, lipglossv2.Center, lipglossv2.Center, dialogView, lipglossv2.WithWhitespaceStyle(backgroundStyle))
-		// Render with alert notifications
-		return a.alertModel.Render(overlayView)
+	// Show the dialog opened last over it
+	if dialog := a.dialogs.Current(); dialog != nil {
+		return a.notifications.Render(a.viewAsOverlay(dialog, mainLayout))
 	}
 
-	// Show prompt dialog if visible
-	if a.promptDialog.IsVisible() {
-		dialogView := a.promptDialog.View()
-		// Render dialog over the background using Lipgloss v2 Place
-		backgroundStyle := lipglossv2.NewStyle().SetString(mainLayout)
-		overlayView := lipglossv2.Place(a.width, a.height, lipglossv2.Center, lipglossv2.Center, dialogView, lipglossv2.WithWhitespaceStyle(backgroundStyle))
-		// Render with alert notifications
-		return a.alertModel.Render(overlayView)
+	// Render main layout with notifications
+	return a.notifications.Render(mainLayout)
+}
+
+// updateDialog sends msg to dialog, the dialog on top of the stack
+func (a *App) updateDialog(dialog Dialog, msg tea.KeyMsg) tea.Cmd {
+	switch dialog := dialog.(type) {
+	case *HelpOverlayModel:
+		// The help key closes it again
+		if helpKey, err := config.ParseKeyBinding(a.settingsManager.GetHelpKey()); err == nil && helpKey.MatchesKeyMsg(msg) {
+			dialog.Hide()
+			return nil
+		}
+		model, cmd := dialog.Update(msg)
+		a.helpOverlay = model
+		return cmd
+	case *RecoveryDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.recoveryDialog = model
+		return cmd
+	case *TextDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.textDialog = model
+		return cmd
+	case *SearchDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.searchDialog = model
+		return cmd
+	case *GlobInputModel:
+		model, cmd := dialog.Update(msg)
+		a.globInput = model
+		return cmd
+	case *TimeInputModel:
+		model, cmd := dialog.Update(msg)
+		a.timeInput = model
+		return cmd
+	case *NameInputModel:
+		model, cmd := dialog.Update(msg)
+		a.nameInput = model
+		return cmd
+	case *PresetDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.presetDialog = model
+		return cmd
+	case *LineRangeDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.lineRangeDialog = model
+		return cmd
+	case *TagInputModel:
+		model, cmd := dialog.Update(msg)
+		a.tagInput = model
+		return cmd
+	case *SettingsPanelModel:
+		model, cmd := dialog.Update(msg)
+		a.settingsPanel = model
+		return cmd
+	case *SaveDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.saveDialog = model
+		return cmd
+	case *ManifestDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.manifestDialog = model
+		return cmd
+	case *HistoryDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.historyDialog = model
+		return cmd
+	case *PersonaCreateWizard:
+		model, cmd := dialog.Update(msg)
+		a.personaWizard = model
+		return cmd
+	case *PersonaDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.personaDialog = model
+		if a.debugLogger != nil {
+			a.debugLogger.Debug("forwarded key to persona dialog", "component", "app", "event", "key_forwarded",
+				"key", msg.String(), "dialog_visible", a.personaDialog.IsVisible())
+		}
+		return cmd
+	case *PromptDialogModel:
+		model, cmd := dialog.Update(msg)
+		a.promptDialog = model
+		return cmd
 	}
+	return nil
+}
 
-	// Render main layout with alert notifications
-	return a.alertModel.Render(mainLayout)
+// viewAsOverlay renders dialog centered over background
+func (a *App) viewAsOverlay(dialog Dialog, background string) string {
+	// Render dialog over the background using Lipgloss v2 Place
+	backgroundStyle := lipglossv2.NewStyle().SetString(background)
+	return lipglossv2.Place(a.width, a.height, lipglossv2.Center, lipglossv2.Center, dialog.View(), lipglossv2.WithWhitespaceStyle(backgroundStyle))
 }
 
 func (a *App) mainLayout() string {
-	// Calculate panel dimensions using layout config
+	a.syncLayoutSettings()
+
+	switch a.layoutConfig.Mode {
+	case LayoutVertical:
+		return a.mainLayoutVertical()
+	case LayoutCompact:
+		return a.mainLayoutCompact()
+	default:
+		return a.mainLayoutDefault()
+	}
+}
+
+// syncLayoutSettings picks up the configured split ratios, which may change on settings reload
+func (a *App) syncLayoutSettings() {
+	layoutSettings := a.settingsManager.GetLayoutSettings()
+	a.layoutConfig.TopHeightRatio = layoutSettings.TopHeightRatio
+	a.layoutConfig.LeftPanelRatio = layoutSettings.LeftPanelRatio
+}
+
+// panelSize is the outer size of a panel, including its border
+type panelSize struct {
+	Width  int
+	Height int
+}
+
+// panelSizes holds the outer sizes of the main panels. Preview is zero when the file
+// preview is disabled.
+type panelSizes struct {
+	FileTree panelSize
+	Preview  panelSize
+	Chat     panelSize
+	Selected panelSize
+}
+
+// panelLayout returns the outer sizes of the main panels for the current layout mode
+func (a *App) panelLayout() panelSizes {
 	topHeight := a.layoutConfig.TopPanelHeight(a.height)
 	bottomHeight := a.layoutConfig.BottomPanelHeight(a.height)
 	leftWidth := a.layoutConfig.LeftPanelWidth(a.width)
 	rightWidth := a.layoutConfig.RightPanelWidth(a.width)
 
+	var sizes panelSizes
+	if a.layoutConfig.Mode == LayoutVertical {
+		sizes = panelSizes{
+			FileTree: panelSize{leftWidth, a.layoutConfig.AvailableHeight(a.height)},
+			Chat:     panelSize{rightWidth, topHeight},
+			Selected: panelSize{rightWidth, bottomHeight},
+		}
+	} else {
+		sizes = panelSizes{
+			FileTree: panelSize{leftWidth, topHeight},
+			Chat:     panelSize{rightWidth, topHeight},
+			Selected: panelSize{a.width, bottomHeight},
+		}
+	}
+
+	// The preview takes the lower half of the file tree's space
+	if a.settingsManager.IsPreviewEnabled() {
+		sizes.Preview = panelSize{sizes.FileTree.Width, sizes.FileTree.Height / 2}
+		sizes.FileTree.Height -= sizes.Preview.Height
+	}
+	return sizes
+}
+
+// mainPanels renders the file tree, chat and selected files panels at their layout sizes
+func (a *App) mainPanels() (fileTreePanel, chatPanel, selectedPanel string) {
+	sizes := a.panelLayout()
+
 	// Create styles for panels
 	focusedBorder := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("69"))
+		BorderForeground(lipgloss.Color(a.theme.FocusBorder))
 
 	normalBorder := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(lipgloss.Color(a.theme.NormalBorder))
 
-	// File tree panel (top-left)
-	fileTreePanel := CreatePanel(
+	fileTreePanel = CreatePanel(
 		a.fileTree.View(),
 		a.focused == FileTreePanel,
 		normalBorder,
 		focusedBorder,
-		StretchWidth(leftWidth, true),
-		StretchHeight(topHeight, true),
+		StretchWidth(sizes.FileTree.Width, true),
+		StretchHeight(sizes.FileTree.Height, true),
 	)
+	if sizes.Preview.Height > 0 {
+		// The preview can't be focused, it follows the file tree cursor
+		previewPanel := CreatePanel(
+			a.preview.View(),
+			false,
+			normalBorder,
+			focusedBorder,
+			StretchWidth(sizes.Preview.Width, true),
+			StretchHeight(sizes.Preview.Height, true),
+		)
+		fileTreePanel = lipgloss.JoinVertical(lipgloss.Left, fileTreePanel, previewPanel)
+	}
 
-	// Chat panel (top-right)
-	chatPanel := CreatePanel(
+	chatPanel = CreatePanel(
 		a.chat.View(),
 		a.focused == ChatPanel,
 		normalBorder,
 		focusedBorder,
-		StretchWidth(rightWidth, true),
-		StretchHeight(topHeight, true),
+		StretchWidth(sizes.Chat.Width, true),
+		StretchHeight(sizes.Chat.Height, true),
 	)
 
-	// Selected files panel (middle row, full width)
-	selectedPanel := CreatePanel(
+	selectedPanel = CreatePanel(
 		a.selectedFiles.View(),
 		a.focused == SelectedFilesPanel,
 		normalBorder,
 		focusedBorder,
-		StretchWidth(a.width, true),
-		StretchHeight(bottomHeight, true),
+		StretchWidth(sizes.Selected.Width, true),
+		StretchHeight(sizes.Selected.Height, true),
 	)
+	return fileTreePanel, chatPanel, selectedPanel
+}
+
+// mainLayoutDefault puts the file tree and chat side by side above the selected files
+func (a *App) mainLayoutDefault() string {
+	fileTreePanel, chatPanel, selectedPanel := a.mainPanels()
+	topRow := lipgloss.JoinHorizontal(lipgloss.Top, fileTreePanel, chatPanel)
+	return lipgloss.JoinVertical(lipgloss.Left, a.header(), topRow, selectedPanel, a.footer())
+}
+
+// mainLayoutVertical gives the file tree the full height on the left, with the chat
+// above the selected files on the right
+func (a *App) mainLayoutVertical() string {
+	fileTreePanel, chatPanel, selectedPanel := a.mainPanels()
+	rightColumn := lipgloss.JoinVertical(lipgloss.Left, chatPanel, selectedPanel)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, fileTreePanel, rightColumn)
+	return lipgloss.JoinVertical(lipgloss.Left, a.header(), body, a.footer())
+}
+
+// mainLayoutCompact is the default arrangement without the header
+func (a *App) mainLayoutCompact() string {
+	fileTreePanel, chatPanel, selectedPanel := a.mainPanels()
+	topRow := lipgloss.JoinHorizontal(lipgloss.Top, fileTreePanel, chatPanel)
+	return lipgloss.JoinVertical(lipgloss.Left, topRow, selectedPanel, a.footer())
+}
 
-	// Create header with persona information
+// header renders the header showing the active personas
+func (a *App) header() string {
 	headerStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		Width(StretchWidth(a.width, true)).
 		Height(1).
 		Padding(0, 2).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(lipgloss.Color(a.theme.NormalBorder))
 
 	// Get active personas, default to "default" if none set
 	activePersonas := a.workspace.ActivePersonas
@@ -470,9 +1391,14 @@ func (a *App) mainLayout() string {
 	} else {
 		headerContent = "Personas: " + strings.Join(activePersonas, ", ")
 	}
-	header := headerStyle.Render(headerContent)
+	if a.buildingPrompt {
+		headerContent += "  " + a.spinner.View() + " building prompt…"
+	}
+	return headerStyle.Render(headerContent)
+}
 
-	// Create footer with menu button
+// footer renders the footer with the menu button and key hints
+func (a *App) footer() string {
 	footerStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		Width(StretchWidth(a.width, true)).
@@ -481,9 +1407,9 @@ func (a *App) mainLayout() string {
 
 	// Apply focused style to footer if it has focus
 	if a.focused == FooterMenuPanel {
-		footerStyle = footerStyle.BorderForeground(lipgloss.Color("69"))
+		footerStyle = footerStyle.BorderForeground(lipgloss.Color(a.theme.FocusBorder))
 	} else {
-		footerStyle = footerStyle.BorderForeground(lipgloss.Color("240"))
+		footerStyle = footerStyle.BorderForeground(lipgloss.Color(a.theme.NormalBorder))
 	}
 
 	// Display appropriate menu activation key based on mode
@@ -502,36 +1428,287 @@ func (a *App) mainLayout() string {
 		debugInfo = fmt.Sprintf(" • %s: debug", debugToggleKey)
 	}
 
-	footerContent := "menu (" + menuActivationDisplay + ") • personas (" + a.settingsManager.GetPersonaMenuKey() + ")" + debugInfo
+	footerContent := "menu (" + menuActivationDisplay + ") • personas (" + a.settingsManager.GetPersonaMenuKey() + ")" +
+		" • search (" + a.settingsManager.GetQuickOpenKey() + ")" +
+		" • history (" + a.settingsManager.GetHistoryKey() + ")" +
+		" • export (" + a.settingsManager.GetExportKey() + ")" +
+		" • format: " + string(a.outputFormat()) + " (" + a.settingsManager.GetMenuModeFormatToggle() + ")" + debugInfo
 
 	// Add contextual help for selected files panel
 	if a.focused == SelectedFilesPanel {
-		footerContent += " • ctrl+c: clear file selection"
+		footerContent += " • ctrl+c: copy paths • ctrl+d: clear file selection"
 	}
-	footer := footerStyle.Render(footerContent)
+	return footerStyle.Render(footerContent)
+}
 
-	// Layout the panels
-	// topRow := lipgloss.JoinHorizontal(lipgloss.Top, fileTreePanel, selectedPanel)
-	topRow := lipgloss.JoinHorizontal(lipgloss.Top, fileTreePanel, chatPanel)
+// createAlert creates a command showing a notification for the configured TTL
+func (a *App) createAlert(alertType string, message string) tea.Cmd {
+	// Pick up TTL changes from reloaded settings
+	a.notifications.SetTTL(time.Duration(a.settingsManager.GetNotificationTTL()) * time.Second)
+	return Notify(alertType, message)
+}
+
+// buildPrompt generates the prompt for the current selection in the workspace's output format
+// and records it in the prompt history
+func (a *App) buildPrompt() (string, error) {
+	userPrompt := a.chat.textarea.Value()
+	generatedPrompt, err := prompt.BuildWithOptions(a.targetDir, a.selectedFiles.GetPaths(), userPrompt, a.workspace.ActivePersonas, a.outputFormat(), a.buildOptions())
+	if err != nil {
+		return "", err
+	}
 
-	// return lipgloss.JoinVertical(lipgloss.Left, header, topRow, chatPanel, footer)
-	return lipgloss.JoinVertical(lipgloss.Left, header, topRow, selectedPanel, footer)
+	a.recordPrompt(userPrompt, generatedPrompt)
+	return generatedPrompt, nil
 }
 
-// createAlert creates an alert command with configured TTL
-func (a *App) createAlert(alertType string, message string) tea.Cmd {
-	// TODO: The bubbleup library doesn't currently support configurable TTL
-	// For now, we just create the standard alert. The TTL configuration is ready
-	// for when we either:
-	// 1. Find a way to configure TTL in bubbleup
-	// 2. Switch to a different notification library
-	// 3. Create a custom notification system
+// recordPrompt remembers a generated prompt as the latest one and adds it to the prompt history
+func (a *App) recordPrompt(userPrompt, generatedPrompt string) {
+	a.lastPrompt = generatedPrompt
+	a.history.Push(userPrompt, generatedPrompt)
+	if err := a.history.Save(); err != nil && a.debugLogger != nil {
+		a.debugLogger.Error("failed to save prompt history", "component", "app", "event", "history_save_failed", "error", err)
+	}
+}
+
+// GeneratePromptAsync returns a command that builds the prompt for the current selection
+// in the background, so reading large selections doesn't freeze the UI. The header shows
+// a spinner until the PromptBuiltMsg arrives; with copyPrompt the prompt is then copied
+// to the clipboard instead of shown. It returns nil while another build is running.
+func (a *App) GeneratePromptAsync(copyPrompt bool) tea.Cmd {
+	if a.buildingPrompt {
+		return nil
+	}
+	a.buildingPrompt = true
+
+	// Capture the inputs now; the build runs outside the update loop
+	targetDir := a.targetDir
+	paths := a.selectedFiles.GetPaths()
+	userPrompt := a.chat.textarea.Value()
+	personas := slices.Clone(a.workspace.ActivePersonas)
+	format := a.outputFormat()
+	opts := a.buildOptions()
+	build := func() tea.Msg {
+		content, err := prompt.BuildWithOptions(targetDir, paths, userPrompt, personas, format, opts)
+		return PromptBuiltMsg{Content: content, Err: err, UserPrompt: userPrompt, Copy: copyPrompt}
+	}
+	return tea.Batch(build, a.spinner.Tick)
+}
 
-	// Get the configured TTL (currently unused but ready)
-	ttlSeconds := a.settingsManager.GetNotificationTTL()
-	_ = ttlSeconds // Silence unused variable warning
+// copyPrompt copies a generated prompt to the clipboard, warning when it is large
+func (a *App) copyPrompt(generatedPrompt string) tea.Cmd {
+	if err := a.clipboard.WriteAll(generatedPrompt); err != nil {
+		return a.createAlert(ErrorAlert, "clipboard error")
+	}
+	if warnCmd := a.tokenWarning(generatedPrompt); warnCmd != nil {
+		return warnCmd
+	}
+	return a.createAlert(InfoAlert, "prompt copied")
+}
 
-	return a.alertModel.NewAlertCmd(alertType, message)
+// RefreshFile re-reads path after it may have changed outside the app, updating
+// its stats in the selected files panel if it is selected. The returned command
+// estimates its tokens again.
+func (a *App) RefreshFile(path string) tea.Cmd {
+	a.selectedFiles.RefreshFile(path)
+	return a.selectedFiles.estimateTokens()
+}
+
+// bindingValues returns the current key of each binding that can be changed in the settings panel
+func (a *App) bindingValues() map[string]string {
+	values := make(map[string]string, len(config.BindingFields))
+	for _, field := range config.BindingFields {
+		values[field.Name] = a.settingsManager.GetBinding(field.Name)
+	}
+	return values
+}
+
+// buildOptions returns the line ranges, tags, buckets, comment stripping, encoding normalization, overview files, size limit, modified-since filter and file cache applied to selected files in generated prompts
+func (a *App) buildOptions() prompt.BuildOptions {
+	return prompt.BuildOptions{
+		LineRanges:              a.selectedFiles.GetLineRanges(),
+		Tags:                    a.selectedFiles.GetTags(),
+		Buckets:                 a.selectedFiles.GetFileBuckets(),
+		StripComments:           a.settingsManager.IsStripCommentsEnabled(),
+		CommentLanguages:        a.settingsManager.GetStripCommentLanguages(),
+		NormalizeEncoding:       a.settingsManager.IsNormalizeEncodingEnabled(),
+		OverviewFiles:           a.settingsManager.GetOverviewFiles(),
+		IncludeAllOverviewFiles: a.settingsManager.IsIncludeAllOverviewFilesEnabled(),
+		MaxFileSize:             a.settingsManager.GetMaxFileSizeBytes(),
+		ModifiedSince:           a.workspace.ModifiedSinceFilter,
+		Cache:                   a.promptCache,
+		Logger:                  a.debugLogger,
+	}
+}
+
+// storeSelection copies the selected files, their buckets and sort mode, and
+// the expanded directories of the file tree into the workspace state
+func (a *App) storeSelection() {
+	a.workspace.SelectedFiles = a.selectedFiles.GetFileStates()
+	a.workspace.Buckets = a.selectedFiles.GetBucketPaths()
+	a.workspace.SelectedSortMode = a.selectedFiles.GetSortMode().String()
+	a.workspace.ExpandedPaths = a.fileTree.GetExpandedPaths()
+}
+
+// syncWatchedFiles points the watcher at the currently selected files
+func (a *App) syncWatchedFiles() {
+	if a.watcher == nil {
+		return
+	}
+	if err := a.watcher.SetFiles(a.selectedFiles.GetPaths()); err != nil && a.debugLogger != nil {
+		a.debugLogger.Error("failed to watch selected files", "component", "app", "event", "watch_failed", "error", err)
+	}
+}
+
+// exportPrompt generates the prompt and writes it to path
+func (a *App) exportPrompt(path string) tea.Cmd {
+	generatedPrompt, err := a.buildPrompt()
+	if err != nil {
+		return a.createAlert(ErrorAlert, "error building prompt: "+err.Error())
+	}
+
+	if err := os.WriteFile(path, []byte(generatedPrompt), 0644); err != nil {
+		return a.createAlert(ErrorAlert, "error saving prompt")
+	}
+
+	if warnCmd := a.tokenWarning(generatedPrompt); warnCmd != nil {
+		return warnCmd
+	}
+	return a.createAlert(InfoAlert, "prompt saved to "+filepath.Base(path))
+}
+
+// exportManifest copies the paths of the selected files to the clipboard in format
+func (a *App) exportManifest(format config.ManifestFormat) tea.Cmd {
+	a.storeSelection()
+	var manifest strings.Builder
+	if err := config.ExportManifest(a.workspace, format, &manifest); err != nil {
+		return a.createAlert(ErrorAlert, err.Error())
+	}
+	if err := a.clipboard.WriteAll(manifest.String()); err != nil {
+		return a.createAlert(ErrorAlert, "clipboard error")
+	}
+	return a.createAlert(InfoAlert, fmt.Sprintf("copied %d file paths", len(a.workspace.SelectedFiles)))
+}
+
+// copyPaths copies paths to the clipboard relative to the target directory, one per line
+func (a *App) copyPaths(paths []string) tea.Cmd {
+	var b strings.Builder
+	for _, path := range paths {
+		if rel, err := filepath.Rel(a.targetDir, path); err == nil {
+			path = rel
+		}
+		b.WriteString(filepath.ToSlash(path))
+		b.WriteString("\n")
+	}
+	if err := a.clipboard.WriteAll(b.String()); err != nil {
+		return a.createAlert(ErrorAlert, "clipboard error")
+	}
+	if len(paths) == 1 {
+		return a.createAlert(InfoAlert, "copied 1 path to clipboard")
+	}
+	return a.createAlert(InfoAlert, fmt.Sprintf("copied %d paths to clipboard", len(paths)))
+}
+
+// deliverPrompt generates the prompt and sends it to the configured webhook in the background
+func (a *App) deliverPrompt() tea.Cmd {
+	webhook := a.settingsManager.GetWebhookSettings()
+	if webhook.URL == "" {
+		return a.createAlert(WarnAlert, "no webhook url configured")
+	}
+
+	generatedPrompt, err := a.buildPrompt()
+	if err != nil {
+		return a.createAlert(ErrorAlert, "error building prompt: "+err.Error())
+	}
+
+	cfg := prompt.WebhookConfig{
+		URL:     webhook.URL,
+		Method:  webhook.Method,
+		Headers: webhook.Headers,
+		Timeout: time.Duration(webhook.TimeoutSeconds) * time.Second,
+		Format:  a.outputFormat(),
+	}
+	return func() tea.Msg {
+		return WebhookDeliveredMsg{Err: prompt.DeliverPrompt(generatedPrompt, cfg)}
+	}
+}
+
+// tokenWarning returns a warning alert if the prompt's estimated token count exceeds the configured threshold
+func (a *App) tokenWarning(generatedPrompt string) tea.Cmd {
+	tokens := prompt.EstimateTokens(generatedPrompt)
+	if tokens <= a.settingsManager.GetTokenWarningThreshold() {
+		return nil
+	}
+	return a.createAlert(WarnAlert, fmt.Sprintf("prompt is ~%s tokens", formatTokenCount(tokens)))
+}
+
+// outputFormat returns the workspace's prompt output format, defaulting to XML
+func (a *App) outputFormat() prompt.OutputFormat {
+	switch format := prompt.OutputFormat(a.workspace.OutputFormat); format {
+	case prompt.OutputJSON, prompt.OutputMarkdown:
+		return format
+	default:
+		return prompt.OutputXML
+	}
+}
+
+// applyPreset replaces the selected files with those of preset that still
+// exist, in the order they were saved and with the tags they were saved with
+func (a *App) applyPreset(preset config.SelectionPreset) tea.Cmd {
+	a.selectedFiles.files = []SelectedFile{}
+	selected := make(map[string]bool)
+	missing := 0
+	for i, path := range preset.Files {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			missing++
+			continue
+		}
+		a.selectedFiles.AddFile(filepath.Base(path), path)
+		if i < len(preset.Tags) {
+			a.selectedFiles.SetTags(path, ParseTags(preset.Tags[i]))
+		}
+		selected[path] = true
+	}
+	a.fileTree.selected = selected
+	a.fileTree.pushSelection()
+	a.fileTree.refreshItems()
+	if refused := a.updateSelectedFilesFromSelection(selected); len(refused) > 0 {
+		a.fileTree.deselectFiles(refused)
+	}
+	a.storeSelection()
+	a.configManager.Save()
+	a.syncWatchedFiles()
+
+	alert := a.createAlert(InfoAlert, fmt.Sprintf("selected %d files from preset %s", len(a.selectedFiles.files), preset.Name))
+	if missing > 0 {
+		alert = a.createAlert(WarnAlert, fmt.Sprintf("selected %d files from preset %s; %d no longer exist", len(a.selectedFiles.files), preset.Name, missing))
+	}
+	return tea.Batch(alert, a.selectedFiles.estimateTokens())
+}
+
+// setModifiedSince leaves files last modified before since out of prompts,
+// or includes every file again if since is nil
+func (a *App) setModifiedSince(since *time.Time) tea.Cmd {
+	a.workspace.ModifiedSinceFilter = since
+	a.fileTree.SetModifiedSince(since)
+	a.configManager.Save()
+	if since == nil {
+		return a.createAlert(InfoAlert, "including files however old they are")
+	}
+	return a.createAlert(InfoAlert, "only including files modified since "+since.Local().Format(modifiedSinceLayout))
+}
+
+// toggleOutputFormat cycles the workspace between XML, JSON and Markdown output and persists the choice
+func (a *App) toggleOutputFormat() tea.Cmd {
+	switch a.outputFormat() {
+	case prompt.OutputXML:
+		a.workspace.OutputFormat = string(prompt.OutputJSON)
+	case prompt.OutputJSON:
+		a.workspace.OutputFormat = string(prompt.OutputMarkdown)
+	default:
+		a.workspace.OutputFormat = string(prompt.OutputXML)
+	}
+	a.configManager.Save()
+	return a.createAlert(InfoAlert, "output format: "+a.workspace.OutputFormat)
 }
 
 // nextPanel returns a command to move focus to the next panel
@@ -575,7 +1752,7 @@ func (a *App) prevPanel() tea.Cmd {
 // handleMouseClick determines which panel was clicked and returns a command to set focus
 func (a *App) handleMouseClick(x, y int) tea.Cmd {
 	// Calculate panel dimensions using layout config - matches mainLayout()
-	headerHeight := a.layoutConfig.HeaderHeight
+	headerHeight := a.layoutConfig.VisibleHeaderHeight()
 	footerHeight := a.layoutConfig.FooterHeight
 	topHeight := a.layoutConfig.TopPanelHeight(a.height)
 	bottomHeight := a.layoutConfig.BottomPanelHeight(a.height)
@@ -588,7 +1765,10 @@ func (a *App) handleMouseClick(x, y int) tea.Cmd {
 	}
 
 	var targetFocus FocusedPanel
-	if y < headerHeight+topHeight {
+	if a.layoutConfig.Mode == LayoutVertical && x < leftWidth && y < headerHeight+topHeight+bottomHeight {
+		// The file tree spans the full height on the left
+		targetFocus = FileTreePanel
+	} else if y < headerHeight+topHeight {
 		// Check if click is in the left half (file tree panel)
 		if x < leftWidth {
 			targetFocus = FileTreePanel
@@ -636,7 +1816,10 @@ func (a *App) handleHeaderClick(x, y int) tea.Cmd {
 	if x >= personaStartX && x <= personaEndX && y == 1 { // y=1 is the text line in the header
 		// Click is on persona text - show persona dialog
 		a.personaDialog.SetActivePersonas(a.workspace.ActivePersonas)
-		a.personaDialog.Show()
+		a.dialogs.Push(a.personaDialog, func() tea.Cmd {
+			a.personaDialog.Show()
+			return nil
+		})
 		return nil
 	}
 
@@ -644,24 +1827,42 @@ func (a *App) handleHeaderClick(x, y int) tea.Cmd {
 	return nil
 }
 
-// updateSelectedFilesFromSelection synchronizes the selected files panel with file tree selection
-func (a *App) updateSelectedFilesFromSelection(selectedFiles map[string]bool) {
-	// Clear current selection
-	a.selectedFiles.files = []SelectedFile{}
+// updateSelectedFilesFromSelection synchronizes the selected files panel with file tree selection.
+// It returns the paths of newly selected files that were refused because they are binary.
+func (a *App) updateSelectedFilesFromSelection(selectedFiles map[string]bool) []string {
+	// Keep files that are still selected in their current order
+	kept := []SelectedFile{}
+	for _, file := range a.selectedFiles.files {
+		if selectedFiles[file.Path] {
+			kept = append(kept, file)
+		}
+	}
+	a.selectedFiles.files = kept
 
-	// Add all currently selected files
+	// Append newly selected files in path order
+	var added []string
 	for path, selected := range selectedFiles {
 		if selected {
-			a.selectedFiles.AddFile(filepath.Base(path), path)
+			added = append(added, path)
+		}
+	}
+	sort.Strings(added)
+	var refused []string
+	for _, path := range added {
+		if err := a.selectedFiles.AddFile(filepath.Base(path), path); err != nil {
+			refused = append(refused, path)
 		}
 	}
 
-	// Reset cursor if needed
-	if len(a.selectedFiles.files) == 0 {
-		a.selectedFiles.cursor = 0
-	} else if a.selectedFiles.cursor >= len(a.selectedFiles.files) {
-		a.selectedFiles.cursor = len(a.selectedFiles.files) - 1
+	// Refresh sizes and mark token estimates stale, as files may have changed on disk
+	for i := range a.selectedFiles.files {
+		a.selectedFiles.refreshStats(i)
 	}
+	a.selectedFiles.sortFiles(a.selectedFiles.sortMode)
+
+	// Reset cursor if needed
+	a.selectedFiles.clampCursor()
+	return refused
 }
 
 // handleMenuActivation checks if the given key message should activate menu mode
@@ -682,7 +1883,7 @@ func (a *App) handleMenuActivation(msg tea.KeyMsg) tea.Cmd {
 		// Legacy mode: menu binding only works when footer has focus
 		if a.focused == FooterMenuPanel && msg.String() == a.settingsManager.GetMenuActivationKey() {
 			// In legacy mode, this just shows a notification since menu is already "active"
-			return a.createAlert("info", "menu mode activated")
+			return a.createAlert(InfoAlert, "menu mode activated")
 		}
 		return nil
 	}
@@ -702,7 +1903,7 @@ func (a *App) handleMenuActivation(msg tea.KeyMsg) tea.Cmd {
 	if keyCombination.MatchesKeyMsg(msg) {
 		return tea.Batch(
 			a.enterMenuMode(),
-			a.createAlert("info", "menu mode activated"),
+			a.createAlert(InfoAlert, "menu mode activated"),
 		)
 	}
 
@@ -719,8 +1920,17 @@ func (a *App) exitMenuMode() tea.Cmd {
 	return a.setMenuMode(false)
 }
 
+// componentLogger returns logger with every record tagged with component, or nil if logger is nil
+func componentLogger(logger *slog.Logger, component string) *slog.Logger {
+	if logger == nil {
+		return nil
+	}
+	return logger.With("component", component)
+}
+
 // initializeDebugLogger creates and configures a debug logger based on settings
-func initializeDebugLogger(targetDir string, settingsManager *config.SettingsManager) *log.Logger {
+// that writes JSON records to the configured log file
+func initializeDebugLogger(targetDir string, settingsManager *config.SettingsManager) *slog.Logger {
 	// Only initialize logger if file logging is enabled
 	if !settingsManager.IsDebugFileLoggingEnabled() {
 		return nil
@@ -744,11 +1954,11 @@ func initializeDebugLogger(targetDir string, settingsManager *config.SettingsMan
 		return nil
 	}
 
-	// Create logger with timestamp prefix
-	logger := log.New(file, "", log.LstdFlags)
+	// Create a JSON logger that records debug messages too
+	logger := slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
 	// Log initialization message
-	logger.Printf("=== Debug session started at %s ===", time.Now().Format("2006-01-02 15:04:05"))
+	logger.Info("debug session started", "component", "app", "event", "session_started", "target_dir", targetDir)
 
 	return logger
 }
@@ -772,6 +1982,37 @@ func (a *App) toggleDebugMode() tea.Cmd {
 	}
 }
 
+// resizePanels propagates the panel sizes of the current layout mode to the sub-models that need them
+func (a *App) resizePanels() {
+	a.syncLayoutSettings()
+	sizes := a.panelLayout()
+	// Content is the panel size minus its border and padding
+	a.fileTree.SetSize(sizes.FileTree.Width-2-2, sizes.FileTree.Height-2-2)
+	a.preview.SetSize(sizes.Preview.Width-2-2, sizes.Preview.Height-2-2)
+	a.chat.SetSize(sizes.Chat.Width-2-2, sizes.Chat.Height-2-2)
+	a.selectedFiles.SetSize(sizes.Selected.Width-2-2, sizes.Selected.Height-2-2)
+}
+
+// showGitignoreReport opens a dialog listing the ignore patterns with what each one matches
+func (a *App) showGitignoreReport() tea.Cmd {
+	results, err := filesystem.ValidateGitignore(a.targetDir)
+	if err != nil {
+		return a.createAlert(ErrorAlert, "failed to check ignore patterns: "+err.Error())
+	}
+	a.dialogs.Push(a.textDialog, func() tea.Cmd {
+		a.textDialog.Show("Ignore Patterns", formatValidationReport(results))
+		return nil
+	})
+	return nil
+}
+
+// toggleLayout switches to the next panel layout for this session
+func (a *App) toggleLayout() tea.Cmd {
+	a.layoutConfig.Mode = a.layoutConfig.Mode.Next()
+	a.resizePanels()
+	return a.createAlert(InfoAlert, fmt.Sprintf("Layout: %s", a.layoutConfig.Mode))
+}
+
 func (a *App) updateLayout(width, height int) tea.Cmd {
 	return func() tea.Msg {
 		return LayoutChangeMsg{Width: width, Height: height}
@@ -787,7 +2028,7 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Validate focus change
 		if !a.isValidPanel(msg.Panel) {
 			if a.debugMode {
-				cmds = append(cmds, a.createAlert("error", "Invalid focus panel"))
+				cmds = append(cmds, a.createAlert(ErrorAlert, "Invalid focus panel"))
 			}
 			return a, tea.Batch(cmds...)
 		}
@@ -804,8 +2045,8 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Debug log state changes
 				if a.debugMode && a.debugLogger != nil {
-					a.debugLogger.Printf("STATE: Focus changed %v→%v, MenuMode %v→%v",
-						oldFocus, a.focused, oldMenuMode, a.menuBindingMode)
+					a.debugLogger.Debug("focus changed", "component", "app", "event", "focus_changed",
+						"from", int(oldFocus), "to", int(a.focused), "menu_mode_from", oldMenuMode, "menu_mode_to", a.menuBindingMode)
 				}
 			}
 		}
@@ -823,8 +2064,8 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Debug log state changes
 				if a.debugMode && a.debugLogger != nil {
-					a.debugLogger.Printf("STATE: MenuMode %v→%v, Focus %v→%v",
-						oldMenuMode, a.menuBindingMode, oldFocus, a.focused)
+					a.debugLogger.Debug("menu mode changed", "component", "app", "event", "menu_mode_changed",
+						"from", oldMenuMode, "to", a.menuBindingMode, "focus_from", int(oldFocus), "focus_to", int(a.focused))
 				}
 			}
 		}
@@ -837,7 +2078,8 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Debug log state changes (before mode is disabled)
 			if a.debugLogger != nil {
-				a.debugLogger.Printf("STATE: DebugMode %v→%v", oldDebugMode, a.debugMode)
+				a.debugLogger.Debug("debug mode changed", "component", "app", "event", "debug_mode_changed",
+					"from", oldDebugMode, "to", a.debugMode)
 			}
 
 			// Show notification about debug mode change
@@ -847,14 +2089,14 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				message = "Debug mode OFF"
 			}
-			cmds = append(cmds, a.createAlert("info", message))
+			cmds = append(cmds, a.createAlert(InfoAlert, message))
 		}
 
 	case LayoutChangeMsg:
 		// Validate layout dimensions
 		if msg.Width <= 0 || msg.Height <= 0 {
 			if a.debugMode {
-				cmds = append(cmds, a.createAlert("error", "Invalid layout dimensions"))
+				cmds = append(cmds, a.createAlert(ErrorAlert, "Invalid layout dimensions"))
 			}
 			return a, tea.Batch(cmds...)
 		}
@@ -868,6 +2110,21 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Update dialogs with new size
 			a.promptDialog.SetSize(msg.Width, msg.Height)
 			a.personaDialog.SetSize(msg.Width, msg.Height)
+			a.personaWizard.SetSize(msg.Width, msg.Height)
+			a.searchDialog.SetSize(msg.Width, msg.Height)
+			a.historyDialog.SetSize(msg.Width, msg.Height)
+			a.saveDialog.SetSize(msg.Width, msg.Height)
+			a.manifestDialog.SetSize(msg.Width, msg.Height)
+			a.globInput.SetSize(msg.Width, msg.Height)
+			a.timeInput.SetSize(msg.Width, msg.Height)
+			a.presetDialog.SetSize(msg.Width, msg.Height)
+			a.nameInput.SetSize(msg.Width, msg.Height)
+			a.lineRangeDialog.SetSize(msg.Width, msg.Height)
+			a.tagInput.SetSize(msg.Width, msg.Height)
+			a.settingsPanel.SetSize(msg.Width, msg.Height)
+			a.helpOverlay.SetSize(msg.Width, msg.Height)
+			a.textDialog.SetSize(msg.Width, msg.Height)
+			a.recoveryDialog.SetSize(msg.Width, msg.Height)
 
 			// Update notification width to 30% of interface width, with reasonable bounds
 			notificationWidth := int(float64(msg.Width) * 0.3)
@@ -877,29 +2134,14 @@ func (a *App) handleStateChange(msg tea.Msg) (tea.Model, tea.Cmd) {
 				notificationWidth = 80 // Maximum width to prevent overly wide notifications
 			}
 
-			// Create new AlertModel with updated width
-			a.alertModel = *bubbleup.NewAlertModel(notificationWidth, true)
-
-			// Propagate calculated panel sizes to sub-models that need them
-			headerHeight := 3 // Single line header with padding
-			footerHeight := 3 // Single line footer with padding
-			availableHeight := a.height - headerHeight - footerHeight
-			topHeight := int(float64(availableHeight) * 0.66)
-			leftWidth := a.width / 2
-			contentWidth := leftWidth - 2 - 2  // border width minus border padding
-			contentHeight := topHeight - 2 - 2 // border height minus border padding
-			a.fileTree.SetSize(contentWidth, contentHeight)
-
-			// Set size for chat panel (right side of top row)
-			rightWidth := a.width - leftWidth
-			chatContentWidth := rightWidth - 2 - 2 // border width minus border padding
-			chatContentHeight := topHeight - 2 - 2 // border height minus border padding
-			a.chat.SetSize(chatContentWidth, chatContentHeight)
+			a.notifications.SetWidth(notificationWidth)
+
+			a.resizePanels()
 
 			// Debug log state changes
 			if a.debugMode && a.debugLogger != nil {
-				a.debugLogger.Printf("STATE: Layout changed %dx%d→%dx%d",
-					oldWidth, oldHeight, a.width, a.height)
+				a.debugLogger.Debug("layout changed", "component", "app", "event", "layout_changed",
+					"old_width", oldWidth, "old_height", oldHeight, "width", a.width, "height", a.height)
 			}
 		}
 
@@ -916,6 +2158,24 @@ func (a *App) isValidPanel(panel FocusedPanel) bool {
 	return panel >= FileTreePanel && panel <= FooterMenuPanel
 }
 
+// checkStateInvariants returns an error alert when the state is inconsistent.
+// A violation is alerted once, until the state is consistent again.
+func (a *App) checkStateInvariants() tea.Cmd {
+	err := a.validateStateInvariants()
+	if err == nil {
+		a.invariantError = ""
+		return nil
+	}
+	if err.Error() == a.invariantError {
+		return nil
+	}
+	a.invariantError = err.Error()
+	if a.debugLogger != nil {
+		a.debugLogger.Error("state invariant violated", "component", "app", "event", "invariant_violated", "error", err)
+	}
+	return a.createAlert(ErrorAlert, "invariant violated: "+err.Error())
+}
+
 // validateStateInvariants checks that the current state is consistent
 func (a *App) validateStateInvariants() error {
 	// Check focus is valid
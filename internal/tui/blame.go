@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"coding-prompts-tui/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BlameFinishedMsg is sent when the blame started by BlameFile is ready
+type BlameFinishedMsg struct {
+	Path  string
+	Lines []filesystem.BlameLine
+	Err   error
+}
+
+// BlameFile returns a command that runs git blame on path in the background
+func BlameFile(repoRoot, path string) tea.Cmd {
+	return func() tea.Msg {
+		lines, err := filesystem.GetBlame(repoRoot, path)
+		return BlameFinishedMsg{Path: path, Lines: lines, Err: err}
+	}
+}
+
+// formatBlame lists each line of a file with the short commit, author and
+// summary of the commit that last changed it
+func formatBlame(lines []filesystem.BlameLine) string {
+	if len(lines) == 0 {
+		return "The file is empty."
+	}
+
+	commitStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	width := len(fmt.Sprint(lines[len(lines)-1].Line))
+
+	var b strings.Builder
+	for _, line := range lines {
+		commit := line.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		info := fmt.Sprintf("%-16.16s %-24.24s", line.Author, line.Summary)
+		b.WriteString(fmt.Sprintf("%*d %s %s %s\n", width, line.Line, commitStyle.Render(commit), infoStyle.Render(info), line.Content))
+	}
+	return b.String()
+}
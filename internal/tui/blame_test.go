@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBlameKey(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	model := NewFileTreeModel(dir, []string{}, DefaultTheme())
+	model.items = []filesystem.FileTreeItem{{Name: "main.go", Path: path}}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if cmd == nil {
+		t.Fatal("Expected b to blame the file")
+	}
+	msg, ok := cmd().(BlameFinishedMsg)
+	if !ok || msg.Path != path || !errors.Is(msg.Err, filesystem.ErrNotGitRepository) {
+		t.Errorf("Expected a BlameFinishedMsg saying the directory is not a repository, got %#v", msg)
+	}
+}
+
+func TestBlameFinishedShowsDialog(t *testing.T) {
+	app := createTestApp(t)
+
+	app.Update(BlameFinishedMsg{Path: "/project/main.go", Err: filesystem.ErrNotGitRepository})
+	if !app.textDialog.IsVisible() || !strings.Contains(app.textDialog.content, "Not a git repository") {
+		t.Errorf("Expected a friendly message outside a repository, got %q", app.textDialog.content)
+	}
+	app.textDialog.Hide()
+
+	lines := []filesystem.BlameLine{
+		{Line: 1, Author: "Alice", Commit: "1a2b3c4d5e6f", Summary: "Add main", Content: "package main"},
+	}
+	app.Update(BlameFinishedMsg{Path: "/project/main.go", Lines: lines})
+	if app.textDialog.title != "Blame: main.go" {
+		t.Errorf("Expected the blame dialog title, got %q", app.textDialog.title)
+	}
+	for _, want := range []string{"1a2b3c4d", "Alice", "Add main", "package main"} {
+		if !strings.Contains(app.textDialog.content, want) {
+			t.Errorf("Expected %q in the blame, got:\n%s", want, app.textDialog.content)
+		}
+	}
+	if strings.Contains(app.textDialog.content, "1a2b3c4d5e") {
+		t.Errorf("Expected the commit to be shortened, got:\n%s", app.textDialog.content)
+	}
+
+	_, cmd := app.Update(BlameFinishedMsg{Path: "/project/main.go", Err: errors.New("no such path")})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != ErrorAlert {
+		t.Errorf("Expected an error alert for other failures, got %#v", cmd())
+	}
+}
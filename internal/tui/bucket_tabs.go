@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Buckets lists the buckets selected files can be put in, in prompt order.
+// Keys 1 to 3 switch between them; files are in the first bucket by default.
+var Buckets = []string{"context", "focus", "reference"}
+
+// DefaultBucket is the bucket of files that weren't put in another one
+var DefaultBucket = Buckets[0]
+
+// BucketedSelection holds the selected files of each bucket, by bucket name
+type BucketedSelection map[string][]SelectedFile
+
+// BucketTabModel renders the bucket tabs above the selected files
+type BucketTabModel struct {
+	active string
+	theme  Theme
+}
+
+// NewBucketTabModel creates a new bucket tab model with the first bucket active
+func NewBucketTabModel(theme Theme) *BucketTabModel {
+	return &BucketTabModel{active: DefaultBucket, theme: theme}
+}
+
+// Active returns the name of the active bucket
+func (m *BucketTabModel) Active() string {
+	return m.active
+}
+
+// SetActive makes the bucket at index (0-based) active. It returns false if
+// there is no such bucket.
+func (m *BucketTabModel) SetActive(index int) bool {
+	if index < 0 || index >= len(Buckets) {
+		return false
+	}
+	m.active = Buckets[index]
+	return true
+}
+
+// View renders a tab per bucket with its number key and file count
+func (m *BucketTabModel) View(selection BucketedSelection) string {
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.theme.CursorFG)).Bold(true).Underline(true)
+	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	tabs := make([]string, len(Buckets))
+	for i, name := range Buckets {
+		label := fmt.Sprintf("%d %s (%d)", i+1, strings.ToUpper(name[:1])+name[1:], len(selection[name]))
+		if name == m.active {
+			tabs[i] = activeStyle.Render(label)
+		} else {
+			tabs[i] = inactiveStyle.Render(label)
+		}
+	}
+	return strings.Join(tabs, inactiveStyle.Render(" │ "))
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listedPaths returns the paths of the files listed for the active bucket
+func listedPaths(model *SelectedFilesModel) []string {
+	var paths []string
+	for _, i := range model.displayOrder() {
+		paths = append(paths, model.files[i].Path)
+	}
+	return paths
+}
+
+func TestSelectedFilesBuckets(t *testing.T) {
+	model := newTestSelectedFiles("/a.go", "/b.go")
+	if model.GetFileBuckets() != nil || model.GetBucketPaths() != nil {
+		t.Error("Expected no buckets while all files are in the default bucket")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if model.tabs.Active() != "focus" {
+		t.Fatalf("Expected the focus bucket to be active, got %q", model.tabs.Active())
+	}
+	if paths := listedPaths(model); len(paths) != 0 {
+		t.Errorf("Expected an empty focus bucket, got %v", paths)
+	}
+
+	model.AddFile("/c.go", "/c.go")
+	model.AddFile("/d.go", "/d.go")
+	if paths := listedPaths(model); !reflect.DeepEqual(paths, []string{"/c.go", "/d.go"}) {
+		t.Errorf("Expected c.go and d.go in the focus bucket, got %v", paths)
+	}
+	if model.files[model.cursor].Path != "/c.go" {
+		t.Errorf("Expected the cursor on c.go, got %s", model.files[model.cursor].Path)
+	}
+
+	// Reordering stays within the bucket
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlDown})
+	if paths := listedPaths(model); !reflect.DeepEqual(paths, []string{"/d.go", "/c.go"}) {
+		t.Errorf("Expected c.go moved after d.go, got %v", paths)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	if paths := listedPaths(model); !reflect.DeepEqual(paths, []string{"/a.go", "/b.go"}) {
+		t.Errorf("Expected a.go and b.go in the context bucket, got %v", paths)
+	}
+
+	if paths := model.GetPaths(); !reflect.DeepEqual(paths, []string{"/a.go", "/b.go", "/d.go", "/c.go"}) {
+		t.Errorf("Expected files in bucket order, got %v", paths)
+	}
+	expected := map[string][]string{"context": {"/a.go", "/b.go"}, "focus": {"/d.go", "/c.go"}}
+	if paths := model.GetBucketPaths(); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected bucket paths %v, got %v", expected, paths)
+	}
+	if buckets := model.GetFileBuckets(); buckets["/c.go"] != "focus" || buckets["/a.go"] != "context" {
+		t.Errorf("Expected the bucket of each file, got %v", buckets)
+	}
+
+	model.SetBucket("/a.go", "unknown")
+	if model.GetFileBuckets()["/a.go"] != "context" {
+		t.Error("Expected an unknown bucket to be ignored")
+	}
+}
+
+func TestBucketTabsView(t *testing.T) {
+	model := newTestSelectedFiles("/a.go", "/b.go")
+	model.configManager, _ = config.NewManager()
+	model.SetBucket("/b.go", "reference")
+
+	view := model.View()
+	for _, tab := range []string{"1 Context (1)", "2 Focus (0)", "3 Reference (1)"} {
+		if !strings.Contains(view, tab) {
+			t.Errorf("Expected tab %q, got:\n%s", tab, view)
+		}
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if view := model.View(); !strings.Contains(view, "No files in this bucket") {
+		t.Errorf("Expected the empty focus bucket to be shown, got:\n%s", view)
+	}
+}
+
+func TestRestoreWorkspaceBuckets(t *testing.T) {
+	app := createTestApp(t)
+	main := app.workspace.Path + "/main.go"
+	util := app.workspace.Path + "/util.go"
+
+	app.restoreWorkspace(config.WorkspaceState{
+		Path:          app.workspace.Path,
+		SelectedFiles: []config.SelectedFileState{{Path: main}, {Path: util}},
+		Buckets:       map[string][]string{"context": {main}, "focus": {util}},
+	})
+
+	if buckets := app.buildOptions().Buckets; buckets[main] != "context" || buckets[util] != "focus" {
+		t.Errorf("Expected the buckets to be restored, got %v", buckets)
+	}
+	if !reflect.DeepEqual(app.workspace.Buckets["focus"], []string{util}) {
+		t.Errorf("Expected the buckets to be saved in the workspace, got %v", app.workspace.Buckets)
+	}
+}
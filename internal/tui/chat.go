@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
@@ -19,18 +20,25 @@ type ChatModel struct {
 	textarea textarea.Model
 	width    int
 	height   int
+	wordWrap bool // Wrap at the panel width rather than the textarea's own
+	theme    Theme
 }
 
-// NewChatModel creates a new chat model
-func NewChatModel(initialValue string) *ChatModel {
+// NewChatModel creates a new chat model holding at most charLimit characters,
+// zero for no limit. With wordWrap, the prompt wraps at the panel width.
+func NewChatModel(initialValue string, charLimit int, wordWrap bool, theme Theme) *ChatModel {
 	ta := textarea.New()
 	ta.Placeholder = "Enter your prompt for the LLM here..."
+	ta.CharLimit = 0 // A saved prompt is restored whole, even past the limit
 	ta.SetValue(initialValue)
+	ta.CharLimit = charLimit
 	ta.Focus()
 
 	return &ChatModel{
 		title:    "💬 User Prompt",
 		textarea: ta,
+		wordWrap: wordWrap,
+		theme:    theme,
 	}
 }
 
@@ -53,26 +61,44 @@ func (m *ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// View renders the chat input panel
-func (m *ChatModel) View() string {
+// chatHelpStyle renders the help text and character count of the chat panel
+var chatHelpStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("240")).
+	Italic(true)
+
+// header renders the title and help text above the textarea, wrapped at the panel width
+func (m *ChatModel) header() string {
 	var b strings.Builder
 
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("99"))
+		Foreground(lipgloss.Color(m.theme.FocusBorder))
 	b.WriteString(titleStyle.Render(m.title))
 	b.WriteString("\n\n")
 
 	// Help text
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Italic(true)
-	b.WriteString(helpStyle.Render("Enter your prompt below. Ctrl+S to generate XML prompt, Ctrl+Y to copy"))
+	b.WriteString(chatHelpStyle.Width(m.width).Render("Enter your prompt below. Ctrl+S to generate XML prompt, Ctrl+Y to copy"))
 	b.WriteString("\n\n")
 
+	return b.String()
+}
+
+// View renders the chat input panel
+func (m *ChatModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.header())
+
 	// Textarea
 	b.WriteString(m.textarea.View())
+	b.WriteString("\n")
+
+	// Character count
+	count := fmt.Sprintf("%d chars", m.textarea.Length())
+	if m.textarea.CharLimit > 0 {
+		count = fmt.Sprintf("%d/%d chars", m.textarea.Length(), m.textarea.CharLimit)
+	}
+	b.WriteString(chatHelpStyle.Render(count))
 
 	return b.String()
 }
@@ -101,11 +127,15 @@ func (m *ChatModel) Blur() {
 func (m *ChatModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height
-	// Account for title and help text (roughly 4 lines)
-	textareaHeight := height - 4
+	// Leave room for the lines above the textarea, which grow when the help
+	// text wraps in a narrow panel, and the character count below it
+	headerLines := strings.Count(m.header(), "\n")
+	textareaHeight := height - headerLines - 1
 	if textareaHeight < 3 {
 		textareaHeight = 3 // Minimum height
 	}
-	m.textarea.SetWidth(width)
+	if m.wordWrap {
+		m.textarea.SetWidth(width)
+	}
 	m.textarea.SetHeight(textareaHeight)
 }
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/prompt"
+)
+
+// mockClipboard records what is copied instead of using a real clipboard
+type mockClipboard struct {
+	content string
+	err     error
+}
+
+func (m *mockClipboard) WriteAll(text string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.content = text
+	return nil
+}
+
+func (m *mockClipboard) ReadAll() (string, error) {
+	return m.content, m.err
+}
+
+func TestAppCopiesWithClipboardBackend(t *testing.T) {
+	app := createTestApp(t)
+	clip := &mockClipboard{}
+	app.clipboard = clip
+
+	_, cmd := app.Update(HistoryCopyMsg{Content: "<prompt>copied</prompt>"})
+	if clip.content != "<prompt>copied</prompt>" {
+		t.Errorf("Expected the prompt on the clipboard, got %q", clip.content)
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected an info alert, got %#v", msg)
+	}
+
+	clip.err = errors.New("no display")
+	_, cmd = app.Update(HistoryCopyMsg{Content: "<prompt>failed</prompt>"})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != ErrorAlert || msg.Message != "clipboard error" {
+		t.Errorf("Expected a clipboard error alert, got %#v", msg)
+	}
+}
+
+func TestRegeneratePromptCopiesWithClipboardBackend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	clip := &mockClipboard{}
+	msg := regeneratePrompt(path, dir, []string{path}, "review", nil, prompt.OutputXML, prompt.BuildOptions{}, clip)()
+	if regenerated, ok := msg.(PromptRegeneratedMsg); !ok || regenerated.Err != nil {
+		t.Fatalf("Expected the prompt to be regenerated, got %#v", msg)
+	}
+	if !strings.Contains(clip.content, `<file name="main.go">`) {
+		t.Errorf("Expected the regenerated prompt on the clipboard, got %q", clip.content)
+	}
+}
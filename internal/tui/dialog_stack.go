@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Dialog is a modal shown over the main layout
+type Dialog interface {
+	IsVisible() bool
+	Hide()
+	View() string
+}
+
+// DialogStack keeps the visible dialogs in the order they were opened, so the
+// one opened last is drawn on top and gets the keys, whichever dialog it is
+type DialogStack struct {
+	dialogs []Dialog // Bottom first
+}
+
+// Push shows d and puts it on top of the stack, moving it there if it is already
+// on it. Dialogs take different arguments to be shown, so show calls d's own Show
+// method; Push returns the command it returns.
+func (s *DialogStack) Push(d Dialog, show func() tea.Cmd) tea.Cmd {
+	cmd := show()
+	s.dropHidden()
+	s.dialogs = slices.DeleteFunc(s.dialogs, func(other Dialog) bool { return other == d })
+	s.dialogs = append(s.dialogs, d)
+	return cmd
+}
+
+// Pop hides and removes the dialog on top of the stack and returns it, or nil
+// if the stack is empty. The top dialog may already have hidden itself, as
+// dialogs do on their own close keys.
+func (s *DialogStack) Pop() Dialog {
+	if len(s.dialogs) == 0 {
+		return nil
+	}
+	top := s.dialogs[len(s.dialogs)-1]
+	s.dialogs = s.dialogs[:len(s.dialogs)-1]
+	top.Hide()
+	return top
+}
+
+// Current returns the topmost visible dialog, or nil if none is visible
+func (s *DialogStack) Current() Dialog {
+	for i := len(s.dialogs) - 1; i >= 0; i-- {
+		if s.dialogs[i].IsVisible() {
+			return s.dialogs[i]
+		}
+	}
+	return nil
+}
+
+// dropHidden removes the dialogs that were closed without being popped
+func (s *DialogStack) dropHidden() {
+	s.dialogs = slices.DeleteFunc(s.dialogs, func(d Dialog) bool { return !d.IsVisible() })
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testDialog is a dialog that only tracks its visibility
+type testDialog struct {
+	name    string
+	visible bool
+}
+
+func (d *testDialog) IsVisible() bool { return d.visible }
+func (d *testDialog) Hide()           { d.visible = false }
+func (d *testDialog) View() string    { return d.name }
+
+// show returns a show function for Push that makes d visible
+func show(d *testDialog) func() tea.Cmd {
+	return func() tea.Cmd {
+		d.visible = true
+		return nil
+	}
+}
+
+func TestDialogStackPushPop(t *testing.T) {
+	var stack DialogStack
+	if stack.Current() != nil || stack.Pop() != nil {
+		t.Fatal("Expected an empty stack to have no dialog")
+	}
+
+	first := &testDialog{name: "first"}
+	second := &testDialog{name: "second"}
+	stack.Push(first, show(first))
+	stack.Push(second, show(second))
+	if !first.visible || !second.visible {
+		t.Fatal("Expected pushed dialogs to be shown")
+	}
+	if stack.Current() != second {
+		t.Errorf("Expected the dialog pushed last on top, got %v", stack.Current())
+	}
+
+	// Pushing a dialog again moves it to the top
+	stack.Push(first, show(first))
+	if stack.Current() != first {
+		t.Errorf("Expected the dialog pushed again on top, got %v", stack.Current())
+	}
+
+	if popped := stack.Pop(); popped != first || first.visible {
+		t.Errorf("Expected the top dialog to be popped and hidden, got %v", popped)
+	}
+	if stack.Current() != second {
+		t.Errorf("Expected the dialog below to be current, got %v", stack.Current())
+	}
+	stack.Pop()
+	if stack.Current() != nil || second.visible {
+		t.Errorf("Expected no dialog after popping both, got %v", stack.Current())
+	}
+}
+
+func TestDialogStackSkipsClosedDialogs(t *testing.T) {
+	var stack DialogStack
+	low := &testDialog{name: "low"}
+	high := &testDialog{name: "high"}
+	stack.Push(low, show(low))
+	stack.Push(high, show(high))
+
+	// A dialog closed by its own keys leaves the one below it current, and
+	// popping it then leaves the one below it open
+	high.Hide()
+	if stack.Current() != low {
+		t.Errorf("Expected the dialog below to be current, got %v", stack.Current())
+	}
+	if popped := stack.Pop(); popped != high || !low.visible {
+		t.Errorf("Expected the closed dialog to be popped, got %v", popped)
+	}
+
+	// Pushing drops dialogs closed without being popped
+	low.Hide()
+	stack.Push(high, show(high))
+	if stack.Pop(); stack.Pop() != nil {
+		t.Error("Expected the closed dialog to be dropped from the stack")
+	}
+}
+
+func TestAppShowsDialogOpenedLast(t *testing.T) {
+	app := createTestApp(t)
+	_, cmd := app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.Update(cmd())
+
+	// The persona dialog opens over the prompt dialog, and gets the keys
+	app.Update(PromptBuiltMsg{Content: "<prompt>review</prompt>"})
+	app.dialogs.Push(app.personaDialog, func() tea.Cmd {
+		app.personaDialog.Show()
+		return nil
+	})
+	if app.dialogs.Current() != app.personaDialog {
+		t.Fatalf("Expected the persona dialog on top, got %T", app.dialogs.Current())
+	}
+	if view := app.View(); !strings.Contains(view, "Select Active Personas") {
+		t.Errorf("Expected the persona dialog in the view, got:\n%s", view)
+	}
+
+	// Closing it goes back to the prompt dialog
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.personaDialog.IsVisible() || !app.promptDialog.IsVisible() {
+		t.Fatal("Expected esc to close only the persona dialog")
+	}
+	if view := app.View(); !strings.Contains(view, "review") {
+		t.Errorf("Expected the prompt dialog in the view, got:\n%s", view)
+	}
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultEditor is used when neither $EDITOR nor $VISUAL is set
+const defaultEditor = "vi"
+
+// EditorFinishedMsg is sent when the editor opened by OpenInEditor exits
+type EditorFinishedMsg struct {
+	Path string
+	Err  error
+}
+
+// editorCommand builds the command that opens path in the user's editor. The
+// editor may include arguments, e.g. EDITOR="code --wait".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("EDITOR")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{defaultEditor}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// OpenInEditor returns a command that suspends the TUI, opens path in the
+// user's editor and resumes once the editor exits
+func OpenInEditor(path string) tea.Cmd {
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		return EditorFinishedMsg{Path: path, Err: err}
+	})
+}
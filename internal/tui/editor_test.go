@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeFakeEditor writes a shell script that stands in for the user's editor
+func writeFakeEditor(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake editor: %v", err)
+	}
+	return path
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name, editor, visual string
+		want                 []string
+	}{
+		{"editor", "nano", "code", []string{"nano", "file.go"}},
+		{"editor with arguments", "code --wait", "", []string{"code", "--wait", "file.go"}},
+		{"visual fallback", "", "code", []string{"code", "file.go"}},
+		{"default", "", "", []string{defaultEditor, "file.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("EDITOR", tt.editor)
+			t.Setenv("VISUAL", tt.visual)
+			cmd := editorCommand("file.go")
+			if len(cmd.Args) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, cmd.Args)
+			}
+			for i := range tt.want {
+				if cmd.Args[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, cmd.Args)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenInEditorKey(t *testing.T) {
+	model := newTestTree()
+	for i, item := range model.items {
+		if !item.IsDir {
+			model.cursor = i
+			break
+		}
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}}); cmd == nil {
+		t.Error("Expected o to open the file in the editor")
+	}
+
+	model.cursor = 0
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}}); cmd != nil {
+		t.Error("Expected o to do nothing on a directory")
+	}
+}
+
+func TestEditorRefreshesSelectedFile(t *testing.T) {
+	app := createTestApp(t)
+	path := filepath.Join(app.targetDir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	app.selectedFiles.AddFile("main.go", path)
+	before := app.selectedFiles.files[0].Size
+
+	t.Setenv("EDITOR", writeFakeEditor(t, `echo "func main() {}" >> "$1"`))
+	err := editorCommand(path).Run()
+	if err != nil {
+		t.Fatalf("Fake editor failed: %v", err)
+	}
+
+	// Only the token estimate is left to run, no alert
+	_, cmd := app.Update(EditorFinishedMsg{Path: path, Err: err})
+	if cmd == nil {
+		t.Fatal("Expected the tokens of the file to be estimated again")
+	}
+	if msg, ok := cmd().(TokenEstimateMsg); !ok || msg.FilePath != path {
+		t.Errorf("Expected a token estimate for %s when the editor succeeds, got %#v", path, cmd())
+	}
+	if after := app.selectedFiles.files[0].Size; after <= before {
+		t.Errorf("Expected the file size to be refreshed after editing, got %d (was %d)", after, before)
+	}
+}
+
+func TestEditorFailureShowsError(t *testing.T) {
+	app := createTestApp(t)
+	path := filepath.Join(app.targetDir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	t.Setenv("EDITOR", writeFakeEditor(t, "exit 3"))
+	err := editorCommand(path).Run()
+	if err == nil {
+		t.Fatal("Expected the fake editor to fail")
+	}
+
+	_, cmd := app.Update(EditorFinishedMsg{Path: path, Err: err})
+	if cmd == nil {
+		t.Fatal("Expected an alert when the editor fails")
+	}
+	msg, ok := cmd().(NotificationMsg)
+	if !ok || msg.AlertType != ErrorAlert {
+		t.Errorf("Expected an error notification, got %+v", cmd())
+	}
+}
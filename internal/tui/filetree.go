@@ -1,7 +1,15 @@
 package tui
 
 import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,34 +27,83 @@ type FileTreeModel struct {
 	title     string
 	expanded  map[string]bool
 	selected  map[string]bool
+	// undo/redo snapshots of the selection; historyIndex is the current one
+	selectionHistory []map[string]bool
+	historyIndex     int
+	// whether symlinked directories are expanded
+	followSymlinks bool
+	// ignore rules shared by every directory scan, and directories being scanned in the background
+	matcher  filesystem.Matcher
+	scanning map[string]bool
+	// files larger than this many bytes can't be selected; zero means no limit
+	maxFileSize int64
+	// most files ctrl+a selects at once; zero means no limit
+	maxBulkSelect int
+	// files last modified before this are left out of prompts; nil includes all
+	modifiedSince *time.Time
+	// shift+up/down range selection: the item it started on, whether files in
+	// the range are selected or deselected, and the selection before it started
+	ranging    bool
+	rangeStart int
+	rangeValue bool
+	rangeBase  map[string]bool
+	// regex filter typed after /; items whose relative path doesn't match are hidden
+	filtering   bool
+	filter      string
+	filterRegex *regexp.Regexp
+	filterErr   string
+	// debug logger for scan failures; nil disables logging
+	debugLogger *slog.Logger
+	// git status annotations, only populated when enabled
+	showGitStatus bool
+	gitStatus     map[string]filesystem.GitStatus
+	// counts for the listed items, shown below the tree and updated by refreshItems
+	stats TreeStatistics
+	// line counts of files, counted in the background when the cursor reaches them
+	lineCounts map[string]int
+	counting   map[string]bool
 	// viewport to enable scrolling when content exceeds available space
 	viewport viewport.Model
 	width    int
 	height   int
+	theme    Theme
 }
 
 // NewFileTreeModel creates a new file tree model
-func NewFileTreeModel(targetDir string, initialSelection []string) *FileTreeModel {
+func NewFileTreeModel(targetDir string, initialSelection []string, theme Theme) *FileTreeModel {
 	selected := make(map[string]bool)
 	for _, f := range initialSelection {
 		selected[f] = true
 	}
 
 	return &FileTreeModel{
-		targetDir: targetDir,
-		title:     "📁 File Tree",
-		items:     []filesystem.FileTreeItem{},
-		cursor:    0,
-		expanded:  make(map[string]bool),
-		selected:  selected,
+		targetDir:        targetDir,
+		title:            "📁 File Tree",
+		items:            []filesystem.FileTreeItem{},
+		cursor:           0,
+		expanded:         make(map[string]bool),
+		selected:         selected,
+		selectionHistory: []map[string]bool{copySelection(selected)},
+		scanning:         make(map[string]bool),
+		theme:            theme,
 	}
 }
 
 // Init initializes the file tree model
 func (m *FileTreeModel) Init() tea.Cmd {
-	// Scan the target directory
-	rootNode, err := filesystem.ScanDirectory(m.targetDir)
+	// Build the ignore rules once; fall back to the scanner's own rules if they can't be loaded
+	m.matcher = nil
+	if matcher, err := filesystem.NewProjectMatcher(m.targetDir); err == nil {
+		m.matcher = matcher
+	} else {
+		m.logError("failed to load ignore rules", "ignore_rules_failed", m.targetDir, err)
+	}
+
+	// Scan only the top level of the target directory; subdirectories are
+	// scanned when they are expanded
+	rootNode, err := filesystem.ScanDirectory(m.targetDir, m.scanOptions(1))
 	if err != nil {
+		m.logError("failed to scan directory", "scan_failed", m.targetDir, err)
 		// If we can't scan the directory, create a simple error item
 		m.items = []filesystem.FileTreeItem{
 			{Name: "Error: " + err.Error(), Path: "", IsDir: false, Level: 0},
@@ -55,8 +112,414 @@ func (m *FileTreeModel) Init() tea.Cmd {
 	}
 
 	m.rootNode = rootNode
+	m.scanning = make(map[string]bool)
+	m.scanExpanded(m.rootNode)
+	m.loadGitStatus()
 	m.refreshItems()
-	return nil
+	return m.countLinesCmd()
+}
+
+// DirScannedMsg delivers the children of a directory scanned in the background
+type DirScannedMsg struct {
+	Path     string
+	Children []*filesystem.FileNode
+	Err      error
+}
+
+// scanOptions returns the options for scanning depth levels of a directory
+func (m *FileTreeModel) scanOptions(depth int) filesystem.ScanOptions {
+	return filesystem.ScanOptions{
+		FollowSymlinks: m.followSymlinks,
+		Depth:          depth,
+		Matcher:        m.matcher,
+	}
+}
+
+// ScanDirCmd returns a command that scans the children of the directory at path
+func (m *FileTreeModel) ScanDirCmd(path string) tea.Cmd {
+	opts := m.scanOptions(1)
+	return func() tea.Msg {
+		node, err := filesystem.ScanDirectory(path, opts)
+		if err != nil {
+			return DirScannedMsg{Path: path, Err: err}
+		}
+		return DirScannedMsg{Path: path, Children: node.Children}
+	}
+}
+
+// LineCountMsg delivers the line count of a file counted in the background
+type LineCountMsg struct {
+	Path  string
+	Lines int
+	Err   error
+}
+
+// countLinesCmd returns a command that counts the lines of the file under the
+// cursor, or nil if it's a directory or counted already
+func (m *FileTreeModel) countLinesCmd() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].IsDir || m.items[m.cursor].Path == "" {
+		return nil
+	}
+	path := m.items[m.cursor].Path
+	if _, ok := m.lineCounts[path]; ok || m.counting[path] {
+		return nil
+	}
+	if m.counting == nil {
+		m.counting = make(map[string]bool)
+	}
+	m.counting[path] = true
+	return func() tea.Msg {
+		lines, err := filesystem.GetLineCount(path)
+		return LineCountMsg{Path: path, Lines: lines, Err: err}
+	}
+}
+
+// TreeRefreshedMsg delivers a rescan of the whole tree after the target
+// directory changed on disk, or when a refresh was asked for with ctrl+r
+type TreeRefreshedMsg struct {
+	Root   *filesystem.FileNode
+	Err    error
+	Manual bool // Asked for with ctrl+r, so the outcome is announced
+}
+
+// RefreshCmd returns a command that rescans the tree in the background,
+// including every expanded directory so the refreshed tree opens the same way
+func (m *FileTreeModel) RefreshCmd() tea.Cmd {
+	targetDir := m.targetDir
+	opts := m.scanOptions(1)
+	expanded := maps.Clone(m.expanded)
+	return func() tea.Msg {
+		root, err := filesystem.ScanDirectory(targetDir, opts)
+		if err != nil {
+			return TreeRefreshedMsg{Err: err}
+		}
+		scanExpandedDirs(root, opts, expanded)
+		return TreeRefreshedMsg{Root: root}
+	}
+}
+
+// manualRefreshCmd returns a command that rescans the tree like RefreshCmd,
+// marking the result as asked for by the user
+func (m *FileTreeModel) manualRefreshCmd() tea.Cmd {
+	refresh := m.RefreshCmd()
+	return func() tea.Msg {
+		msg := refresh().(TreeRefreshedMsg)
+		msg.Manual = true
+		return msg
+	}
+}
+
+// scanExpandedDirs scans the children of every directory below node that is
+// expanded, skipping directories that can't be read
+func scanExpandedDirs(node *filesystem.FileNode, opts filesystem.ScanOptions, expanded map[string]bool) {
+	for _, child := range node.Children {
+		if !child.IsDir || !expanded[child.Path] {
+			continue
+		}
+		if child.Unscanned {
+			scanned, err := filesystem.ScanDirectory(child.Path, opts)
+			if err != nil {
+				continue
+			}
+			child.Children = scanned.Children
+			child.Unscanned = false
+		}
+		scanExpandedDirs(child, opts, expanded)
+	}
+}
+
+// applyRefresh replaces the tree with a rescan, keeping the cursor on the same
+// path while it exists. Selected files and expanded directories that were
+// deleted are forgotten.
+func (m *FileTreeModel) applyRefresh(msg TreeRefreshedMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.logError("failed to rescan directory", "rescan_failed", m.targetDir, msg.Err)
+		return nil
+	}
+
+	for path := range m.expanded {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(m.expanded, path)
+		}
+	}
+
+	cursorPath := ""
+	if m.cursor < len(m.items) {
+		cursorPath = m.items[m.cursor].Path
+	}
+	m.rootNode = msg.Root
+	// Files may have changed on disk, so count their lines again when needed
+	clear(m.lineCounts)
+	m.loadGitStatus()
+	m.refreshItems()
+	for i, item := range m.items {
+		if item.Path == cursorPath {
+			m.cursor = i
+			break
+		}
+	}
+	if m.cursor >= len(m.items) {
+		m.cursor = max(0, len(m.items)-1)
+	}
+	m.ensureVisible()
+
+	var deleted []string
+	for path, selected := range m.selected {
+		if _, err := os.Stat(path); selected && os.IsNotExist(err) {
+			deleted = append(deleted, path)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	m.deselectFiles(deleted)
+	return m.sendFileSelectionUpdate()
+}
+
+// graftChildren attaches scanned children to the unscanned directory at path.
+// It returns false if the directory isn't waiting for a scan.
+func (m *FileTreeModel) graftChildren(path string, children []*filesystem.FileNode) bool {
+	node := findNode(m.rootNode, path)
+	if node == nil || !node.Unscanned {
+		return false
+	}
+	node.Children = children
+	node.Unscanned = false
+	return true
+}
+
+// scanNode synchronously scans depth levels of an unscanned directory
+func (m *FileTreeModel) scanNode(node *filesystem.FileNode, depth int) {
+	if !node.Unscanned {
+		return
+	}
+	scanned, err := filesystem.ScanDirectory(node.Path, m.scanOptions(depth))
+	if err != nil {
+		m.logError("failed to scan directory", "scan_failed", node.Path, err)
+		return
+	}
+	node.Children = scanned.Children
+	node.Unscanned = false
+}
+
+// scanExpanded synchronously scans every expanded directory below node that
+// hasn't been scanned yet
+func (m *FileTreeModel) scanExpanded(node *filesystem.FileNode) {
+	for _, child := range node.Children {
+		if child.IsDir && m.expanded[child.Path] {
+			m.scanNode(child, 1)
+			m.scanExpanded(child)
+		}
+	}
+}
+
+// maxExpandAllItems is the most items expanding every folder may list; larger
+// trees are only expanded expandAllFallbackDepth levels deep
+const (
+	maxExpandAllItems      = 5000
+	expandAllFallbackDepth = 2
+)
+
+// expandAll expands every directory in the tree, keeping the cursor on the same
+// item. It returns false if the tree would list more than maxExpandAllItems
+// items, in which case only the top expandAllFallbackDepth levels are expanded.
+func (m *FileTreeModel) expandAll() bool {
+	if m.rootNode == nil {
+		return true
+	}
+	cursorPath := m.cursorPath()
+	m.scanAll(m.rootNode)
+	complete := countNodes(m.rootNode) <= maxExpandAllItems
+	depth := -1
+	if !complete {
+		depth = expandAllFallbackDepth
+	}
+	m.expandDirs(m.rootNode, depth)
+	m.refreshItems()
+	m.moveCursorTo(cursorPath)
+	return complete
+}
+
+// expandDirs expands the directories below node down to depth levels, or all
+// of them when depth is negative
+func (m *FileTreeModel) expandDirs(node *filesystem.FileNode, depth int) {
+	if depth == 0 {
+		return
+	}
+	for _, child := range node.Children {
+		if child.IsDir {
+			m.expanded[child.Path] = true
+			m.expandDirs(child, depth-1)
+		}
+	}
+}
+
+// collapseAll collapses every directory, moving the cursor to the top-level
+// item containing it
+func (m *FileTreeModel) collapseAll() {
+	cursorPath := m.cursorPath()
+	clear(m.expanded)
+	m.refreshItems()
+	m.moveCursorTo(cursorPath)
+}
+
+// countNodes returns the number of files and directories below node
+func countNodes(node *filesystem.FileNode) int {
+	count := 0
+	for _, child := range node.Children {
+		count += 1 + countNodes(child)
+	}
+	return count
+}
+
+// cursorPath returns the path of the item under the cursor, or "" if there is none
+func (m *FileTreeModel) cursorPath() string {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return ""
+	}
+	return m.items[m.cursor].Path
+}
+
+// moveCursorTo puts the cursor on the item at path, or on its closest listed
+// parent directory if it's hidden, and scrolls it into view
+func (m *FileTreeModel) moveCursorTo(path string) {
+	for path != "" && path != m.targetDir {
+		if i, found := m.FindByPath(path); found {
+			m.cursor = i
+			m.ensureVisible()
+			return
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	if m.cursor >= len(m.items) {
+		m.cursor = max(0, len(m.items)-1)
+	}
+	m.ensureVisible()
+}
+
+// FindByPath returns the index of the listed item at path
+func (m *FileTreeModel) FindByPath(path string) (index int, found bool) {
+	for i, item := range m.items {
+		if item.Path == path {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// JumpToPath moves the cursor to the item at path and scrolls it into view,
+// expanding its parent directories if it exists but isn't listed. It reports
+// whether the cursor was moved.
+func (m *FileTreeModel) JumpToPath(path string) bool {
+	index, found := m.FindByPath(path)
+	if !found {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+		m.expandAncestors(path)
+		if index, found = m.FindByPath(path); !found {
+			return false
+		}
+	}
+	m.cursor = index
+	m.ensureVisible()
+	return true
+}
+
+// expandAncestors expands every directory between the root and path and lists them
+func (m *FileTreeModel) expandAncestors(path string) {
+	for dir := filepath.Dir(path); dir != m.targetDir && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		m.expanded[dir] = true
+	}
+	if m.rootNode != nil {
+		m.scanExpanded(m.rootNode)
+	}
+	m.refreshItems()
+}
+
+// scanAll synchronously scans every directory below node that hasn't been scanned yet
+func (m *FileTreeModel) scanAll(node *filesystem.FileNode) {
+	if node == nil {
+		return
+	}
+	m.scanNode(node, 0)
+	for _, child := range node.Children {
+		if child.IsDir {
+			m.scanAll(child)
+		}
+	}
+}
+
+// SetDebugLogger sets the debug logger for the file tree; nil disables logging
+func (m *FileTreeModel) SetDebugLogger(logger *slog.Logger) {
+	m.debugLogger = componentLogger(logger, "file_tree")
+}
+
+// logError records a failure affecting path if a debug logger is set
+func (m *FileTreeModel) logError(msg, event, path string, err error) {
+	if m.debugLogger != nil {
+		m.debugLogger.Error(msg, "event", event, "path", path, "error", err)
+	}
+}
+
+// SetFollowSymlinks sets whether symlinked directories are expanded, rescanning
+// the tree if it was already loaded
+func (m *FileTreeModel) SetFollowSymlinks(follow bool) {
+	m.followSymlinks = follow
+	if m.rootNode != nil {
+		m.Init()
+	}
+}
+
+// GetExpandedPaths returns the expanded directories, sorted
+func (m *FileTreeModel) GetExpandedPaths() []string {
+	var paths []string
+	for path, expanded := range m.expanded {
+		if expanded {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// SetExpandedPaths expands exactly the directories in paths, e.g. as saved with
+// the workspace, scanning them if the tree was already loaded. Paths that are
+// no longer directories are skipped.
+func (m *FileTreeModel) SetExpandedPaths(paths []string) {
+	m.expanded = make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			m.expanded[path] = true
+		}
+	}
+	if m.rootNode != nil {
+		m.scanExpanded(m.rootNode)
+		m.refreshItems()
+	}
+}
+
+// SetShowGitStatus enables or disables git status annotations
+func (m *FileTreeModel) SetShowGitStatus(show bool) {
+	m.showGitStatus = show
+	m.loadGitStatus()
+	m.refreshItems()
+}
+
+// loadGitStatus reads the git status of the target directory if annotations are enabled.
+// Directories that aren't git repositories simply have no annotations.
+func (m *FileTreeModel) loadGitStatus() {
+	m.gitStatus = nil
+	if !m.showGitStatus {
+		return
+	}
+	if status, err := filesystem.GetGitStatus(m.targetDir); err == nil {
+		m.gitStatus = status
+	}
 }
 
 // refreshItems rebuilds the flattened item list based on current expanded state
@@ -73,9 +536,144 @@ func (m *FileTreeModel) refreshItems() {
 		// Update selected state from our local state
 		for i := range childItems {
 			childItems[i].Selected = m.selected[childItems[i].Path]
+			childItems[i].GitStatus = m.gitStatus[childItems[i].Path]
+			childItems[i].LineCount = -1
+			if lines, ok := m.lineCounts[childItems[i].Path]; ok {
+				childItems[i].LineCount = lines
+			}
 		}
 		m.items = append(m.items, childItems...)
 	}
+
+	if m.filterRegex != nil {
+		m.markHidden(m.items)
+		visible := m.items[:0]
+		for _, item := range m.items {
+			if !item.Hidden {
+				visible = append(visible, item)
+			}
+		}
+		m.items = visible
+		if m.cursor >= len(m.items) {
+			m.cursor = max(0, len(m.items)-1)
+		}
+	}
+	m.stats = m.GetStatistics()
+}
+
+// TreeStatistics counts the items listed in the file tree
+type TreeStatistics struct {
+	FileCount     int
+	DirCount      int
+	SelectedCount int
+	TotalBytes    int64
+	SelectedBytes int64
+}
+
+// String summarizes the statistics, e.g. "23 files, 4 dirs, 7 selected (142 KB)"
+func (s TreeStatistics) String() string {
+	return fmt.Sprintf("%d files, %d dirs, %d selected (%s)", s.FileCount, s.DirCount, s.SelectedCount, formatFileSize(s.SelectedBytes))
+}
+
+// GetStatistics counts the files and directories currently listed in the tree,
+// so files inside collapsed or filtered out directories aren't included
+func (m *FileTreeModel) GetStatistics() TreeStatistics {
+	var stats TreeStatistics
+	for _, item := range m.items {
+		if item.IsDir {
+			stats.DirCount++
+			continue
+		}
+		stats.FileCount++
+		stats.TotalBytes += item.SizeBytes
+		if item.Selected {
+			stats.SelectedCount++
+			stats.SelectedBytes += item.SizeBytes
+		}
+	}
+	return stats
+}
+
+// markHidden hides the items whose path relative to the target directory doesn't match
+// the filter, keeping directories that contain a visible item
+func (m *FileTreeModel) markHidden(items []filesystem.FileTreeItem) {
+	// Walk backwards so a directory's children are seen before it;
+	// visibleBelow[level] records whether an item at that level is visible
+	visibleBelow := make(map[int]bool)
+	for i := len(items) - 1; i >= 0; i-- {
+		item := &items[i]
+		relPath, err := filepath.Rel(m.targetDir, item.Path)
+		if err != nil {
+			relPath = item.Path
+		}
+		visible := m.filterRegex.MatchString(filepath.ToSlash(relPath))
+		if item.IsDir {
+			visible = visible || visibleBelow[item.Level+1]
+			visibleBelow[item.Level+1] = false
+		}
+		item.Hidden = !visible
+		visibleBelow[item.Level] = visibleBelow[item.Level] || visible
+	}
+}
+
+// applyFilter hides the items that don't match the regex pattern; an empty pattern
+// shows every item again. An invalid pattern is returned as an error and leaves the
+// current filter in place.
+func (m *FileTreeModel) applyFilter(pattern string) error {
+	m.filter = pattern
+	if pattern == "" {
+		m.filterRegex = nil
+		m.filterErr = ""
+		m.refreshItems()
+		return nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		m.filterErr = err.Error()
+		return err
+	}
+	m.filterRegex = re
+	m.filterErr = ""
+	m.refreshItems()
+	m.ensureVisible()
+	return nil
+}
+
+// clearFilter leaves filter mode and shows every item again
+func (m *FileTreeModel) clearFilter() {
+	m.filtering = false
+	m.applyFilter("")
+	m.ensureVisible()
+}
+
+// IsFiltering returns whether a filter pattern is being typed
+func (m *FileTreeModel) IsFiltering() bool {
+	return m.filtering
+}
+
+// updateFilter edits the filter pattern while it is being typed. It reports false for
+// keys it doesn't handle, such as the arrow keys, which keep navigating the tree.
+func (m *FileTreeModel) updateFilter(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.clearFilter()
+	case tea.KeyEnter:
+		// Keep the filter and go back to navigating
+		m.filtering = false
+	case tea.KeyBackspace:
+		if m.filter == "" {
+			m.filtering = false
+		} else {
+			runes := []rune(m.filter)
+			m.applyFilter(string(runes[:len(runes)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.applyFilter(m.filter + string(msg.Runes))
+	default:
+		return false
+	}
+	return true
 }
 
 // Update handles messages for the file tree
@@ -84,7 +682,22 @@ func (m *FileTreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		prevCursor := m.cursor
+		if m.filtering && m.updateFilter(msg) {
+			return m, nil
+		}
+		// Any other key ends a range selection
+		if key := msg.String(); key != "shift+up" && key != "shift+down" {
+			m.ranging = false
+		}
 		switch msg.String() {
+		case "/":
+			// Start typing a filter pattern
+			m.filtering = true
+		case "esc":
+			if m.filter != "" {
+				m.clearFilter()
+			}
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -95,6 +708,15 @@ func (m *FileTreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 			m.ensureVisible()
+		case "shift+up", "shift+down":
+			// Select or deselect the files between where shift was first held and the cursor
+			delta := 1
+			if msg.String() == "shift+up" {
+				delta = -1
+			}
+			if m.extendRange(delta) {
+				return m, tea.Batch(m.sendFileSelectionUpdate(), m.sendFileFocused())
+			}
 		case "pgdown", "ctrl+f":
 			if m.viewport.Height > 0 {
 				m.cursor += m.viewport.Height
@@ -127,27 +749,352 @@ func (m *FileTreeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.refreshItems()
 				m.ensureVisible()
 				// Return a file selection message to communicate with other panels
-				return m, m.sendFileSelectionUpdate()
+				cmds := []tea.Cmd{m.sendFileSelectionUpdate()}
+
+				// Scan the directory's children the first time it is expanded
+				if node := findNode(m.rootNode, currentItem.Path); node != nil && node.Unscanned && m.expanded[currentItem.Path] && !m.scanning[currentItem.Path] {
+					m.scanning[currentItem.Path] = true
+					cmds = append(cmds, m.ScanDirCmd(currentItem.Path))
+				}
+				return m, tea.Batch(cmds...)
 			}
 		case " ":
 			// Toggle file selection (only for files, not directories)
 			if m.cursor < len(m.items) && !m.items[m.cursor].IsDir {
 				currentItem := m.items[m.cursor]
+				if !m.selected[currentItem.Path] && m.tooLarge(currentItem.SizeBytes) {
+					// Oversized files can be deselected but not selected
+					return m, nil
+				}
 				m.selected[currentItem.Path] = !m.selected[currentItem.Path]
+				m.pushSelection()
 				m.refreshItems()
 				m.ensureVisible()
 				// Return a file selection message to communicate with other panels
 				return m, m.sendFileSelectionUpdate()
 			}
+		case "o":
+			// Open the file under the cursor in the user's editor
+			if m.cursor < len(m.items) && !m.items[m.cursor].IsDir && m.items[m.cursor].Path != "" {
+				return m, OpenInEditor(m.items[m.cursor].Path)
+			}
+		case "b":
+			// Show who last changed each line of the file under the cursor
+			if m.cursor < len(m.items) && !m.items[m.cursor].IsDir && m.items[m.cursor].Path != "" {
+				return m, BlameFile(m.targetDir, m.items[m.cursor].Path)
+			}
+		case "a":
+			// Select every file under the current directory
+			if dirPath := m.currentDirectory(); dirPath != "" {
+				m.selectAllInDirectory(dirPath)
+				m.pushSelection()
+				return m, m.sendFileSelectionUpdate()
+			}
+		case "A":
+			// Deselect every file under the current directory
+			if dirPath := m.currentDirectory(); dirPath != "" {
+				m.deselectAllInDirectory(dirPath)
+				m.pushSelection()
+				return m, m.sendFileSelectionUpdate()
+			}
+		case "ctrl+a":
+			// Select every visible file, up to the bulk selection limit
+			limited := m.selectVisible()
+			m.pushSelection()
+			cmds := []tea.Cmd{m.sendFileSelectionUpdate()}
+			if limited {
+				cmds = append(cmds, Notify(WarnAlert, fmt.Sprintf("selected the first %d files only", m.maxBulkSelect)))
+			}
+			return m, tea.Batch(cmds...)
+		case "ctrl+d":
+			// Deselect every file
+			clear(m.selected)
+			m.pushSelection()
+			m.refreshItems()
+			return m, m.sendFileSelectionUpdate()
+		case "ctrl+r":
+			// Rescan the tree for files changed outside the app
+			return m, m.manualRefreshCmd()
+		case "e":
+			// Expand every folder, or only the top levels of a very large tree
+			if !m.expandAll() {
+				return m, Notify(WarnAlert, fmt.Sprintf("tree too large, expanded %d levels", expandAllFallbackDepth))
+			}
+		case "E":
+			// Collapse every folder
+			m.collapseAll()
+		case "ctrl+z":
+			// Undo the last selection change
+			if m.undoSelection() {
+				return m, m.sendFileSelectionUpdate()
+			}
+		case "ctrl+shift+z", "alt+z":
+			// Redo the last undone selection change; alt+z for terminals that can't send ctrl+shift+z
+			if m.redoSelection() {
+				return m, m.sendFileSelectionUpdate()
+			}
+		}
+		// Let other panels know when the cursor moves onto another file
+		if m.cursor != prevCursor {
+			return m, m.sendFileFocused()
+		}
+	case DirScannedMsg:
+		delete(m.scanning, msg.Path)
+		if msg.Err != nil {
+			m.logError("failed to scan directory", "scan_failed", msg.Path, msg.Err)
+		} else if m.graftChildren(msg.Path, msg.Children) {
+			m.refreshItems()
+			m.ensureVisible()
 		}
+		return m, nil
+	case LineCountMsg:
+		delete(m.counting, msg.Path)
+		if msg.Err != nil {
+			m.logError("failed to count lines", "line_count_failed", msg.Path, msg.Err)
+			return m, nil
+		}
+		if m.lineCounts == nil {
+			m.lineCounts = make(map[string]int)
+		}
+		m.lineCounts[msg.Path] = msg.Lines
+		for i := range m.items {
+			if m.items[i].Path == msg.Path {
+				m.items[i].LineCount = msg.Lines
+			}
+		}
+		return m, nil
+	case TreeRefreshedMsg:
+		cmd := m.applyRefresh(msg)
+		if !msg.Manual {
+			return m, cmd
+		}
+		if msg.Err != nil {
+			return m, tea.Batch(cmd, Notify(ErrorAlert, "failed to refresh tree"))
+		}
+		return m, tea.Batch(cmd, Notify(InfoAlert, "tree refreshed"))
 	case tea.MouseMsg:
-		// Let viewport handle mouse wheel scrolling
-		m.viewport, cmd = m.viewport.Update(msg)
-		return m, cmd
+		// Scroll with the mouse wheel
+		prevCursor := m.cursor
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scrollBy(-m.viewport.MouseWheelDelta)
+		case tea.MouseButtonWheelDown:
+			m.scrollBy(m.viewport.MouseWheelDelta)
+		}
+		if m.cursor != prevCursor {
+			return m, m.sendFileFocused()
+		}
+		return m, nil
 	}
 	return m, cmd
 }
 
+// sendFileFocused returns a command announcing the file under the cursor, or nil for directories
+func (m *FileTreeModel) sendFileFocused() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].IsDir || m.items[m.cursor].Path == "" {
+		return nil
+	}
+	path := m.items[m.cursor].Path
+	return func() tea.Msg {
+		return FileFocusedMsg{Path: path}
+	}
+}
+
+// cursorStatus describes the file under the cursor, e.g. "main.go — 142 lines, 4 KB",
+// or returns "" for directories
+func (m *FileTreeModel) cursorStatus() string {
+	if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].IsDir || m.items[m.cursor].Path == "" {
+		return ""
+	}
+	item := m.items[m.cursor]
+	lines := "counting lines"
+	switch {
+	case item.LineCount == 1:
+		lines = "1 line"
+	case item.LineCount >= 0:
+		lines = fmt.Sprintf("%d lines", item.LineCount)
+	}
+	return fmt.Sprintf("%s — %s, %s", item.Name, lines, formatFileSize(item.SizeBytes))
+}
+
+// currentDirectory returns the directory under the cursor, or the parent directory of the file under the cursor
+func (m *FileTreeModel) currentDirectory() string {
+	if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].Path == "" {
+		return ""
+	}
+	item := m.items[m.cursor]
+	if item.IsDir {
+		return item.Path
+	}
+	return filepath.Dir(item.Path)
+}
+
+// selectAllInDirectory selects every file under dirPath, recursively
+func (m *FileTreeModel) selectAllInDirectory(dirPath string) {
+	m.setSelectionInDirectory(dirPath, true)
+}
+
+// deselectAllInDirectory deselects every file under dirPath, recursively
+func (m *FileTreeModel) deselectAllInDirectory(dirPath string) {
+	m.setSelectionInDirectory(dirPath, false)
+}
+
+// setSelectionInDirectory sets the selection state of every file under dirPath
+func (m *FileTreeModel) setSelectionInDirectory(dirPath string, selected bool) {
+	node := findNode(m.rootNode, dirPath)
+	if node == nil {
+		return
+	}
+	m.scanAll(node)
+
+	var walk func(n *filesystem.FileNode)
+	walk = func(n *filesystem.FileNode) {
+		for _, child := range n.Children {
+			if child.IsDir {
+				walk(child)
+			} else if !selected || !m.tooLarge(child.SizeBytes) {
+				m.selected[child.Path] = selected
+			}
+		}
+	}
+	walk(node)
+	m.refreshItems()
+}
+
+// selectVisible selects the files currently listed in the tree, skipping
+// oversized ones. When there are more than the bulk selection limit, only the
+// first ones up to the limit are selected and it returns true.
+func (m *FileTreeModel) selectVisible() bool {
+	var files []string
+	for _, item := range m.items {
+		if !item.IsDir && !m.tooLarge(item.SizeBytes) {
+			files = append(files, item.Path)
+		}
+	}
+
+	limited := m.maxBulkSelect > 0 && len(files) > m.maxBulkSelect
+	if limited {
+		files = files[:m.maxBulkSelect]
+	}
+	for _, path := range files {
+		m.selected[path] = true
+	}
+	m.refreshItems()
+	return limited
+}
+
+// extendRange moves the cursor by delta and gives every file between the start
+// of the range selection and the cursor the same selected state, starting a
+// range on the item under the cursor if there isn't one. A range started on a
+// file flips its state, a range started on a directory selects. Files that
+// left the range go back to their state before it started. It returns false if
+// the cursor can't move.
+func (m *FileTreeModel) extendRange(delta int) bool {
+	next := m.cursor + delta
+	if next < 0 || next >= len(m.items) {
+		return false
+	}
+	if !m.ranging {
+		m.ranging = true
+		m.rangeStart = m.cursor
+		m.rangeValue = true
+		if item := m.items[m.cursor]; !item.IsDir {
+			m.rangeValue = !m.selected[item.Path]
+		}
+		m.rangeBase = copySelection(m.selected)
+	}
+	m.cursor = next
+	m.ensureVisible()
+
+	m.selected = copySelection(m.rangeBase)
+	for i := min(m.rangeStart, m.cursor); i <= max(m.rangeStart, m.cursor); i++ {
+		item := m.items[i]
+		if item.IsDir || item.Path == "" {
+			continue
+		}
+		if !m.rangeValue {
+			delete(m.selected, item.Path)
+		} else if !m.tooLarge(item.SizeBytes) {
+			m.selected[item.Path] = true
+		}
+	}
+	m.pushSelection()
+	m.refreshItems()
+	return true
+}
+
+// findNode returns the node with the given path in the tree rooted at node
+func findNode(node *filesystem.FileNode, path string) *filesystem.FileNode {
+	if node == nil {
+		return nil
+	}
+	if node.Path == path {
+		return node
+	}
+	for _, child := range node.Children {
+		if child.IsDir && (child.Path == path || strings.HasPrefix(path, child.Path+string(filepath.Separator))) {
+			return findNode(child, path)
+		}
+	}
+	return nil
+}
+
+// maxSelectionHistory is the number of selection snapshots kept for undo
+const maxSelectionHistory = 50
+
+// pushSelection records the current selection as a new undo snapshot,
+// discarding any snapshots that were undone
+func (m *FileTreeModel) pushSelection() {
+	m.selectionHistory = append(m.selectionHistory[:m.historyIndex+1], copySelection(m.selected))
+	if len(m.selectionHistory) > maxSelectionHistory {
+		m.selectionHistory = m.selectionHistory[len(m.selectionHistory)-maxSelectionHistory:]
+	}
+	m.historyIndex = len(m.selectionHistory) - 1
+}
+
+// deselectFiles deselects paths without recording an undo step, for selections
+// that were refused after the fact
+func (m *FileTreeModel) deselectFiles(paths []string) {
+	for _, path := range paths {
+		delete(m.selected, path)
+	}
+	m.selectionHistory[m.historyIndex] = copySelection(m.selected)
+	m.refreshItems()
+}
+
+// undoSelection restores the previous selection snapshot. It returns false if there is nothing to undo.
+func (m *FileTreeModel) undoSelection() bool {
+	if m.historyIndex == 0 {
+		return false
+	}
+	m.historyIndex--
+	m.selected = copySelection(m.selectionHistory[m.historyIndex])
+	m.refreshItems()
+	return true
+}
+
+// redoSelection re-applies the next selection snapshot. It returns false if there is nothing to redo.
+func (m *FileTreeModel) redoSelection() bool {
+	if m.historyIndex >= len(m.selectionHistory)-1 {
+		return false
+	}
+	m.historyIndex++
+	m.selected = copySelection(m.selectionHistory[m.historyIndex])
+	m.refreshItems()
+	return true
+}
+
+// copySelection returns a copy of a selection map without deselected entries
+func copySelection(selected map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(selected))
+	for path, isSelected := range selected {
+		if isSelected {
+			copied[path] = true
+		}
+	}
+	return copied
+}
+
 // FileSelectionMsg represents a message about file selection changes
 type FileSelectionMsg struct {
 	SelectedFiles map[string]bool
@@ -165,6 +1112,121 @@ func (m *FileTreeModel) GetSelectedFiles() map[string]bool {
 	return m.selected
 }
 
+// AllFilePaths returns the paths of every file in the tree, regardless of
+// expansion. Directories that haven't been scanned yet are scanned first.
+func (m *FileTreeModel) AllFilePaths() []string {
+	var paths []string
+	for _, file := range m.allFiles() {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+// allFiles returns the node of every file in the tree, scanning directories that haven't been scanned yet
+func (m *FileTreeModel) allFiles() []*filesystem.FileNode {
+	m.scanAll(m.rootNode)
+
+	var files []*filesystem.FileNode
+	var walk func(node *filesystem.FileNode)
+	walk = func(node *filesystem.FileNode) {
+		for _, child := range node.Children {
+			if child.IsDir {
+				walk(child)
+			} else {
+				files = append(files, child)
+			}
+		}
+	}
+	if m.rootNode != nil {
+		walk(m.rootNode)
+	}
+	return files
+}
+
+// SetMaxFileSize sets the size in bytes above which files can't be selected. Zero removes the limit.
+func (m *FileTreeModel) SetMaxFileSize(bytes int64) {
+	m.maxFileSize = bytes
+}
+
+// SetMaxBulkSelect sets the most files selecting every visible file selects at once. Zero removes the limit.
+func (m *FileTreeModel) SetMaxBulkSelect(count int) {
+	m.maxBulkSelect = count
+}
+
+// SetModifiedSince sets the time files must be modified since to be included
+// in prompts; older files are dimmed. Nil removes the filter.
+func (m *FileTreeModel) SetModifiedSince(since *time.Time) {
+	m.modifiedSince = since
+}
+
+// unmodified reports whether item was last modified before the modified-since filter
+func (m *FileTreeModel) unmodified(item filesystem.FileTreeItem) bool {
+	return !item.IsDir && m.modifiedSince != nil && item.ModTime.Before(*m.modifiedSince)
+}
+
+// tooLarge reports whether a file of the given size exceeds the selection size limit
+func (m *FileTreeModel) tooLarge(size int64) bool {
+	return m.maxFileSize > 0 && size > m.maxFileSize
+}
+
+// SelectByGlob selects every file in the tree matching pattern, which is
+// relative to the target directory and may use "**" to match any number of
+// directories. It returns the paths that matched.
+func (m *FileTreeModel) SelectByGlob(pattern string) ([]string, error) {
+	matcher, err := filesystem.NewProjectMatcher(m.targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filesystem.GlobFiles(m.targetDir, pattern, matcher)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only select files that are shown in the tree and within the size limit
+	inTree := make(map[string]bool)
+	for _, file := range m.allFiles() {
+		inTree[file.Path] = !m.tooLarge(file.SizeBytes)
+	}
+
+	var selected []string
+	for _, path := range matches {
+		if !inTree[path] {
+			continue
+		}
+		m.selected[path] = true
+		selected = append(selected, path)
+	}
+	if len(selected) > 0 {
+		m.pushSelection()
+	}
+	m.refreshItems()
+
+	return selected, nil
+}
+
+// RevealAndSelect expands the ancestors of path, moves the cursor to it and selects it
+func (m *FileTreeModel) RevealAndSelect(path string) tea.Cmd {
+	m.expandAncestors(path)
+
+	var size int64
+	if i, found := m.FindByPath(path); found {
+		m.cursor = i
+		size = m.items[i].SizeBytes
+	}
+	m.ensureVisible()
+
+	// Oversized files are revealed but not selected
+	if m.tooLarge(size) {
+		return nil
+	}
+	m.selected[path] = true
+	m.pushSelection()
+	m.refreshItems()
+
+	return m.sendFileSelectionUpdate()
+}
+
 // GetItems returns the current items for testing
 func (m *FileTreeModel) GetItems() []filesystem.FileTreeItem {
 	return m.items
@@ -180,12 +1242,37 @@ func (m *FileTreeModel) calculateHeaderContent() (string, int) {
 	header.WriteString(titleStyle.Render(m.title))
 	header.WriteString("\n\n")
 
+	// Filter bar, shown while a filter is typed or applied
+	if m.filtering || m.filter != "" {
+		filterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		bar := "/" + m.filter
+		if m.filtering {
+			bar += "█"
+		}
+		header.WriteString(filterStyle.Render(bar))
+		if m.filterErr != "" {
+			header.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  " + m.filterErr))
+		}
+		header.WriteString("\n\n")
+	}
+
+	// Modified-since filter, shown while files are left out of prompts by it
+	if m.modifiedSince != nil {
+		filterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		header.WriteString(filterStyle.Render("modified since " + m.modifiedSince.Local().Format(modifiedSinceLayout)))
+		header.WriteString("\n\n")
+	}
+
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
 		Italic(true)
-	header.WriteString(helpStyle.Render("↑/↓: navigate, PgUp/PgDn: page, Enter: expand/collapse, Space: select file, g/G: top/bottom"))
+	header.WriteString(helpStyle.Render("↑/↓: navigate, PgUp/PgDn: page, Enter: expand/collapse, Space: select file, shift+↑/↓: select range, /: filter, o: open in editor, b: git blame, a/A: select/deselect dir, ctrl+z/alt+z: undo/redo, g/G: top/bottom"))
 	header.WriteString("\n\n")
 
+	// Breadcrumb of the directory holding the item under the cursor
+	header.WriteString(m.renderBreadcrumb())
+	header.WriteString("\n")
+
 	// Compute rendered header height with wrapping against current width
 	renderedHeader := lipgloss.NewStyle().Width(max(1, m.width)).Render(header.String())
 	headerLineCount := 0
@@ -196,6 +1283,43 @@ func (m *FileTreeModel) calculateHeaderContent() (string, int) {
 	return renderedHeader, headerLineCount
 }
 
+// breadcrumbSeparator separates the directories of a breadcrumb
+const breadcrumbSeparator = " / "
+
+// BreadcrumbFor returns the path of the directory containing item relative to
+// rootPath, e.g. "src / internal / tui", or "" for items directly in rootPath
+func BreadcrumbFor(item filesystem.FileTreeItem, rootPath string) string {
+	return strings.Join(breadcrumbDirs(item, rootPath), breadcrumbSeparator)
+}
+
+// breadcrumbDirs returns the directories between rootPath and item, outermost first
+func breadcrumbDirs(item filesystem.FileTreeItem, rootPath string) []string {
+	rel, err := filepath.Rel(rootPath, filepath.Dir(item.Path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+// renderBreadcrumb renders the project name followed by the directories
+// leading to the item under the cursor, each as a label
+func (m *FileTreeModel) renderBreadcrumb() string {
+	crumbs := []string{filepath.Base(m.targetDir)}
+	if m.cursor >= 0 && m.cursor < len(m.items) && m.items[m.cursor].Path != "" {
+		crumbs = append(crumbs, breadcrumbDirs(m.items[m.cursor], m.targetDir)...)
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(m.theme.CursorFG)).
+		Underline(true)
+	separatorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	labels := make([]string, len(crumbs))
+	for i, crumb := range crumbs {
+		labels[i] = labelStyle.Render(crumb)
+	}
+	return strings.Join(labels, separatorStyle.Render(breadcrumbSeparator))
+}
+
 // View renders the file tree
 func (m *FileTreeModel) View() string {
 	// Get header content and height
@@ -204,9 +1328,15 @@ func (m *FileTreeModel) View() string {
 	// Update viewport size first with correct header height
 	m.ensureViewportSizedWithHeader(headerLineCount)
 
-	// Build scrollable content
+	// Scroll to the cursor first, so only the rows in view need rendering
+	m.ensureVisible()
+
+	// Build the rows in view plus the overscan rather than the whole tree,
+	// which can have tens of thousands of items
+	start, _ := m.visibleRange()
 	var content strings.Builder
-	for i, item := range m.items {
+	for n, item := range m.visibleItems() {
+		i := start + n
 		var line strings.Builder
 
 		indent := strings.Repeat("  ", item.Level)
@@ -219,37 +1349,99 @@ func (m *FileTreeModel) View() string {
 		}
 
 		if item.IsDir {
-			if m.expanded[item.Path] {
+			if item.IsSymlink && !m.followSymlinks {
+				// Symlinked directories aren't expanded unless following symlinks
+				line.WriteString("🔗 ")
+			} else if m.expanded[item.Path] {
 				line.WriteString("📂 ")
 			} else {
 				line.WriteString("📁 ")
 			}
 		} else {
+			if m.showGitStatus {
+				line.WriteString(gitStatusSigil(item.GitStatus))
+			}
 			if item.Selected {
 				line.WriteString("☑️ ")
+			} else if item.IsSymlink {
+				line.WriteString("🔗 ")
 			} else {
 				line.WriteString("📄 ")
 			}
 		}
 
+		tooLarge := !item.IsDir && m.tooLarge(item.SizeBytes)
+		unmodified := m.unmodified(item)
 		itemStyle := lipgloss.NewStyle()
+		if tooLarge || unmodified {
+			itemStyle = itemStyle.Foreground(lipgloss.Color("240"))
+		}
 		if i == m.cursor {
-			itemStyle = itemStyle.Foreground(lipgloss.Color("69")).Bold(true)
+			itemStyle = itemStyle.Foreground(lipgloss.Color(m.theme.CursorFG)).Bold(true)
 		}
 		if item.Selected {
-			itemStyle = itemStyle.Foreground(lipgloss.Color("10"))
+			itemStyle = itemStyle.Foreground(lipgloss.Color(m.theme.SelectedFG))
 		}
 		line.WriteString(itemStyle.Render(item.Name))
+		if tooLarge {
+			line.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" (too large)"))
+		} else if unmodified {
+			line.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" (unmodified)"))
+		}
+		if item.IsDir && !m.expanded[item.Path] && item.ChildCount >= 0 {
+			badge := fmt.Sprintf(" (%d)", item.ChildCount)
+			line.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(badge))
+		}
 
 		content.WriteString(line.String())
 		content.WriteString("\n")
 	}
 
-	// Set content and ensure proper scrolling
-	m.viewport.SetContent(content.String())
-	m.ensureVisible()
+	// The rendered rows start at start rather than at the top of the tree, so show
+	// them through a copy of the viewport scrolled to the offset within them
+	vp := m.viewport
+	vp.SetContent(content.String())
+	vp.SetYOffset(m.viewport.YOffset - start)
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MaxWidth(max(1, m.width))
+	return renderedHeader + vp.View() + "\n" + footerStyle.Render(m.cursorStatus()) + "\n" + footerStyle.Render(m.stats.String())
+}
+
+// treeFooterHeight is the number of lines below the tree, holding the file
+// under the cursor and the statistics
+const treeFooterHeight = 2
 
-	return renderedHeader + m.viewport.View()
+// treeOverscan is the number of rows rendered above and below the viewport
+const treeOverscan = 5
+
+// visibleRange returns the indices [start, end) of the items in view, plus the overscan
+func (m *FileTreeModel) visibleRange() (int, int) {
+	start := max(0, m.viewport.YOffset-treeOverscan)
+	end := min(len(m.items), m.viewport.YOffset+m.viewport.Height+treeOverscan)
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// visibleItems returns the items in view, plus the overscan
+func (m *FileTreeModel) visibleItems() []filesystem.FileTreeItem {
+	start, end := m.visibleRange()
+	return m.items[start:end]
+}
+
+// scrollBy scrolls the tree by delta rows, moving the cursor along when it
+// would leave the view. The viewport only holds the rendered rows, so it
+// can't scroll itself.
+func (m *FileTreeModel) scrollBy(delta int) {
+	if m.viewport.Height <= 0 || len(m.items) == 0 {
+		return
+	}
+	maxOffset := max(0, len(m.items)-m.viewport.Height)
+	m.viewport.YOffset = min(maxOffset, max(0, m.viewport.YOffset+delta))
+	m.cursor = min(m.viewport.YOffset+m.viewport.Height-1, max(m.viewport.YOffset, m.cursor))
 }
 
 // SetSize sets the available width and height for the panel (including header).
@@ -262,12 +1454,13 @@ func (m *FileTreeModel) SetSize(width, height int) {
 	m.ensureVisible()
 }
 
-// ensureViewportSizedWithHeader sizes the viewport using the provided header height.
+// ensureViewportSizedWithHeader sizes the viewport using the provided header height,
+// leaving room for the footer.
 func (m *FileTreeModel) ensureViewportSizedWithHeader(headerHeight int) {
 	if m.width <= 0 || m.height <= 0 {
 		return
 	}
-	vpHeight := m.height - headerHeight
+	vpHeight := m.height - headerHeight - treeFooterHeight
 	if vpHeight < 1 {
 		vpHeight = 1
 	}
@@ -330,3 +1523,19 @@ func (m *FileTreeModel) ensureVisible() {
 		m.viewport.YOffset = max(0, len(m.items)-m.viewport.Height)
 	}
 }
+
+// gitStatusSigil renders a one-character colored marker for a file's git status
+func gitStatusSigil(status filesystem.GitStatus) string {
+	var color string
+	switch status {
+	case filesystem.GitModified:
+		color = "220"
+	case filesystem.GitStaged:
+		color = "10"
+	case filesystem.GitUntracked:
+		color = "196"
+	default:
+		return "  "
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(status.Sigil()) + " "
+}
@@ -1,15 +1,25 @@
 package tui
 
 import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"coding-prompts-tui/internal/config"
 	"coding-prompts-tui/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestFileTreeHeaderCalculation(t *testing.T) {
 	// Create a new file tree model
-	model := NewFileTreeModel("/tmp", []string{})
+	model := NewFileTreeModel("/tmp", []string{}, DefaultTheme())
 
 	// Set a reasonable width for testing
 	model.width = 50
@@ -35,7 +45,7 @@ func TestFileTreeHeaderCalculation(t *testing.T) {
 }
 
 func TestViewportSizing(t *testing.T) {
-	model := NewFileTreeModel("/tmp", []string{})
+	model := NewFileTreeModel("/tmp", []string{}, DefaultTheme())
 
 	// Set panel dimensions
 	panelWidth := 40
@@ -45,8 +55,8 @@ func TestViewportSizing(t *testing.T) {
 	// Calculate expected header height
 	_, headerHeight := model.calculateHeaderContent()
 
-	// Expected viewport size should be panel size minus header
-	expectedViewportHeight := panelHeight - headerHeight
+	// Expected viewport size should be panel size minus header and footer
+	expectedViewportHeight := panelHeight - headerHeight - treeFooterHeight
 	if expectedViewportHeight < 1 {
 		expectedViewportHeight = 1
 	}
@@ -62,7 +72,7 @@ func TestViewportSizing(t *testing.T) {
 }
 
 func TestEnsureVisibleBounds(t *testing.T) {
-	model := NewFileTreeModel("/tmp", []string{})
+	model := NewFileTreeModel("/tmp", []string{}, DefaultTheme())
 	model.width = 40
 	model.height = 20
 
@@ -106,3 +116,968 @@ func TestEnsureVisibleBounds(t *testing.T) {
 		t.Errorf("Cursor should be clamped to %d, got %d", len(model.items)-1, model.cursor)
 	}
 }
+
+// newTestTree builds a FileTreeModel over an in-memory tree:
+// /project/{main.go, pkg/{a.go, sub/b.go}, docs/readme.md}
+func newTestTree() *FileTreeModel {
+	model := NewFileTreeModel("/project", []string{}, DefaultTheme())
+	model.rootNode = &filesystem.FileNode{
+		Name: "project", Path: "/project", IsDir: true,
+		Children: []*filesystem.FileNode{
+			{Name: "docs", Path: "/project/docs", IsDir: true, Children: []*filesystem.FileNode{
+				{Name: "readme.md", Path: "/project/docs/readme.md"},
+			}},
+			{Name: "pkg", Path: "/project/pkg", IsDir: true, Children: []*filesystem.FileNode{
+				{Name: "a.go", Path: "/project/pkg/a.go"},
+				{Name: "sub", Path: "/project/pkg/sub", IsDir: true, Children: []*filesystem.FileNode{
+					{Name: "b.go", Path: "/project/pkg/sub/b.go"},
+				}},
+			}},
+			{Name: "main.go", Path: "/project/main.go"},
+		},
+	}
+	model.refreshItems()
+	return model
+}
+
+func TestCollapsedDirectoryShowsFileCount(t *testing.T) {
+	model := newTestTree()
+	model.SetSize(80, 20)
+
+	if view := model.View(); !strings.Contains(view, "pkg (1)") || !strings.Contains(view, "docs (1)") {
+		t.Errorf("Expected collapsed directories to show their file counts, got:\n%s", view)
+	}
+
+	model.expanded["/project/pkg"] = true
+	model.refreshItems()
+	view := model.View()
+	if strings.Contains(view, "pkg (1)") {
+		t.Errorf("Expected no count on the expanded directory, got:\n%s", view)
+	}
+	if !strings.Contains(view, "sub (1)") {
+		t.Errorf("Expected the collapsed subdirectory to show its count, got:\n%s", view)
+	}
+}
+
+func TestSelectAllInDirectory(t *testing.T) {
+	model := newTestTree()
+	model.selected["/project/main.go"] = true
+
+	model.selectAllInDirectory("/project/pkg")
+
+	for _, path := range []string{"/project/pkg/a.go", "/project/pkg/sub/b.go", "/project/main.go"} {
+		if !model.selected[path] {
+			t.Errorf("Expected %s to be selected", path)
+		}
+	}
+	if model.selected["/project/docs/readme.md"] {
+		t.Error("Expected file outside the directory not to be selected")
+	}
+	if model.selected["/project/pkg/sub"] {
+		t.Error("Expected directories themselves not to be selected")
+	}
+}
+
+func TestDeselectAllInDirectory(t *testing.T) {
+	model := newTestTree()
+	for _, path := range []string{"/project/pkg/a.go", "/project/pkg/sub/b.go", "/project/docs/readme.md"} {
+		model.selected[path] = true
+	}
+
+	model.deselectAllInDirectory("/project/pkg")
+
+	if model.selected["/project/pkg/a.go"] || model.selected["/project/pkg/sub/b.go"] {
+		t.Error("Expected files in the directory to be deselected")
+	}
+	if !model.selected["/project/docs/readme.md"] {
+		t.Error("Expected file outside the directory to stay selected")
+	}
+}
+
+func TestSelectAllKeySendsSingleUpdate(t *testing.T) {
+	model := newTestTree()
+
+	// Cursor on the pkg directory (items: docs, pkg, main.go)
+	model.cursor = 1
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd == nil {
+		t.Fatal("Expected a file selection command")
+	}
+	msg, ok := cmd().(FileSelectionMsg)
+	if !ok {
+		t.Fatalf("Expected FileSelectionMsg, got %T", cmd())
+	}
+	if !msg.SelectedFiles["/project/pkg/a.go"] || !msg.SelectedFiles["/project/pkg/sub/b.go"] {
+		t.Errorf("Expected pkg files in selection, got %v", msg.SelectedFiles)
+	}
+
+	// Cursor on a file deselects its parent directory
+	model.cursor = 2
+	model.selected["/project/main.go"] = true
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	if model.selected["/project/main.go"] || model.selected["/project/pkg/a.go"] {
+		t.Error("Expected A on a top-level file to deselect all files in the project root")
+	}
+}
+
+func TestSelectByGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/sub/b.go", "docs/readme.md"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.Init()
+
+	selected, err := model.SelectByGlob("pkg/**/*.go")
+	if err != nil {
+		t.Fatalf("SelectByGlob failed: %v", err)
+	}
+	if len(selected) != 2 {
+		t.Errorf("Expected 2 files selected, got %v", selected)
+	}
+	for _, name := range []string{"pkg/a.go", "pkg/sub/b.go"} {
+		if !model.selected[filepath.Join(root, name)] {
+			t.Errorf("Expected %s to be selected", name)
+		}
+	}
+	if model.selected[filepath.Join(root, "main.go")] {
+		t.Error("Expected main.go not to be selected")
+	}
+
+	if _, err := model.SelectByGlob("[.go"); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+}
+
+func TestSelectionUndoRedo(t *testing.T) {
+	model := newTestTree()
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	undo := tea.KeyMsg{Type: tea.KeyCtrlZ}
+	redo := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}, Alt: true}
+
+	// Select main.go (items: docs, pkg, main.go), then everything in pkg
+	model.cursor = 2
+	model.Update(space)
+	model.cursor = 1
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+
+	// Undo restores the state before select-all
+	_, cmd := model.Update(undo)
+	if cmd == nil {
+		t.Fatal("Expected a file selection command after undo")
+	}
+	if msg, ok := cmd().(FileSelectionMsg); !ok || msg.SelectedFiles["/project/pkg/a.go"] {
+		t.Errorf("Expected undo to send the restored selection, got %#v", cmd())
+	}
+	if !model.selected["/project/main.go"] || model.selected["/project/pkg/a.go"] {
+		t.Errorf("Expected only main.go to be selected after undo, got %v", model.selected)
+	}
+
+	// Undo again restores the empty selection; further undos do nothing
+	model.Update(undo)
+	if len(copySelection(model.selected)) != 0 {
+		t.Errorf("Expected empty selection after second undo, got %v", model.selected)
+	}
+	if _, cmd := model.Update(undo); cmd != nil {
+		t.Error("Expected no command when there is nothing to undo")
+	}
+
+	// Redo re-applies both changes
+	model.Update(redo)
+	model.Update(redo)
+	for _, path := range []string{"/project/main.go", "/project/pkg/a.go", "/project/pkg/sub/b.go"} {
+		if !model.selected[path] {
+			t.Errorf("Expected %s to be selected after redo", path)
+		}
+	}
+	if _, cmd := model.Update(redo); cmd != nil {
+		t.Error("Expected no command when there is nothing to redo")
+	}
+
+	// A new change after undo discards the redo history
+	model.Update(undo)
+	model.cursor = 2
+	model.Update(space)
+	if model.redoSelection() {
+		t.Error("Expected redo history to be discarded after a new change")
+	}
+}
+
+func TestSelectionHistoryIsCapped(t *testing.T) {
+	model := newTestTree()
+	for i := 0; i < maxSelectionHistory+10; i++ {
+		model.selected["/project/main.go"] = i%2 == 0
+		model.pushSelection()
+	}
+	if len(model.selectionHistory) != maxSelectionHistory {
+		t.Errorf("Expected history capped at %d, got %d", maxSelectionHistory, len(model.selectionHistory))
+	}
+	if model.historyIndex != maxSelectionHistory-1 {
+		t.Errorf("Expected history index at the newest snapshot, got %d", model.historyIndex)
+	}
+}
+
+func TestLazyDirectoryScan(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/sub/b.go"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.Init()
+
+	pkgDir := filepath.Join(root, "pkg")
+	pkg := findNode(model.rootNode, pkgDir)
+	if pkg == nil || !pkg.Unscanned {
+		t.Fatal("Expected pkg to be left unscanned until expanded")
+	}
+
+	// Expanding the directory scans it in the background
+	for i, item := range model.items {
+		if item.Path == pkgDir {
+			model.cursor = i
+		}
+	}
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a command when expanding a directory")
+	}
+	var scanned *DirScannedMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(DirScannedMsg); ok {
+			scanned = &msg
+		}
+	}
+	if scanned == nil || scanned.Path != pkgDir {
+		t.Fatalf("Expected a scan of %s, got %+v", pkgDir, scanned)
+	}
+
+	model.Update(*scanned)
+	if pkg.Unscanned {
+		t.Error("Expected pkg to be scanned")
+	}
+	found := false
+	for _, item := range model.items {
+		found = found || item.Path == filepath.Join(pkgDir, "a.go")
+	}
+	if !found {
+		t.Error("Expected pkg/a.go to be listed")
+	}
+	if sub := findNode(model.rootNode, filepath.Join(pkgDir, "sub")); sub == nil || !sub.Unscanned {
+		t.Error("Expected pkg/sub to stay unscanned")
+	}
+
+	// Listing every file scans the rest of the tree
+	if paths := model.AllFilePaths(); len(paths) != 3 {
+		t.Errorf("Expected 3 files, got %v", paths)
+	}
+}
+
+func TestExpandAndCollapseAll(t *testing.T) {
+	model := newTestTree()
+	for i, item := range model.items {
+		if item.Path == "/project/main.go" {
+			model.cursor = i
+		}
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if len(model.items) != 7 {
+		t.Errorf("Expected all 7 items listed, got %d", len(model.items))
+	}
+	if model.items[model.cursor].Path != "/project/main.go" {
+		t.Errorf("Expected the cursor to stay on main.go, got %s", model.items[model.cursor].Path)
+	}
+
+	// Collapsing moves the cursor to the top-level folder holding it
+	for i, item := range model.items {
+		if item.Path == "/project/pkg/sub/b.go" {
+			model.cursor = i
+		}
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if len(model.items) != 3 || len(model.expanded) != 0 {
+		t.Errorf("Expected only the 3 top-level items, got %d", len(model.items))
+	}
+	if model.items[model.cursor].Path != "/project/pkg" {
+		t.Errorf("Expected the cursor on pkg, got %s", model.items[model.cursor].Path)
+	}
+}
+
+func TestExpandAllLimitsLargeTrees(t *testing.T) {
+	// Three levels of 20 folders, each holding 20 files: far more than the limit
+	var build func(path string, depth int) []*filesystem.FileNode
+	build = func(path string, depth int) []*filesystem.FileNode {
+		var children []*filesystem.FileNode
+		for i := range 20 {
+			child := &filesystem.FileNode{Name: fmt.Sprintf("n%02d", i), Path: fmt.Sprintf("%s/n%02d", path, i)}
+			if depth > 0 {
+				child.IsDir = true
+				child.Children = build(child.Path, depth-1)
+			}
+			children = append(children, child)
+		}
+		return children
+	}
+	model := NewFileTreeModel("/big", []string{}, DefaultTheme())
+	model.rootNode = &filesystem.FileNode{Name: "big", Path: "/big", IsDir: true, Children: build("/big", 3)}
+	model.refreshItems()
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("Expected a warning about the tree size")
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != WarnAlert {
+		t.Errorf("Expected a warning notification, got %+v", msg)
+	}
+	if !model.expanded["/big/n00/n00"] || model.expanded["/big/n00/n00/n00"] {
+		t.Error("Expected only two levels of folders to be expanded")
+	}
+	if len(model.items) != 20+20*20+20*20*20 {
+		t.Errorf("Expected three levels of items listed, got %d", len(model.items))
+	}
+}
+
+func TestSelectAndDeselectAllVisible(t *testing.T) {
+	model := newTestTree()
+	model.expanded["/project/pkg"] = true
+	model.refreshItems()
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	msg, ok := cmd().(FileSelectionMsg)
+	if !ok {
+		t.Fatalf("Expected a FileSelectionMsg, got %T", msg)
+	}
+	// Files inside collapsed folders aren't visible, so they stay unselected
+	expected := map[string]bool{"/project/main.go": true, "/project/pkg/a.go": true}
+	if !maps.Equal(msg.SelectedFiles, expected) {
+		t.Errorf("Expected the visible files selected, got %v", msg.SelectedFiles)
+	}
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if msg, ok := cmd().(FileSelectionMsg); !ok || len(msg.SelectedFiles) != 0 {
+		t.Errorf("Expected ctrl+d to deselect every file, got %+v", msg)
+	}
+
+	// Both are undoable
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if !maps.Equal(model.GetSelectedFiles(), expected) {
+		t.Errorf("Expected undo to restore the selection, got %v", model.GetSelectedFiles())
+	}
+}
+
+func TestSelectAllVisibleLimit(t *testing.T) {
+	root := &filesystem.FileNode{Name: "big", Path: "/big", IsDir: true}
+	for i := range 150 {
+		name := fmt.Sprintf("file%03d.go", i)
+		root.Children = append(root.Children, &filesystem.FileNode{Name: name, Path: "/big/" + name})
+	}
+	model := NewFileTreeModel("/big", []string{}, DefaultTheme())
+	model.rootNode = root
+	model.SetMaxBulkSelect(100)
+	model.refreshItems()
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Expected the selection update and a warning, got %T", cmd())
+	}
+	if msg, ok := batch[1]().(NotificationMsg); !ok || msg.AlertType != WarnAlert {
+		t.Errorf("Expected a warning notification, got %+v", msg)
+	}
+	selected := model.GetSelectedFiles()
+	if len(selected) != 100 || !selected["/big/file099.go"] || selected["/big/file100.go"] {
+		t.Errorf("Expected only the first 100 files selected, got %d", len(selected))
+	}
+
+	// Without a limit every file is selected
+	model.SetMaxBulkSelect(0)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	if len(model.GetSelectedFiles()) != 150 {
+		t.Errorf("Expected all 150 files selected, got %d", len(model.GetSelectedFiles()))
+	}
+}
+
+func TestFileTreeRefreshKeepsCursorAndExpansion(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/c.go"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	model := NewFileTreeModel(root, []string{filepath.Join(root, "pkg", "a.go")}, DefaultTheme())
+	model.expanded[filepath.Join(root, "pkg")] = true
+	model.Init()
+	cursorPath := filepath.Join(root, "pkg", "c.go")
+	for i, item := range model.items {
+		if item.Path == cursorPath {
+			model.cursor = i
+		}
+	}
+
+	// A new file listed before the cursor, and a selected file deleted
+	if err := os.WriteFile(filepath.Join(root, "pkg", "b.go"), []byte("content"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "pkg", "a.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+
+	msg, ok := model.RefreshCmd()().(TreeRefreshedMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("Expected a TreeRefreshedMsg, got %+v", msg)
+	}
+	cmd := model.applyRefresh(msg)
+
+	var paths []string
+	for _, item := range model.items {
+		rel, _ := filepath.Rel(root, item.Path)
+		paths = append(paths, rel)
+	}
+	expected := []string{"main.go", "pkg", filepath.Join("pkg", "b.go"), filepath.Join("pkg", "c.go")}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v with pkg still expanded, got %v", expected, paths)
+	}
+	if model.items[model.cursor].Path != cursorPath {
+		t.Errorf("Expected the cursor to stay on %s, got %s", cursorPath, model.items[model.cursor].Path)
+	}
+	if model.selected[filepath.Join(root, "pkg", "a.go")] {
+		t.Error("Expected the deleted file to be deselected")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a selection update for the deleted file")
+	}
+	if _, ok := cmd().(FileSelectionMsg); !ok {
+		t.Error("Expected a FileSelectionMsg")
+	}
+}
+
+func TestFileTreeManualRefresh(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "old"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.expanded[filepath.Join(root, "old")] = true
+	model.Init()
+
+	// Files added and removed after the initial scan
+	newFile := filepath.Join(root, "new.go")
+	if err := os.WriteFile(newFile, []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "old")); err != nil {
+		t.Fatalf("Failed to remove directory: %v", err)
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if cmd == nil {
+		t.Fatal("Expected ctrl+r to rescan the tree")
+	}
+	msg, ok := cmd().(TreeRefreshedMsg)
+	if !ok || !msg.Manual {
+		t.Fatalf("Expected a manual TreeRefreshedMsg, got %+v", msg)
+	}
+	_, cmd = model.Update(msg)
+
+	if len(model.items) != 1 || model.items[0].Path != newFile {
+		t.Errorf("Expected only the new file listed, got %+v", model.items)
+	}
+	if len(model.expanded) != 0 {
+		t.Errorf("Expected the deleted folder to be forgotten, got %v", model.expanded)
+	}
+	// Nothing selected was deleted, so the alert is the only command
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected the refresh to be announced, got %+v", msg)
+	}
+}
+
+func TestOversizedFilesCannotBeSelected(t *testing.T) {
+	root := t.TempDir()
+	small := filepath.Join(root, "small.go")
+	large := filepath.Join(root, "large.json")
+	if err := os.WriteFile(small, []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to write small.go: %v", err)
+	}
+	if err := os.WriteFile(large, []byte(strings.Repeat("x", 2048)), 0644); err != nil {
+		t.Fatalf("Failed to write large.json: %v", err)
+	}
+
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.SetMaxFileSize(1024)
+	model.Init()
+	model.SetSize(80, 20)
+
+	for i, item := range model.items {
+		if item.Path == large {
+			model.cursor = i
+		}
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}); cmd != nil || model.selected[large] {
+		t.Error("Expected the oversized file not to be selectable")
+	}
+	if !strings.Contains(model.View(), "large.json (too large)") {
+		t.Error("Expected the oversized file to be marked in the tree")
+	}
+
+	// Selecting the directory only picks up files within the limit
+	model.selectAllInDirectory(root)
+	if !model.selected[small] || model.selected[large] {
+		t.Errorf("Expected only small.go to be selected, got %v", model.selected)
+	}
+	if _, err := model.SelectByGlob("*.json"); err != nil || model.selected[large] {
+		t.Errorf("Expected glob selection to skip the oversized file, err %v", err)
+	}
+	if cmd := model.RevealAndSelect(large); cmd != nil || model.selected[large] {
+		t.Error("Expected quick open not to select the oversized file")
+	}
+}
+
+func TestApplyFilter(t *testing.T) {
+	model := newTestTree()
+	model.expanded["/project/pkg"] = true
+	model.refreshItems()
+
+	if err := model.applyFilter(`\.go$`); err != nil {
+		t.Fatalf("applyFilter failed: %v", err)
+	}
+	var paths []string
+	for _, item := range model.items {
+		paths = append(paths, item.Path)
+	}
+	// docs has no matching files and is hidden, pkg keeps its matching child;
+	// sub is collapsed and doesn't match itself
+	want := []string{"/project/pkg", "/project/pkg/a.go", "/project/main.go"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, paths)
+	}
+
+	// An invalid pattern is reported and keeps the current filter
+	if err := model.applyFilter("("); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+	if len(model.items) != len(want) || model.filterErr == "" {
+		t.Errorf("Expected the previous filter to stay applied with an error, got %d items", len(model.items))
+	}
+}
+
+func TestFilterModeTypingAndClearing(t *testing.T) {
+	model := newTestTree()
+	total := len(model.items)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !model.IsFiltering() {
+		t.Fatal("Expected / to start filter mode")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("main")})
+	if model.filter != "main" || len(model.items) != 1 || model.items[0].Path != "/project/main.go" {
+		t.Errorf("Expected only main.go to match, got filter %q and %d items", model.filter, len(model.items))
+	}
+
+	// Enter keeps the filter, esc clears it
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.IsFiltering() || len(model.items) != 1 {
+		t.Errorf("Expected enter to keep the filter, got %d items", len(model.items))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.filter != "" || len(model.items) != total {
+		t.Errorf("Expected esc to restore all %d items, got %d", total, len(model.items))
+	}
+}
+
+// newLargeTestTree returns a sized file tree model holding count flat items
+func newLargeTestTree(count int) *FileTreeModel {
+	model := NewFileTreeModel("/tmp", []string{}, DefaultTheme())
+	model.items = make([]filesystem.FileTreeItem, count)
+	for i := range model.items {
+		name := fmt.Sprintf("file%05d.go", i)
+		model.items[i] = filesystem.FileTreeItem{Name: name, Path: "/tmp/" + name}
+	}
+	model.SetSize(80, 40)
+	return model
+}
+
+func TestFileTreeRendersOnlyVisibleRows(t *testing.T) {
+	model := newLargeTestTree(50000)
+	model.cursor = 25000
+
+	start := time.Now()
+	const renders = 10
+	var view string
+	for range renders {
+		view = model.View()
+	}
+	if elapsed := time.Since(start) / renders; elapsed > 5*time.Millisecond {
+		t.Errorf("Expected a render of 50000 items to take under 5ms, took %v", elapsed)
+	}
+
+	if !strings.Contains(view, "file25000.go") {
+		t.Errorf("Expected the row under the cursor in the view, got:\n%s", view)
+	}
+	if strings.Contains(view, "file00000.go") || strings.Contains(view, "file49999.go") {
+		t.Errorf("Expected rows out of view not to be rendered, got:\n%s", view)
+	}
+	if items := model.visibleItems(); len(items) != model.viewport.Height+2*treeOverscan {
+		t.Errorf("Expected %d visible items, got %d", model.viewport.Height+2*treeOverscan, len(items))
+	}
+
+	// The last rows can be scrolled into view
+	model.cursor = 49999
+	if view := model.View(); !strings.Contains(view, "file49999.go") {
+		t.Errorf("Expected the last row in the view, got:\n%s", view)
+	}
+}
+
+func TestFileTreeStatistics(t *testing.T) {
+	model := newTestTree()
+	for _, node := range model.rootNode.Children {
+		node.SizeBytes = 0
+		for _, child := range node.Children {
+			child.SizeBytes = 1024
+		}
+	}
+	model.rootNode.Children[2].SizeBytes = 2048 // main.go
+	model.expanded["/project/pkg"] = true
+	model.selected["/project/main.go"] = true
+	model.selected["/project/pkg/a.go"] = true
+	model.selected["/project/docs/readme.md"] = true // Inside a collapsed folder
+	model.refreshItems()
+
+	expected := TreeStatistics{FileCount: 2, DirCount: 3, SelectedCount: 2, TotalBytes: 3072, SelectedBytes: 3072}
+	if stats := model.GetStatistics(); stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+
+	model.SetSize(80, 20)
+	if view := model.View(); !strings.Contains(view, "2 files, 3 dirs, 2 selected (3 KB)") {
+		t.Errorf("Expected the statistics below the tree, got:\n%s", view)
+	}
+
+	// The footer follows selection changes
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if view := model.View(); !strings.Contains(view, "0 selected (0 B)") {
+		t.Errorf("Expected the statistics to be updated, got:\n%s", view)
+	}
+}
+
+func TestFileTreeShowsLineCountOfCursorFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x\n", 142)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	model := NewFileTreeModel(dir, nil, DefaultTheme())
+	cmd := model.Init()
+	model.SetSize(80, 20)
+	if view := model.View(); !strings.Contains(view, "main.go — counting lines, 284 B") {
+		t.Errorf("Expected the size while the lines are counted, got:\n%s", view)
+	}
+
+	if cmd == nil {
+		t.Fatal("Expected the lines of the file under the cursor to be counted")
+	}
+	msg, ok := cmd().(LineCountMsg)
+	if !ok || msg.Path != path || msg.Lines != 142 {
+		t.Fatalf("Expected 142 lines for main.go, got %+v", msg)
+	}
+	model.Update(msg)
+	if view := model.View(); !strings.Contains(view, "main.go — 142 lines, 284 B") {
+		t.Errorf("Expected the line count below the tree, got:\n%s", view)
+	}
+
+	// Counted files aren't counted again until the tree is refreshed
+	if model.countLinesCmd() != nil {
+		t.Error("Expected the line count to be cached")
+	}
+}
+
+func TestBreadcrumbFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     filesystem.FileTreeItem
+		expected string
+	}{
+		{name: "file at the root", item: filesystem.FileTreeItem{Path: "/project/main.go"}, expected: ""},
+		{name: "directory at the root", item: filesystem.FileTreeItem{Path: "/project/src", IsDir: true}, expected: ""},
+		{name: "nested file", item: filesystem.FileTreeItem{Path: "/project/src/internal/tui/app.go"}, expected: "src / internal / tui"},
+		{name: "nested directory", item: filesystem.FileTreeItem{Path: "/project/src/internal", IsDir: true}, expected: "src"},
+		{name: "outside the root", item: filesystem.FileTreeItem{Path: "/other/main.go"}, expected: ""},
+		{name: "sibling with a longer name", item: filesystem.FileTreeItem{Path: "/project2/pkg/a.go"}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BreadcrumbFor(tt.item, "/project"); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFileTreeBreadcrumbFollowsCursor(t *testing.T) {
+	model := newTestTree()
+	model.expanded["/project/pkg"] = true
+	model.expanded["/project/pkg/sub"] = true
+	model.refreshItems()
+	model.SetSize(80, 30)
+
+	if !model.JumpToPath("/project/pkg/sub/b.go") {
+		t.Fatal("Expected to find b.go")
+	}
+	if view := model.View(); !strings.Contains(view, "project / pkg / sub") {
+		t.Errorf("Expected the breadcrumb of b.go, got:\n%s", view)
+	}
+}
+
+func TestFileTreeRangeSelection(t *testing.T) {
+	model := NewFileTreeModel("/project", []string{}, DefaultTheme())
+	model.rootNode = &filesystem.FileNode{Name: "project", Path: "/project", IsDir: true}
+	for i := range 8 {
+		name := fmt.Sprintf("file%05d.go", i)
+		model.rootNode.Children = append(model.rootNode.Children, &filesystem.FileNode{Name: name, Path: "/project/" + name})
+	}
+	// Directories in the range are skipped
+	model.rootNode.Children = slices.Insert(model.rootNode.Children, 4, &filesystem.FileNode{Name: "docs", Path: "/project/docs", IsDir: true})
+	model.refreshItems()
+	model.SetSize(80, 40)
+	shiftDown := tea.KeyMsg{Type: tea.KeyShiftDown}
+	shiftUp := tea.KeyMsg{Type: tea.KeyShiftUp}
+	selectedRange := func() []string {
+		var paths []string
+		for _, item := range model.items {
+			if model.selected[item.Path] {
+				paths = append(paths, item.Name)
+			}
+		}
+		return paths
+	}
+
+	model.cursor = 2
+	for range 3 {
+		if _, cmd := model.Update(shiftDown); cmd == nil {
+			t.Fatal("Expected a selection update for each extension")
+		}
+	}
+	expected := []string{"file00002.go", "file00003.go", "file00004.go"}
+	if got := selectedRange(); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// Going back shrinks the range
+	model.Update(shiftUp)
+	if got := selectedRange(); !slices.Equal(got, expected[:2]) {
+		t.Errorf("Expected %v, got %v", expected[:2], got)
+	}
+
+	// Going past the start selects above it
+	for range 4 {
+		model.Update(shiftUp)
+	}
+	expected = []string{"file00000.go", "file00001.go", "file00002.go"}
+	if got := selectedRange(); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// Another key ends the range, so the next one starts at the cursor and
+	// deselects when started on a selected file
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model.Update(shiftDown)
+	expected = []string{"file00000.go"}
+	if got := selectedRange(); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestFileTreeStatisticsAreFast(t *testing.T) {
+	model := newLargeTestTree(10000)
+
+	start := time.Now()
+	const runs = 100
+	for range runs {
+		model.GetStatistics()
+	}
+	if elapsed := time.Since(start) / runs; elapsed > time.Millisecond {
+		t.Errorf("Expected statistics for 10000 items to take under 1ms, took %v", elapsed)
+	}
+}
+
+func TestFileTreeMouseWheelScrolls(t *testing.T) {
+	model := newLargeTestTree(100)
+
+	model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if model.viewport.YOffset != model.viewport.MouseWheelDelta {
+		t.Errorf("Expected the wheel to scroll by %d rows, got offset %d", model.viewport.MouseWheelDelta, model.viewport.YOffset)
+	}
+	if model.cursor != model.viewport.YOffset {
+		t.Errorf("Expected the cursor to stay in view at row %d, got %d", model.viewport.YOffset, model.cursor)
+	}
+	if view := model.View(); !strings.Contains(view, fmt.Sprintf("file%05d.go", model.cursor)) {
+		t.Errorf("Expected the scrolled rows in the view, got:\n%s", view)
+	}
+
+	model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if model.viewport.YOffset != 0 {
+		t.Errorf("Expected scrolling up to stop at the top, got offset %d", model.viewport.YOffset)
+	}
+}
+
+func BenchmarkFileTreeView(b *testing.B) {
+	model := newLargeTestTree(50000)
+	model.cursor = 25000
+	b.ResetTimer()
+	for range b.N {
+		model.View()
+	}
+}
+
+func BenchmarkFileTreeStatistics(b *testing.B) {
+	model := newLargeTestTree(10000)
+	b.ResetTimer()
+	for range b.N {
+		model.GetStatistics()
+	}
+}
+
+func TestFileTreeFindByPath(t *testing.T) {
+	model := newTestTree()
+
+	if i, found := model.FindByPath("/project/main.go"); !found || model.items[i].Path != "/project/main.go" {
+		t.Errorf("Expected main.go to be found, got index %d, found %v", i, found)
+	}
+	// Files in collapsed directories aren't listed
+	for _, path := range []string{"/project/pkg/a.go", "/project/missing.go"} {
+		if _, found := model.FindByPath(path); found {
+			t.Errorf("Expected %s not to be found", path)
+		}
+	}
+}
+
+func TestFileTreeJumpToPath(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "pkg", "sub", "b.go")
+	if err := os.MkdirAll(filepath.Dir(nested), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(nested, []byte("package sub"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.Init()
+	model.SetSize(40, 10)
+
+	// The parent directories of a file that isn't listed are expanded
+	if !model.JumpToPath(nested) {
+		t.Fatal("Expected to jump to the nested file")
+	}
+	if model.cursorPath() != nested {
+		t.Errorf("Expected the cursor on %s, got %s", nested, model.cursorPath())
+	}
+
+	if model.JumpToPath(filepath.Join(root, "missing.go")) {
+		t.Error("Expected no jump to a missing file")
+	}
+	if model.cursorPath() != nested {
+		t.Errorf("Expected the cursor to stay on %s, got %s", nested, model.cursorPath())
+	}
+}
+
+func TestFileTreeExpandedPathsRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/sub/b.go", "docs/readme.md"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	nested := filepath.Join(root, "pkg", "sub", "b.go")
+
+	model := NewFileTreeModel(root, []string{}, DefaultTheme())
+	model.Init()
+	if !model.JumpToPath(nested) {
+		t.Fatal("Expected to jump to the nested file")
+	}
+	expected := []string{filepath.Join(root, "pkg"), filepath.Join(root, "pkg", "sub")}
+	if paths := model.GetExpandedPaths(); !slices.Equal(paths, expected) {
+		t.Fatalf("Expected expanded paths %v, got %v", expected, paths)
+	}
+
+	// Save the expanded paths with the workspace and load them again
+	data, err := json.Marshal(config.WorkspaceState{Path: root, ExpandedPaths: model.GetExpandedPaths()})
+	if err != nil {
+		t.Fatalf("Failed to marshal workspace: %v", err)
+	}
+	var workspace config.WorkspaceState
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		t.Fatalf("Failed to unmarshal workspace: %v", err)
+	}
+
+	// Directories deleted since are skipped
+	workspace.ExpandedPaths = append(workspace.ExpandedPaths, filepath.Join(root, "gone"))
+	restored := NewFileTreeModel(root, []string{}, DefaultTheme())
+	restored.SetExpandedPaths(workspace.ExpandedPaths)
+	restored.Init()
+	if paths := restored.GetExpandedPaths(); !slices.Equal(paths, expected) {
+		t.Errorf("Expected restored expanded paths %v, got %v", expected, paths)
+	}
+	if _, found := restored.FindByPath(nested); !found {
+		t.Error("Expected the nested file to be listed in the restored tree")
+	}
+	if _, found := restored.FindByPath(filepath.Join(root, "docs", "readme.md")); found {
+		t.Error("Expected docs to stay collapsed")
+	}
+
+	// Setting the paths on a loaded tree expands them straight away
+	restored.SetExpandedPaths([]string{filepath.Join(root, "docs")})
+	if _, found := restored.FindByPath(filepath.Join(root, "docs", "readme.md")); !found {
+		t.Error("Expected docs to be expanded")
+	}
+	if _, found := restored.FindByPath(nested); found {
+		t.Error("Expected pkg to be collapsed")
+	}
+}
+
+func TestAppSavesExpandedPaths(t *testing.T) {
+	app := createTestApp(t)
+	pkgDir := filepath.Join(app.targetDir, "pkg")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	app.fileTree.Init()
+	index, found := app.fileTree.FindByPath(pkgDir)
+	if !found {
+		t.Fatal("Expected pkg to be listed")
+	}
+	app.fileTree.cursor = index
+
+	// Expanding the directory announces the tree change to the app
+	_, cmd := app.fileTree.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(FileSelectionMsg); ok {
+			app.Update(msg)
+		}
+	}
+	if !slices.Equal(app.workspace.ExpandedPaths, []string{pkgDir}) {
+		t.Errorf("Expected the workspace to record pkg as expanded, got %v", app.workspace.ExpandedPaths)
+	}
+}
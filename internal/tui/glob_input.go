@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GlobSelectMsg is sent when the user confirms a glob pattern to select files with
+type GlobSelectMsg struct {
+	Pattern string
+}
+
+// GlobInputModel is a small input for selecting files by glob pattern
+type GlobInputModel struct {
+	input   textinput.Model
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewGlobInputModel creates a new glob input model
+func NewGlobInputModel(theme Theme) *GlobInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "src/**/*.go"
+	ti.Prompt = "Glob: "
+
+	return &GlobInputModel{
+		input: ti,
+		theme: theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *GlobInputModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = int(float64(width)*0.6) - 14
+}
+
+// Show displays the input with an empty pattern
+func (m *GlobInputModel) Show() tea.Cmd {
+	m.visible = true
+	m.input.Reset()
+	return m.input.Focus()
+}
+
+// Hide closes the input
+func (m *GlobInputModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the input is currently shown
+func (m *GlobInputModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the glob input
+func (m *GlobInputModel) Update(msg tea.Msg) (*GlobInputModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			pattern := strings.TrimSpace(m.input.Value())
+			if pattern == "" {
+				return m, nil
+			}
+			m.Hide()
+			return m, func() tea.Msg {
+				return GlobSelectMsg{Pattern: pattern}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the glob input
+func (m *GlobInputModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.6)
+	dialogHeight := 9
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Select Files by Pattern"))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("*, ?, [abc] and ** (any directories) • Enter: select • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"strings"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Help contexts, in the order they are shown
+const (
+	HelpContextGlobal        = "Global"
+	HelpContextFileTree      = "File Tree"
+	HelpContextSelectedFiles = "Selected Files"
+	HelpContextChat          = "Chat"
+	HelpContextDialogs       = "Dialogs"
+)
+
+// HelpEntry describes what a key does in a context
+type HelpEntry struct {
+	Context     string
+	Key         string
+	Description string
+}
+
+// BuildHelpTable returns the key bindings relevant to the focused panel: the
+// global bindings, the panel's own bindings and the bindings shared by dialogs.
+// Configurable bindings are read from sm.
+func BuildHelpTable(sm *config.SettingsManager, focused FocusedPanel) []HelpEntry {
+	entries := []HelpEntry{
+		{HelpContextGlobal, "tab / shift+tab", "Next / previous panel"},
+		{HelpContextGlobal, sm.GetQuickOpenKey(), "Quick-open a file"},
+		{HelpContextGlobal, sm.GetGlobSelectKey(), "Select files by glob pattern"},
+		{HelpContextGlobal, sm.GetModifiedSinceKey(), "Only include files modified since a time"},
+		{HelpContextGlobal, sm.GetPresetsKey(), "Select a saved selection preset"},
+		{HelpContextGlobal, sm.GetSavePresetKey(), "Save the selection as a preset"},
+		{HelpContextGlobal, sm.GetHistoryKey(), "Prompt history"},
+		{HelpContextGlobal, sm.GetExportKey(), "Save the prompt to a file"},
+		{HelpContextGlobal, sm.GetExportManifestKey(), "Copy the list of selected files"},
+		{HelpContextGlobal, "ctrl+s", "Generate the prompt"},
+		{HelpContextGlobal, "ctrl+y", "Copy the prompt"},
+		{HelpContextGlobal, "ctrl+shift+s / alt+s", "Save a screenshot for bug reports"},
+		{HelpContextGlobal, sm.GetWebhookKey(), "Send the prompt to the webhook"},
+		{HelpContextGlobal, sm.GetWorkspaceListKey(), "Recent workspaces"},
+		{HelpContextGlobal, sm.GetMenuActivationKey(), "Enter menu mode"},
+		{HelpContextGlobal, sm.GetPersonaMenuKey(), "Choose personas (menu mode)"},
+		{HelpContextGlobal, sm.GetMenuModeFormatToggle(), "Cycle XML / JSON / Markdown (menu mode)"},
+		{HelpContextGlobal, sm.GetDebugToggleKey(), "Toggle debug mode"},
+		{HelpContextGlobal, sm.GetLayoutToggleKey(), "Cycle the panel layout"},
+		{HelpContextGlobal, sm.GetGitignoreCheckKey(), "Check the ignore patterns"},
+		{HelpContextGlobal, sm.GetSettingsKey(), "Change key bindings"},
+		{HelpContextGlobal, sm.GetHelpKey(), "Show this help"},
+		{HelpContextGlobal, "q / ctrl+c", "Quit"},
+	}
+
+	switch focused {
+	case FileTreePanel:
+		entries = append(entries,
+			HelpEntry{HelpContextFileTree, "↑/↓ or k/j", "Move the cursor"},
+			HelpEntry{HelpContextFileTree, "pgup/pgdn, g/G", "Page up / down, top / bottom"},
+			HelpEntry{HelpContextFileTree, "enter", "Expand or collapse a folder"},
+			HelpEntry{HelpContextFileTree, "e / E", "Expand / collapse every folder"},
+			HelpEntry{HelpContextFileTree, "ctrl+r", "Rescan the tree"},
+			HelpEntry{HelpContextFileTree, "space", "Select or deselect a file"},
+			HelpEntry{HelpContextFileTree, "/", "Filter by regex (esc clears it)"},
+			HelpEntry{HelpContextFileTree, "a / A", "Select / deselect everything in the folder"},
+			HelpEntry{HelpContextFileTree, "ctrl+a / ctrl+d", "Select every visible file / deselect all"},
+			HelpEntry{HelpContextFileTree, "ctrl+z / alt+z", "Undo / redo a selection change"},
+			HelpEntry{HelpContextFileTree, "o", "Open the file in $EDITOR"},
+			HelpEntry{HelpContextFileTree, "b", "Show git blame for the file"},
+		)
+	case SelectedFilesPanel:
+		entries = append(entries,
+			HelpEntry{HelpContextSelectedFiles, "↑/↓", "Move the cursor"},
+			HelpEntry{HelpContextSelectedFiles, "pgup/pgdown / home/end", "Move a page / to the first or last file"},
+			HelpEntry{HelpContextSelectedFiles, "ctrl+↑/ctrl+↓", "Move the file earlier / later"},
+			HelpEntry{HelpContextSelectedFiles, "x / delete", "Remove the file"},
+			HelpEntry{HelpContextSelectedFiles, "r", "Include a range of lines"},
+			HelpEntry{HelpContextSelectedFiles, "t", "Tag the file"},
+			HelpEntry{HelpContextSelectedFiles, "ctrl+j", "Jump to the file in the tree"},
+			HelpEntry{HelpContextSelectedFiles, "1 / 2 / 3", "Show the Context / Focus / Reference bucket"},
+			HelpEntry{HelpContextSelectedFiles, "s", "Sort by selection order / name / size"},
+			HelpEntry{HelpContextSelectedFiles, "ctrl+c", "Copy the paths of all files"},
+			HelpEntry{HelpContextSelectedFiles, "ctrl+shift+c / alt+c", "Copy the path of the file"},
+			HelpEntry{HelpContextSelectedFiles, "ctrl+d", "Clear all files"},
+		)
+	case ChatPanel:
+		entries = append(entries,
+			HelpEntry{HelpContextChat, "type", "Write the user prompt"},
+			HelpEntry{HelpContextChat, "tab", "Leave the chat panel"},
+		)
+	}
+
+	return append(entries,
+		HelpEntry{HelpContextDialogs, "↑/↓", "Move the cursor"},
+		HelpEntry{HelpContextDialogs, "enter", "Confirm"},
+		HelpEntry{HelpContextDialogs, "esc", "Close"},
+		HelpEntry{HelpContextDialogs, "ctrl+n", "New persona (persona dialog)"},
+		HelpEntry{HelpContextDialogs, "d", "Delete persona (persona dialog)"},
+	)
+}
+
+// HelpOverlayModel shows the key bindings available in the focused panel
+type HelpOverlayModel struct {
+	entries []HelpEntry
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewHelpOverlayModel creates a new help overlay model
+func NewHelpOverlayModel(theme Theme) *HelpOverlayModel {
+	return &HelpOverlayModel{theme: theme}
+}
+
+// SetSize updates the overlay dimensions
+func (m *HelpOverlayModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the overlay with the given entries
+func (m *HelpOverlayModel) Show(entries []HelpEntry) {
+	m.entries = entries
+	m.visible = true
+}
+
+// Hide closes the overlay
+func (m *HelpOverlayModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the overlay is currently shown
+func (m *HelpOverlayModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the help overlay
+func (m *HelpOverlayModel) Update(msg tea.Msg) (*HelpOverlayModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "q":
+			m.Hide()
+		}
+	}
+	return m, nil
+}
+
+// View renders the help overlay
+func (m *HelpOverlayModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	keyWidth := 0
+	for _, entry := range m.entries {
+		if w := lipgloss.Width(entry.Key); w > keyWidth {
+			keyWidth = w
+		}
+	}
+
+	titleStyle := m.theme.titleStyle()
+	contextStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.theme.FocusBorder))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Width(keyWidth + 2)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Key Bindings"))
+	b.WriteString("\n")
+
+	lines := 4
+	context := ""
+	for _, entry := range m.entries {
+		if entry.Context != context {
+			context = entry.Context
+			b.WriteString("\n")
+			b.WriteString(contextStyle.Render(context))
+			b.WriteString("\n")
+			lines += 2
+		}
+		b.WriteString(keyStyle.Render(entry.Key))
+		b.WriteString(entry.Description)
+		b.WriteString("\n")
+		lines++
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("Esc or ?: close"))
+
+	dialogWidth := 64
+	if m.width > 0 && dialogWidth > m.width-4 {
+		dialogWidth = m.width - 4
+	}
+	dialogHeight := lines + 4
+	if m.height > 0 && dialogHeight > m.height-2 {
+		dialogHeight = m.height - 2
+	}
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBuildHelpTableFollowsFocus(t *testing.T) {
+	app := createTestApp(t)
+
+	hasContext := func(entries []HelpEntry, context string) bool {
+		for _, entry := range entries {
+			if entry.Context == context {
+				return true
+			}
+		}
+		return false
+	}
+
+	entries := BuildHelpTable(app.settingsManager, FileTreePanel)
+	if !hasContext(entries, HelpContextGlobal) || !hasContext(entries, HelpContextFileTree) || !hasContext(entries, HelpContextDialogs) {
+		t.Errorf("Expected global, file tree and dialog bindings, got %+v", entries)
+	}
+	if hasContext(entries, HelpContextSelectedFiles) {
+		t.Error("Expected no selected files bindings while the file tree is focused")
+	}
+
+	entries = BuildHelpTable(app.settingsManager, SelectedFilesPanel)
+	if !hasContext(entries, HelpContextSelectedFiles) || hasContext(entries, HelpContextFileTree) {
+		t.Errorf("Expected only the selected files panel bindings, got %+v", entries)
+	}
+}
+
+func TestHelpOverlayToggle(t *testing.T) {
+	app := createTestApp(t)
+	help := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+
+	app.focused = ChatPanel
+	app.Update(help)
+	if app.helpOverlay.IsVisible() {
+		t.Error("Expected ? to be typed into the chat, not open the help overlay")
+	}
+
+	app.focused = FileTreePanel
+	app.Update(help)
+	if !app.helpOverlay.IsVisible() {
+		t.Fatal("Expected ? to open the help overlay")
+	}
+	app.Update(help)
+	if app.helpOverlay.IsVisible() {
+		t.Error("Expected ? to close the help overlay")
+	}
+
+	app.Update(help)
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.helpOverlay.IsVisible() {
+		t.Error("Expected esc to close the help overlay")
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"coding-prompts-tui/internal/prompt"
+)
+
+// HistoryRestoreMsg is sent when a past prompt should be restored into the chat input
+type HistoryRestoreMsg struct {
+	UserPrompt string
+}
+
+// HistoryCopyMsg is sent when a past generated prompt should be copied to the clipboard
+type HistoryCopyMsg struct {
+	Content string
+}
+
+// HistoryDialogModel lists previously generated prompts
+type HistoryDialogModel struct {
+	entries []prompt.HistoryEntry
+	cursor  int
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewHistoryDialogModel creates a new history dialog model
+func NewHistoryDialogModel(theme Theme) *HistoryDialogModel {
+	return &HistoryDialogModel{theme: theme}
+}
+
+// SetSize updates the dialog dimensions
+func (m *HistoryDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog with the given entries, newest first
+func (m *HistoryDialogModel) Show(entries []prompt.HistoryEntry) {
+	m.entries = entries
+	m.cursor = 0
+	m.visible = true
+}
+
+// Hide closes the dialog
+func (m *HistoryDialogModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *HistoryDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the history dialog
+func (m *HistoryDialogModel) Update(msg tea.Msg) (*HistoryDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "ctrl+c":
+		m.Hide()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if m.cursor >= len(m.entries) {
+			return m, nil
+		}
+		userPrompt := m.entries[m.cursor].UserPrompt
+		m.Hide()
+		return m, func() tea.Msg {
+			return HistoryRestoreMsg{UserPrompt: userPrompt}
+		}
+	case "c":
+		if m.cursor >= len(m.entries) {
+			return m, nil
+		}
+		content := m.entries[m.cursor].Content
+		return m, func() tea.Msg {
+			return HistoryCopyMsg{Content: content}
+		}
+	}
+	return m, nil
+}
+
+// View renders the history dialog
+func (m *HistoryDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.6)
+	dialogHeight := int(float64(m.height) * 0.6)
+
+	// Lines available for entries: minus borders, padding, title, spacing and help
+	listHeight := dialogHeight - 8
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Prompt History"))
+	b.WriteString("\n\n")
+
+	// Keep the cursor within the visible window of entries
+	start := 0
+	if m.cursor >= listHeight {
+		start = m.cursor - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(m.entries) {
+		end = len(m.entries)
+	}
+
+	if len(m.entries) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("No prompts generated yet"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		entry := m.entries[i]
+		text := fmt.Sprintf("%s  %s", entry.Timestamp.Format("Jan 02 15:04"), entry.Label)
+		line := "  " + text
+		if i == m.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.theme.CursorFG)).
+				Bold(true).
+				Render("▶ " + text)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: restore prompt • c: copy • Esc: close"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"coding-prompts-tui/internal/prompt"
+)
+
+func TestHistoryDialog_RestoreAndCopy(t *testing.T) {
+	dialog := NewHistoryDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.Show([]prompt.HistoryEntry{
+		{Label: "newest", UserPrompt: "newest prompt", Content: "<prompt>newest</prompt>"},
+		{Label: "oldest", UserPrompt: "oldest prompt", Content: "<prompt>oldest</prompt>"},
+	})
+
+	dialog, _ = dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	_, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("Expected copy command")
+	}
+	copyMsg, ok := cmd().(HistoryCopyMsg)
+	if !ok || copyMsg.Content != "<prompt>oldest</prompt>" {
+		t.Errorf("Expected copy of oldest entry, got %#v", cmd())
+	}
+	if !dialog.IsVisible() {
+		t.Error("Expected dialog to stay open after copying")
+	}
+
+	_, cmd = dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected restore command")
+	}
+	restoreMsg, ok := cmd().(HistoryRestoreMsg)
+	if !ok || restoreMsg.UserPrompt != "oldest prompt" {
+		t.Errorf("Expected restore of oldest entry, got %#v", cmd())
+	}
+	if dialog.IsVisible() {
+		t.Error("Expected dialog to close after restoring")
+	}
+}
+
+func TestHistoryDialog_EmptyHistory(t *testing.T) {
+	dialog := NewHistoryDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.Show(nil)
+
+	if _, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Expected no command when history is empty")
+	}
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if dialog.IsVisible() {
+		t.Error("Expected esc to close the dialog")
+	}
+}
+
+func TestHistoryKeyFromApp(t *testing.T) {
+	app := createTestApp(t)
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	if !app.historyDialog.IsVisible() {
+		t.Fatal("Expected ctrl+h to open the history dialog")
+	}
+	app.historyDialog.Hide()
+
+	// While typing in the chat, ctrl+h is backspace
+	app.focused = ChatPanel
+	app.chat.textarea.SetValue("review")
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	if app.historyDialog.IsVisible() {
+		t.Error("Expected ctrl+h not to open the history dialog in the chat")
+	}
+	if got := app.chat.textarea.Value(); got != "revie" {
+		t.Errorf("Expected ctrl+h to delete a character of the chat, got %q", got)
+	}
+}
@@ -2,9 +2,14 @@ package tui
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"coding-prompts-tui/internal/cli"
 	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestFileTreeInitializationFromWorkspace(t *testing.T) {
@@ -19,12 +24,12 @@ func TestFileTreeInitializationFromWorkspace(t *testing.T) {
 	testPath := "/test/workspace"
 	workspace := &config.WorkspaceState{
 		Path:          testPath,
-		SelectedFiles: []string{"file1.go", "file2.go"},
+		SelectedFiles: config.NewSelectedFileStates([]string{"file1.go", "file2.go"}),
 		ChatInput:     "Test prompt",
 	}
 
 	// Create a file tree model with the workspace's selected files
-	model := NewFileTreeModel(testPath, workspace.SelectedFiles)
+	model := NewFileTreeModel(testPath, workspace.SelectedPaths(), DefaultTheme())
 
 	// Verify that the selected files are properly initialized
 	if len(model.selected) != 2 {
@@ -48,7 +53,7 @@ func TestFileTreeInitializationFromWorkspace(t *testing.T) {
 func TestChatModelInitializationFromWorkspace(t *testing.T) {
 	// Create a chat model with initial value from workspace
 	initialValue := "This is a test prompt from workspace"
-	model := NewChatModel(initialValue)
+	model := NewChatModel(initialValue, 0, true, DefaultTheme())
 
 	// Verify the textarea has the correct initial value
 	if model.textarea.Value() != initialValue {
@@ -57,7 +62,7 @@ func TestChatModelInitializationFromWorkspace(t *testing.T) {
 }
 
 func TestChatModelSetSize(t *testing.T) {
-	model := NewChatModel("")
+	model := NewChatModel("", 0, true, DefaultTheme())
 
 	// Test setting size
 	width := 80
@@ -72,11 +77,114 @@ func TestChatModelSetSize(t *testing.T) {
 		t.Errorf("Expected height %d, got %d", height, model.height)
 	}
 
-	// Verify textarea dimensions (height should account for title/help text)
-	expectedTextareaHeight := height - 4
+	// Verify textarea dimensions (height should account for title/help text and the character count)
+	expectedTextareaHeight := height - 5
 	if expectedTextareaHeight < 3 {
 		expectedTextareaHeight = 3
 	}
-	// Note: We can't directly check textarea width/height as they're private,
-	// but we can verify the method doesn't panic and the model stores the values
+	if model.textarea.Height() != expectedTextareaHeight {
+		t.Errorf("Expected textarea height %d, got %d", expectedTextareaHeight, model.textarea.Height())
+	}
+
+	// Without word wrap the textarea keeps its own width
+	fixed := NewChatModel("", 0, false, DefaultTheme())
+	before := fixed.textarea.Width()
+	fixed.SetSize(width, height)
+	if fixed.textarea.Width() != before {
+		t.Errorf("Expected the textarea width to stay %d, got %d", before, fixed.textarea.Width())
+	}
+	if model.textarea.Width() <= before {
+		t.Errorf("Expected the textarea to follow the panel width with word wrap, got %d", model.textarea.Width())
+	}
+}
+
+func TestChatModelCharLimit(t *testing.T) {
+	// A saved prompt is kept whole, but typing stops at the limit
+	model := NewChatModel("hello world", 5, true, DefaultTheme())
+	if model.GetPrompt() != "hello world" {
+		t.Errorf("Expected the saved prompt to be kept, got %q", model.GetPrompt())
+	}
+	model.SetPrompt("")
+	for _, r := range "abcdefgh" {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if model.GetPrompt() != "abcde" {
+		t.Errorf("Expected typing to stop at 5 characters, got %q", model.GetPrompt())
+	}
+	if view := model.View(); !strings.Contains(view, "5/5 chars") {
+		t.Errorf("Expected the character count and limit, got:\n%s", view)
+	}
+
+	unlimited := NewChatModel("abc", 0, true, DefaultTheme())
+	if view := unlimited.View(); !strings.Contains(view, "3 chars") || strings.Contains(view, "3/") {
+		t.Errorf("Expected only the character count without a limit, got:\n%s", view)
+	}
+}
+
+func TestManifestPreselectsFiles(t *testing.T) {
+	t.Setenv(config.ConfigDirEnv, t.TempDir())
+	targetDir := t.TempDir()
+	for _, name := range []string{"main.go", "pkg/a.go", "pkg/b.go"} {
+		path := filepath.Join(targetDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	manifest := filepath.Join(t.TempDir(), "files.txt")
+	if err := os.WriteFile(manifest, []byte("# review\nmain.go\npkg/b.go\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	// Loaded the way main does before opening the workspace
+	files, err := cli.ReadManifest(manifest, targetDir)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	workspace := &config.WorkspaceState{Path: targetDir, SelectedFiles: config.NewSelectedFileStates(files), ActivePersonas: []string{"default"}}
+	app := NewApp(targetDir, WithConfigManager(config.NewMemoryManager()), WithWorkspace(workspace))
+	app.AnnounceOnStart("selected 2 files from files.txt")
+
+	for _, name := range []string{"main.go", "pkg/b.go"} {
+		if !app.fileTree.selected[filepath.Join(targetDir, name)] {
+			t.Errorf("Expected %s to be selected", name)
+		}
+	}
+	if app.fileTree.selected[filepath.Join(targetDir, "pkg", "a.go")] {
+		t.Error("Expected pkg/a.go not to be selected")
+	}
+	if len(app.selectedFiles.GetPaths()) != 2 {
+		t.Errorf("Expected 2 files in the selected files panel, got %v", app.selectedFiles.GetPaths())
+	}
+
+	// Init announces them
+	var announced bool
+	cmds := app.Init()().(tea.BatchMsg)
+	app.Close() // Stops waiting for persona changes
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		if msg, ok := cmd().(NotificationMsg); ok && msg.Message == "selected 2 files from files.txt" {
+			announced = true
+		}
+	}
+	if !announced {
+		t.Error("Expected Init to announce the files from the manifest")
+	}
+}
+
+func TestAppSendsWebhookWithDefaultKey(t *testing.T) {
+	app := NewApp(t.TempDir(), WithConfigManager(config.NewMemoryManager()), WithSettingsManager(config.NewDefaultSettingsManager()))
+
+	// Without a url the key reaches the webhook delivery, which warns
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}, Alt: true})
+	if cmd == nil {
+		t.Fatal("Expected alt+y to deliver the prompt to the webhook")
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.Message != "no webhook url configured" {
+		t.Errorf("Expected the missing url warning, got %#v", msg)
+	}
 }
@@ -2,27 +2,75 @@ package tui
 
 import "github.com/charmbracelet/lipgloss"
 
+// LayoutMode selects how the file tree, chat and selected files panels are arranged
+type LayoutMode string
+
+const (
+	// LayoutDefault puts the file tree and chat side by side above the selected files
+	LayoutDefault LayoutMode = "default"
+	// LayoutVertical gives the file tree the full height on the left, with the chat
+	// above the selected files on the right
+	LayoutVertical LayoutMode = "vertical"
+	// LayoutCompact is the default arrangement without the header
+	LayoutCompact LayoutMode = "compact"
+)
+
+// layoutModes is the order in which the layout toggle cycles through the modes
+var layoutModes = []LayoutMode{LayoutDefault, LayoutVertical, LayoutCompact}
+
+// ParseLayoutMode returns the layout mode named by s, falling back to LayoutDefault
+func ParseLayoutMode(s string) LayoutMode {
+	for _, mode := range layoutModes {
+		if string(mode) == s {
+			return mode
+		}
+	}
+	return LayoutDefault
+}
+
+// Next returns the layout mode that follows m when toggling
+func (m LayoutMode) Next() LayoutMode {
+	for i, mode := range layoutModes {
+		if mode == m {
+			return layoutModes[(i+1)%len(layoutModes)]
+		}
+	}
+	return LayoutDefault
+}
+
 // LayoutConfig holds centralized layout configuration
 type LayoutConfig struct {
+	Mode               LayoutMode
 	HeaderHeight       int
 	FooterHeight       int
 	BorderCompensation int
 	TopHeightRatio     float64
+	LeftPanelRatio     float64
 }
 
 // NewLayoutConfig creates a default layout configuration
 func NewLayoutConfig() *LayoutConfig {
 	return &LayoutConfig{
+		Mode:               LayoutDefault,
 		HeaderHeight:       3,
 		FooterHeight:       3,
 		BorderCompensation: 2, // 1 pixel border on each side
 		TopHeightRatio:     0.66,
+		LeftPanelRatio:     0.5,
+	}
+}
+
+// VisibleHeaderHeight returns the height of the header, which the compact layout hides
+func (lc *LayoutConfig) VisibleHeaderHeight() int {
+	if lc.Mode == LayoutCompact {
+		return 0
 	}
+	return lc.HeaderHeight
 }
 
 // AvailableHeight calculates the height available for main content panels
 func (lc *LayoutConfig) AvailableHeight(totalHeight int) int {
-	return totalHeight - lc.HeaderHeight - lc.FooterHeight
+	return totalHeight - lc.VisibleHeaderHeight() - lc.FooterHeight
 }
 
 // TopPanelHeight calculates the height for top panels
@@ -38,13 +86,14 @@ func (lc *LayoutConfig) BottomPanelHeight(totalHeight int) int {
 	return availableHeight - topHeight
 }
 
+// CalcPanelWidth returns percentage percent of totalWidth, e.g. 36 for 30 of 120
 func (lc *LayoutConfig) CalcPanelWidth(totalWidth int, percentage float64) int {
 	return int(float64(totalWidth) * percentage / 100)
 }
 
-// LeftPanelWidth calculates the width for left panels (50% split)
+// LeftPanelWidth calculates the width for left panels
 func (lc *LayoutConfig) LeftPanelWidth(totalWidth int) int {
-	return lc.CalcPanelWidth(totalWidth, 30)
+	return int(float64(totalWidth) * lc.LeftPanelRatio)
 }
 
 // RightPanelWidth calculates the width for right panels
@@ -87,3 +136,20 @@ func CreatePanel(content string, focused bool, normalStyle, focusedStyle lipglos
 	style := FocusStyle(focused, normalStyle, focusedStyle)
 	return PanelStyle(style, width, height).Render(content)
 }
+
+// RenderDialog renders content in a bordered dialog box of the given size, centered in the screen area,
+// with the border in the title color of theme
+func RenderDialog(theme Theme, content string, dialogWidth, dialogHeight, screenWidth, screenHeight int) string {
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.ThickBorder()).
+		BorderForeground(lipgloss.Color(theme.TitleFG)).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Height(dialogHeight)
+
+	return lipgloss.Place(screenWidth, screenHeight, lipgloss.Center, lipgloss.Center,
+		dialogStyle.Render(content),
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("237")),
+	)
+}
@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -34,8 +36,8 @@ func TestLayoutConfig_Calculations(t *testing.T) {
 		t.Errorf("BottomPanelHeight: expected %d, got %d", expectedBottomHeight, bottomHeight)
 	}
 
-	// Test left panel width calculation (30% of total width)
-	expectedLeftWidth := int(float64(totalWidth) * 0.3)
+	// Test left panel width calculation (half of total width)
+	expectedLeftWidth := int(float64(totalWidth) * 0.5)
 	leftWidth := config.LeftPanelWidth(totalWidth)
 	if leftWidth != expectedLeftWidth {
 		t.Errorf("LeftPanelWidth: expected %d, got %d", expectedLeftWidth, leftWidth)
@@ -134,3 +136,107 @@ func TestCreatePanel(t *testing.T) {
 		t.Error("CreatePanel should return rendered content")
 	}
 }
+
+func TestLayoutConfig_CustomRatios(t *testing.T) {
+	config := NewLayoutConfig()
+	config.TopHeightRatio = 0.5
+	config.LeftPanelRatio = 0.5
+
+	if got := config.LeftPanelWidth(120); got != 60 {
+		t.Errorf("LeftPanelWidth: expected 60, got %d", got)
+	}
+	if got := config.RightPanelWidth(120); got != 60 {
+		t.Errorf("RightPanelWidth: expected 60, got %d", got)
+	}
+
+	// The right panels take whatever the configured left share leaves
+	config.LeftPanelRatio = 0.7
+	if left, right := config.LeftPanelWidth(120), config.RightPanelWidth(120); left != 84 || right != 36 {
+		t.Errorf("Expected an 84/36 split, got %d/%d", left, right)
+	}
+	if got := config.CalcPanelWidth(120, 30); got != 36 {
+		t.Errorf("CalcPanelWidth: expected 36, got %d", got)
+	}
+
+	available := config.AvailableHeight(46)
+	if got := config.TopPanelHeight(46); got != available/2 {
+		t.Errorf("TopPanelHeight: expected %d, got %d", available/2, got)
+	}
+}
+
+func TestLayoutModeCycle(t *testing.T) {
+	if got := ParseLayoutMode("vertical"); got != LayoutVertical {
+		t.Errorf("Expected vertical, got %q", got)
+	}
+	if got := ParseLayoutMode("unknown"); got != LayoutDefault {
+		t.Errorf("Expected unknown modes to fall back to default, got %q", got)
+	}
+
+	mode := LayoutDefault
+	for _, want := range []LayoutMode{LayoutVertical, LayoutCompact, LayoutDefault} {
+		mode = mode.Next()
+		if mode != want {
+			t.Errorf("Expected %q, got %q", want, mode)
+		}
+	}
+}
+
+func TestLayoutToggle(t *testing.T) {
+	app := createTestApp(t)
+	app.handleStateChange(LayoutChangeMsg{Width: 120, Height: 40})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if app.layoutConfig.Mode != LayoutVertical {
+		t.Fatalf("Expected ctrl+l to switch to the vertical layout, got %q", app.layoutConfig.Mode)
+	}
+
+	// The file tree spans the full height, the chat is above the selected files
+	sizes := app.panelLayout()
+	fileTree, chat, selected := sizes.FileTree, sizes.Chat, sizes.Selected
+	available := app.layoutConfig.AvailableHeight(app.height)
+	if fileTree.Height != available || chat.Height+selected.Height != available {
+		t.Errorf("Expected a full height file tree, got %+v, %+v, %+v", fileTree, chat, selected)
+	}
+	if chat.Width != selected.Width || fileTree.Width+chat.Width != app.width {
+		t.Errorf("Expected chat and selected files to share the right column, got %+v, %+v", chat, selected)
+	}
+	if lines := strings.Count(app.mainLayout(), "\n") + 1; lines != app.height {
+		t.Errorf("Expected the layout to fill %d lines, got %d", app.height, lines)
+	}
+
+	// Clicking low on the left still focuses the file tree
+	app.focused = ChatPanel
+	if cmd := app.handleMouseClick(1, app.height-app.layoutConfig.FooterHeight-1); cmd != nil {
+		app.Update(cmd())
+	}
+	if app.focused != FileTreePanel {
+		t.Errorf("Expected the click to focus the file tree, got %v", app.focused)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if app.layoutConfig.Mode != LayoutCompact || app.layoutConfig.VisibleHeaderHeight() != 0 {
+		t.Errorf("Expected the compact layout without a header, got %q", app.layoutConfig.Mode)
+	}
+	if strings.Contains(app.mainLayout(), "Persona") {
+		t.Error("Expected the compact layout to hide the persona header")
+	}
+}
+
+func TestChatResizesWithLayout(t *testing.T) {
+	app := createTestApp(t)
+
+	// Narrow panels wrap the chat help text onto more lines
+	for _, size := range []LayoutChangeMsg{{Width: 160, Height: 50}, {Width: 70, Height: 30}} {
+		app.handleStateChange(size)
+
+		panel := app.panelLayout().Chat
+		contentWidth, contentHeight := panel.Width-2-2, panel.Height-2-2
+		view := app.chat.View()
+		if width := lipgloss.Width(view); width != contentWidth {
+			t.Errorf("%dx%d: expected the chat to be %d wide, got %d", size.Width, size.Height, contentWidth, width)
+		}
+		if height := lipgloss.Height(view); height != contentHeight {
+			t.Errorf("%dx%d: expected the chat to fill %d lines, got %d", size.Width, size.Height, contentHeight, height)
+		}
+	}
+}
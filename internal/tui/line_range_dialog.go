@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LineRangeMsg is sent when the user confirms a line range for a selected file.
+// A zero StartLine clears the range.
+type LineRangeMsg struct {
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+// LineRangeDialogModel edits the range of lines included from a selected file
+type LineRangeDialogModel struct {
+	startInput textinput.Model
+	endInput   textinput.Model
+	focusEnd   bool
+	path       string
+	err        string
+	width      int
+	height     int
+	visible    bool
+	theme      Theme
+}
+
+// NewLineRangeDialogModel creates a new line range dialog model
+func NewLineRangeDialogModel(theme Theme) *LineRangeDialogModel {
+	newInput := func(prompt, placeholder string) textinput.Model {
+		ti := textinput.New()
+		ti.Prompt = prompt
+		ti.Placeholder = placeholder
+		ti.CharLimit = 9
+		ti.Width = 10
+		ti.Validate = validateDigits
+		return ti
+	}
+
+	return &LineRangeDialogModel{
+		startInput: newInput("Start line: ", "1"),
+		endInput:   newInput("End line:   ", "end of file"),
+		theme:      theme,
+	}
+}
+
+// validateDigits rejects input containing anything but digits
+func validateDigits(s string) error {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("line numbers must be digits")
+		}
+	}
+	return nil
+}
+
+// SetSize updates the dialog dimensions
+func (m *LineRangeDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog for the file at path, pre-filled with its current range
+func (m *LineRangeDialogModel) Show(path string, startLine, endLine int) tea.Cmd {
+	m.visible = true
+	m.path = path
+	m.err = ""
+	m.startInput.Reset()
+	m.endInput.Reset()
+	if startLine > 0 {
+		m.startInput.SetValue(strconv.Itoa(startLine))
+	}
+	if endLine > 0 {
+		m.endInput.SetValue(strconv.Itoa(endLine))
+	}
+	m.focusEnd = false
+	m.endInput.Blur()
+	return m.startInput.Focus()
+}
+
+// Hide closes the dialog
+func (m *LineRangeDialogModel) Hide() {
+	m.visible = false
+	m.startInput.Blur()
+	m.endInput.Blur()
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *LineRangeDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the line range dialog
+func (m *LineRangeDialogModel) Update(msg tea.Msg) (*LineRangeDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "tab", "shift+tab", "up", "down":
+			return m, m.toggleFocus()
+		case "enter":
+			startLine, endLine, err := m.parseRange()
+			if err != nil {
+				m.err = err.Error()
+				return m, nil
+			}
+			m.Hide()
+			path := m.path
+			return m, func() tea.Msg {
+				return LineRangeMsg{Path: path, StartLine: startLine, EndLine: endLine}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.focusEnd {
+		m.endInput, cmd = m.endInput.Update(msg)
+	} else {
+		m.startInput, cmd = m.startInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// toggleFocus moves focus between the start and end inputs
+func (m *LineRangeDialogModel) toggleFocus() tea.Cmd {
+	m.focusEnd = !m.focusEnd
+	if m.focusEnd {
+		m.startInput.Blur()
+		return m.endInput.Focus()
+	}
+	m.endInput.Blur()
+	return m.startInput.Focus()
+}
+
+// parseRange validates the inputs. An empty start clears the range and an
+// empty end reads to the end of the file.
+func (m *LineRangeDialogModel) parseRange() (int, int, error) {
+	startText := strings.TrimSpace(m.startInput.Value())
+	endText := strings.TrimSpace(m.endInput.Value())
+	if startText == "" {
+		if endText != "" {
+			return 0, 0, fmt.Errorf("enter a start line")
+		}
+		return 0, 0, nil
+	}
+
+	startLine, err := strconv.Atoi(startText)
+	if err != nil || startLine < 1 {
+		return 0, 0, fmt.Errorf("start line must be 1 or more")
+	}
+	if endText == "" {
+		return startLine, 0, nil
+	}
+
+	endLine, err := strconv.Atoi(endText)
+	if err != nil || endLine < startLine {
+		return 0, 0, fmt.Errorf("end line must not be before the start line")
+	}
+	return startLine, endLine, nil
+}
+
+// View renders the line range dialog
+func (m *LineRangeDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := 56
+	dialogHeight := 12
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Line Range: " + filepath.Base(m.path)))
+	b.WriteString("\n\n")
+	b.WriteString(m.startInput.View())
+	b.WriteString("\n")
+	b.WriteString(m.endInput.View())
+	b.WriteString("\n\n")
+
+	if m.err != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		b.WriteString(errorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("Tab: switch • Enter: apply (empty start: whole file) • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeLineRange shows the dialog and enters start and end, returning the resulting command
+func typeLineRange(m *LineRangeDialogModel, start, end string) tea.Cmd {
+	m.Show("/project/main.go", 0, 0)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(start)})
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(end)})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return cmd
+}
+
+func TestLineRangeDialog(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		wantStart  int
+		wantEnd    int
+		wantErr    bool
+	}{
+		{name: "start and end", start: "10", end: "50", wantStart: 10, wantEnd: 50},
+		{name: "start only reads to end of file", start: "10", wantStart: 10},
+		{name: "empty clears the range"},
+		{name: "end before start", start: "50", end: "10", wantErr: true},
+		{name: "zero start", start: "0", end: "10", wantErr: true},
+		{name: "end without start", end: "10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewLineRangeDialogModel(DefaultTheme())
+			cmd := typeLineRange(m, tt.start, tt.end)
+
+			if tt.wantErr {
+				if cmd != nil || !m.IsVisible() || m.err == "" {
+					t.Errorf("Expected dialog to stay open with an error, got visible=%v err=%q", m.IsVisible(), m.err)
+				}
+				return
+			}
+
+			if cmd == nil {
+				t.Fatal("Expected a line range command")
+			}
+			msg, ok := cmd().(LineRangeMsg)
+			if !ok {
+				t.Fatalf("Expected LineRangeMsg, got %T", cmd())
+			}
+			if msg.Path != "/project/main.go" || msg.StartLine != tt.wantStart || msg.EndLine != tt.wantEnd {
+				t.Errorf("Expected %s %d-%d, got %+v", "/project/main.go", tt.wantStart, tt.wantEnd, msg)
+			}
+			if m.IsVisible() {
+				t.Error("Expected dialog to close after confirming")
+			}
+		})
+	}
+}
+
+func TestLineRangeDialogRejectsNonDigits(t *testing.T) {
+	m := NewLineRangeDialogModel(DefaultTheme())
+	m.Show("/project/main.go", 5, 8)
+	if m.startInput.Value() != "5" || m.endInput.Value() != "8" {
+		t.Errorf("Expected inputs pre-filled with 5 and 8, got %q and %q", m.startInput.Value(), m.endInput.Value())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.startInput.Value() != "5" {
+		t.Errorf("Expected non-digit input to be rejected, got %q", m.startInput.Value())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.IsVisible() {
+		t.Error("Expected esc to close the dialog")
+	}
+}
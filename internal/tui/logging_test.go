@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logRecords decodes the JSON log records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected a JSON log record, got %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// expectFields fails the test unless record has each of the expected values
+func expectFields(t *testing.T, record map[string]any, expected map[string]any) {
+	t.Helper()
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v in the log record, got %v (record %v)", key, value, record[key], record)
+		}
+	}
+}
+
+func TestPersonaDialogLogsKeys(t *testing.T) {
+	var buf bytes.Buffer
+	dialog := NewPersonaDialogModel(nil, DefaultTheme())
+	dialog.SetDebugLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	dialog.SetAvailablePersonas([]string{"default"})
+	dialog.Show()
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+	}
+	expectFields(t, records[0], map[string]any{"component": "persona_dialog", "event": "key_pressed", "key": "esc"})
+	expectFields(t, records[1], map[string]any{"component": "persona_dialog", "event": "dialog_closed"})
+}
+
+func TestFileTreeLogsScanFailures(t *testing.T) {
+	var buf bytes.Buffer
+	missing := filepath.Join(t.TempDir(), "missing")
+	model := NewFileTreeModel(missing, []string{}, DefaultTheme())
+	model.SetDebugLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	model.Update(DirScannedMsg{Path: missing, Err: os.ErrNotExist})
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 log record, got %d: %v", len(records), records)
+	}
+	expectFields(t, records[0], map[string]any{"level": "ERROR", "component": "file_tree", "event": "scan_failed", "path": missing})
+	if records[0]["error"] == nil {
+		t.Errorf("Expected the error in the log record, got %v", records[0])
+	}
+}
+
+func TestInitializeDebugLoggerWritesJSON(t *testing.T) {
+	dir := t.TempDir()
+	// Enable file logging with a project override
+	if err := os.WriteFile(filepath.Join(dir, ".coding-prompts.toml"), []byte("[debug]\nfile_logging = true\nlog_file = \"logs/debug.log\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	settingsManager, err := config.NewSettingsManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create settings manager: %v", err)
+	}
+
+	logger := initializeDebugLogger(dir, settingsManager)
+	if logger == nil {
+		t.Fatal("Expected a logger when file logging is enabled")
+	}
+	logger.Debug("key pressed", "component", "app", "event", "key_pressed", "key", "a")
+
+	data, err := os.ReadFile(filepath.Join(dir, "logs", "debug.log"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	records := logRecords(t, bytes.NewBuffer(data))
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+	}
+	expectFields(t, records[0], map[string]any{"component": "app", "event": "session_started", "target_dir": dir})
+	expectFields(t, records[1], map[string]any{"level": "DEBUG", "component": "app", "event": "key_pressed", "key": "a"})
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"strings"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ManifestExportMsg is sent when a format is picked in the manifest dialog
+type ManifestExportMsg struct {
+	Format config.ManifestFormat
+}
+
+// manifestFormatLabels describes each manifest format in the dialog
+var manifestFormatLabels = map[config.ManifestFormat]string{
+	config.ManifestPlain: "Plain text, one path per line",
+	config.ManifestJSON:  "JSON array",
+	config.ManifestShell: "Shell array",
+}
+
+// ManifestDialogModel picks the format the list of selected files is exported in
+type ManifestDialogModel struct {
+	cursor  int
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewManifestDialogModel creates a new manifest dialog model
+func NewManifestDialogModel(theme Theme) *ManifestDialogModel {
+	return &ManifestDialogModel{theme: theme}
+}
+
+// SetSize updates the dialog dimensions
+func (m *ManifestDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog with the first format highlighted
+func (m *ManifestDialogModel) Show() {
+	m.cursor = 0
+	m.visible = true
+}
+
+// Hide closes the dialog
+func (m *ManifestDialogModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *ManifestDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the manifest dialog
+func (m *ManifestDialogModel) Update(msg tea.Msg) (*ManifestDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(config.ManifestFormats)-1 {
+				m.cursor++
+			}
+		case "enter":
+			m.Hide()
+			format := config.ManifestFormats[m.cursor]
+			return m, func() tea.Msg {
+				return ManifestExportMsg{Format: format}
+			}
+		case "esc", "ctrl+c":
+			m.Hide()
+		}
+	}
+	return m, nil
+}
+
+// View renders the manifest dialog
+func (m *ManifestDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.4)
+	if dialogWidth < 40 {
+		dialogWidth = 40
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Copy Selected Files"))
+	b.WriteString("\n\n")
+
+	for i, format := range config.ManifestFormats {
+		line := "  " + manifestFormatLabels[format]
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			line = "▶ " + manifestFormatLabels[format]
+			style = style.Foreground(lipgloss.Color(m.theme.CursorFG)).Bold(true)
+		}
+		b.WriteString(style.Render(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("↑/↓: choose • Enter: copy • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, len(config.ManifestFormats)+4, m.width, m.height)
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestManifestDialogPicksFormat(t *testing.T) {
+	dialog := NewManifestDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.Show()
+	if !strings.Contains(dialog.View(), "Shell array") {
+		t.Errorf("Expected every format listed, got:\n%s", dialog.View())
+	}
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if dialog.IsVisible() {
+		t.Error("Expected the dialog to close once a format is picked")
+	}
+	if msg, ok := cmd().(ManifestExportMsg); !ok || msg.Format != config.ManifestJSON {
+		t.Errorf("Expected the JSON format to be picked, got %+v", msg)
+	}
+
+	dialog.Show()
+	if _, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || dialog.IsVisible() {
+		t.Error("Expected esc to close the dialog without exporting")
+	}
+}
+
+func TestAppCopiesManifest(t *testing.T) {
+	app := createTestApp(t)
+	clip := &mockClipboard{}
+	app.clipboard = clip
+	app.selectedFiles.AddFile("main.go", app.targetDir+"/main.go")
+	app.selectedFiles.AddFile("b.go", app.targetDir+"/pkg/b.go")
+
+	_, cmd := app.Update(ManifestExportMsg{Format: config.ManifestPlain})
+	if clip.content != "main.go\npkg/b.go\n" {
+		t.Errorf("Expected the relative paths on the clipboard, got %q", clip.content)
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected an info alert, got %#v", msg)
+	}
+}
+
+func TestAppOpensManifestDialogWithDefaultKey(t *testing.T) {
+	app := NewApp(t.TempDir(), WithConfigManager(config.NewMemoryManager()), WithSettingsManager(config.NewDefaultSettingsManager()))
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}, Alt: true})
+	if !app.manifestDialog.IsVisible() {
+		t.Error("Expected alt+e to open the manifest dialog")
+	}
+}
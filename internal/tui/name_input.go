@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PresetNameMsg is sent when the user confirms the name to save the selection as a preset under
+type PresetNameMsg struct {
+	Name string
+}
+
+// NameInputModel is a small input for the name of a new selection preset
+type NameInputModel struct {
+	input   textinput.Model
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewNameInputModel creates a new name input model
+func NewNameInputModel(theme Theme) *NameInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "auth files"
+	ti.Prompt = "Name: "
+
+	return &NameInputModel{
+		input: ti,
+		theme: theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *NameInputModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = int(float64(width)*0.5) - 14
+}
+
+// Show displays the input with an empty name
+func (m *NameInputModel) Show() tea.Cmd {
+	m.visible = true
+	m.input.Reset()
+	return m.input.Focus()
+}
+
+// Hide closes the input
+func (m *NameInputModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the input is currently shown
+func (m *NameInputModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the name input
+func (m *NameInputModel) Update(msg tea.Msg) (*NameInputModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(m.input.Value())
+			if name == "" {
+				return m, nil
+			}
+			m.Hide()
+			return m, func() tea.Msg {
+				return PresetNameMsg{Name: name}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the name input
+func (m *NameInputModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.5)
+	dialogHeight := 9
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Save Selection as Preset"))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("Enter: save (replaces a preset of the same name) • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Notification types
+const (
+	InfoAlert  = "info"
+	WarnAlert  = "warn"
+	ErrorAlert = "error"
+)
+
+// DefaultNotificationTTL is how long a notification is shown when no TTL is configured
+const DefaultNotificationTTL = 3 * time.Second
+
+// NotificationMsg asks the notification model to show a message
+type NotificationMsg struct {
+	AlertType string
+	Message   string
+}
+
+// notificationExpiryMsg triggers a check for expired notifications
+type notificationExpiryMsg time.Time
+
+// notification is a queued message with its expiry time
+type notification struct {
+	message   string
+	alertType string
+	expiresAt time.Time
+}
+
+// NotificationModel shows short-lived notifications in the top-right corner
+type NotificationModel struct {
+	queue []notification
+	ttl   time.Duration
+	width int
+	now   func() time.Time
+}
+
+// NewNotificationModel creates a notification model with the given box width and TTL
+func NewNotificationModel(width int, ttl time.Duration) *NotificationModel {
+	if ttl <= 0 {
+		ttl = DefaultNotificationTTL
+	}
+	return &NotificationModel{
+		ttl:   ttl,
+		width: width,
+		now:   time.Now,
+	}
+}
+
+// SetWidth sets the width of the notification box
+func (m *NotificationModel) SetWidth(width int) {
+	m.width = width
+}
+
+// SetTTL sets how long new notifications are shown
+func (m *NotificationModel) SetTTL(ttl time.Duration) {
+	if ttl > 0 {
+		m.ttl = ttl
+	}
+}
+
+// Notify returns a command that shows a notification
+func Notify(alertType, message string) tea.Cmd {
+	return func() tea.Msg {
+		return NotificationMsg{AlertType: alertType, Message: message}
+	}
+}
+
+// Update handles new notifications and expiry checks
+func (m *NotificationModel) Update(msg tea.Msg) (*NotificationModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case NotificationMsg:
+		if msg.Message == "" {
+			return m, nil
+		}
+		m.queue = append(m.queue, notification{
+			message:   msg.Message,
+			alertType: msg.AlertType,
+			expiresAt: m.now().Add(m.ttl),
+		})
+		return m, tea.Tick(m.ttl, func(t time.Time) tea.Msg {
+			return notificationExpiryMsg(t)
+		})
+
+	case notificationExpiryMsg:
+		m.expire(time.Time(msg))
+	}
+
+	return m, nil
+}
+
+// expire drops notifications that expired at or before now
+func (m *NotificationModel) expire(now time.Time) {
+	active := m.queue[:0]
+	for _, n := range m.queue {
+		if n.expiresAt.After(now) {
+			active = append(active, n)
+		}
+	}
+	m.queue = active
+}
+
+// current returns the most recent notification that hasn't expired, if any
+func (m *NotificationModel) current() (notification, bool) {
+	now := m.now()
+	for i := len(m.queue) - 1; i >= 0; i-- {
+		if m.queue[i].expiresAt.After(now) {
+			return m.queue[i], true
+		}
+	}
+	return notification{}, false
+}
+
+// View renders the current notification box, or "" when there is none
+func (m *NotificationModel) View() string {
+	n, ok := m.current()
+	if !ok {
+		return ""
+	}
+
+	var color, prefix string
+	switch n.alertType {
+	case ErrorAlert:
+		color, prefix = "196", "[!!]"
+	case WarnAlert:
+		color, prefix = "220", "(!)"
+	default:
+		color, prefix = "10", "(i)"
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(color)).
+		Foreground(lipgloss.Color(color)).
+		Width(m.width)
+
+	return style.Render(fmt.Sprintf("%s %s", prefix, n.message))
+}
+
+// Render draws the current notification over the top-right corner of content
+func (m *NotificationModel) Render(content string) string {
+	box := m.View()
+	if box == "" {
+		return content
+	}
+
+	contentLines := strings.Split(content, "\n")
+	width := lipgloss.Width(content)
+	boxWidth := lipgloss.Width(box)
+	if boxWidth > width {
+		return content
+	}
+
+	// Right-align the box across the full width, then keep the content to its left
+	placed := strings.Split(lipgloss.Place(width, lipgloss.Height(box), lipgloss.Right, lipgloss.Top, box), "\n")
+	offset := width - boxWidth
+	for i, line := range placed {
+		if i >= len(contentLines) {
+			break
+		}
+		left := lipgloss.PlaceHorizontal(offset, lipgloss.Left, ansi.Truncate(contentLines[i], offset, ""))
+		contentLines[i] = left + ansi.Cut(line, offset, width)
+	}
+
+	return strings.Join(contentLines, "\n")
+}
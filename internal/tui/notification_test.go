@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newTestNotifications returns a model whose clock is controlled by the returned pointer
+func newTestNotifications(ttl time.Duration) (*NotificationModel, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	model := NewNotificationModel(30, ttl)
+	model.now = func() time.Time { return now }
+	return model, &now
+}
+
+func TestNotificationExpiresAfterTTL(t *testing.T) {
+	model, now := newTestNotifications(3 * time.Second)
+
+	_, cmd := model.Update(NotificationMsg{AlertType: InfoAlert, Message: "prompt copied"})
+	if cmd == nil {
+		t.Fatal("Expected an expiry tick command")
+	}
+	if !strings.Contains(model.View(), "prompt copied") {
+		t.Fatalf("Expected notification to be shown, got %q", model.View())
+	}
+
+	// Still visible just before the TTL elapses
+	*now = now.Add(2 * time.Second)
+	model.Update(notificationExpiryMsg(*now))
+	if !strings.Contains(model.View(), "prompt copied") {
+		t.Error("Expected notification to be visible before its TTL elapses")
+	}
+
+	// Gone once the TTL has elapsed
+	*now = now.Add(time.Second)
+	model.Update(notificationExpiryMsg(*now))
+	if model.View() != "" {
+		t.Errorf("Expected notification to expire after its TTL, got %q", model.View())
+	}
+	if len(model.queue) != 0 {
+		t.Errorf("Expected expired notifications to be dropped, got %d", len(model.queue))
+	}
+}
+
+func TestNotificationShowsMostRecent(t *testing.T) {
+	model, now := newTestNotifications(3 * time.Second)
+
+	model.Update(NotificationMsg{AlertType: InfoAlert, Message: "first"})
+	*now = now.Add(time.Second)
+	model.Update(NotificationMsg{AlertType: ErrorAlert, Message: "second"})
+
+	if view := model.View(); !strings.Contains(view, "second") || strings.Contains(view, "first") {
+		t.Errorf("Expected only the most recent notification, got %q", view)
+	}
+
+	// The newer notification expires later, so it outlives the first
+	*now = now.Add(2500 * time.Millisecond)
+	model.Update(notificationExpiryMsg(*now))
+	if len(model.queue) != 1 || !strings.Contains(model.View(), "second") {
+		t.Errorf("Expected second notification to remain, got %q", model.View())
+	}
+}
+
+func TestNotificationSetTTL(t *testing.T) {
+	model, now := newTestNotifications(3 * time.Second)
+	model.SetTTL(10 * time.Second)
+	model.SetTTL(0) // ignored
+
+	model.Update(NotificationMsg{AlertType: WarnAlert, Message: "large prompt"})
+	*now = now.Add(5 * time.Second)
+	model.Update(notificationExpiryMsg(*now))
+	if model.View() == "" {
+		t.Error("Expected notification to use the updated TTL")
+	}
+}
+
+func TestNotificationRenderTopRight(t *testing.T) {
+	model, _ := newTestNotifications(3 * time.Second)
+	model.SetWidth(10)
+
+	content := strings.TrimSuffix(strings.Repeat(strings.Repeat("x", 40)+"\n", 6), "\n")
+	if got := model.Render(content); got != content {
+		t.Error("Expected content unchanged without notifications")
+	}
+
+	model.Update(NotificationMsg{AlertType: InfoAlert, Message: "hi"})
+	rendered := model.Render(content)
+	lines := strings.Split(rendered, "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %d", len(lines))
+	}
+
+	boxWidth := lipgloss.Width(model.View())
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != 40 {
+			t.Errorf("Line %d: expected width 40, got %d", i, w)
+		}
+	}
+	if !strings.HasPrefix(lines[0], strings.Repeat("x", 40-boxWidth)) || !strings.Contains(lines[1], "hi") {
+		t.Errorf("Expected notification in the top-right corner, got:\n%s", rendered)
+	}
+	if lines[5] != strings.Repeat("x", 40) {
+		t.Errorf("Expected lines below the notification unchanged, got %q", lines[5])
+	}
+}
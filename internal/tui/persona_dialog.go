@@ -2,9 +2,12 @@ package tui
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
+	"coding-prompts-tui/internal/persona"
+
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,7 +18,14 @@ type PersonaDialogModel struct {
 	availablePersonas []string
 	selectedPersonas  map[string]bool
 	cursor            int
-	debugLogger       *log.Logger
+	confirmDelete     string // Persona waiting for the user to confirm its deletion
+	debugLogger       *slog.Logger
+	// manager reads the persona under the cursor into the scrollable preview
+	// beside the list; there is no preview without one
+	manager        *persona.Manager
+	preview        viewport.Model
+	previewPersona string // Persona shown in the preview
+	theme          Theme
 }
 
 // PersonaSelectionMsg is sent when personas are selected/deselected
@@ -23,19 +33,29 @@ type PersonaSelectionMsg struct {
 	ActivePersonas []string
 }
 
-// NewPersonaDialogModel creates a new persona dialog model
-func NewPersonaDialogModel() *PersonaDialogModel {
+// PersonaDeleteMsg is sent when the user confirms deleting a persona
+type PersonaDeleteMsg struct {
+	Name string
+}
+
+// NewPersonaDialogModel creates a new persona dialog model that previews
+// personas read through manager, which may be nil
+func NewPersonaDialogModel(manager *persona.Manager, theme Theme) *PersonaDialogModel {
 	return &PersonaDialogModel{
-		promptDialog:     NewPromptDialogModel(),
+		promptDialog:     NewPromptDialogModel(theme),
 		selectedPersonas: make(map[string]bool),
 		cursor:           0,
 		debugLogger:      nil,
+		manager:          manager,
+		preview:          viewport.New(0, 0),
+		theme:            theme,
 	}
 }
 
 // SetAvailablePersonas sets the list of available personas
 func (m *PersonaDialogModel) SetAvailablePersonas(personas []string) {
 	m.availablePersonas = personas
+	m.previewPersona = ""
 	if m.cursor >= len(m.availablePersonas) {
 		m.cursor = 0
 	}
@@ -51,6 +71,8 @@ func (m *PersonaDialogModel) SetActivePersonas(personas []string) {
 
 // Show displays the dialog
 func (m *PersonaDialogModel) Show() {
+	m.confirmDelete = ""
+	m.previewPersona = ""
 	content := m.generateDialogContent()
 	m.promptDialog.Show(content)
 }
@@ -68,11 +90,19 @@ func (m *PersonaDialogModel) IsVisible() bool {
 // SetSize sets the dialog size for centering
 func (m *PersonaDialogModel) SetSize(width, height int) {
 	m.promptDialog.SetSize(width, height)
+
+	// The preview takes the right two thirds of the dialog, below the title
+	// and above the two help lines
+	inner := m.promptDialog.viewport
+	m.preview.Width = max(0, inner.Width-inner.Width/3-3)
+	m.preview.Height = max(0, inner.Height-5)
+	m.previewPersona = ""
+	m.updateDialogContent()
 }
 
-// SetDebugLogger sets the debug logger for the dialog
-func (m *PersonaDialogModel) SetDebugLogger(logger *log.Logger) {
-	m.debugLogger = logger
+// SetDebugLogger sets the debug logger for the dialog; nil disables logging
+func (m *PersonaDialogModel) SetDebugLogger(logger *slog.Logger) {
+	m.debugLogger = componentLogger(logger, "persona_dialog")
 }
 
 // Init initializes the dialog
@@ -89,7 +119,11 @@ func (m *PersonaDialogModel) Update(msg tea.Msg) (*PersonaDialogModel, tea.Cmd)
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.debugLogger != nil {
-			m.debugLogger.Printf("PERSONA_DIALOG: Key pressed: %q", msg.String())
+			m.debugLogger.Debug("key pressed", "event", "key_pressed", "key", msg.String())
+		}
+
+		if m.confirmDelete != "" {
+			return m, m.updateConfirmDelete(msg)
 		}
 
 		switch msg.String() {
@@ -119,9 +153,26 @@ func (m *PersonaDialogModel) Update(msg tea.Msg) (*PersonaDialogModel, tea.Cmd)
 			return m, func() tea.Msg {
 				return PersonaSelectionMsg{ActivePersonas: activePersonas}
 			}
+		case "d":
+			// Ask before deleting the persona under the cursor
+			if m.cursor >= 0 && m.cursor < len(m.availablePersonas) {
+				m.confirmDelete = m.availablePersonas[m.cursor]
+				m.updateDialogContent()
+			}
+		case "pgdown":
+			m.preview.ViewDown()
+			m.updateDialogContent()
+		case "pgup":
+			m.preview.ViewUp()
+			m.updateDialogContent()
+		case "ctrl+n":
+			// Create a new persona; the dialog stays open underneath
+			return m, func() tea.Msg {
+				return ShowPersonaWizardMsg{}
+			}
 		case "esc":
 			if m.debugLogger != nil {
-				m.debugLogger.Printf("PERSONA_DIALOG: Escape key pressed - hiding dialog")
+				m.debugLogger.Debug("dialog closed", "event", "dialog_closed")
 			}
 			m.Hide()
 			return m, nil
@@ -136,6 +187,23 @@ func (m *PersonaDialogModel) Update(msg tea.Msg) (*PersonaDialogModel, tea.Cmd)
 	return m, nil
 }
 
+// updateConfirmDelete handles keys while a deletion waits for confirmation
+func (m *PersonaDialogModel) updateConfirmDelete(msg tea.KeyMsg) tea.Cmd {
+	name := m.confirmDelete
+	switch msg.String() {
+	case "y":
+		m.confirmDelete = ""
+		m.updateDialogContent()
+		return func() tea.Msg {
+			return PersonaDeleteMsg{Name: name}
+		}
+	case "n", "esc":
+		m.confirmDelete = ""
+		m.updateDialogContent()
+	}
+	return nil
+}
+
 // getActivePersonasList returns the currently selected personas as a slice
 func (m *PersonaDialogModel) getActivePersonasList() []string {
 	var active []string
@@ -158,9 +226,17 @@ func (m *PersonaDialogModel) View() string {
 // generateDialogContent creates the persona selection content
 func (m *PersonaDialogModel) generateDialogContent() string {
 	var content strings.Builder
+	if m.confirmDelete != "" {
+		content.WriteString(fmt.Sprintf("Delete persona %s?\n\n", m.confirmDelete))
+		content.WriteString(fmt.Sprintf("personas/%s.md will be removed.\n\n", m.confirmDelete))
+		content.WriteString("y: Delete • n/Escape: Cancel")
+		return content.String()
+	}
+
 	content.WriteString("Select Active Personas:\n\n")
 
 	// Render persona list with checkboxes
+	var list strings.Builder
 	for i, persona := range m.availablePersonas {
 		cursor := " "
 		if i == m.cursor {
@@ -177,26 +253,71 @@ func (m *PersonaDialogModel) generateDialogContent() string {
 		// Highlight current selection
 		if i == m.cursor {
 			line = lipgloss.NewStyle().
-				Background(lipgloss.Color("69")).
+				Background(lipgloss.Color(m.theme.CursorFG)).
 				Foreground(lipgloss.Color("0")).
 				Render(" " + line + " ")
 		} else {
 			line = " " + line + " "
 		}
 
-		content.WriteString(line + "\n")
+		list.WriteString(line + "\n")
+	}
+
+	help := "Space: Toggle • Enter: Apply • Ctrl+N: New • D: Delete • Escape: Cancel"
+	if m.hasPreview() {
+		m.loadPreview()
+		listWidth := m.promptDialog.viewport.Width - m.preview.Width - 3
+		separator := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+			Render(strings.TrimSuffix(strings.Repeat(" │\n", m.preview.Height), "\n"))
+		content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).Render(strings.TrimSuffix(list.String(), "\n")),
+			separator+" ",
+			m.preview.View(),
+		))
+		content.WriteString("\n")
+		help += "\nPgUp/PgDn: Scroll preview"
+	} else {
+		content.WriteString(list.String())
 	}
 
 	content.WriteString("\n")
-	content.WriteString("Space: Toggle • Enter: Apply • Escape: Cancel")
+	content.WriteString(help)
 
 	return content.String()
 }
 
-// updateDialogContent refreshes the dialog content after changes
+// hasPreview reports whether the persona under the cursor is previewed beside the list
+func (m *PersonaDialogModel) hasPreview() bool {
+	return m.manager != nil && m.preview.Width > 0 && m.preview.Height > 0
+}
+
+// loadPreview reads the persona under the cursor into the preview, scrolled to
+// the top, unless it is already shown
+func (m *PersonaDialogModel) loadPreview() {
+	name := ""
+	if m.cursor >= 0 && m.cursor < len(m.availablePersonas) {
+		name = m.availablePersonas[m.cursor]
+	}
+	if name == m.previewPersona {
+		return
+	}
+	m.previewPersona = name
+
+	content := ""
+	if name != "" {
+		var err error
+		if content, err = m.manager.ReadPersonaContent(name); err != nil {
+			content = "[error reading persona]"
+		}
+	}
+	m.preview.SetContent(lipgloss.NewStyle().Width(m.preview.Width).Render(strings.TrimSpace(content)))
+	m.preview.GotoTop()
+}
+
+// updateDialogContent refreshes the dialog content after changes, keeping the
+// list scrolled where it was
 func (m *PersonaDialogModel) updateDialogContent() {
 	if m.IsVisible() {
-		content := m.generateDialogContent()
-		m.promptDialog.Show(content)
+		m.promptDialog.UpdateContent(m.generateDialogContent())
 	}
 }
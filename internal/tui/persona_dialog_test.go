@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"coding-prompts-tui/internal/persona"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPersonaDialogConfirmsDelete(t *testing.T) {
+	dialog := NewPersonaDialogModel(nil, DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.SetAvailablePersonas([]string{"default", "reviewer"})
+	dialog.Show()
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+	// d asks for confirmation first
+	_, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if cmd != nil || dialog.confirmDelete != "reviewer" {
+		t.Fatalf("Expected confirmation for reviewer, got %q", dialog.confirmDelete)
+	}
+	if !strings.Contains(dialog.generateDialogContent(), "Delete persona reviewer?") {
+		t.Errorf("Expected the confirmation prompt, got %q", dialog.generateDialogContent())
+	}
+
+	// n cancels without closing the dialog
+	_, cmd = dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd != nil || dialog.confirmDelete != "" || !dialog.IsVisible() {
+		t.Fatalf("Expected the deletion to be cancelled, got %q", dialog.confirmDelete)
+	}
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	_, cmd = dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("Expected y to confirm the deletion")
+	}
+	if msg, ok := cmd().(PersonaDeleteMsg); !ok || msg.Name != "reviewer" {
+		t.Errorf("Expected PersonaDeleteMsg for reviewer, got %#v", cmd())
+	}
+}
+
+func TestAppDeletesPersona(t *testing.T) {
+	app := createTestApp(t)
+	if err := app.personaManager.CreatePersona("reviewer", "Review the code.\n"); err != nil {
+		t.Fatalf("CreatePersona failed: %v", err)
+	}
+	app.personaDialog.SetAvailablePersonas(app.personaManager.GetAvailablePersonas())
+	app.workspace.ActivePersonas = []string{"reviewer"}
+	app.personaDialog.SetActivePersonas(app.workspace.ActivePersonas)
+
+	_, cmd := app.Update(PersonaDeleteMsg{Name: "reviewer"})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected an info alert, got %#v", msg)
+	}
+	if app.personaManager.PersonaExists("reviewer") || contains(app.personaDialog.availablePersonas, "reviewer") {
+		t.Error("Expected reviewer to be deleted")
+	}
+	if contains(app.workspace.ActivePersonas, "reviewer") || app.personaDialog.selectedPersonas["reviewer"] {
+		t.Errorf("Expected reviewer to no longer be active, got %v", app.workspace.ActivePersonas)
+	}
+
+	// Deleting it again reports the error
+	_, cmd = app.Update(PersonaDeleteMsg{Name: "reviewer"})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != ErrorAlert {
+		t.Errorf("Expected an error alert, got %#v", msg)
+	}
+}
+
+func TestPersonaDialogPreview(t *testing.T) {
+	manager := persona.NewManager(t.TempDir())
+	if err := manager.CreatePersona("reviewer", "Review the code carefully.\n"); err != nil {
+		t.Fatalf("CreatePersona failed: %v", err)
+	}
+	dialog := NewPersonaDialogModel(manager, DefaultTheme())
+	dialog.SetSize(120, 40)
+	dialog.SetAvailablePersonas([]string{"missing", "reviewer"})
+	dialog.Show()
+
+	if content := dialog.generateDialogContent(); !strings.Contains(content, "[error reading persona]") {
+		t.Errorf("Expected an error for an unreadable persona, got:\n%s", content)
+	}
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	content := dialog.generateDialogContent()
+	if !strings.Contains(content, "Review the code carefully.") {
+		t.Errorf("Expected the reviewer persona to be previewed, got:\n%s", content)
+	}
+	// The list and the preview share lines
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, "reviewer") && !strings.Contains(line, "│") {
+			t.Errorf("Expected the list beside the preview, got line %q", line)
+		}
+	}
+}
+
+func TestNewAppCreatesDefaultPersona(t *testing.T) {
+	app := createTestApp(t)
+	if !app.personasCreated || !app.personaManager.PersonaExists("default") {
+		t.Fatal("Expected a default persona to be created for a project without personas")
+	}
+	if !slices.Contains(app.personaDialog.availablePersonas, "default") {
+		t.Errorf("Expected the default persona in the dialog, got %v", app.personaDialog.availablePersonas)
+	}
+
+	// Init announces it once
+	var announced bool
+	cmds := app.Init()().(tea.BatchMsg)
+	app.Close() // Stops waiting for persona changes
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		if msg, ok := cmd().(NotificationMsg); ok && strings.Contains(msg.Message, "personas/default.md") {
+			announced = true
+		}
+	}
+	if !announced {
+		t.Error("Expected Init to announce the created persona")
+	}
+}
+
+func TestAppListsPersonasChangedOnDisk(t *testing.T) {
+	app := createTestApp(t)
+	if app.watchPersonas() == nil {
+		t.Fatal("Expected the personas directory to be watched")
+	}
+	defer app.Close()
+
+	// nextChange waits for the watcher to report the expected personas and applies them
+	nextChange := func(expected []string) {
+		t.Helper()
+		for {
+			result := make(chan tea.Msg, 1)
+			go func() { result <- waitForPersonasChange(app.personaChanges)() }()
+			select {
+			case msg := <-result:
+				app.Update(msg)
+				if slices.Equal(msg.(PersonasChangedMsg).Personas, expected) {
+					return
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("Timed out waiting for personas %v", expected)
+			}
+		}
+	}
+
+	if err := os.WriteFile(app.personaManager.GetPersonaPath("reviewer"), []byte("Review rules"), 0644); err != nil {
+		t.Fatalf("Failed to write persona: %v", err)
+	}
+	nextChange([]string{"default", "reviewer"})
+	if !slices.Equal(app.personaDialog.availablePersonas, []string{"default", "reviewer"}) {
+		t.Errorf("Expected the new persona in the dialog, got %v", app.personaDialog.availablePersonas)
+	}
+
+	// Removing an active persona's file deactivates it
+	app.workspace.ActivePersonas = []string{"default", "reviewer"}
+	if err := os.Remove(app.personaManager.GetPersonaPath("reviewer")); err != nil {
+		t.Fatalf("Failed to remove persona: %v", err)
+	}
+	nextChange([]string{"default"})
+	if !slices.Equal(app.workspace.ActivePersonas, []string{"default"}) {
+		t.Errorf("Expected the removed persona to be deactivated, got %v", app.workspace.ActivePersonas)
+	}
+}
+
+func TestPersonaDialogKeepsScrollWhileNavigating(t *testing.T) {
+	dialog := NewPersonaDialogModel(nil, DefaultTheme())
+	dialog.SetSize(100, 20)
+	var personas []string
+	for i := 0; i < 40; i++ {
+		personas = append(personas, fmt.Sprintf("persona-%02d", i))
+	}
+	dialog.SetAvailablePersonas(personas)
+	dialog.Show()
+
+	// Scroll the list down, then move the cursor and toggle a persona
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	offset := dialog.promptDialog.viewport.YOffset
+	if offset == 0 {
+		t.Fatal("Expected f to scroll the persona list")
+	}
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	dialog.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if dialog.promptDialog.viewport.YOffset != offset {
+		t.Errorf("Expected the list to stay scrolled at %d, got %d", offset, dialog.promptDialog.viewport.YOffset)
+	}
+	if !dialog.selectedPersonas["persona-01"] {
+		t.Error("Expected the persona under the cursor to be toggled")
+	}
+
+	// Reopening the dialog starts at the top again
+	dialog.Hide()
+	dialog.Show()
+	if dialog.promptDialog.viewport.YOffset != 0 {
+		t.Errorf("Expected a reopened dialog to start at the top, got %d", dialog.promptDialog.viewport.YOffset)
+	}
+}
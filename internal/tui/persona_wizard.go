@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"coding-prompts-tui/internal/persona"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ShowPersonaWizardMsg asks for the persona creation wizard to be shown
+type ShowPersonaWizardMsg struct{}
+
+// PersonaCreatedMsg is sent when the wizard has written a new persona file
+type PersonaCreatedMsg struct {
+	Name string
+}
+
+// Wizard steps
+const (
+	wizardStepName = iota
+	wizardStepContent
+)
+
+// PersonaCreateWizard creates a persona in two steps: its name, then its content
+type PersonaCreateWizard struct {
+	personaManager *persona.Manager
+	nameInput      textinput.Model
+	contentArea    textarea.Model
+	step           int
+	err            string
+	width          int
+	height         int
+	visible        bool
+	theme          Theme
+}
+
+// NewPersonaCreateWizard creates a wizard that writes personas with personaManager
+func NewPersonaCreateWizard(personaManager *persona.Manager, theme Theme) *PersonaCreateWizard {
+	ti := textinput.New()
+	ti.Prompt = "Name: "
+	ti.Placeholder = "code-reviewer"
+	ti.CharLimit = 64
+
+	ta := textarea.New()
+	ta.Placeholder = "You are a ..."
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetHeight(10)
+
+	return &PersonaCreateWizard{
+		personaManager: personaManager,
+		nameInput:      ti,
+		contentArea:    ta,
+		theme:          theme,
+	}
+}
+
+// SetSize updates the wizard dimensions
+func (m *PersonaCreateWizard) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.nameInput.Width = m.dialogWidth() - 14
+	m.contentArea.SetWidth(m.dialogWidth() - 8)
+}
+
+// dialogWidth returns the width of the wizard dialog
+func (m *PersonaCreateWizard) dialogWidth() int {
+	width := int(float64(m.width) * 0.6)
+	if width < 40 {
+		width = 40
+	}
+	return width
+}
+
+// Show displays the wizard at its first step with empty inputs
+func (m *PersonaCreateWizard) Show() tea.Cmd {
+	m.visible = true
+	m.step = wizardStepName
+	m.err = ""
+	m.nameInput.Reset()
+	m.contentArea.Reset()
+	m.contentArea.Blur()
+	return m.nameInput.Focus()
+}
+
+// Hide closes the wizard
+func (m *PersonaCreateWizard) Hide() {
+	m.visible = false
+	m.nameInput.Blur()
+	m.contentArea.Blur()
+}
+
+// IsVisible returns whether the wizard is currently shown
+func (m *PersonaCreateWizard) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the wizard
+func (m *PersonaCreateWizard) Update(msg tea.Msg) (*PersonaCreateWizard, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			if m.step == wizardStepName {
+				return m, m.confirmName()
+			}
+		case "shift+tab":
+			if m.step == wizardStepContent {
+				// Go back to fix the name
+				m.step = wizardStepName
+				m.err = ""
+				m.contentArea.Blur()
+				return m, m.nameInput.Focus()
+			}
+		case "ctrl+s":
+			if m.step == wizardStepContent {
+				return m, m.create()
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == wizardStepName {
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	} else {
+		m.contentArea, cmd = m.contentArea.Update(msg)
+	}
+	return m, cmd
+}
+
+// name returns the persona name entered in the first step
+func (m *PersonaCreateWizard) name() string {
+	return strings.TrimSpace(m.nameInput.Value())
+}
+
+// confirmName validates the name and moves on to the content step
+func (m *PersonaCreateWizard) confirmName() tea.Cmd {
+	name := m.name()
+	if err := persona.ValidateName(name); err != nil {
+		m.err = err.Error()
+		return nil
+	}
+	if m.personaManager.PersonaExists(name) {
+		m.err = fmt.Sprintf("persona %s already exists", name)
+		return nil
+	}
+
+	m.err = ""
+	m.step = wizardStepContent
+	m.nameInput.Blur()
+	return m.contentArea.Focus()
+}
+
+// create writes the persona file, keeping the wizard open with an error if it fails
+func (m *PersonaCreateWizard) create() tea.Cmd {
+	content := m.contentArea.Value()
+	if strings.TrimSpace(content) == "" {
+		m.err = "persona content cannot be empty"
+		return nil
+	}
+
+	name := m.name()
+	if err := m.personaManager.CreatePersona(name, strings.TrimRight(content, "\n")+"\n"); err != nil {
+		m.err = err.Error()
+		return nil
+	}
+
+	m.Hide()
+	return func() tea.Msg {
+		return PersonaCreatedMsg{Name: name}
+	}
+}
+
+// View renders the wizard
+func (m *PersonaCreateWizard) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+	dialogHeight := 9
+
+	if m.step == wizardStepName {
+		b.WriteString(titleStyle.Render("New Persona (1/2)"))
+		b.WriteString("\n\n")
+		b.WriteString(m.nameInput.View())
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(titleStyle.Render("New Persona (2/2): " + m.name()))
+		b.WriteString("\n\n")
+		b.WriteString(m.contentArea.View())
+		b.WriteString("\n\n")
+		dialogHeight += m.contentArea.Height()
+	}
+
+	if m.err != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		b.WriteString(errorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	if m.step == wizardStepName {
+		b.WriteString(helpStyle.Render("Letters, digits, - and _ • Enter: next • Esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("Ctrl+S: create • Shift+Tab: back • Esc: cancel"))
+	}
+
+	return RenderDialog(m.theme, b.String(), m.dialogWidth(), dialogHeight, m.width, m.height)
+}
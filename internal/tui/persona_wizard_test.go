@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPersonaWizardValidatesInline(t *testing.T) {
+	app := createTestApp(t)
+	if err := app.personaManager.CreatePersona("reviewer", "Review code.\n"); err != nil {
+		t.Fatalf("Failed to create persona: %v", err)
+	}
+	wizard := app.personaWizard
+	wizard.Show()
+
+	for _, name := range []string{"bad name", "reviewer"} {
+		wizard.nameInput.SetValue(name)
+		wizard.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if !wizard.IsVisible() || wizard.step != wizardStepName || wizard.err == "" {
+			t.Errorf("Expected an inline error for name %q, got step %d, err %q", name, wizard.step, wizard.err)
+		}
+	}
+
+	wizard.nameInput.SetValue("architect")
+	wizard.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if wizard.step != wizardStepContent || wizard.err != "" {
+		t.Fatalf("Expected the content step, got step %d, err %q", wizard.step, wizard.err)
+	}
+
+	// Empty content is refused without closing the wizard
+	if _, cmd := wizard.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); cmd != nil || !wizard.IsVisible() || wizard.err == "" {
+		t.Error("Expected an inline error for empty content")
+	}
+}
+
+func TestPersonaWizardCreatesActivePersona(t *testing.T) {
+	app := createTestApp(t)
+	app.dialogs.Push(app.personaDialog, func() tea.Cmd {
+		app.personaDialog.Show()
+		return nil
+	})
+
+	// ctrl+n in the persona dialog opens the wizard over it
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if cmd == nil {
+		t.Fatal("Expected ctrl+n to open the wizard")
+	}
+	app.Update(cmd())
+	if !app.personaWizard.IsVisible() || !app.personaDialog.IsVisible() {
+		t.Fatal("Expected the wizard to be shown over the persona dialog")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("architect")})
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Design systems.")})
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatalf("Expected the persona to be created, got error %q", app.personaWizard.err)
+	}
+	msg, ok := cmd().(PersonaCreatedMsg)
+	if !ok || msg.Name != "architect" {
+		t.Fatalf("Expected PersonaCreatedMsg for architect, got %+v", cmd())
+	}
+
+	content, err := os.ReadFile(filepath.Join(app.targetDir, "personas", "architect.md"))
+	if err != nil || string(content) != "Design systems.\n" {
+		t.Errorf("Expected the persona file to be written, got %q (err %v)", content, err)
+	}
+
+	app.Update(msg)
+	if !contains(app.personaDialog.availablePersonas, "architect") {
+		t.Errorf("Expected architect in the persona dialog, got %v", app.personaDialog.availablePersonas)
+	}
+	if !contains(app.workspace.ActivePersonas, "architect") || !app.personaDialog.selectedPersonas["architect"] {
+		t.Errorf("Expected architect to be active, got %v", app.workspace.ActivePersonas)
+	}
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"coding-prompts-tui/internal/config"
+)
+
+// PresetApplyMsg is sent when a selection preset is picked to replace the selected files
+type PresetApplyMsg struct {
+	Preset config.SelectionPreset
+}
+
+// PresetDialogModel lists the selection presets saved in the workspace
+type PresetDialogModel struct {
+	presets []config.SelectionPreset
+	cursor  int
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewPresetDialogModel creates a new preset dialog model
+func NewPresetDialogModel(theme Theme) *PresetDialogModel {
+	return &PresetDialogModel{theme: theme}
+}
+
+// SetSize updates the dialog dimensions
+func (m *PresetDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog with the given presets, in the order they were saved
+func (m *PresetDialogModel) Show(presets []config.SelectionPreset) {
+	m.presets = presets
+	m.cursor = 0
+	m.visible = true
+}
+
+// Hide closes the dialog
+func (m *PresetDialogModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *PresetDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the preset dialog
+func (m *PresetDialogModel) Update(msg tea.Msg) (*PresetDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "ctrl+c":
+		m.Hide()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.presets)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if m.cursor >= len(m.presets) {
+			return m, nil
+		}
+		preset := m.presets[m.cursor]
+		m.Hide()
+		return m, func() tea.Msg {
+			return PresetApplyMsg{Preset: preset}
+		}
+	}
+	return m, nil
+}
+
+// View renders the preset dialog
+func (m *PresetDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.5)
+	dialogHeight := int(float64(m.height) * 0.6)
+
+	// Lines available for presets: minus borders, padding, title, spacing and help
+	listHeight := dialogHeight - 8
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Selection Presets"))
+	b.WriteString("\n\n")
+
+	// Keep the cursor within the visible window of presets
+	start := 0
+	if m.cursor >= listHeight {
+		start = m.cursor - listHeight + 1
+	}
+	end := min(start+listHeight, len(m.presets))
+
+	if len(m.presets) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("No presets saved yet"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		preset := m.presets[i]
+		text := fmt.Sprintf("%s  (%d files)", preset.Name, len(preset.Files))
+		line := "  " + text
+		if i == m.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.theme.CursorFG)).
+				Bold(true).
+				Render("▶ " + text)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: select these files • Esc: close"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPresetDialogPicksPreset(t *testing.T) {
+	dialog := NewPresetDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.Show(nil)
+	if !strings.Contains(dialog.View(), "No presets saved yet") {
+		t.Errorf("Expected an empty list message, got:\n%s", dialog.View())
+	}
+	if _, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Expected enter to do nothing without presets")
+	}
+
+	presets := []config.SelectionPreset{
+		{Name: "auth", Files: []string{"/project/auth.go", "/project/session.go"}},
+		{Name: "models", Files: []string{"/project/user.go"}},
+	}
+	dialog.Show(presets)
+	if view := dialog.View(); !strings.Contains(view, "auth  (2 files)") || !strings.Contains(view, "models  (1 files)") {
+		t.Errorf("Expected every preset listed with its file count, got:\n%s", view)
+	}
+
+	dialog.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if dialog.IsVisible() {
+		t.Error("Expected the dialog to close once a preset is picked")
+	}
+	if msg, ok := cmd().(PresetApplyMsg); !ok || msg.Preset.Name != "models" {
+		t.Errorf("Expected the models preset to be picked, got %+v", msg)
+	}
+}
+
+func TestNameInputSendsName(t *testing.T) {
+	input := NewNameInputModel(DefaultTheme())
+	input.SetSize(100, 40)
+	input.Show()
+
+	if _, cmd := input.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !input.IsVisible() {
+		t.Error("Expected an empty name to keep the input open")
+	}
+	input.input.SetValue("  auth files ")
+	_, cmd := input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(PresetNameMsg); !ok || msg.Name != "auth files" {
+		t.Errorf("Expected the trimmed name to be sent, got %+v", msg)
+	}
+}
+
+func TestAppSavesAndAppliesPresets(t *testing.T) {
+	targetDir := t.TempDir()
+	paths := map[string]string{}
+	for _, name := range []string{"auth.go", "session.go", "user.go"} {
+		paths[name] = filepath.Join(targetDir, name)
+		if err := os.WriteFile(paths[name], []byte("package main"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	cfgManager := config.NewMemoryManager()
+	app := NewApp(targetDir, WithConfigManager(cfgManager), WithSettingsManager(config.NewDefaultSettingsManager()),
+		WithWorkspace(cfgManager.GetWorkspace(targetDir)))
+
+	// The presets key opens the list of saved presets
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlT}); cmd != nil || !app.presetDialog.IsVisible() {
+		t.Fatal("Expected ctrl+t to open the preset dialog")
+	}
+	app.presetDialog.Hide()
+
+	// While typing in the chat, ctrl+t transposes characters instead
+	app.focused = ChatPanel
+	app.chat.textarea.SetValue("ab")
+	if app.Update(tea.KeyMsg{Type: tea.KeyCtrlT}); app.presetDialog.IsVisible() || app.chat.textarea.Value() != "ba" {
+		t.Errorf("Expected ctrl+t to transpose the chat text, got %q", app.chat.textarea.Value())
+	}
+	app.focused = FileTreePanel
+
+	// The save key needs selected files to ask for the preset name
+	saveKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}, Alt: true}
+	if app.Update(saveKey); app.nameInput.IsVisible() {
+		t.Fatal("Expected alt+p not to ask for a name without selected files")
+	}
+	app.selectedFiles.AddFile("session.go", paths["session.go"])
+	app.selectedFiles.AddFile("auth.go", paths["auth.go"])
+	app.selectedFiles.SetTags(paths["auth.go"], []string{"handlers", "auth"})
+	if app.Update(saveKey); !app.nameInput.IsVisible() {
+		t.Fatal("Expected alt+p to ask for the preset name")
+	}
+	app.nameInput.input.SetValue("auth")
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, cmd := app.Update(cmd()); cmd == nil {
+		t.Fatal("Expected an alert once the preset is saved")
+	}
+	presets := cfgManager.GetPresets(targetDir)
+	if len(presets) != 1 || !slices.Equal(presets[0].Files, []string{paths["session.go"], paths["auth.go"]}) {
+		t.Fatalf("Expected the selection saved in order, got %v", presets)
+	}
+	if !slices.Equal(presets[0].Tags, []string{"", "handlers,auth"}) {
+		t.Fatalf("Expected the tags saved with the files, got %v", presets[0].Tags)
+	}
+
+	// Applying replaces the selection, skipping files that no longer exist
+	app.selectedFiles.files = nil
+	app.selectedFiles.AddFile("user.go", paths["user.go"])
+	app.fileTree.selected = map[string]bool{paths["user.go"]: true}
+	if err := os.Remove(paths["session.go"]); err != nil {
+		t.Fatalf("Failed to remove session.go: %v", err)
+	}
+	_, cmd = app.Update(PresetApplyMsg{Preset: presets[0]})
+	if got := app.selectedFiles.GetPaths(); !slices.Equal(got, []string{paths["auth.go"]}) {
+		t.Errorf("Expected only auth.go to be selected, got %v", got)
+	}
+	if tags := app.selectedFiles.GetTags()[paths["auth.go"]]; !slices.Equal(tags, []string{"handlers", "auth"}) {
+		t.Errorf("Expected the saved tags to be restored, got %v", tags)
+	}
+	if !app.fileTree.selected[paths["auth.go"]] || app.fileTree.selected[paths["user.go"]] {
+		t.Errorf("Expected the tree selection to follow the preset, got %v", app.fileTree.selected)
+	}
+	if !slices.Equal(app.workspace.SelectedPaths(), []string{paths["auth.go"]}) {
+		t.Errorf("Expected the workspace to store the new selection, got %v", app.workspace.SelectedPaths())
+	}
+	if cmd == nil {
+		t.Error("Expected an alert once the preset is applied")
+	}
+}
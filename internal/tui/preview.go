@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"coding-prompts-tui/internal/filesystem"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FileFocusedMsg is sent when the file tree cursor moves onto a file
+type FileFocusedMsg struct {
+	Path string
+}
+
+// FilePreviewModel shows the first lines of the file under the file tree cursor
+type FilePreviewModel struct {
+	viewport viewport.Model
+	path     string
+	width    int
+	height   int
+	theme    Theme
+}
+
+// NewFilePreviewModel creates a new, empty file preview
+func NewFilePreviewModel(theme Theme) *FilePreviewModel {
+	return &FilePreviewModel{
+		viewport: viewport.New(0, 0),
+		theme:    theme,
+	}
+}
+
+// SetSize updates the preview dimensions
+func (m *FilePreviewModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Leave room for the title line
+	m.viewport.Width = width
+	m.viewport.Height = height - 1
+	if m.viewport.Height < 0 {
+		m.viewport.Height = 0
+	}
+}
+
+// SetFile shows the first maxLines lines of the file at path
+func (m *FilePreviewModel) SetFile(path string, maxLines int) {
+	m.path = path
+	m.viewport.SetContent(readPreview(path, maxLines))
+	m.viewport.GotoTop()
+}
+
+// readPreview returns the first maxLines lines of the file at path, or a placeholder
+// for binary and unreadable files
+func readPreview(path string, maxLines int) string {
+	binary, err := filesystem.IsBinaryFile(path)
+	if err != nil {
+		return "[unreadable file]"
+	}
+	if binary {
+		return "[binary file]"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "[unreadable file]"
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for len(lines) < maxLines && scanner.Scan() {
+		// Tabs would throw off the panel width
+		lines = append(lines, strings.ReplaceAll(scanner.Text(), "\t", "    "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// View renders the preview
+func (m *FilePreviewModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color(m.theme.SelectedFG))
+
+	if m.path == "" {
+		helpStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+		return titleStyle.Render("👁 Preview") + "\n" + helpStyle.Render("Move the cursor onto a file")
+	}
+
+	// Clip long lines rather than wrapping them
+	content := lipgloss.NewStyle().MaxWidth(m.width).Render(m.viewport.View())
+	return titleStyle.Render("👁 "+filepath.Base(m.path)) + "\n" + content
+}
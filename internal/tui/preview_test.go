@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadPreview(t *testing.T) {
+	dir := t.TempDir()
+
+	textPath := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(textPath, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := readPreview(textPath, 2); got != "one\ntwo" {
+		t.Errorf("Expected the first two lines, got %q", got)
+	}
+
+	binaryPath := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(binaryPath, []byte("\x89PNG\x00\x00"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := readPreview(binaryPath, 50); got != "[binary file]" {
+		t.Errorf("Expected a binary placeholder, got %q", got)
+	}
+
+	preview := NewFilePreviewModel(DefaultTheme())
+	preview.SetSize(40, 10)
+	preview.SetFile(textPath, 50)
+	if view := preview.View(); !strings.Contains(view, "main.go") || !strings.Contains(view, "four") {
+		t.Errorf("Expected the preview to show the file, got %q", view)
+	}
+}
+
+func TestFileTreeAnnouncesFocusedFile(t *testing.T) {
+	model := newTestTree()
+
+	// Moving onto a file announces it
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if cmd == nil {
+		t.Fatal("Expected a command when the cursor moves onto a file")
+	}
+	if msg, ok := cmd().(FileFocusedMsg); !ok || msg.Path != "/project/main.go" {
+		t.Errorf("Expected FileFocusedMsg for main.go, got %+v", cmd())
+	}
+
+	// Moving onto a directory doesn't
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyUp}); cmd != nil {
+		t.Errorf("Expected no command for a directory, got %+v", cmd())
+	}
+}
@@ -2,13 +2,24 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"coding-prompts-tui/internal/prompt"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// PromptCopyMsg is sent when the prompt shown in the dialog should be copied
+// to the clipboard, leaving the dialog open
+type PromptCopyMsg struct {
+	Content string
+}
+
 // PromptDialogModel represents the scrollable prompt dialog
 type PromptDialogModel struct {
 	viewport viewport.Model
@@ -16,16 +27,32 @@ type PromptDialogModel struct {
 	height   int
 	content  string
 	visible  bool
+	// tokenEstimate is shown as a footer when greater than zero
+	tokenEstimate int
+	// previous is the prompt generated before this one; when set, d toggles
+	// ShowDiff between the raw prompt and what changed since previous
+	previous string
+	ShowDiff bool
+	// wrapped is the prompt or diff as wrapped to the viewport, before highlighting
+	wrapped string
+	// search typed after /; matchLines holds the wrapped line of each occurrence
+	// and matchIndex the one last jumped to
+	searching  bool
+	searchTerm string
+	matchLines []int
+	matchIndex int
+	theme      Theme
 }
 
 // NewPromptDialogModel creates a new prompt dialog model
-func NewPromptDialogModel() *PromptDialogModel {
+func NewPromptDialogModel(theme Theme) *PromptDialogModel {
 	vp := viewport.New(0, 0)
 	vp.KeyMap = viewport.DefaultKeyMap()
 
 	return &PromptDialogModel{
 		viewport: vp,
 		visible:  false,
+		theme:    theme,
 	}
 }
 
@@ -38,9 +65,9 @@ func (m *PromptDialogModel) SetSize(width, height int) {
 	dialogWidth := int(float64(width) * 0.8)
 	dialogHeight := int(float64(height) * 0.8)
 
-	// Calculate viewport dimensions (minus borders and padding)
+	// Calculate viewport dimensions (minus borders, padding and footer line)
 	viewportWidth := dialogWidth - 4
-	viewportHeight := dialogHeight - 4
+	viewportHeight := dialogHeight - 5
 
 	m.viewport.Width = viewportWidth
 	m.viewport.Height = viewportHeight
@@ -50,15 +77,194 @@ func (m *PromptDialogModel) SetSize(width, height int) {
 func (m *PromptDialogModel) Show(content string) {
 	m.content = content
 	m.visible = true
+	m.previous = ""
+	m.ShowDiff = false
+	m.searching = false
+	m.searchTerm = ""
+	m.setContent()
+}
+
+// ShowWithPrevious displays a generated prompt, starting with what changed since the
+// previous one. An empty previous shows the raw prompt like Show.
+func (m *PromptDialogModel) ShowWithPrevious(content, previous string) {
+	m.Show(content)
+	m.previous = previous
+	m.ShowDiff = previous != ""
+	m.setContent()
+}
+
+// UpdateContent replaces the content shown, keeping the scroll position unless
+// the new content is too short for it. Unlike Show, the dialog's visibility,
+// diff view and search are left alone.
+func (m *PromptDialogModel) UpdateContent(content string) {
+	offset := m.viewport.YOffset
+	m.content = content
+	m.setContent()
+	// SetYOffset stops at the bottom of shorter content
+	m.viewport.SetYOffset(offset)
+}
+
+// setContent fills the viewport with the raw prompt or the diff, scrolled to the top
+func (m *PromptDialogModel) setContent() {
+	content := m.content
+	if m.ShowDiff {
+		content = renderDiff(m.theme, prompt.DiffPrompts(m.previous, m.content))
+	}
 
 	// Word wrap content to fit viewport width
-	wrappedContent := lipgloss.NewStyle().Width(m.viewport.Width).Render(content)
-	m.viewport.SetContent(wrappedContent)
+	m.wrapped = lipgloss.NewStyle().Width(m.viewport.Width).Render(content)
+	m.applySearch()
 
 	// Reset scroll position to top
 	m.viewport.GotoTop()
 }
 
+// applySearch highlights the occurrences of the search term in the viewport
+// and records the line each one is on
+func (m *PromptDialogModel) applySearch() {
+	m.matchLines = m.matchLines[:0]
+	for _, occurrence := range findOccurrences(m.wrapped, m.searchTerm) {
+		m.matchLines = append(m.matchLines, strings.Count(m.wrapped[:occurrence[0]], "\n"))
+	}
+	m.matchIndex = 0
+	m.viewport.SetContent(highlightOccurrences(m.wrapped, m.searchTerm))
+}
+
+// jumpToMatch scrolls to the occurrence delta places from the current one,
+// wrapping around at either end
+func (m *PromptDialogModel) jumpToMatch(delta int) {
+	if len(m.matchLines) == 0 {
+		return
+	}
+	m.matchIndex = (m.matchIndex + delta + len(m.matchLines)) % len(m.matchLines)
+	m.viewport.SetYOffset(m.matchLines[m.matchIndex])
+}
+
+// updateSearch handles keys while the search term is typed. Enter keeps the
+// highlights for n/N, esc clears them.
+func (m *PromptDialogModel) updateSearch(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		return
+	case tea.KeyEsc:
+		m.searching = false
+		m.searchTerm = ""
+	case tea.KeyBackspace:
+		if m.searchTerm == "" {
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(m.searchTerm)
+		m.searchTerm = m.searchTerm[:len(m.searchTerm)-size]
+	case tea.KeyRunes, tea.KeySpace:
+		m.searchTerm += string(msg.Runes)
+	default:
+		return
+	}
+	m.applySearch()
+	m.jumpToMatch(0)
+}
+
+// searchHighlightStyle marks occurrences of the search term
+var searchHighlightStyle = lipgloss.NewStyle().Background(lipgloss.Color("226"))
+
+// highlightOccurrences highlights every case-insensitive occurrence of term in
+// content. Escape sequences already styling content are left intact.
+func highlightOccurrences(content, term string) string {
+	occurrences := findOccurrences(content, term)
+	if len(occurrences) == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, occurrence := range occurrences {
+		b.WriteString(content[last:occurrence[0]])
+		b.WriteString(searchHighlightStyle.Render(content[occurrence[0]:occurrence[1]]))
+		last = occurrence[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// findOccurrences returns the byte ranges of the case-insensitive occurrences
+// of term in content, skipping ANSI escape sequences. An empty term has none.
+func findOccurrences(content, term string) [][2]int {
+	needle := []rune(strings.ToLower(term))
+	if len(needle) == 0 {
+		return nil
+	}
+
+	// The visible runes of content, lowercased, with their byte ranges
+	var runes []rune
+	var starts, ends []int
+	for i := 0; i < len(content); {
+		if end := escapeSequenceEnd(content, i); end > i {
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(content[i:])
+		runes = append(runes, unicode.ToLower(r))
+		starts = append(starts, i)
+		ends = append(ends, i+size)
+		i += size
+	}
+
+	var occurrences [][2]int
+	for i := 0; i+len(needle) <= len(runes); {
+		if slices.Equal(runes[i:i+len(needle)], needle) {
+			occurrences = append(occurrences, [2]int{starts[i], ends[i+len(needle)-1]})
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+	return occurrences
+}
+
+// escapeSequenceEnd returns the index just past the ANSI escape sequence
+// starting at i, or i if there is none
+func escapeSequenceEnd(s string, i int) int {
+	if !strings.HasPrefix(s[i:], "\x1b[") {
+		return i
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// renderDiff colors the lines of a prompt diff
+func renderDiff(theme Theme, diff string) string {
+	if diff == "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true).Render("No changes since the previous prompt")
+	}
+
+	addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	removedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	hunkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.FocusBorder))
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+ "):
+			lines[i] = addedStyle.Render(line)
+		case strings.HasPrefix(line, "- "):
+			lines[i] = removedStyle.Render(line)
+		case strings.HasPrefix(line, "@@ "):
+			lines[i] = hunkStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetTokenEstimate sets the estimated token count shown in the dialog footer
+func (m *PromptDialogModel) SetTokenEstimate(tokens int) {
+	m.tokenEstimate = tokens
+}
+
 // Hide closes the dialog
 func (m *PromptDialogModel) Hide() {
 	m.visible = false
@@ -82,10 +288,40 @@ func (m *PromptDialogModel) Update(msg tea.Msg) (*PromptDialogModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.searching {
+			m.updateSearch(msg)
+			return m, nil
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q", "enter", "esc":
+		case "q", "enter", "esc":
 			m.Hide()
 			return m, nil
+		case "ctrl+c":
+			// Copy the prompt and keep reading it
+			content := m.content
+			return m, func() tea.Msg {
+				return PromptCopyMsg{Content: content}
+			}
+		case "/":
+			// Type a new search term
+			m.searching = true
+			m.searchTerm = ""
+			m.applySearch()
+			return m, nil
+		case "n":
+			m.jumpToMatch(1)
+			return m, nil
+		case "N":
+			m.jumpToMatch(-1)
+			return m, nil
+		case "d":
+			// Switch between the raw prompt and the diff
+			if m.previous != "" {
+				m.ShowDiff = !m.ShowDiff
+				m.setContent()
+			}
+			return m, nil
 		}
 
 		// Pass scroll controls to viewport
@@ -110,7 +346,7 @@ func (m *PromptDialogModel) View() string {
 	// Create dialog style
 	dialogStyle := lipgloss.NewStyle().
 		Border(lipgloss.ThickBorder()).
-		BorderForeground(lipgloss.Color("228")).
+		BorderForeground(lipgloss.Color(m.theme.TitleFG)).
 		Padding(1, 2).
 		Width(dialogWidth).
 		Height(dialogHeight)
@@ -140,6 +376,41 @@ func (m *PromptDialogModel) View() string {
 		content = strings.Join(contentLines, "\n")
 	}
 
+	// Add token estimate footer, with the diff toggle when there is a previous prompt,
+	// after the search bar while searching
+	var footer []string
+	if m.searching || m.searchTerm != "" {
+		bar := "/" + m.searchTerm
+		if m.searching {
+			bar += "█"
+		}
+		if m.searchTerm != "" {
+			if len(m.matchLines) == 0 {
+				bar += " (no matches)"
+			} else {
+				bar += fmt.Sprintf(" (%d/%d, n/N: next/previous)", m.matchIndex+1, len(m.matchLines))
+			}
+		}
+		footer = append(footer, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(bar))
+	} else {
+		footer = append(footer, "/: search", "ctrl+c: copy")
+	}
+	if m.tokenEstimate > 0 {
+		footer = append(footer, "~"+formatTokenCount(m.tokenEstimate)+" tokens")
+	}
+	if m.previous != "" {
+		if m.ShowDiff {
+			footer = append(footer, "d: show prompt")
+		} else {
+			footer = append(footer, "d: show changes")
+		}
+	}
+	if len(footer) > 0 {
+		content += "\n" + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Render(strings.Join(footer, " • "))
+	}
+
 	dialog := dialogStyle.Render(content)
 
 	// Center the dialog on screen
@@ -149,3 +420,16 @@ func (m *PromptDialogModel) View() string {
 		lipgloss.WithWhitespaceForeground(lipgloss.Color("237")),
 	)
 }
+
+// formatTokenCount formats a token count with spaces as thousands separators (e.g. 4 200)
+func formatTokenCount(tokens int) string {
+	digits := fmt.Sprintf("%d", tokens)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
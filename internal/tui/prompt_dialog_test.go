@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// generatePrompt sends key to the app and runs the background prompt build it
+// starts, delivering the PromptBuiltMsg back to the app
+func generatePrompt(t *testing.T, app *App, key tea.KeyMsg) {
+	t.Helper()
+	_, cmd := app.Update(key)
+	if cmd == nil {
+		t.Fatal("Expected a command building the prompt")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Expected the build and the spinner to be batched")
+	}
+	for _, cmd := range batch {
+		if msg, ok := cmd().(PromptBuiltMsg); ok {
+			app.Update(msg)
+			return
+		}
+	}
+	t.Fatal("Expected a PromptBuiltMsg")
+}
+
+func TestPromptDialogDiffToggle(t *testing.T) {
+	app := createTestApp(t)
+	app.handleStateChange(LayoutChangeMsg{Width: 120, Height: 40})
+	generate := tea.KeyMsg{Type: tea.KeyCtrlS}
+
+	// The first prompt of the session has nothing to compare with
+	app.chat.textarea.SetValue("first request")
+	generatePrompt(t, app, generate)
+	if !app.promptDialog.IsVisible() || app.promptDialog.ShowDiff {
+		t.Fatal("Expected the raw prompt for the first generation")
+	}
+	app.promptDialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.promptDialog.ShowDiff {
+		t.Error("Expected d to do nothing without a previous prompt")
+	}
+	app.promptDialog.Hide()
+
+	app.chat.textarea.SetValue("second request")
+	generatePrompt(t, app, generate)
+	if !app.promptDialog.ShowDiff {
+		t.Fatal("Expected the diff for the second generation")
+	}
+	view := app.promptDialog.View()
+	if !strings.Contains(view, "first request") || !strings.Contains(view, "second request") {
+		t.Errorf("Expected the diff to show the changed user prompt, got:\n%s", view)
+	}
+
+	app.promptDialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.promptDialog.ShowDiff || strings.Contains(app.promptDialog.View(), "first request") {
+		t.Error("Expected d to switch back to the raw prompt")
+	}
+}
+
+func TestGeneratePromptInBackground(t *testing.T) {
+	app := createTestApp(t)
+	app.handleStateChange(LayoutChangeMsg{Width: 120, Height: 40})
+	app.chat.textarea.SetValue("background request")
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil || !app.buildingPrompt {
+		t.Fatal("Expected ctrl+s to start a background build")
+	}
+	if app.promptDialog.IsVisible() {
+		t.Error("Expected the dialog to wait for the build")
+	}
+	if !strings.Contains(app.header(), "building prompt") {
+		t.Errorf("Expected the header to show the build, got %q", app.header())
+	}
+	if _, again := app.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); again != nil {
+		t.Error("Expected no second build while one is running")
+	}
+
+	app.Update(PromptBuiltMsg{Content: "<prompt/>", UserPrompt: "background request"})
+	if app.buildingPrompt || strings.Contains(app.header(), "building prompt") {
+		t.Error("Expected the spinner to stop once the prompt is built")
+	}
+	if !app.promptDialog.IsVisible() || app.promptDialog.GetContent() != "<prompt/>" {
+		t.Error("Expected the built prompt to be shown")
+	}
+	if app.lastPrompt != "<prompt/>" {
+		t.Error("Expected the built prompt to be recorded")
+	}
+
+	// A failed build says why
+	_, cmd = app.Update(PromptBuiltMsg{Err: errors.New("unknown template variable Author")})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != ErrorAlert || !strings.Contains(msg.Message, "unknown template variable Author") {
+		t.Errorf("Expected an error alert with the reason, got %#v", msg)
+	}
+}
+
+// typeSearch opens the search bar of the dialog and types term
+func typeSearch(dialog *PromptDialogModel, term string) {
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range term {
+		dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestFindOccurrences(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		term    string
+		want    int
+	}{
+		{"case-insensitive", "Func main() {\n\tfunc() {}\n}\nFUNC", "func", 3},
+		{"non-overlapping", "aaaa", "aa", 2},
+		{"empty term", "anything", "", 0},
+		{"no match", "anything", "zzz", 0},
+		{"escape sequences are skipped", "\x1b[38;5;10m+ added\x1b[0m", "m", 0},
+		{"across escape sequences", "ad\x1b[1mded", "added", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(findOccurrences(tt.content, tt.term)); got != tt.want {
+				t.Errorf("Expected %d occurrences, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHighlightOccurrences(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	highlighted := highlightOccurrences("one Two two", "two")
+	if strings.Count(highlighted, "\x1b[") < 2 {
+		t.Errorf("Expected both occurrences styled, got %q", highlighted)
+	}
+	if plain := ansi.Strip(highlighted); plain != "one Two two" {
+		t.Errorf("Expected the text to be unchanged, got %q", plain)
+	}
+	if highlightOccurrences("one two", "") != "one two" {
+		t.Error("Expected an empty term to leave the content alone")
+	}
+}
+
+func TestPromptDialogSearchNavigation(t *testing.T) {
+	dialog := NewPromptDialogModel(DefaultTheme())
+	dialog.SetSize(100, 20)
+	var lines []string
+	for i := range 60 {
+		if i%20 == 5 {
+			lines = append(lines, fmt.Sprintf("line %d: Needle", i))
+		} else {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+	}
+	dialog.Show(strings.Join(lines, "\n"))
+
+	typeSearch(dialog, "needle")
+	if len(dialog.matchLines) != 3 {
+		t.Fatalf("Expected 3 occurrences, got %d", len(dialog.matchLines))
+	}
+	if dialog.viewport.YOffset != 5 {
+		t.Errorf("Expected typing to jump to the first occurrence, got offset %d", dialog.viewport.YOffset)
+	}
+	if !strings.Contains(dialog.View(), "/needle█ (1/3") {
+		t.Errorf("Expected the search bar with the match count, got:\n%s", dialog.View())
+	}
+
+	// Enter keeps the search for n/N, which wrap around at either end
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !dialog.IsVisible() {
+		t.Fatal("Expected enter to end the search without closing the dialog")
+	}
+	next := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+	previous := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")}
+	for _, step := range []struct {
+		key   tea.KeyMsg
+		index int
+	}{{next, 1}, {next, 2}, {next, 0}, {previous, 2}} {
+		dialog.Update(step.key)
+		if dialog.matchIndex != step.index {
+			t.Errorf("Expected occurrence %d, got %d", step.index, dialog.matchIndex)
+		}
+	}
+	if offset := dialog.viewport.YOffset; offset != min(45, dialog.viewport.TotalLineCount()-dialog.viewport.Height) {
+		t.Errorf("Expected the last occurrence scrolled into view, got offset %d", offset)
+	}
+}
+
+func TestPromptDialogEmptySearch(t *testing.T) {
+	dialog := NewPromptDialogModel(DefaultTheme())
+	dialog.SetSize(100, 20)
+	dialog.Show("some prompt")
+
+	typeSearch(dialog, "")
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if dialog.searchTerm != "n" {
+		t.Fatalf("Expected n to be typed while searching, got %q", dialog.searchTerm)
+	}
+	dialog.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if dialog.searchTerm != "" || len(dialog.matchLines) != 0 {
+		t.Errorf("Expected an empty search with no matches, got %q", dialog.searchTerm)
+	}
+
+	// n/N do nothing without matches, and esc leaves the search before closing the dialog
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if !dialog.IsVisible() || dialog.viewport.YOffset != 0 {
+		t.Error("Expected n without matches to do nothing")
+	}
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if dialog.IsVisible() {
+		t.Error("Expected esc to close the dialog once the search is left")
+	}
+}
+
+func TestPromptDialogUpdateContentKeepsScroll(t *testing.T) {
+	dialog := NewPromptDialogModel(DefaultTheme())
+	dialog.SetSize(100, 20)
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	dialog.Show(strings.Join(lines, "\n"))
+	dialog.viewport.SetYOffset(10)
+
+	lines[12] = "line 12 changed"
+	dialog.UpdateContent(strings.Join(lines, "\n"))
+	if dialog.viewport.YOffset != 10 {
+		t.Errorf("Expected the scroll position to be kept, got offset %d", dialog.viewport.YOffset)
+	}
+	if !strings.Contains(dialog.View(), "line 12 changed") || !dialog.IsVisible() {
+		t.Errorf("Expected the updated content to be shown, got:\n%s", dialog.View())
+	}
+
+	// Shorter content scrolls back as far as it needs to
+	dialog.UpdateContent(strings.Join(lines[:15], "\n"))
+	if maxOffset := 15 - dialog.viewport.Height; dialog.viewport.YOffset != maxOffset {
+		t.Errorf("Expected offset %d at the bottom of shorter content, got %d", maxOffset, dialog.viewport.YOffset)
+	}
+	dialog.UpdateContent("short")
+	if dialog.viewport.YOffset != 0 {
+		t.Errorf("Expected content shorter than the dialog to be shown from the top, got offset %d", dialog.viewport.YOffset)
+	}
+	if got := dialog.GetContent(); got != "short" {
+		t.Errorf("Expected the updated content, got %q", got)
+	}
+}
+
+func TestPromptDialogCopyKeepsDialogOpen(t *testing.T) {
+	dialog := NewPromptDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.Show("<prompt>review</prompt>")
+
+	_, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !dialog.visible {
+		t.Error("Expected ctrl+c to leave the dialog open")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command copying the prompt")
+	}
+	if msg, ok := cmd().(PromptCopyMsg); !ok || msg.Content != "<prompt>review</prompt>" {
+		t.Errorf("Expected PromptCopyMsg with the prompt, got %#v", cmd())
+	}
+
+	// The app copies it and confirms with an alert
+	app := createTestApp(t)
+	clip := &mockClipboard{}
+	app.clipboard = clip
+	_, cmd = app.Update(PromptCopyMsg{Content: "<prompt>review</prompt>"})
+	if clip.content != "<prompt>review</prompt>" {
+		t.Errorf("Expected the prompt on the clipboard, got %q", clip.content)
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected an info alert, got %#v", msg)
+	}
+
+	// Esc still closes it
+	dialog.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if dialog.visible {
+		t.Error("Expected esc to close the dialog")
+	}
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AutosaveTickMsg triggers a periodic autosave of the workspace
+type AutosaveTickMsg struct{}
+
+// RecoveryMsg is sent when the user answers the recovery dialog
+type RecoveryMsg struct {
+	Restore  bool
+	Autosave *config.Autosave
+}
+
+// RecoveryDialogModel asks whether to restore a workspace autosaved before the app exited abnormally
+type RecoveryDialogModel struct {
+	autosave *config.Autosave
+	width    int
+	height   int
+	visible  bool
+	theme    Theme
+}
+
+// NewRecoveryDialogModel creates a new recovery dialog model
+func NewRecoveryDialogModel(theme Theme) *RecoveryDialogModel {
+	return &RecoveryDialogModel{theme: theme}
+}
+
+// SetSize updates the dialog dimensions
+func (m *RecoveryDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog for the given autosave
+func (m *RecoveryDialogModel) Show(autosave *config.Autosave) {
+	m.autosave = autosave
+	m.visible = true
+}
+
+// Hide closes the dialog
+func (m *RecoveryDialogModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *RecoveryDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the recovery dialog
+func (m *RecoveryDialogModel) Update(msg tea.Msg) (*RecoveryDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		var restore bool
+		switch keyMsg.String() {
+		case "y", "enter":
+			restore = true
+		case "n", "esc":
+			restore = false
+		default:
+			return m, nil
+		}
+		m.Hide()
+		autosave := m.autosave
+		return m, func() tea.Msg {
+			return RecoveryMsg{Restore: restore, Autosave: autosave}
+		}
+	}
+	return m, nil
+}
+
+// View renders the recovery dialog
+func (m *RecoveryDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.5)
+	if dialogWidth < 40 {
+		dialogWidth = 40
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Recover Session"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("Restore autosaved session from %s?", formatAge(time.Since(m.autosave.SavedAt))))
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("y/Enter: restore • n/Esc: discard"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, 7, m.width, m.height)
+}
+
+// formatAge formats how long ago something happened, e.g. "2m ago"
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds ago", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
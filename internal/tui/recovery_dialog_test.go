@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecoveryDialogAnswers(t *testing.T) {
+	autosave := &config.Autosave{SavedAt: time.Now()}
+
+	tests := []struct {
+		key     tea.KeyMsg
+		restore bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}, true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}, false},
+		{tea.KeyMsg{Type: tea.KeyEsc}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			dialog := NewRecoveryDialogModel(DefaultTheme())
+			dialog.Show(autosave)
+
+			dialog, cmd := dialog.Update(tt.key)
+			if dialog.IsVisible() {
+				t.Error("Expected the dialog to close")
+			}
+			msg, ok := cmd().(RecoveryMsg)
+			if !ok || msg.Restore != tt.restore || msg.Autosave != autosave {
+				t.Errorf("Expected RecoveryMsg with Restore=%v, got %#v", tt.restore, msg)
+			}
+		})
+	}
+}
+
+func TestRestoreWorkspace(t *testing.T) {
+	app := createTestApp(t)
+	path := filepath.Join(app.workspace.Path, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	app.restoreWorkspace(config.WorkspaceState{
+		Path:           app.workspace.Path,
+		SelectedFiles:  []config.SelectedFileState{{Path: path, Tags: []string{"focus"}}},
+		ChatInput:      "unsaved prompt",
+		ActivePersonas: []string{"default"},
+	})
+
+	if got := app.chat.textarea.Value(); got != "unsaved prompt" {
+		t.Errorf("Expected chat input to be restored, got %q", got)
+	}
+	if paths := app.selectedFiles.GetPaths(); len(paths) != 1 || paths[0] != path {
+		t.Errorf("Expected selection to be restored, got %v", paths)
+	}
+	if !app.fileTree.selected[path] {
+		t.Error("Expected the file tree to show the restored selection")
+	}
+	if app.workspace.ChatInput != "unsaved prompt" {
+		t.Errorf("Expected workspace chat input to be updated, got %q", app.workspace.ChatInput)
+	}
+}
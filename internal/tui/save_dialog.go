@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SaveConfirmMsg is sent when the user confirms a writable path in the save dialog
+type SaveConfirmMsg struct {
+	Path string
+}
+
+// SaveDialogModel asks for the path the generated prompt should be written to
+type SaveDialogModel struct {
+	textarea textarea.Model
+	err      error
+	width    int
+	height   int
+	visible  bool
+	theme    Theme
+}
+
+// NewSaveDialogModel creates a new save dialog model
+func NewSaveDialogModel(theme Theme) *SaveDialogModel {
+	ta := textarea.New()
+	ta.Placeholder = "Path to save the prompt to..."
+	ta.ShowLineNumbers = false
+	ta.Prompt = ""
+	ta.CharLimit = 0
+	ta.SetHeight(1)
+	// Enter confirms the path instead of inserting a newline
+	ta.KeyMap.InsertNewline.SetEnabled(false)
+
+	return &SaveDialogModel{
+		textarea: ta,
+		theme:    theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *SaveDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.textarea.SetWidth(int(float64(width)*0.6) - 8)
+}
+
+// Show displays the dialog pre-filled with the given path
+func (m *SaveDialogModel) Show(defaultPath string) tea.Cmd {
+	m.visible = true
+	m.err = nil
+	m.textarea.SetValue(defaultPath)
+	m.textarea.CursorEnd()
+	return m.textarea.Focus()
+}
+
+// Hide closes the dialog
+func (m *SaveDialogModel) Hide() {
+	m.visible = false
+	m.textarea.Blur()
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *SaveDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// GetPath returns the path currently entered in the dialog
+func (m *SaveDialogModel) GetPath() string {
+	return strings.TrimSpace(m.textarea.Value())
+}
+
+// Update handles messages for the save dialog
+func (m *SaveDialogModel) Update(msg tea.Msg) (*SaveDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			path := m.GetPath()
+			if err := ValidateWritablePath(path); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.Hide()
+			return m, func() tea.Msg {
+				return SaveConfirmMsg{Path: path}
+			}
+		}
+		// Clear a stale validation error once the path is edited
+		m.err = nil
+	}
+
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+// View renders the save dialog
+func (m *SaveDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.6)
+	dialogHeight := 9
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Save Prompt"))
+	b.WriteString("\n\n")
+	b.WriteString(m.textarea.View())
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		b.WriteString(errorStyle.Render(m.err.Error()))
+	} else {
+		helpStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+		b.WriteString(helpStyle.Render("Enter: save • Esc: cancel"))
+	}
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
+
+// ValidateWritablePath checks that a file can be written at path: its directory
+// must exist and accept new files, and the path itself must not be a directory
+func ValidateWritablePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("path cannot be empty")
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", path)
+		}
+		// An existing file will be overwritten, so it must be writable itself
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("%s is not writable", path)
+		}
+		f.Close()
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("directory %s does not exist", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	// Probe the directory with a temporary file, since permission bits alone
+	// don't account for ACLs or read-only mounts
+	probe, err := os.CreateTemp(dir, ".prompt-write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable", dir)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
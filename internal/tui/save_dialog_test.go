@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestValidateWritablePath(t *testing.T) {
+	tempDir := t.TempDir()
+	existingFile := filepath.Join(tempDir, "existing.xml")
+	if err := os.WriteFile(existingFile, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"new file in existing directory", filepath.Join(tempDir, "prompt.xml"), false},
+		{"existing file", existingFile, false},
+		{"empty path", "", true},
+		{"directory", tempDir, true},
+		{"missing directory", filepath.Join(tempDir, "missing", "prompt.xml"), true},
+		{"parent is a file", filepath.Join(existingFile, "prompt.xml"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWritablePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWritablePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+
+	// The write probe must not leave files behind
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the existing file in %s, found %d entries", tempDir, len(entries))
+	}
+}
+
+func TestSaveDialog_Confirm(t *testing.T) {
+	tempDir := t.TempDir()
+	dialog := NewSaveDialogModel(DefaultTheme())
+	dialog.SetSize(100, 40)
+
+	// An invalid path keeps the dialog open
+	dialog.Show(filepath.Join(tempDir, "missing", "prompt.xml"))
+	dialog, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("Expected no command for an invalid path")
+	}
+	if !dialog.IsVisible() {
+		t.Error("Expected dialog to stay open for an invalid path")
+	}
+
+	// A valid path confirms and closes the dialog
+	path := filepath.Join(tempDir, "prompt.xml")
+	dialog.Show(path)
+	dialog, cmd = dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected confirm command for a valid path")
+	}
+	msg, ok := cmd().(SaveConfirmMsg)
+	if !ok || msg.Path != path {
+		t.Errorf("Expected SaveConfirmMsg for %q, got %#v", path, cmd())
+	}
+	if dialog.IsVisible() {
+		t.Error("Expected dialog to close after confirming")
+	}
+}
+
+func TestExportKeyFromApp(t *testing.T) {
+	app := createTestApp(t)
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if !app.saveDialog.IsVisible() {
+		t.Fatal("Expected ctrl+e to open the export dialog")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// While typing in the chat, ctrl+e moves to the end of the line instead
+	app.focused = ChatPanel
+	app.chat.textarea.SetValue("review")
+	app.chat.textarea.CursorStart()
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+	if app.saveDialog.IsVisible() || app.chat.textarea.Value() != "review!" {
+		t.Errorf("Expected ctrl+e to move to the end of the chat line, got %q", app.chat.textarea.Value())
+	}
+}
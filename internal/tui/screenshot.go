@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// screenshotTimeFormat is the timestamp in screenshot file names
+const screenshotTimeFormat = "20060102-150405"
+
+// captureScreenshot writes the current render of the app, ANSI colors included,
+// to a coding-prompts-screenshot-<timestamp>.txt file in the target directory
+// for attaching to bug reports. The generated prompt shown in the prompt
+// dialog, which the render may cut off, is appended in full. It returns the
+// path of the file.
+func (a *App) captureScreenshot() (string, error) {
+	var b strings.Builder
+	b.WriteString(a.View())
+	b.WriteString("\n")
+	if a.promptDialog.IsVisible() && a.promptDialog.GetContent() != "" {
+		b.WriteString("\n--- prompt ---\n")
+		b.WriteString(a.promptDialog.GetContent())
+		b.WriteString("\n")
+	}
+
+	name := fmt.Sprintf("coding-prompts-screenshot-%s.txt", time.Now().Format(screenshotTimeFormat))
+	path := filepath.Join(a.targetDir, name)
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write screenshot: %w", err)
+	}
+	return path, nil
+}
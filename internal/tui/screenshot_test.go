@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCaptureScreenshot(t *testing.T) {
+	app := createTestApp(t)
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	app.promptDialog.SetSize(100, 30)
+	app.dialogs.Push(app.promptDialog, func() tea.Cmd {
+		app.promptDialog.Show("<prompt>full generated prompt</prompt>")
+		return nil
+	})
+
+	path, err := app.captureScreenshot()
+	if err != nil {
+		t.Fatalf("captureScreenshot failed: %v", err)
+	}
+	if filepath.Dir(path) != app.targetDir || !strings.HasPrefix(filepath.Base(path), "coding-prompts-screenshot-") {
+		t.Errorf("Expected a screenshot file in the target directory, got %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read screenshot: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("Expected the screenshot to have content")
+	}
+	if !strings.Contains(string(data), "full generated prompt") {
+		t.Errorf("Expected the screenshot to include the prompt, got:\n%s", data)
+	}
+}
+
+func TestScreenshotKeyShowsPath(t *testing.T) {
+	app := createTestApp(t)
+	app.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}, Alt: true})
+
+	matches, err := filepath.Glob(filepath.Join(app.targetDir, "coding-prompts-screenshot-*.txt"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Expected one screenshot file, got %v (%v)", matches, err)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command showing the screenshot path")
+	}
+	msg, ok := cmd().(NotificationMsg)
+	if !ok || msg.AlertType != InfoAlert || !strings.Contains(msg.Message, matches[0]) {
+		t.Errorf("Expected an info alert with the screenshot path, got %#v", msg)
+	}
+}
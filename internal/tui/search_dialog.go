@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SearchSelectMsg is sent when a file is chosen from the quick-open dialog
+type SearchSelectMsg struct {
+	Path string
+}
+
+// searchMatch is a candidate file ranked against the current query
+type searchMatch struct {
+	path    string
+	relPath string
+	score   int
+}
+
+// SearchDialogModel represents the fuzzy quick-open dialog
+type SearchDialogModel struct {
+	input     textinput.Model
+	targetDir string
+	files     []string
+	matches   []searchMatch
+	cursor    int
+	width     int
+	height    int
+	visible   bool
+	theme     Theme
+}
+
+// NewSearchDialogModel creates a new search dialog model
+func NewSearchDialogModel(targetDir string, theme Theme) *SearchDialogModel {
+	ti := textinput.New()
+	ti.Placeholder = "Type to search files..."
+	ti.Prompt = "🔍 "
+
+	return &SearchDialogModel{
+		input:     ti,
+		targetDir: targetDir,
+		theme:     theme,
+	}
+}
+
+// SetFiles sets the absolute paths of all files that can be searched
+func (m *SearchDialogModel) SetFiles(files []string) {
+	m.files = files
+	m.refreshMatches()
+}
+
+// SetSize updates the dialog dimensions
+func (m *SearchDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = int(float64(width)*0.6) - 8
+}
+
+// Show displays the dialog with an empty query
+func (m *SearchDialogModel) Show() tea.Cmd {
+	m.visible = true
+	m.input.Reset()
+	m.refreshMatches()
+	return m.input.Focus()
+}
+
+// Hide closes the dialog
+func (m *SearchDialogModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *SearchDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the search dialog
+func (m *SearchDialogModel) Update(msg tea.Msg) (*SearchDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "up", "ctrl+k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+j":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case "enter":
+			if m.cursor >= len(m.matches) {
+				return m, nil
+			}
+			path := m.matches[m.cursor].path
+			m.Hide()
+			return m, func() tea.Msg {
+				return SearchSelectMsg{Path: path}
+			}
+		}
+	}
+
+	// Pass remaining input to the text field and re-rank on change
+	oldQuery := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != oldQuery {
+		m.refreshMatches()
+	}
+	return m, cmd
+}
+
+// refreshMatches re-ranks the file list against the current query
+func (m *SearchDialogModel) refreshMatches() {
+	query := m.input.Value()
+	m.matches = m.matches[:0]
+	for _, path := range m.files {
+		relPath, err := filepath.Rel(m.targetDir, path)
+		if err != nil {
+			relPath = path
+		}
+		score := FuzzyScore(query, relPath)
+		if score < 0 {
+			continue
+		}
+		m.matches = append(m.matches, searchMatch{path: path, relPath: relPath, score: score})
+	}
+
+	// Highest score first, shorter paths break ties
+	sort.SliceStable(m.matches, func(i, j int) bool {
+		if m.matches[i].score != m.matches[j].score {
+			return m.matches[i].score > m.matches[j].score
+		}
+		return len(m.matches[i].relPath) < len(m.matches[j].relPath)
+	})
+
+	m.cursor = 0
+}
+
+// View renders the search dialog
+func (m *SearchDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.6)
+	dialogHeight := int(float64(m.height) * 0.6)
+
+	// Lines available for results: minus borders, padding, input, spacing and help
+	listHeight := dialogHeight - 8
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	var b strings.Builder
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	// Keep the cursor within the visible window of results
+	start := 0
+	if m.cursor >= listHeight {
+		start = m.cursor - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+
+	if len(m.matches) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("No matching files"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		line := "  " + m.matches[i].relPath
+		if i == m.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.theme.CursorFG)).
+				Bold(true).
+				Render("▶ " + m.matches[i].relPath)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render(fmt.Sprintf("↑/↓: navigate • Enter: select • Esc: cancel • %d/%d files", len(m.matches), len(m.files))))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
+
+// FuzzyScore scores how well pattern fuzzy-matches candidate. Every pattern
+// character must appear in order in candidate (case-insensitive); otherwise
+// -1 is returned. Matches score a point each, with bonuses for consecutive
+// matches and for matches at the start of a path segment or word. Each
+// possible starting position is tried and the best score is kept.
+func FuzzyScore(pattern, candidate string) int {
+	if pattern == "" {
+		return 0
+	}
+
+	patternRunes := []rune(strings.ToLower(pattern))
+	candidateRunes := []rune(strings.ToLower(candidate))
+
+	best := -1
+	for start, r := range candidateRunes {
+		if r != patternRunes[0] {
+			continue
+		}
+		if score := fuzzyScoreFrom(patternRunes, candidateRunes, start); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// fuzzyScoreFrom greedily matches pattern against candidate beginning at start
+func fuzzyScoreFrom(pattern, candidate []rune, start int) int {
+	score := 0
+	consecutive := 0
+	pi := 0
+	for ci := start; ci < len(candidate) && pi < len(pattern); ci++ {
+		if candidate[ci] != pattern[pi] {
+			consecutive = 0
+			continue
+		}
+
+		score++
+		if consecutive > 0 {
+			score += 5 * consecutive
+		}
+		if ci == 0 || isWordBoundary(candidate[ci-1]) {
+			score += 3
+		}
+		consecutive++
+		pi++
+	}
+
+	if pi < len(pattern) {
+		return -1
+	}
+	return score
+}
+
+// isWordBoundary reports whether r separates words in a file path
+func isWordBoundary(r rune) bool {
+	switch r {
+	case '/', '\\', '_', '-', '.', ' ':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		candidate string
+		matches   bool
+	}{
+		{"", "anything.go", true},
+		{"app", "internal/tui/app.go", true},
+		{"itapp", "internal/tui/app.go", true},
+		{"APP", "internal/tui/app.go", true},
+		{"xyz", "internal/tui/app.go", false},
+		{"ppa", "app.go", false},
+	}
+
+	for _, tt := range tests {
+		score := FuzzyScore(tt.pattern, tt.candidate)
+		if (score >= 0) != tt.matches {
+			t.Errorf("FuzzyScore(%q, %q) = %d, expected match=%t", tt.pattern, tt.candidate, score, tt.matches)
+		}
+	}
+
+	// Consecutive matches should outrank scattered ones
+	if FuzzyScore("app", "app.go") <= FuzzyScore("app", "a_p_p.go") {
+		t.Error("Expected consecutive match to score higher than scattered match")
+	}
+
+	// Matches at a path segment start should outrank mid-word matches
+	if FuzzyScore("main", "cmd/main.go") <= FuzzyScore("main", "cmd/domain.go") {
+		t.Error("Expected segment-start match to score higher than mid-word match")
+	}
+}
+
+func TestSearchDialogRanksAndSelects(t *testing.T) {
+	targetDir := "/project"
+	dialog := NewSearchDialogModel(targetDir, DefaultTheme())
+	dialog.SetSize(100, 40)
+	dialog.SetFiles([]string{
+		filepath.Join(targetDir, "internal", "config", "domain.go"),
+		filepath.Join(targetDir, "main.go"),
+		filepath.Join(targetDir, "README.md"),
+	})
+	dialog.Show()
+
+	for _, r := range "main" {
+		dialog, _ = dialog.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	if len(dialog.matches) != 2 {
+		t.Fatalf("Expected 2 matches for 'main', got %d", len(dialog.matches))
+	}
+	if dialog.matches[0].relPath != "main.go" {
+		t.Errorf("Expected main.go to rank first, got %s", dialog.matches[0].relPath)
+	}
+
+	dialog, cmd := dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if dialog.IsVisible() {
+		t.Error("Expected dialog to close after selection")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a selection command")
+	}
+	msg, ok := cmd().(SearchSelectMsg)
+	if !ok {
+		t.Fatalf("Expected SearchSelectMsg, got %T", cmd())
+	}
+	if msg.Path != filepath.Join(targetDir, "main.go") {
+		t.Errorf("Expected selected path %s, got %s", filepath.Join(targetDir, "main.go"), msg.Path)
+	}
+}
+
+func TestFileTreeRevealAndSelect(t *testing.T) {
+	tmpDir := t.TempDir()
+	nestedDir := filepath.Join(tmpDir, "pkg", "util")
+	if err := os.MkdirAll(nestedDir, 0755); err != nil {
+		t.Fatalf("Failed to create nested dir: %v", err)
+	}
+	target := filepath.Join(nestedDir, "strings.go")
+	if err := os.WriteFile(target, []byte("package util"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	model := NewFileTreeModel(tmpDir, []string{}, DefaultTheme())
+	model.Init()
+
+	if paths := model.AllFilePaths(); len(paths) != 1 || paths[0] != target {
+		t.Fatalf("Expected AllFilePaths to return [%s], got %v", target, paths)
+	}
+
+	model.RevealAndSelect(target)
+
+	if !model.selected[target] {
+		t.Error("Expected revealed file to be selected")
+	}
+	if model.cursor >= len(model.items) || model.items[model.cursor].Path != target {
+		t.Errorf("Expected cursor to be on %s", target)
+	}
+}
+
+func TestQuickOpenKeyFromApp(t *testing.T) {
+	app := createTestApp(t)
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !app.searchDialog.IsVisible() {
+		t.Fatal("Expected ctrl+p to open the quick-open dialog")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// While typing in the chat, ctrl+p moves to the previous line instead
+	app.focused = ChatPanel
+	app.chat.textarea.SetValue("first\nsecond")
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if app.searchDialog.IsVisible() || app.chat.textarea.Line() != 0 {
+		t.Errorf("Expected ctrl+p to move up a line in the chat, got line %d", app.chat.textarea.Line())
+	}
+}
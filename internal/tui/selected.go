@@ -1,56 +1,204 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/filesystem"
+	"coding-prompts-tui/internal/prompt"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // SelectedFile represents a file that has been selected for inclusion
 type SelectedFile struct {
-	Name string
-	Path string
+	Name   string
+	Path   string
+	Size   int64 // Size in bytes
+	Tokens int   // Estimated tokens of the included content
+	// Estimating is set while the token estimate is computed in the background
+	Estimating bool
+	StartLine  int  // First line to include, or zero to include the whole file
+	EndLine    int  // Last line to include, or zero to read to the end of the file
+	Binary     bool // Included as a placeholder rather than its content
+	Tags       []string
+	Bucket     string // One of Buckets; empty means DefaultBucket
+	seq        int    // Position in selection order, restored by SortByOrder
+}
+
+// SortMode is the order the selected files are listed and included in
+type SortMode int
+
+const (
+	SortByOrder SortMode = iota // Selection order, as rearranged with ctrl+up/down
+	SortByName                  // File name, A to Z
+	SortBySize                  // Size, largest first
+)
+
+// String returns the name the sort mode is saved as
+func (s SortMode) String() string {
+	switch s {
+	case SortByName:
+		return "name"
+	case SortBySize:
+		return "size"
+	default:
+		return "order"
+	}
+}
+
+// label returns the sort mode as shown in the panel title, empty for selection order
+func (s SortMode) label() string {
+	switch s {
+	case SortByName:
+		return "name ↑"
+	case SortBySize:
+		return "size ↓"
+	default:
+		return ""
+	}
+}
+
+// ParseSortMode returns the sort mode saved as name, defaulting to SortByOrder
+func ParseSortMode(name string) SortMode {
+	switch name {
+	case "name":
+		return SortByName
+	case "size":
+		return SortBySize
+	default:
+		return SortByOrder
+	}
+}
+
+// bucket returns the name of the bucket the file is in
+func (f SelectedFile) bucket() string {
+	if f.Bucket == "" {
+		return DefaultBucket
+	}
+	return f.Bucket
+}
+
+// LineRange returns the lines of the file to include in the prompt
+func (f SelectedFile) LineRange() prompt.LineRange {
+	return prompt.LineRange{Start: f.StartLine, End: f.EndLine}
 }
 
 // SelectedFilesModel represents the selected files panel
 type SelectedFilesModel struct {
 	files         []SelectedFile
-	cursor        int
+	cursor        int // Index in files; only files in the active bucket are listed
 	title         string
+	tabs          *BucketTabModel
+	sortMode      SortMode
+	nextSeq       int // seq of the next file added
 	configManager *config.ConfigManager
+	// whether binary files can be added; refused otherwise
+	allowBinaryFiles bool
+	theme            Theme
+	viewport         viewport.Model // Scrolls the file list; its YOffset is the first line shown
+	width            int
+	height           int
 }
 
 // NewSelectedFilesModel creates a new selected files model
-func NewSelectedFilesModel(configManager *config.ConfigManager) *SelectedFilesModel {
+func NewSelectedFilesModel(configManager *config.ConfigManager, theme Theme) *SelectedFilesModel {
 	return &SelectedFilesModel{
 		title:         "✅ Selected Files",
 		files:         []SelectedFile{},
 		cursor:        0,
+		tabs:          NewBucketTabModel(theme),
 		configManager: configManager,
+		theme:         theme,
 	}
 }
 
-// Init initializes the selected files model
+// Init initializes the selected files model, estimating the tokens of restored files
 func (m *SelectedFilesModel) Init() tea.Cmd {
-	return nil
+	return m.estimateTokens()
 }
 
 // Update handles messages for the selected files panel
 func (m *SelectedFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Keep the cursor in view, whichever way the key moved it
+		defer m.ensureVisible()
+
 		switch msg.String() {
 		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+			m.moveCursor(-1)
 		case "down", "j":
-			if m.cursor < len(m.files)-1 {
-				m.cursor++
+			m.moveCursor(1)
+		case "pgup":
+			m.moveCursor(-m.listHeight())
+		case "pgdown":
+			m.moveCursor(m.listHeight())
+		case "home":
+			m.moveCursor(-len(m.files))
+		case "end":
+			m.moveCursor(len(m.files))
+		case "s":
+			// Cycle through selection order, name and size
+			m.sortFiles((m.sortMode + 1) % 3)
+			return m, m.sendFileOrderUpdate()
+		case "1", "2", "3":
+			// Switch to the bucket with that number
+			if m.tabs.SetActive(int(msg.Runes[0] - '1')) {
+				m.clampCursor()
+			}
+		case "t":
+			// Edit the tags of the file under the cursor
+			if m.hasCursorFile() {
+				file := m.files[m.cursor]
+				return m, func() tea.Msg {
+					return TagRequestMsg{Path: file.Path, Tags: file.Tags}
+				}
+			}
+		case "r":
+			// Ask for the line range of the file under the cursor
+			if m.hasCursorFile() {
+				file := m.files[m.cursor]
+				return m, func() tea.Msg {
+					return LineRangeRequestMsg{Path: file.Path, StartLine: file.StartLine, EndLine: file.EndLine}
+				}
+			}
+		case "ctrl+j":
+			// Show the file under the cursor in the file tree
+			if m.hasCursorFile() {
+				path := m.files[m.cursor].Path
+				return m, func() tea.Msg {
+					return JumpToFileMsg{Path: path}
+				}
+			}
+		case "ctrl+c":
+			// Copy the paths of every selected file
+			if len(m.files) > 0 {
+				return m, m.sendCopyPaths(m.GetPaths())
+			}
+		case "ctrl+shift+c", "alt+c":
+			// Copy the path of the file under the cursor; alt+c for terminals that can't send ctrl+shift+c
+			if m.hasCursorFile() {
+				return m, m.sendCopyPaths([]string{m.files[m.cursor].Path})
+			}
+		case "ctrl+d":
+			// Remove every file
+			return m, m.ClearAllFiles()
+		case "ctrl+up":
+			// Move the file under the cursor one position earlier in the prompt
+			if m.moveFile(m.cursor, m.bucketNeighbour(-1)) {
+				return m, m.sendFileOrderUpdate()
+			}
+		case "ctrl+down":
+			// Move the file under the cursor one position later in the prompt
+			if m.moveFile(m.cursor, m.bucketNeighbour(1)) {
+				return m, m.sendFileOrderUpdate()
 			}
 		default:
 			// Check if this key is configured for file removal
@@ -58,7 +206,7 @@ func (m *SelectedFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for _, removalKey := range settings.RemovalKeys {
 				if msg.String() == removalKey {
 					// Remove selected file
-					if len(m.files) > 0 && m.cursor < len(m.files) {
+					if m.hasCursorFile() {
 						removedFile := m.files[m.cursor]
 						m.removeFile(m.cursor)
 						// Send a message to update the file tree selection state
@@ -72,18 +220,41 @@ func (m *SelectedFilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// View renders the selected files panel
+// View renders the selected files panel. Once sized, the file list scrolls
+// between the header and the totals.
 func (m *SelectedFilesModel) View() string {
+	list, _ := m.listContent()
+	height := m.listHeight()
+	if height <= 0 {
+		return m.header() + list + m.footer()
+	}
+
+	vp := m.viewport
+	vp.Width = m.width
+	vp.Height = height
+	vp.SetContent(strings.TrimSuffix(list, "\n"))
+	vp.SetYOffset(m.viewport.YOffset)
+	return m.header() + vp.View() + "\n" + m.footer()
+}
+
+// header renders the title, the bucket tabs and the help text above the file list
+func (m *SelectedFilesModel) header() string {
 	var b strings.Builder
 
 	// Title row
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("10"))
+		Foreground(lipgloss.Color(m.theme.SelectedFG))
 
-	titleText := titleStyle.Render(m.title)
+	title := m.title
+	if label := m.sortMode.label(); label != "" {
+		title += " [" + label + "]"
+	}
+	titleText := titleStyle.Render(title)
 
 	b.WriteString(titleText)
+	b.WriteString("\n")
+	b.WriteString(m.tabs.View(m.GetBuckets()))
 	b.WriteString("\n\n")
 
 	// Help text - contextual based on whether files exist and are selected
@@ -104,12 +275,43 @@ func (m *SelectedFilesModel) View() string {
 		b.WriteString("\n\n")
 	}
 
-	// Selected files list
+	return b.String()
+}
+
+// listContent renders the files of the active bucket, grouped by tags once any
+// file has them, a line each. It also returns the line the cursor is on.
+func (m *SelectedFilesModel) listContent() (string, int) {
+	var b strings.Builder
+	cursorLine := 0
+	lines := 0
+	order := m.displayOrder()
 	if len(m.files) == 0 {
 		// Empty state is already shown in help text
 		// No additional content needed here
+	} else if len(order) == 0 {
+		emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+		b.WriteString(emptyStyle.Render("No files in this bucket"))
+		b.WriteString("\n")
 	} else {
-		for i, file := range m.files {
+		grouped := m.hasTags()
+		group := ""
+		groupStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141")).Bold(true)
+		for n, i := range order {
+			file := m.files[i]
+			if key := tagGroup(file); grouped && (n == 0 || key != group) {
+				group = key
+				if n > 0 {
+					b.WriteString("\n")
+					lines++
+				}
+				if key == "" {
+					key = "untagged"
+				}
+				b.WriteString(groupStyle.Render("# " + key))
+				b.WriteString("\n")
+				lines++
+			}
+
 			var line strings.Builder
 
 			// Cursor indicator
@@ -125,38 +327,271 @@ func (m *SelectedFilesModel) View() string {
 			// File name
 			fileStyle := lipgloss.NewStyle()
 			if i == m.cursor {
-				fileStyle = fileStyle.Foreground(lipgloss.Color("69")).Bold(true)
+				fileStyle = fileStyle.Foreground(lipgloss.Color(m.theme.CursorFG)).Bold(true)
 			}
 
 			line.WriteString(fileStyle.Render(file.Name))
 
+			// Binary placeholder or line range
+			if file.Binary {
+				binaryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+				line.WriteString(binaryStyle.Render(" [binary]"))
+			} else if lineRange := file.LineRange(); lineRange.IsSet() {
+				rangeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+				line.WriteString(rangeStyle.Render(" [" + lineRange.String() + "]"))
+			}
+
+			// File size and token contribution
+			sizeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			line.WriteString(sizeStyle.Render("  " + formatFileSize(file.Size)))
+			if file.Estimating {
+				line.WriteString(sizeStyle.Render(" (...)"))
+			} else if !file.Binary {
+				line.WriteString(sizeStyle.Render(" (~" + formatTokenCount(file.Tokens) + " tok)"))
+			}
+
+			if i == m.cursor {
+				cursorLine = lines
+			}
 			b.WriteString(line.String())
 			b.WriteString("\n")
+			lines++
 		}
 	}
 
+	return b.String(), cursorLine
+}
+
+// footer renders the totals below the file list
+func (m *SelectedFilesModel) footer() string {
+	var b strings.Builder
+
 	// Count
 	b.WriteString("\n")
 	countStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240"))
-	b.WriteString(countStyle.Render(fmt.Sprintf("Total: %d files", len(m.files))))
+	var totalSize int64
+	var totalTokens int
+	estimating := false
+	for _, file := range m.files {
+		totalSize += file.Size
+		totalTokens += file.Tokens
+		estimating = estimating || file.Estimating
+	}
+	tokens := "~" + formatTokenCount(totalTokens)
+	if estimating {
+		tokens = "..."
+	}
+	b.WriteString(countStyle.Render(fmt.Sprintf("Total: %d files (%s, %s tokens)", len(m.files), formatFileSize(totalSize), tokens)))
 
 	return b.String()
 }
 
-// AddFile adds a file to the selected files list
-func (m *SelectedFilesModel) AddFile(name, path string) {
+// listHeight returns the number of lines the file list is shown in, or zero
+// before the panel is sized
+func (m *SelectedFilesModel) listHeight() int {
+	if m.height <= 0 {
+		return 0
+	}
+	return max(1, m.height-strings.Count(m.header(), "\n")-strings.Count(m.footer(), "\n")-1)
+}
+
+// SetSize sets the available width and height for the panel
+func (m *SelectedFilesModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ensureVisible()
+}
+
+// ensureVisible scrolls the file list so the cursor is within the visible lines
+func (m *SelectedFilesModel) ensureVisible() {
+	height := m.listHeight()
+	if height <= 0 {
+		return
+	}
+	list, cursorLine := m.listContent()
+	// Show the tag group heading above the first file too
+	if order := m.displayOrder(); len(order) > 0 && m.cursor == order[0] {
+		cursorLine = 0
+	}
+
+	if cursorLine < m.viewport.YOffset {
+		m.viewport.YOffset = cursorLine
+	} else if cursorLine >= m.viewport.YOffset+height {
+		m.viewport.YOffset = cursorLine - height + 1
+	}
+	maxOffset := max(0, strings.Count(list, "\n")-height)
+	m.viewport.YOffset = min(maxOffset, max(0, m.viewport.YOffset))
+}
+
+// tagGroup returns the name of the group a file is listed under, empty for untagged files
+func tagGroup(file SelectedFile) string {
+	return strings.Join(file.Tags, ", ")
+}
+
+// hasTags reports whether any file in the active bucket is tagged
+func (m *SelectedFilesModel) hasTags() bool {
+	for _, file := range m.files {
+		if len(file.Tags) > 0 && file.bucket() == m.tabs.Active() {
+			return true
+		}
+	}
+	return false
+}
+
+// displayOrder returns the indices of the files in the active bucket in the
+// order they're listed: grouped by tags, with groups in order of their first
+// file and files in prompt order within a group
+func (m *SelectedFilesModel) displayOrder() []int {
+	var groups []string
+	members := make(map[string][]int)
+	for i, file := range m.files {
+		if file.bucket() != m.tabs.Active() {
+			continue
+		}
+		key := tagGroup(file)
+		if _, ok := members[key]; !ok {
+			groups = append(groups, key)
+		}
+		members[key] = append(members[key], i)
+	}
+
+	order := make([]int, 0, len(m.files))
+	for _, key := range groups {
+		order = append(order, members[key]...)
+	}
+	return order
+}
+
+// moveCursor moves the cursor delta places through the listed order, stopping
+// at the first and last file
+func (m *SelectedFilesModel) moveCursor(delta int) {
+	order := m.displayOrder()
+	for pos, i := range order {
+		if i == m.cursor {
+			m.cursor = order[min(len(order)-1, max(0, pos+delta))]
+			return
+		}
+	}
+}
+
+// hasCursorFile reports whether the cursor is on a listed file
+func (m *SelectedFilesModel) hasCursorFile() bool {
+	return m.cursor < len(m.files) && m.files[m.cursor].bucket() == m.tabs.Active()
+}
+
+// clampCursor moves the cursor onto a listed file after files were removed or
+// the bucket was switched: the closest one before it in prompt order, or the
+// first one listed
+func (m *SelectedFilesModel) clampCursor() {
+	if m.hasCursorFile() {
+		return
+	}
+	order := m.displayOrder()
+	if len(order) == 0 {
+		m.cursor = 0
+		return
+	}
+	closest := -1
+	for _, i := range order {
+		if i < m.cursor && i > closest {
+			closest = i
+		}
+	}
+	if closest < 0 {
+		closest = order[0]
+	}
+	m.cursor = closest
+}
+
+// bucketNeighbour returns the index of the closest file after (delta 1) or
+// before (delta -1) the cursor in prompt order that is in the same bucket, or
+// -1 if there is none
+func (m *SelectedFilesModel) bucketNeighbour(delta int) int {
+	if !m.hasCursorFile() {
+		return -1
+	}
+	for i := m.cursor + delta; i >= 0 && i < len(m.files); i += delta {
+		if m.files[i].bucket() == m.files[m.cursor].bucket() {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetAllowBinaryFiles sets whether binary files can be added
+func (m *SelectedFilesModel) SetAllowBinaryFiles(allow bool) {
+	m.allowBinaryFiles = allow
+}
+
+// AddFile adds a file to the active bucket of the selected files list. Binary
+// files are refused with an error unless they are allowed.
+func (m *SelectedFilesModel) AddFile(name, path string) error {
 	// Check if file is already selected
 	for _, file := range m.files {
 		if file.Path == path {
-			return // Already selected
+			return nil // Already selected
 		}
 	}
 
+	binary, err := filesystem.IsBinaryFile(path)
+	if err == nil && binary && !m.allowBinaryFiles {
+		return fmt.Errorf("%s is a binary file", name)
+	}
+
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+
 	m.files = append(m.files, SelectedFile{
-		Name: name,
-		Path: path,
+		Name:   name,
+		Path:   path,
+		Size:   size,
+		Binary: binary,
+		Bucket: m.tabs.Active(),
+		seq:    m.nextSeq,
+	})
+	m.nextSeq++
+	m.sortFiles(m.sortMode)
+	m.clampCursor()
+	return nil
+}
+
+// GetSortMode returns the order the files are currently sorted in
+func (m *SelectedFilesModel) GetSortMode() SortMode {
+	return m.sortMode
+}
+
+// sortFiles stably sorts the files by mode, which then applies to files added
+// later too. The cursor stays on the same file.
+func (m *SelectedFilesModel) sortFiles(mode SortMode) {
+	cursorPath := ""
+	if m.cursor < len(m.files) {
+		cursorPath = m.files[m.cursor].Path
+	}
+
+	m.sortMode = mode
+	slices.SortStableFunc(m.files, func(a, b SelectedFile) int {
+		switch mode {
+		case SortByName:
+			if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+				return c
+			}
+		case SortBySize:
+			if c := cmp.Compare(b.Size, a.Size); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.seq, b.seq)
 	})
+
+	for i, file := range m.files {
+		if file.Path == cursorPath {
+			m.cursor = i
+			break
+		}
+	}
 }
 
 // RemoveFile removes a file from the selected files list by path
@@ -178,12 +613,240 @@ func (m *SelectedFilesModel) removeFile(index int) {
 	m.files = append(m.files[:index], m.files[index+1:]...)
 
 	// Adjust cursor if necessary
-	if m.cursor >= len(m.files) && len(m.files) > 0 {
-		m.cursor = len(m.files) - 1
+	m.clampCursor()
+}
+
+// moveFile swaps the file at from with the file at to and moves the cursor along with it.
+// Moving a file while sorted by name or size makes the sorted order the selection order.
+// It returns false if either index is out of range.
+func (m *SelectedFilesModel) moveFile(from, to int) bool {
+	if from < 0 || from >= len(m.files) || to < 0 || to >= len(m.files) {
+		return false
 	}
-	if len(m.files) == 0 {
-		m.cursor = 0
+	if m.sortMode != SortByOrder {
+		for i := range m.files {
+			m.files[i].seq = i
+		}
+		m.sortMode = SortByOrder
 	}
+	m.files[from], m.files[to] = m.files[to], m.files[from]
+	m.files[from].seq, m.files[to].seq = m.files[to].seq, m.files[from].seq
+	m.cursor = to
+	return true
+}
+
+// SetLineRange limits the file at path to lines start through end. A zero start
+// includes the whole file again. Its tokens are then estimated by estimateTokens.
+func (m *SelectedFilesModel) SetLineRange(path string, start, end int) {
+	for i := range m.files {
+		if m.files[i].Path == path {
+			m.files[i].StartLine = start
+			m.files[i].EndLine = end
+			m.refreshStats(i)
+			return
+		}
+	}
+}
+
+// SetTags replaces the tags of the file at path
+func (m *SelectedFilesModel) SetTags(path string, tags []string) {
+	for i := range m.files {
+		if m.files[i].Path == path {
+			m.files[i].Tags = tags
+			return
+		}
+	}
+}
+
+// SetBucket moves the file at path to the named bucket, ignoring unknown buckets
+func (m *SelectedFilesModel) SetBucket(path, bucket string) {
+	if !slices.Contains(Buckets, bucket) {
+		return
+	}
+	for i := range m.files {
+		if m.files[i].Path == path {
+			m.files[i].Bucket = bucket
+			m.clampCursor()
+			return
+		}
+	}
+}
+
+// GetBuckets returns the selected files of each bucket, in prompt order
+func (m *SelectedFilesModel) GetBuckets() BucketedSelection {
+	selection := make(BucketedSelection)
+	for _, file := range m.files {
+		selection[file.bucket()] = append(selection[file.bucket()], file)
+	}
+	return selection
+}
+
+// usesBuckets reports whether any file was put in a bucket other than the default one
+func (m *SelectedFilesModel) usesBuckets() bool {
+	for _, file := range m.files {
+		if file.bucket() != DefaultBucket {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBucketPaths returns the paths of the selected files of each bucket, for
+// saving. It returns nil while all files are in the default bucket.
+func (m *SelectedFilesModel) GetBucketPaths() map[string][]string {
+	if !m.usesBuckets() {
+		return nil
+	}
+	paths := make(map[string][]string)
+	for _, file := range m.files {
+		paths[file.bucket()] = append(paths[file.bucket()], file.Path)
+	}
+	return paths
+}
+
+// GetFileBuckets returns the bucket of each selected file, keyed by path, for
+// grouping them in the prompt. It returns nil while all files are in the
+// default bucket, so the prompt isn't grouped until buckets are used.
+func (m *SelectedFilesModel) GetFileBuckets() map[string]string {
+	if !m.usesBuckets() {
+		return nil
+	}
+	buckets := make(map[string]string)
+	for _, file := range m.files {
+		buckets[file.Path] = file.bucket()
+	}
+	return buckets
+}
+
+// promptOrder returns the selected files grouped by bucket in the order of
+// Buckets, keeping their order within a bucket
+func (m *SelectedFilesModel) promptOrder() []SelectedFile {
+	files := make([]SelectedFile, 0, len(m.files))
+	selection := m.GetBuckets()
+	for _, bucket := range Buckets {
+		files = append(files, selection[bucket]...)
+	}
+	return files
+}
+
+// GetTags returns the tags of the files that have any, keyed by path
+func (m *SelectedFilesModel) GetTags() map[string][]string {
+	tags := make(map[string][]string)
+	for _, file := range m.files {
+		if len(file.Tags) > 0 {
+			tags[file.Path] = file.Tags
+		}
+	}
+	return tags
+}
+
+// RefreshFile reloads the stats of path if it is selected, e.g. after it was edited,
+// leaving its tokens to be estimated by estimateTokens. It returns whether the file is selected.
+func (m *SelectedFilesModel) RefreshFile(path string) bool {
+	for i := range m.files {
+		if m.files[i].Path == path {
+			m.refreshStats(i)
+			if m.sortMode == SortBySize {
+				m.sortFiles(m.sortMode)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// GetLineRanges returns the line ranges of the files that have one, keyed by path
+func (m *SelectedFilesModel) GetLineRanges() map[string]prompt.LineRange {
+	lineRanges := make(map[string]prompt.LineRange)
+	for _, file := range m.files {
+		if lineRange := file.LineRange(); lineRange.IsSet() {
+			lineRanges[file.Path] = lineRange
+		}
+	}
+	return lineRanges
+}
+
+// refreshStats reloads the size of the file at index and marks its tokens to be
+// estimated again
+func (m *SelectedFilesModel) refreshStats(index int) {
+	file := &m.files[index]
+	file.Size = 0
+	if info, err := os.Stat(file.Path); err == nil {
+		file.Size = info.Size()
+	}
+	if file.Binary {
+		// Only a placeholder is included in the prompt
+		file.Tokens, file.Estimating = 0, false
+		return
+	}
+	file.Estimating = true
+}
+
+// estimateTokens returns a command estimating the tokens of each file marked by
+// refreshStats in the background, as reading large files would block the UI
+func (m *SelectedFilesModel) estimateTokens() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, file := range m.files {
+		if file.Estimating {
+			cmds = append(cmds, estimateTokensCmd(file.Path, file.LineRange()))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// estimateTokensCmd reads the included content of path and estimates its tokens
+func estimateTokensCmd(path string, lineRange prompt.LineRange) tea.Cmd {
+	return func() tea.Msg {
+		var content []byte
+		var err error
+		if lineRange.IsSet() {
+			var lines string
+			lines, err = filesystem.ReadLines(path, lineRange.Start, lineRange.End)
+			content = []byte(lines)
+		} else {
+			content, err = os.ReadFile(path)
+		}
+		msg := TokenEstimateMsg{FilePath: path, LineRange: lineRange}
+		if err == nil {
+			msg.Tokens = prompt.EstimateTokens(string(content))
+		}
+		return msg
+	}
+}
+
+// SetTokenEstimate stores a finished token estimate, unless the file was removed
+// or its line range changed while it was computed
+func (m *SelectedFilesModel) SetTokenEstimate(msg TokenEstimateMsg) {
+	for i := range m.files {
+		if m.files[i].Path == msg.FilePath && m.files[i].LineRange() == msg.LineRange {
+			m.files[i].Tokens = msg.Tokens
+			m.files[i].Estimating = false
+			return
+		}
+	}
+}
+
+// GetPaths returns the paths of the selected files in prompt order
+func (m *SelectedFilesModel) GetPaths() []string {
+	paths := make([]string, len(m.files))
+	for i, file := range m.promptOrder() {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// GetFileStates returns the paths and tags of the selected files in selection
+// order, for saving; the sort mode is saved separately
+func (m *SelectedFilesModel) GetFileStates() []config.SelectedFileState {
+	files := slices.Clone(m.files)
+	slices.SortStableFunc(files, func(a, b SelectedFile) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	states := make([]config.SelectedFileState, len(files))
+	for i, file := range files {
+		states[i] = config.SelectedFileState{Path: file.Path, Tags: file.Tags}
+	}
+	return states
 }
 
 // GetSelectedFiles returns the list of selected files
@@ -208,9 +871,51 @@ type FileDeselectionMsg struct {
 	FilePath string
 }
 
+// FileOrderChangedMsg represents a message about the selected files being reordered
+type FileOrderChangedMsg struct {
+	Paths []string
+}
+
 // ClearAllFilesMsg represents a message about clearing all selected files
 type ClearAllFilesMsg struct{}
 
+// CopyPathsMsg asks for the paths of selected files to be copied to the clipboard
+type CopyPathsMsg struct {
+	Paths []string
+}
+
+// sendCopyPaths returns a command asking for paths to be copied
+func (m *SelectedFilesModel) sendCopyPaths(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		return CopyPathsMsg{Paths: paths}
+	}
+}
+
+// LineRangeRequestMsg asks for the line range of a selected file to be edited
+type LineRangeRequestMsg struct {
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+// TokenEstimateMsg carries the estimated tokens of the included content of a selected file
+type TokenEstimateMsg struct {
+	FilePath  string
+	LineRange prompt.LineRange
+	Tokens    int
+}
+
+// JumpToFileMsg asks for the file tree cursor to be moved to a selected file
+type JumpToFileMsg struct {
+	Path string
+}
+
+// TagRequestMsg asks for the tags of a selected file to be edited
+type TagRequestMsg struct {
+	Path string
+	Tags []string
+}
+
 // sendFileDeselectionUpdate creates a file deselection update message
 func (m *SelectedFilesModel) sendFileDeselectionUpdate(filePath string) tea.Cmd {
 	return func() tea.Msg {
@@ -218,6 +923,14 @@ func (m *SelectedFilesModel) sendFileDeselectionUpdate(filePath string) tea.Cmd
 	}
 }
 
+// sendFileOrderUpdate creates a file order update message
+func (m *SelectedFilesModel) sendFileOrderUpdate() tea.Cmd {
+	paths := m.GetPaths()
+	return func() tea.Msg {
+		return FileOrderChangedMsg{Paths: paths}
+	}
+}
+
 // formatKeysForDisplay formats the removal keys for display in help text
 func (m *SelectedFilesModel) formatKeysForDisplay(keys []string) string {
 	if len(keys) == 0 {
@@ -240,3 +953,19 @@ func (m *SelectedFilesModel) formatKeysForDisplay(keys []string) string {
 	// Join with slashes
 	return strings.Join(displayKeys, "/")
 }
+
+// formatFileSize formats a byte count in human-readable form (e.g. 512 B, 12 KB, 1.5 MB)
+func formatFileSize(bytes int64) string {
+	const (
+		kb = 1024
+		mb = 1024 * kb
+	)
+	switch {
+	case bytes < kb:
+		return fmt.Sprintf("%d B", bytes)
+	case bytes < mb:
+		return fmt.Sprintf("%d KB", (bytes+kb/2)/kb)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/mb)
+	}
+}
@@ -0,0 +1,607 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newTestSelectedFiles(paths ...string) *SelectedFilesModel {
+	model := NewSelectedFilesModel(nil, DefaultTheme())
+	for _, path := range paths {
+		model.AddFile(path, path)
+	}
+	return model
+}
+
+// applyTokenEstimates runs the token estimates batched in cmd and stores them in model
+func applyTokenEstimates(model *SelectedFilesModel, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			applyTokenEstimates(model, cmd)
+		}
+	case TokenEstimateMsg:
+		model.SetTokenEstimate(msg)
+	}
+}
+
+func TestSelectedFilesReorder(t *testing.T) {
+	tests := []struct {
+		name          string
+		cursor        int
+		key           tea.KeyType
+		expectedOrder []string
+		expectedCurs  int
+		expectMsg     bool
+	}{
+		{"move middle file up", 1, tea.KeyCtrlUp, []string{"b", "a", "c"}, 0, true},
+		{"move middle file down", 1, tea.KeyCtrlDown, []string{"a", "c", "b"}, 2, true},
+		{"move first file up is a no-op", 0, tea.KeyCtrlUp, []string{"a", "b", "c"}, 0, false},
+		{"move last file down is a no-op", 2, tea.KeyCtrlDown, []string{"a", "b", "c"}, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestSelectedFiles("a", "b", "c")
+			model.cursor = tt.cursor
+
+			_, cmd := model.Update(tea.KeyMsg{Type: tt.key})
+
+			paths := model.GetPaths()
+			for i, path := range tt.expectedOrder {
+				if paths[i] != path {
+					t.Fatalf("Expected order %v, got %v", tt.expectedOrder, paths)
+				}
+			}
+			if model.cursor != tt.expectedCurs {
+				t.Errorf("Expected cursor %d, got %d", tt.expectedCurs, model.cursor)
+			}
+
+			if !tt.expectMsg {
+				if cmd != nil {
+					t.Error("Expected no command for a boundary move")
+				}
+				return
+			}
+			if cmd == nil {
+				t.Fatal("Expected a FileOrderChangedMsg command")
+			}
+			msg, ok := cmd().(FileOrderChangedMsg)
+			if !ok {
+				t.Fatalf("Expected FileOrderChangedMsg, got %T", cmd())
+			}
+			for i, path := range tt.expectedOrder {
+				if msg.Paths[i] != path {
+					t.Errorf("Expected message order %v, got %v", tt.expectedOrder, msg.Paths)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSelectedFilesReorderEmpty(t *testing.T) {
+	model := newTestSelectedFiles()
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlUp}); cmd != nil {
+		t.Error("Expected no command when reordering an empty list")
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlDown}); cmd != nil {
+		t.Error("Expected no command when reordering an empty list")
+	}
+}
+
+func TestFormatFileSize(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1023, "1023 B"},
+		{1024, "1 KB"},
+		{12 * 1024, "12 KB"},
+		{1536, "2 KB"},
+		{1024 * 1024, "1.0 MB"},
+		{1536 * 1024, "1.5 MB"},
+		{250 * 1024 * 1024, "250.0 MB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatFileSize(tt.bytes); got != tt.expected {
+			t.Errorf("formatFileSize(%d) = %q, expected %q", tt.bytes, got, tt.expected)
+		}
+	}
+}
+
+func TestSelectedFilesLineRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(strings.Repeat("some words here\n", 100)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	model := newTestSelectedFiles(path)
+	model.refreshStats(0)
+	applyTokenEstimates(model, model.estimateTokens())
+	wholeTokens := model.files[0].Tokens
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if cmd == nil {
+		t.Fatal("Expected a line range request")
+	}
+	if msg, ok := cmd().(LineRangeRequestMsg); !ok || msg.Path != path {
+		t.Fatalf("Expected LineRangeRequestMsg for %s, got %#v", path, cmd())
+	}
+
+	model.SetLineRange(path, 10, 19)
+	applyTokenEstimates(model, model.estimateTokens())
+	if got := model.GetLineRanges()[path]; got.Start != 10 || got.End != 19 {
+		t.Errorf("Expected line range 10-19, got %v", got)
+	}
+	if tokens := model.files[0].Tokens; tokens <= 0 || tokens >= wholeTokens {
+		t.Errorf("Expected token estimate for the range to be below %d, got %d", wholeTokens, tokens)
+	}
+
+	model.SetLineRange(path, 0, 0)
+	applyTokenEstimates(model, model.estimateTokens())
+	if len(model.GetLineRanges()) != 0 {
+		t.Errorf("Expected line range to be cleared, got %v", model.GetLineRanges())
+	}
+	if model.files[0].Tokens != wholeTokens {
+		t.Errorf("Expected whole-file token estimate %d after clearing, got %d", wholeTokens, model.files[0].Tokens)
+	}
+}
+
+func TestAddFileRefusesBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(image, []byte("PNG\x00\x00"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	model := NewSelectedFilesModel(nil, DefaultTheme())
+	if err := model.AddFile("logo.png", image); err == nil {
+		t.Error("Expected binary files to be refused by default")
+	}
+	if len(model.files) != 0 {
+		t.Errorf("Expected no files, got %+v", model.files)
+	}
+
+	model.SetAllowBinaryFiles(true)
+	if err := model.AddFile("logo.png", image); err != nil {
+		t.Fatalf("Expected binary files to be allowed, got: %v", err)
+	}
+	if len(model.files) != 1 || !model.files[0].Binary {
+		t.Errorf("Expected the file to be marked binary, got %+v", model.files)
+	}
+}
+
+func TestSelectingBinaryFileShowsWarning(t *testing.T) {
+	app := createTestApp(t)
+	image := filepath.Join(app.targetDir, "logo.png")
+	text := filepath.Join(app.targetDir, "main.go")
+	if err := os.WriteFile(image, []byte("PNG\x00\x00"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(text, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	app.fileTree.selected[image] = true
+	app.fileTree.selected[text] = true
+	_, cmd := app.Update(FileSelectionMsg{SelectedFiles: app.fileTree.selected})
+	if cmd == nil {
+		t.Fatal("Expected a warning when selecting a binary file")
+	}
+	var warned, estimated bool
+	for _, c := range cmd().(tea.BatchMsg) {
+		switch msg := c().(type) {
+		case NotificationMsg:
+			warned = msg.AlertType == WarnAlert
+		case TokenEstimateMsg:
+			estimated = msg.FilePath == text
+		}
+	}
+	if !warned || !estimated {
+		t.Errorf("Expected a warning notification and a token estimate for main.go, got warned=%v estimated=%v", warned, estimated)
+	}
+
+	if paths := app.selectedFiles.GetPaths(); len(paths) != 1 || paths[0] != text {
+		t.Errorf("Expected only main.go to be selected, got %v", paths)
+	}
+	if app.fileTree.selected[image] {
+		t.Error("Expected the binary file to be deselected in the tree")
+	}
+}
+
+func TestSelectedFilesTags(t *testing.T) {
+	model := newTestSelectedFiles("a", "b", "c", "d")
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	model.configManager = cfgManager
+
+	// Without tags the list isn't grouped
+	if strings.Contains(model.View(), "# untagged") {
+		t.Errorf("Expected no group headers without tags, got:\n%s", model.View())
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if cmd == nil {
+		t.Fatal("Expected a tag request")
+	}
+	if msg, ok := cmd().(TagRequestMsg); !ok || msg.Path != "a" {
+		t.Fatalf("Expected TagRequestMsg for a, got %#v", cmd())
+	}
+
+	model.SetTags("b", []string{"focus"})
+	model.SetTags("d", []string{"focus"})
+
+	// Files are listed by group but stay in prompt order
+	if order := model.displayOrder(); !slices.Equal(order, []int{0, 2, 1, 3}) {
+		t.Errorf("Expected display order [0 2 1 3], got %v", order)
+	}
+	if paths := model.GetPaths(); !slices.Equal(paths, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Expected prompt order to be unchanged, got %v", paths)
+	}
+	view := model.View()
+	if !strings.Contains(view, "# untagged") || !strings.Contains(view, "# focus") {
+		t.Errorf("Expected group headers, got:\n%s", view)
+	}
+
+	// The cursor follows the listed order
+	var visited []string
+	for range 4 {
+		visited = append(visited, model.files[model.cursor].Path)
+		model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if !slices.Equal(visited, []string{"a", "c", "b", "d"}) {
+		t.Errorf("Expected the cursor to move through a, c, b, d, got %v", visited)
+	}
+
+	if tags := model.GetTags(); len(tags) != 2 || !slices.Equal(tags["b"], []string{"focus"}) {
+		t.Errorf("Expected tags for b and d, got %v", tags)
+	}
+	model.SetTags("b", nil)
+	if states := model.GetFileStates(); len(states[1].Tags) != 0 || !slices.Equal(states[3].Tags, []string{"focus"}) {
+		t.Errorf("Expected the tags of b to be cleared, got %v", states)
+	}
+}
+
+func TestSelectedFilesTokenEstimates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(strings.Repeat("some words here\n", 100)), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	model := newTestSelectedFiles(path)
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	model.configManager = cfgManager
+
+	model.refreshStats(0)
+	if !model.files[0].Estimating || !strings.Contains(model.View(), "(...)") {
+		t.Errorf("Expected the file to show a pending estimate, got:\n%s", model.View())
+	}
+
+	cmd := model.estimateTokens()
+	if cmd == nil {
+		t.Fatal("Expected a token estimate command")
+	}
+	msg, ok := cmd().(TokenEstimateMsg)
+	if !ok || msg.FilePath != path || msg.Tokens <= 0 {
+		t.Fatalf("Expected a TokenEstimateMsg for %s, got %#v", path, msg)
+	}
+
+	// An estimate for a line range that has since changed is stale
+	model.SetLineRange(path, 1, 10)
+	model.SetTokenEstimate(msg)
+	if !model.files[0].Estimating {
+		t.Error("Expected an estimate for a different line range to be ignored")
+	}
+
+	model.SetLineRange(path, 0, 0)
+	model.SetTokenEstimate(msg)
+	if model.files[0].Estimating || model.files[0].Tokens != msg.Tokens {
+		t.Errorf("Expected the estimate to be stored, got %+v", model.files[0])
+	}
+	if view := model.View(); !strings.Contains(view, "(~"+formatTokenCount(msg.Tokens)+" tok)") {
+		t.Errorf("Expected the token count next to the file, got:\n%s", view)
+	}
+
+	if model.estimateTokens() != nil {
+		t.Error("Expected no command once every file is estimated")
+	}
+}
+
+func TestAppStoresTokenEstimates(t *testing.T) {
+	app := createTestApp(t)
+	path := filepath.Join(app.targetDir, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	app.fileTree.selected[path] = true
+	_, cmd := app.Update(FileSelectionMsg{SelectedFiles: app.fileTree.selected})
+	if !app.selectedFiles.files[0].Estimating {
+		t.Fatal("Expected the new file's tokens to be estimated in the background")
+	}
+	msg, ok := cmd().(TokenEstimateMsg)
+	if !ok {
+		t.Fatalf("Expected a TokenEstimateMsg, got %#v", msg)
+	}
+
+	app.Update(msg)
+	if file := app.selectedFiles.files[0]; file.Estimating || file.Tokens != msg.Tokens {
+		t.Errorf("Expected the estimate of %d tokens to be stored, got %+v", msg.Tokens, file)
+	}
+}
+
+// newSortTestFiles selects files with known names and sizes in the order
+// b.go (30 bytes), c.go (20 bytes), a.go (10 bytes)
+func newSortTestFiles(t *testing.T) (*SelectedFilesModel, map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make(map[string]string)
+	model := NewSelectedFilesModel(nil, DefaultTheme())
+	model.configManager, _ = config.NewManager()
+	for _, file := range []struct {
+		name string
+		size int
+	}{{"b.go", 30}, {"c.go", 20}, {"a.go", 10}} {
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", file.size)), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file.name, err)
+		}
+		paths[file.name] = path
+		model.AddFile(file.name, path)
+	}
+	return model, paths
+}
+
+// fileNames returns the names of the selected files in prompt order
+func fileNames(model *SelectedFilesModel) []string {
+	var names []string
+	for _, file := range model.GetSelectedFiles() {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestSelectedFilesSortModes(t *testing.T) {
+	model, _ := newSortTestFiles(t)
+	sortKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
+
+	tests := []struct {
+		mode  SortMode
+		order []string
+		title string
+	}{
+		{SortByName, []string{"a.go", "b.go", "c.go"}, "✅ Selected Files [name ↑]"},
+		{SortBySize, []string{"b.go", "c.go", "a.go"}, "✅ Selected Files [size ↓]"},
+		{SortByOrder, []string{"b.go", "c.go", "a.go"}, "✅ Selected Files\n"},
+	}
+	for _, tt := range tests {
+		_, cmd := model.Update(sortKey)
+		if model.GetSortMode() != tt.mode {
+			t.Fatalf("Expected sort mode %v, got %v", tt.mode, model.GetSortMode())
+		}
+		if names := fileNames(model); !slices.Equal(names, tt.order) {
+			t.Errorf("Expected %v sorted by %v, got %v", tt.order, tt.mode, names)
+		}
+		if !strings.Contains(model.View(), tt.title) {
+			t.Errorf("Expected title %q for %v, got:\n%s", tt.title, tt.mode, model.View())
+		}
+		if cmd == nil {
+			t.Fatalf("Expected a command to save the sort mode")
+		}
+		if _, ok := cmd().(FileOrderChangedMsg); !ok {
+			t.Error("Expected a FileOrderChangedMsg")
+		}
+	}
+}
+
+func TestSelectedFilesSortKeepsSelectionOrder(t *testing.T) {
+	model, paths := newSortTestFiles(t)
+	model.sortFiles(SortByName)
+
+	// Files added later are sorted in too
+	path := filepath.Join(filepath.Dir(paths["a.go"]), "aa.go")
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	model.AddFile("aa.go", path)
+	if names := fileNames(model); !slices.Equal(names, []string{"a.go", "aa.go", "b.go", "c.go"}) {
+		t.Errorf("Expected aa.go sorted in by name, got %v", names)
+	}
+
+	// Saved in selection order, so it can be restored
+	var saved []string
+	for _, state := range model.GetFileStates() {
+		saved = append(saved, filepath.Base(state.Path))
+	}
+	if !slices.Equal(saved, []string{"b.go", "c.go", "a.go", "aa.go"}) {
+		t.Errorf("Expected files saved in selection order, got %v", saved)
+	}
+
+	// Moving a file by hand makes the sorted order the selection order
+	model.cursor = 0
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlDown})
+	if model.GetSortMode() != SortByOrder {
+		t.Errorf("Expected moving a file to switch to selection order, got %v", model.GetSortMode())
+	}
+	if names := fileNames(model); !slices.Equal(names, []string{"aa.go", "a.go", "b.go", "c.go"}) {
+		t.Errorf("Expected a.go moved after aa.go, got %v", names)
+	}
+}
+
+func TestRestoreWorkspaceSortMode(t *testing.T) {
+	app := createTestApp(t)
+	var states []config.SelectedFileState
+	for _, name := range []string{"b.go", "a.go"} {
+		path := filepath.Join(app.workspace.Path, name)
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		states = append(states, config.SelectedFileState{Path: path})
+	}
+
+	app.restoreWorkspace(config.WorkspaceState{
+		Path:             app.workspace.Path,
+		SelectedFiles:    states,
+		SelectedSortMode: "name",
+	})
+
+	if names := fileNames(app.selectedFiles); !slices.Equal(names, []string{"a.go", "b.go"}) {
+		t.Errorf("Expected files sorted by name, got %v", names)
+	}
+	if app.workspace.SelectedSortMode != "name" {
+		t.Errorf("Expected the sort mode to be saved, got %q", app.workspace.SelectedSortMode)
+	}
+}
+
+func TestSelectedFilesJumpToTree(t *testing.T) {
+	app := createTestApp(t)
+	path := filepath.Join(app.targetDir, "pkg", "a.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("package pkg"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	app.fileTree.Init()
+	app.selectedFiles.AddFile("a.go", path)
+	app.focused = SelectedFilesPanel
+
+	_, cmd := app.selectedFiles.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if cmd == nil {
+		t.Fatal("Expected ctrl+j to ask for a jump to the file")
+	}
+	msg, ok := cmd().(JumpToFileMsg)
+	if !ok || msg.Path != path {
+		t.Fatalf("Expected a JumpToFileMsg for %s, got %+v", path, msg)
+	}
+
+	_, cmd = app.Update(msg)
+	if focus, ok := cmd().(FocusChangeMsg); !ok || focus.Panel != FileTreePanel {
+		t.Errorf("Expected the file tree to be focused, got %+v", focus)
+	}
+	if app.fileTree.cursorPath() != path {
+		t.Errorf("Expected the tree cursor on %s, got %s", path, app.fileTree.cursorPath())
+	}
+}
+
+func TestSelectedFilesCopyPaths(t *testing.T) {
+	app := createTestApp(t)
+	clip := &mockClipboard{}
+	app.clipboard = clip
+	paths := []string{
+		filepath.Join(app.targetDir, "main.go"),
+		filepath.Join(app.targetDir, "pkg", "a.go"),
+		filepath.Join(app.targetDir, "pkg", "sub", "b.go"),
+	}
+	for _, path := range paths {
+		app.selectedFiles.AddFile(filepath.Base(path), path)
+	}
+	app.focused = SelectedFilesPanel
+
+	// ctrl+c copies every selected file rather than quitting
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("Expected ctrl+c to copy the paths")
+	}
+	msg, ok := cmd().(CopyPathsMsg)
+	if !ok || !slices.Equal(msg.Paths, paths) {
+		t.Fatalf("Expected CopyPathsMsg with %v, got %#v", paths, msg)
+	}
+	_, cmd = app.Update(msg)
+	if clip.content != "main.go\npkg/a.go\npkg/sub/b.go\n" {
+		t.Errorf("Expected the relative paths on the clipboard, got %q", clip.content)
+	}
+	if alert, ok := cmd().(NotificationMsg); !ok || alert.Message != "copied 3 paths to clipboard" {
+		t.Errorf("Expected the number of paths copied, got %#v", alert)
+	}
+
+	// alt+c copies only the file under the cursor
+	app.selectedFiles.moveCursor(1)
+	_, cmd = app.selectedFiles.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c"), Alt: true})
+	if msg, ok := cmd().(CopyPathsMsg); !ok || !slices.Equal(msg.Paths, paths[1:2]) {
+		t.Errorf("Expected CopyPathsMsg with %s, got %#v", paths[1], msg)
+	}
+
+	// ctrl+d clears the selection instead
+	_, cmd = app.selectedFiles.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if _, ok := cmd().(ClearAllFilesMsg); !ok || len(app.selectedFiles.GetPaths()) != 0 {
+		t.Error("Expected ctrl+d to clear the selection")
+	}
+	if _, cmd := app.selectedFiles.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd != nil {
+		t.Error("Expected nothing to copy without selected files")
+	}
+}
+
+func TestSelectedFilesScrolling(t *testing.T) {
+	var paths []string
+	for i := range 30 {
+		paths = append(paths, fmt.Sprintf("/project/file%02d.go", i))
+	}
+	model := newTestSelectedFiles(paths...)
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	model.configManager = cfgManager
+	model.SetSize(60, 15)
+
+	visible := func() string {
+		t.Helper()
+		view := model.View()
+		if lines := lipgloss.Height(view); lines != 15 {
+			t.Fatalf("Expected the panel to fill 15 lines, got %d:\n%s", lines, view)
+		}
+		return view
+	}
+	if view := visible(); !strings.Contains(view, "file00.go") || strings.Contains(view, "file29.go") {
+		t.Errorf("Expected the list to start at the first file, got:\n%s", view)
+	}
+
+	// Moving the cursor past the bottom scrolls the list
+	for range 20 {
+		model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if view := visible(); !strings.Contains(view, "▶ 📄 /project/file20.go") || strings.Contains(view, "file00.go") {
+		t.Errorf("Expected the list scrolled to the cursor, got:\n%s", view)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if view := visible(); !strings.Contains(view, "▶ 📄 /project/file29.go") {
+		t.Errorf("Expected end to show the last file, got:\n%s", view)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	page := model.listHeight()
+	if want := paths[29-page]; model.files[model.cursor].Path != want {
+		t.Errorf("Expected page up to move the cursor to %s, got %s", want, model.files[model.cursor].Path)
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if model.files[model.cursor].Path != paths[29] {
+		t.Errorf("Expected page down to move the cursor back to the last file, got %s", model.files[model.cursor].Path)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if view := visible(); !strings.Contains(view, "▶ 📄 /project/file00.go") || strings.Contains(view, "file29.go") {
+		t.Errorf("Expected home to scroll back to the first file, got:\n%s", view)
+	}
+}
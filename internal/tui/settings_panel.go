@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BindingChangedMsg is sent when a new key was chosen for a key binding
+type BindingChangedMsg struct {
+	Name string // Binding name, as in config.BindingFields
+	Key  string
+}
+
+// SettingsPanelModel lists the key bindings and captures new keys for them
+type SettingsPanelModel struct {
+	bindings  map[string]string // Current key of each binding, by name
+	cursor    int
+	capturing bool // The next key press becomes the binding under the cursor
+	width     int
+	height    int
+	visible   bool
+	theme     Theme
+}
+
+// NewSettingsPanelModel creates a new settings panel model
+func NewSettingsPanelModel(theme Theme) *SettingsPanelModel {
+	return &SettingsPanelModel{theme: theme}
+}
+
+// SetSize updates the panel dimensions
+func (m *SettingsPanelModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the panel with the current key of each binding
+func (m *SettingsPanelModel) Show(bindings map[string]string) {
+	m.bindings = bindings
+	m.cursor = 0
+	m.capturing = false
+	m.visible = true
+}
+
+// SetBindings updates the keys shown, e.g. after a binding was changed
+func (m *SettingsPanelModel) SetBindings(bindings map[string]string) {
+	m.bindings = bindings
+}
+
+// Hide closes the panel
+func (m *SettingsPanelModel) Hide() {
+	m.visible = false
+	m.capturing = false
+}
+
+// IsVisible returns whether the panel is currently shown
+func (m *SettingsPanelModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the settings panel
+func (m *SettingsPanelModel) Update(msg tea.Msg) (*SettingsPanelModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	name := config.BindingFields[m.cursor].Name
+
+	if m.capturing {
+		m.capturing = false
+		if keyMsg.String() == "esc" {
+			return m, nil
+		}
+		key := keyMsg.String()
+		return m, func() tea.Msg {
+			return BindingChangedMsg{Name: name, Key: key}
+		}
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "ctrl+c":
+		m.Hide()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(config.BindingFields)-1 {
+			m.cursor++
+		}
+	case "enter":
+		m.capturing = true
+	case "r":
+		// Reset to the default, which is also the way back to keys like esc that can't be captured
+		key := config.DefaultBinding(name)
+		return m, func() tea.Msg {
+			return BindingChangedMsg{Name: name, Key: key}
+		}
+	}
+	return m, nil
+}
+
+// View renders the settings panel
+func (m *SettingsPanelModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := 64
+	dialogHeight := int(float64(m.height) * 0.8)
+
+	// Lines available for bindings: minus borders, padding, title, spacing and help
+	listHeight := dialogHeight - 8
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Key Bindings"))
+	b.WriteString("\n\n")
+
+	// Keep the cursor within the visible window of bindings
+	start := 0
+	if m.cursor >= listHeight {
+		start = m.cursor - listHeight + 1
+	}
+	end := min(start+listHeight, len(config.BindingFields))
+
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	for i := start; i < end; i++ {
+		field := config.BindingFields[i]
+		key := m.bindings[field.Name]
+		if i == m.cursor && m.capturing {
+			key = "press a key…"
+		}
+		text := fmt.Sprintf("%-32s %s", field.Description, keyStyle.Render(key))
+		line := "  " + text
+		if i == m.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.theme.CursorFG)).
+				Bold(true).
+				Render("▶ " + text)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	if m.capturing {
+		b.WriteString(helpStyle.Render("Press the new key • Esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: change • r: reset to default • Esc: close"))
+	}
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
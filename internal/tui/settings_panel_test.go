@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSettingsPanelCapturesKeys(t *testing.T) {
+	panel := NewSettingsPanelModel(DefaultTheme())
+	panel.SetSize(100, 40)
+	panel.Show(map[string]string{"quick_open": "ctrl+p"})
+	name := config.BindingFields[0].Name
+
+	panel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(panel.View(), "press a key") {
+		t.Errorf("Expected the panel to wait for a key, got:\n%s", panel.View())
+	}
+	_, cmd := panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}, Alt: true})
+	if cmd == nil {
+		t.Fatal("Expected the captured key to be sent")
+	}
+	if msg, ok := cmd().(BindingChangedMsg); !ok || msg.Name != name || msg.Key != "alt+p" {
+		t.Errorf("Expected BindingChangedMsg for %s with alt+p, got %#v", name, cmd())
+	}
+
+	// Esc cancels capturing instead of becoming the binding
+	panel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, cmd := panel.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || !panel.IsVisible() {
+		t.Error("Expected esc to cancel capturing and keep the panel open")
+	}
+
+	_, cmd = panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if msg, ok := cmd().(BindingChangedMsg); !ok || msg.Key != config.DefaultBinding(name) {
+		t.Errorf("Expected r to reset to the default, got %#v", cmd())
+	}
+
+	panel.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if panel.IsVisible() {
+		t.Error("Expected esc to close the panel")
+	}
+}
+
+func TestBindingChangedMsgUpdatesSettings(t *testing.T) {
+	// Keep the written settings out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	app := createTestApp(t)
+
+	_, cmd := app.Update(BindingChangedMsg{Name: "history", Key: app.settingsManager.GetQuickOpenKey()})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != ErrorAlert {
+		t.Errorf("Expected a conflict error, got %#v", cmd())
+	}
+
+	_, cmd = app.Update(BindingChangedMsg{Name: "history", Key: "alt+h"})
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected the binding to be saved, got %#v", cmd())
+	}
+	if got := app.settingsManager.GetHistoryKey(); got != "alt+h" {
+		t.Errorf("Expected the history key to be alt+h, got %q", got)
+	}
+}
+
+func TestAppOpensSettingsPanelWithDefaultKey(t *testing.T) {
+	app := NewApp(t.TempDir(), WithConfigManager(config.NewMemoryManager()), WithSettingsManager(config.NewDefaultSettingsManager()))
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{','}, Alt: true})
+	if !app.settingsPanel.IsVisible() {
+		t.Error("Expected alt+, to open the settings panel")
+	}
+}
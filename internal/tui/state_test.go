@@ -1,10 +1,15 @@
 package tui
 
 import (
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/persona"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -56,19 +61,74 @@ func createTestApp(t *testing.T) *App {
 		t.Fatalf("Failed to create config manager: %v", err)
 	}
 
-	settingsManager, err := config.NewSettingsManager()
+	settingsManager, err := config.NewSettingsManager("")
 	if err != nil {
 		t.Fatalf("Failed to create settings manager: %v", err)
 	}
 
 	workspace := &config.WorkspaceState{
 		Path:           targetDir,
-		SelectedFiles:  []string{},
+		SelectedFiles:  []config.SelectedFileState{},
 		ChatInput:      "",
 		ActivePersonas: []string{"default"},
 	}
 
-	return NewApp(targetDir, cfgManager, settingsManager, workspace)
+	return NewApp(targetDir, WithConfigManager(cfgManager), WithSettingsManager(settingsManager), WithWorkspace(workspace))
+}
+
+func TestNewAppOptions(t *testing.T) {
+	t.Setenv(config.ConfigDirEnv, t.TempDir())
+	targetDir := t.TempDir()
+
+	cfgManager := config.NewMemoryManager()
+	settingsManager := config.NewDefaultSettingsManager()
+	workspace := &config.WorkspaceState{Path: targetDir, ChatInput: "review this", ActivePersonas: []string{"default"}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	personaManager := persona.NewManager(targetDir)
+
+	app := NewApp(targetDir,
+		WithConfigManager(cfgManager),
+		WithSettingsManager(settingsManager),
+		WithWorkspace(workspace),
+		WithDebugLogger(logger),
+		WithPersonaManager(personaManager),
+	)
+	if app.configManager != cfgManager {
+		t.Error("Expected the config manager option to be applied")
+	}
+	if app.settingsManager != settingsManager {
+		t.Error("Expected the settings manager option to be applied")
+	}
+	if app.workspace != workspace || app.chat.textarea.Value() != "review this" {
+		t.Error("Expected the workspace option to be applied")
+	}
+	if app.debugLogger != logger || app.fileTree.debugLogger == nil {
+		t.Error("Expected the debug logger option to be applied")
+	}
+	if app.personaManager != personaManager {
+		t.Error("Expected the persona manager option to be applied")
+	}
+}
+
+func TestNewAppWithoutOptions(t *testing.T) {
+	t.Setenv(config.ConfigDirEnv, t.TempDir())
+	targetDir := t.TempDir()
+
+	app := NewApp(targetDir)
+	if app.configManager == nil || app.settingsManager == nil || app.personaManager == nil {
+		t.Fatal("Expected the managers to be created")
+	}
+	if app.workspace == nil || app.workspace.Path != targetDir {
+		t.Fatalf("Expected the workspace saved for %s, got %+v", targetDir, app.workspace)
+	}
+
+	// The app starts and renders
+	app.fileTree.Init()
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if app.View() == "" {
+		t.Error("Expected the app to render")
+	}
+	app.Close()
 }
 
 // TestStateCommandGeneration tests that state commands are generated correctly
@@ -484,6 +544,43 @@ func TestStateValidationHelpers(t *testing.T) {
 	})
 }
 
+// TestInvariantCheckInDebugMode tests that an update leaving the state inconsistent is alerted in debug mode
+func TestInvariantCheckInDebugMode(t *testing.T) {
+	app := createTestApp(t)
+	// An update that doesn't otherwise produce a command
+	update := func() tea.Cmd {
+		_, cmd := app.Update(spinner.TickMsg{})
+		return cmd
+	}
+
+	// Deliberately break an invariant
+	app.width = -1
+	if cmd := update(); cmd != nil {
+		t.Error("Expected no invariant check outside debug mode")
+	}
+
+	app.debugMode = true
+	cmd := update()
+	if cmd == nil {
+		t.Fatal("Expected an alert for the broken invariant")
+	}
+	msg, ok := cmd().(NotificationMsg)
+	if !ok || msg.AlertType != ErrorAlert || !strings.Contains(msg.Message, "invalid layout dimensions") {
+		t.Errorf("Expected an error alert naming the violation, got %+v", msg)
+	}
+
+	// The same violation is alerted once
+	if cmd := update(); cmd != nil {
+		t.Error("Expected the violation to be alerted only once")
+	}
+	app.width = 80
+	update()
+	app.width = -1
+	if cmd := update(); cmd == nil {
+		t.Error("Expected a new violation to be alerted again")
+	}
+}
+
 // Benchmark state command generation performance
 func BenchmarkStateCommandGeneration(b *testing.B) {
 	app := createTestApp(&testing.T{})
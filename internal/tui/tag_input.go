@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TagsMsg is sent when the user confirms the tags of a selected file.
+// Empty Tags clears them.
+type TagsMsg struct {
+	Path string
+	Tags []string
+}
+
+// TagInputModel edits the tags of a selected file as a comma-separated list
+type TagInputModel struct {
+	input   textinput.Model
+	path    string
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewTagInputModel creates a new tag input model
+func NewTagInputModel(theme Theme) *TagInputModel {
+	ti := textinput.New()
+	ti.Prompt = "Tags: "
+	ti.Placeholder = "context, focus, reference"
+	ti.CharLimit = 200
+	ti.Width = 40
+
+	return &TagInputModel{
+		input: ti,
+		theme: theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *TagInputModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show displays the dialog for the file at path, pre-filled with its current tags
+func (m *TagInputModel) Show(path string, tags []string) tea.Cmd {
+	m.visible = true
+	m.path = path
+	m.input.Reset()
+	m.input.SetValue(strings.Join(tags, ", "))
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+// Hide closes the dialog
+func (m *TagInputModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *TagInputModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the tag input
+func (m *TagInputModel) Update(msg tea.Msg) (*TagInputModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			m.Hide()
+			path := m.path
+			tags := ParseTags(m.input.Value())
+			return m, func() tea.Msg {
+				return TagsMsg{Path: path, Tags: tags}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// ParseTags splits a comma-separated list of tags, dropping blanks and duplicates
+func ParseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// View renders the tag input
+func (m *TagInputModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Tags: " + filepath.Base(m.path)))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("Comma-separated • Enter: apply (empty: no tags) • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), 56, 9, m.width, m.height)
+}
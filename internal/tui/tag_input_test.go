@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"focus", []string{"focus"}},
+		{" focus , context ", []string{"focus", "context"}},
+		{"focus,,focus, ", []string{"focus"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTags(tt.value); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseTags(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestTagInput(t *testing.T) {
+	m := NewTagInputModel(DefaultTheme())
+	m.Show("/project/main.go", []string{"focus"})
+	if m.input.Value() != "focus" {
+		t.Errorf("Expected the input pre-filled with the current tags, got %q", m.input.Value())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(", context")})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsVisible() {
+		t.Error("Expected the dialog to close after confirming")
+	}
+	if cmd == nil {
+		t.Fatal("Expected a tags command")
+	}
+	msg, ok := cmd().(TagsMsg)
+	if !ok || msg.Path != "/project/main.go" || !reflect.DeepEqual(msg.Tags, []string{"focus", "context"}) {
+		t.Errorf("Expected TagsMsg with focus and context, got %#v", cmd())
+	}
+
+	m.Show("/project/main.go", nil)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || m.IsVisible() {
+		t.Error("Expected esc to close the dialog without a command")
+	}
+}
+
+func TestTagsMsgSavesWorkspace(t *testing.T) {
+	app := createTestApp(t)
+	app.selectedFiles.AddFile("main.go", "/project/main.go")
+
+	app.Update(TagsMsg{Path: "/project/main.go", Tags: []string{"focus"}})
+
+	if len(app.workspace.SelectedFiles) != 1 || !reflect.DeepEqual(app.workspace.SelectedFiles[0].Tags, []string{"focus"}) {
+		t.Errorf("Expected the tags to be saved in the workspace, got %v", app.workspace.SelectedFiles)
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"coding-prompts-tui/internal/filesystem"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TextDialogModel shows read-only text under a title, scrollable with the viewport keys
+type TextDialogModel struct {
+	viewport viewport.Model
+	title    string
+	content  string
+	width    int
+	height   int
+	visible  bool
+	theme    Theme
+}
+
+// NewTextDialogModel creates a new text dialog model
+func NewTextDialogModel(theme Theme) *TextDialogModel {
+	return &TextDialogModel{
+		viewport: viewport.New(0, 0),
+		theme:    theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *TextDialogModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Leave room for the border, padding, title and help lines
+	m.viewport.Width = m.dialogWidth() - 4
+	m.viewport.Height = m.dialogHeight() - 6
+	m.setContent()
+}
+
+// dialogWidth returns the width of the dialog (80% of the screen)
+func (m *TextDialogModel) dialogWidth() int {
+	return int(float64(m.width) * 0.8)
+}
+
+// dialogHeight returns the height of the dialog (80% of the screen)
+func (m *TextDialogModel) dialogHeight() int {
+	return int(float64(m.height) * 0.8)
+}
+
+// setContent wraps the content to the viewport width
+func (m *TextDialogModel) setContent() {
+	m.viewport.SetContent(lipgloss.NewStyle().Width(m.viewport.Width).Render(m.content))
+}
+
+// Show displays the dialog with the given title and content
+func (m *TextDialogModel) Show(title, content string) {
+	m.title = title
+	m.content = content
+	m.visible = true
+	m.setContent()
+	m.viewport.GotoTop()
+}
+
+// Hide closes the dialog
+func (m *TextDialogModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the dialog is currently shown
+func (m *TextDialogModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the text dialog
+func (m *TextDialogModel) Update(msg tea.Msg) (*TextDialogModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+c", "q", "enter", "esc":
+			m.Hide()
+			return m, nil
+		}
+
+		// Pass scroll controls to viewport
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(keyMsg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// View renders the text dialog
+func (m *TextDialogModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	titleStyle := m.theme.titleStyle()
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n\n")
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: scroll • Esc: close"))
+
+	return RenderDialog(m.theme, b.String(), m.dialogWidth(), m.dialogHeight(), m.width, m.height)
+}
+
+// formatValidationReport lists each ignore pattern with what it matches in the project
+func formatValidationReport(results []filesystem.ValidationResult) string {
+	if len(results) == 0 {
+		return "No .gitignore or .promptignore patterns found; the built-in defaults apply."
+	}
+
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	unmatchedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	exampleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	var b strings.Builder
+	file := ""
+	for _, result := range results {
+		if result.File != file {
+			if file != "" {
+				b.WriteString("\n")
+			}
+			file = result.File
+			b.WriteString(lipgloss.NewStyle().Bold(true).Render(file))
+			b.WriteString("\n")
+		}
+
+		line := fmt.Sprintf("%4d  %s", result.Line, result.Pattern)
+		switch {
+		case result.Err != nil:
+			b.WriteString(errorStyle.Render(line + " — invalid: " + result.Err.Error()))
+		case !result.Matched():
+			b.WriteString(unmatchedStyle.Render(line + " — no matches"))
+		default:
+			noun := "matches"
+			if result.Matches == 1 {
+				noun = "match"
+			}
+			b.WriteString(fmt.Sprintf("%s — %d %s", line, result.Matches, noun))
+			for _, example := range result.Examples {
+				b.WriteString("\n")
+				b.WriteString(exampleStyle.Render("        " + example))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
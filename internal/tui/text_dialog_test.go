@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFormatValidationReport(t *testing.T) {
+	report := formatValidationReport([]filesystem.ValidationResult{
+		{File: ".gitignore", Line: 1, Pattern: "*.log", Matches: 2, Examples: []string{"app.log", "logs/debug.log"}},
+		{File: ".gitignore", Line: 2, Pattern: "*.bak"},
+		{File: ".gitignore", Line: 3, Pattern: `foo\`, Err: errors.New("ends with a backslash")},
+	})
+
+	for _, want := range []string{"*.log — 2 matches", "logs/debug.log", "*.bak — no matches", "invalid: ends with a backslash"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestAppShowsGitignoreReportWithDefaultKey(t *testing.T) {
+	app := NewApp(t.TempDir(), WithConfigManager(config.NewMemoryManager()), WithSettingsManager(config.NewDefaultSettingsManager()))
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}, Alt: true})
+	if !app.textDialog.IsVisible() {
+		t.Error("Expected alt+i to show the ignore pattern report")
+	}
+}
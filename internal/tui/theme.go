@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"coding-prompts-tui/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors of the interface, as terminal color codes or hex values
+type Theme struct {
+	FocusBorder  string // Border of the focused panel and the chat title
+	NormalBorder string // Border of the other panels
+	CursorFG     string // Item under the cursor in lists
+	SelectedFG   string // Selected files and the preview title
+	TitleFG      string // Dialog titles and borders
+}
+
+// DefaultTheme returns the standard colors
+func DefaultTheme() Theme {
+	return Theme{
+		FocusBorder:  "69",
+		NormalBorder: "240",
+		CursorFG:     "69",
+		SelectedFG:   "10",
+		TitleFG:      "228",
+	}
+}
+
+// HighContrastTheme returns bright colors that stand out on dark and light terminals
+func HighContrastTheme() Theme {
+	return Theme{
+		FocusBorder:  "11",
+		NormalBorder: "15",
+		CursorFG:     "14",
+		SelectedFG:   "10",
+		TitleFG:      "15",
+	}
+}
+
+// ThemeFromSettings returns the preset named in settings with the colors set
+// there replacing its own
+func ThemeFromSettings(settings config.ThemeSettings) Theme {
+	theme := DefaultTheme()
+	if settings.Preset == "high-contrast" {
+		theme = HighContrastTheme()
+	}
+
+	override := func(color *string, value string) {
+		if value != "" {
+			*color = value
+		}
+	}
+	override(&theme.FocusBorder, settings.FocusBorder)
+	override(&theme.NormalBorder, settings.NormalBorder)
+	override(&theme.CursorFG, settings.CursorFG)
+	override(&theme.SelectedFG, settings.SelectedFG)
+	override(&theme.TitleFG, settings.TitleFG)
+	return theme
+}
+
+// titleStyle returns the style of dialog titles
+func (t Theme) titleStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.TitleFG))
+}
@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"coding-prompts-tui/internal/config"
+)
+
+func TestThemeFromSettings(t *testing.T) {
+	if theme := ThemeFromSettings(config.ThemeSettings{Preset: "default"}); theme != DefaultTheme() {
+		t.Errorf("Expected the default theme, got %+v", theme)
+	}
+	if theme := ThemeFromSettings(config.ThemeSettings{Preset: "high-contrast"}); theme != HighContrastTheme() {
+		t.Errorf("Expected the high contrast theme, got %+v", theme)
+	}
+
+	// Colors set in the settings replace the preset's
+	theme := ThemeFromSettings(config.ThemeSettings{Preset: "high-contrast", CursorFG: "#00ff00"})
+	expected := HighContrastTheme()
+	expected.CursorFG = "#00ff00"
+	if theme != expected {
+		t.Errorf("Expected %+v, got %+v", expected, theme)
+	}
+}
+
+func TestAppPassesThemeToModels(t *testing.T) {
+	app := createTestApp(t)
+	theme := app.selectedFiles.theme
+	if app.chat.theme != theme || app.preview.theme != theme || app.promptDialog.theme != theme || app.personaDialog.promptDialog.theme != theme {
+		t.Error("Expected the chat, preview and prompt dialogs to use the app theme")
+	}
+}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// modifiedSinceLayout is how the modified-since filter time is shown
+const modifiedSinceLayout = "2006-01-02 15:04"
+
+// ModifiedSinceMsg is sent when the user sets the time files must be modified
+// since to be included in prompts. A nil Since removes the filter.
+type ModifiedSinceMsg struct {
+	Since *time.Time
+}
+
+// ParseModifiedSince parses an RFC3339 time such as 2024-01-02T15:04:05Z, or a
+// number of hours before now such as 24h. An empty value returns nil.
+func ParseModifiedSince(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if strings.HasSuffix(value, "h") {
+		ago, err := time.ParseDuration(value)
+		if err != nil || ago < 0 {
+			return nil, fmt.Errorf("invalid number of hours %q", value)
+		}
+		since := now.Add(-ago)
+		return &since, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected a time such as 2024-01-02T15:04:05Z or hours such as 24h")
+	}
+	return &since, nil
+}
+
+// TimeInputModel is a small input for the time files must be modified since
+// to be included in prompts
+type TimeInputModel struct {
+	input   textinput.Model
+	err     string
+	width   int
+	height  int
+	visible bool
+	theme   Theme
+}
+
+// NewTimeInputModel creates a new time input model
+func NewTimeInputModel(theme Theme) *TimeInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "24h or 2024-01-02T15:04:05Z"
+	ti.Prompt = "Modified since: "
+
+	return &TimeInputModel{
+		input: ti,
+		theme: theme,
+	}
+}
+
+// SetSize updates the dialog dimensions
+func (m *TimeInputModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = int(float64(width)*0.6) - 22
+}
+
+// Show displays the input filled in with the current filter time, if any
+func (m *TimeInputModel) Show(current *time.Time) tea.Cmd {
+	m.visible = true
+	m.err = ""
+	m.input.Reset()
+	if current != nil {
+		m.input.SetValue(current.Format(time.RFC3339))
+	}
+	return m.input.Focus()
+}
+
+// Hide closes the input
+func (m *TimeInputModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the input is currently shown
+func (m *TimeInputModel) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the time input
+func (m *TimeInputModel) Update(msg tea.Msg) (*TimeInputModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "ctrl+c":
+			m.Hide()
+			return m, nil
+		case "enter":
+			since, err := ParseModifiedSince(m.input.Value(), time.Now())
+			if err != nil {
+				m.err = err.Error()
+				return m, nil
+			}
+			m.Hide()
+			return m, func() tea.Msg {
+				return ModifiedSinceMsg{Since: since}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the time input
+func (m *TimeInputModel) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := int(float64(m.width) * 0.6)
+	dialogHeight := 10
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Only Include Files Modified Since"))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	if m.err != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		b.WriteString(errorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true)
+	b.WriteString(helpStyle.Render("Enter: apply (empty: include all files) • Esc: cancel"))
+
+	return RenderDialog(m.theme, b.String(), dialogWidth, dialogHeight, m.width, m.height)
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseModifiedSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{" 1.5h ", now.Add(-90 * time.Minute)},
+		{"0h", now},
+		{"2026-03-01T08:30:00Z", time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)},
+		{"2026-03-01T08:30:00+02:00", time.Date(2026, 3, 1, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		since, err := ParseModifiedSince(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseModifiedSince(%q) returned an unexpected error: %v", tt.value, err)
+			continue
+		}
+		if since == nil || !since.Equal(tt.want) {
+			t.Errorf("ParseModifiedSince(%q) = %v, expected %v", tt.value, since, tt.want)
+		}
+	}
+
+	if since, err := ParseModifiedSince("  ", now); err != nil || since != nil {
+		t.Errorf("Expected an empty value to remove the filter, got %v, %v", since, err)
+	}
+	for _, value := range []string{"-2h", "xh", "yesterday", "2026-03-01", "24"} {
+		if _, err := ParseModifiedSince(value, now); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestTimeInputSendsFilter(t *testing.T) {
+	input := NewTimeInputModel(DefaultTheme())
+	input.SetSize(100, 40)
+	input.Show(nil)
+
+	input.input.SetValue("soon")
+	if _, cmd := input.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !input.IsVisible() {
+		t.Fatal("Expected an invalid time to keep the input open")
+	}
+	if !strings.Contains(input.View(), "expected a time") {
+		t.Errorf("Expected the error in the input, got:\n%s", input.View())
+	}
+
+	input.input.SetValue("2026-03-01T08:30:00Z")
+	_, cmd := input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(ModifiedSinceMsg)
+	if !ok || msg.Since == nil || !msg.Since.Equal(time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the filter time to be sent, got %+v", msg)
+	}
+
+	// Reopening shows the current filter, and clearing it removes the filter
+	input.Show(msg.Since)
+	if input.input.Value() != "2026-03-01T08:30:00Z" {
+		t.Errorf("Expected the current filter in the input, got %q", input.input.Value())
+	}
+	input.input.SetValue("")
+	_, cmd = input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(ModifiedSinceMsg); !ok || msg.Since != nil {
+		t.Errorf("Expected an empty input to remove the filter, got %+v", msg)
+	}
+}
+
+func TestAppSetsModifiedSinceFilter(t *testing.T) {
+	app := createTestApp(t)
+	since := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+
+	_, cmd := app.Update(ModifiedSinceMsg{Since: &since})
+	if app.workspace.ModifiedSinceFilter == nil || !app.workspace.ModifiedSinceFilter.Equal(since) {
+		t.Errorf("Expected the filter in the workspace, got %v", app.workspace.ModifiedSinceFilter)
+	}
+	if opts := app.buildOptions(); opts.ModifiedSince == nil || !opts.ModifiedSince.Equal(since) {
+		t.Errorf("Expected the filter in the build options, got %v", opts.ModifiedSince)
+	}
+	if msg, ok := cmd().(NotificationMsg); !ok || msg.AlertType != InfoAlert {
+		t.Errorf("Expected an info alert, got %#v", msg)
+	}
+
+	app.Update(ModifiedSinceMsg{})
+	if app.workspace.ModifiedSinceFilter != nil || app.buildOptions().ModifiedSince != nil {
+		t.Error("Expected the filter to be removed")
+	}
+}
+
+func TestFileTreeDimsUnmodifiedFiles(t *testing.T) {
+	since := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	model := NewFileTreeModel("/project", []string{}, DefaultTheme())
+	model.rootNode = &filesystem.FileNode{
+		Name: "project", Path: "/project", IsDir: true,
+		Children: []*filesystem.FileNode{
+			{Name: "new.go", Path: "/project/new.go", ModTime: since.Add(time.Hour)},
+			{Name: "old.go", Path: "/project/old.go", ModTime: since.Add(-time.Hour)},
+		},
+	}
+	model.refreshItems()
+	model.SetSize(80, 20)
+
+	if view := model.View(); strings.Contains(view, "(unmodified)") || strings.Contains(view, "modified since") {
+		t.Errorf("Expected no filter without one set, got:\n%s", view)
+	}
+
+	model.SetModifiedSince(&since)
+	view := model.View()
+	if !strings.Contains(view, "old.go (unmodified)") || strings.Contains(view, "new.go (unmodified)") {
+		t.Errorf("Expected only old.go to be marked, got:\n%s", view)
+	}
+	if !strings.Contains(view, "modified since "+since.Local().Format(modifiedSinceLayout)) {
+		t.Errorf("Expected the filter in the header, got:\n%s", view)
+	}
+}
+
+func TestAppOpensTimeInputWithDefaultKey(t *testing.T) {
+	app := NewApp(t.TempDir(), WithConfigManager(config.NewMemoryManager()), WithSettingsManager(config.NewDefaultSettingsManager()))
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	if !app.timeInput.IsVisible() {
+		t.Error("Expected alt+t to open the modified-since input")
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"coding-prompts-tui/internal/clipboard"
+	"coding-prompts-tui/internal/filesystem"
+	"coding-prompts-tui/internal/prompt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PromptRegeneratedMsg is sent after watch mode regenerated and copied the prompt
+type PromptRegeneratedMsg struct {
+	Path   string // File whose change triggered the regeneration
+	Tokens int
+	Err    error
+}
+
+// waitForFileChange returns a command that waits for the next watched file change
+func waitForFileChange(watcher *filesystem.FileWatcher) tea.Cmd {
+	return func() tea.Msg {
+		return <-watcher.Events()
+	}
+}
+
+// waitForDirChange returns a command that waits for the next change to the watched directory tree
+func waitForDirChange(watcher *filesystem.DirWatcher) tea.Cmd {
+	return func() tea.Msg {
+		return <-watcher.Events()
+	}
+}
+
+// PersonasChangedMsg reports the personas found after persona files were added,
+// removed or changed while the app is running
+type PersonasChangedMsg struct {
+	Personas []string
+}
+
+// waitForPersonasChange returns a command that waits for the next list of
+// personas from the personas directory watcher. It delivers no message once the
+// watcher is stopped.
+func waitForPersonasChange(changes <-chan []string) tea.Cmd {
+	return func() tea.Msg {
+		personas, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return PersonasChangedMsg{Personas: personas}
+	}
+}
+
+// regeneratePrompt returns a command that rebuilds the prompt and copies it to the clipboard
+func regeneratePrompt(changedPath, targetDir string, files []string, userPrompt string, personas []string, format prompt.OutputFormat, opts prompt.BuildOptions, clip clipboard.Backend) tea.Cmd {
+	return func() tea.Msg {
+		generatedPrompt, err := prompt.BuildWithOptions(targetDir, files, userPrompt, personas, format, opts)
+		if err != nil {
+			return PromptRegeneratedMsg{Path: changedPath, Err: err}
+		}
+		if err := clip.WriteAll(generatedPrompt); err != nil {
+			return PromptRegeneratedMsg{Path: changedPath, Err: err}
+		}
+		return PromptRegeneratedMsg{Path: changedPath, Tokens: prompt.EstimateTokens(generatedPrompt)}
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OpenWorkspaceFunc creates the App for a workspace directory
+type OpenWorkspaceFunc func(path string) (*App, error)
+
+// ShowWorkspaceListMsg asks for the recent workspaces list to be shown
+type ShowWorkspaceListMsg struct{}
+
+// WorkspaceListModel lists recently opened workspaces and hosts the App of the
+// one that is open. It is the root model of the program.
+type WorkspaceListModel struct {
+	configManager *config.ConfigManager
+	openWorkspace OpenWorkspaceFunc
+	workspaces    []config.WorkspaceState
+	sortMode      config.WorkspaceSortMode
+	cursor        int
+	width         int
+	height        int
+	app           *App // The open workspace, nil until one is opened
+	listVisible   bool
+	err           string
+	theme         Theme
+}
+
+// NewWorkspaceListModel creates a workspace list that opens workspaces with openWorkspace
+func NewWorkspaceListModel(configManager *config.ConfigManager, openWorkspace OpenWorkspaceFunc, theme Theme) *WorkspaceListModel {
+	m := &WorkspaceListModel{
+		configManager: configManager,
+		openWorkspace: openWorkspace,
+		listVisible:   true,
+		theme:         theme,
+	}
+	m.refresh()
+	return m
+}
+
+// Open opens the workspace at path, replacing the current one. It is used to
+// open a workspace given on the command line before the program starts.
+func (m *WorkspaceListModel) Open(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("workspace %s is not available: %w", path, err)
+	}
+
+	app, err := m.openWorkspace(path)
+	if err != nil {
+		return err
+	}
+
+	if m.app != nil {
+		m.app.Close()
+	}
+	m.app = app
+	m.listVisible = false
+	m.err = ""
+	return nil
+}
+
+// Close releases the resources of the open workspace
+func (m *WorkspaceListModel) Close() error {
+	if m.app == nil {
+		return nil
+	}
+	return m.app.Close()
+}
+
+// refresh reloads the recent workspaces from the config
+func (m *WorkspaceListModel) refresh() {
+	m.workspaces = config.SortWorkspacesBy(m.configManager.GetRecentWorkspaces(), m.sortMode)
+	if m.cursor >= len(m.workspaces) {
+		m.cursor = len(m.workspaces) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// Init initializes the open workspace, if any
+func (m *WorkspaceListModel) Init() tea.Cmd {
+	if m.app != nil {
+		return m.app.Init()
+	}
+	return nil
+}
+
+// Update handles messages for the workspace list and forwards the rest to the open workspace
+func (m *WorkspaceListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case ShowWorkspaceListMsg:
+		m.refresh()
+		m.cursor = 0
+		m.err = ""
+		m.listVisible = true
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.listVisible {
+			return m, m.handleKey(msg)
+		}
+	}
+
+	if m.app == nil {
+		return m, nil
+	}
+	model, cmd := m.app.Update(msg)
+	m.app = model.(*App)
+	return m, cmd
+}
+
+// handleKey handles key presses while the list is shown
+func (m *WorkspaceListModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.workspaces)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if len(m.workspaces) == 0 {
+			return nil
+		}
+		if err := m.Open(m.workspaces[m.cursor].Path); err != nil {
+			m.err = err.Error()
+			return nil
+		}
+		cmds := []tea.Cmd{m.app.Init()}
+		if m.width > 0 && m.height > 0 {
+			width, height := m.width, m.height
+			cmds = append(cmds, func() tea.Msg {
+				return tea.WindowSizeMsg{Width: width, Height: height}
+			})
+		}
+		return tea.Batch(cmds...)
+	case "s":
+		// Cycle the order the workspaces are listed in
+		m.sortMode = m.sortMode.Next()
+		m.refresh()
+		m.cursor = 0
+	case "d", "delete", "x":
+		// Clear the workspace under the cursor from the list
+		if len(m.workspaces) == 0 {
+			return nil
+		}
+		if m.app != nil && m.workspaces[m.cursor].Path == m.app.targetDir {
+			m.err = "the open workspace can't be cleared"
+			return nil
+		}
+		if err := m.configManager.RemoveWorkspace(m.workspaces[m.cursor].Path); err != nil {
+			m.err = err.Error()
+			return nil
+		}
+		m.refresh()
+	case "esc":
+		// Go back to the open workspace
+		if m.app != nil {
+			m.listVisible = false
+		}
+	case "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
+
+// View renders the workspace list, or the open workspace
+func (m *WorkspaceListModel) View() string {
+	if !m.listVisible && m.app != nil {
+		return m.app.View()
+	}
+
+	var b strings.Builder
+	titleStyle := m.theme.titleStyle()
+	b.WriteString(titleStyle.Render("Recent Workspaces"))
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" (by " + m.sortMode.String() + ")"))
+	b.WriteString("\n\n")
+
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	if len(m.workspaces) == 0 {
+		b.WriteString(mutedStyle.Render("No recent workspaces. Run prompter <directory> to open one."))
+		b.WriteString("\n")
+	}
+
+	var currentPath string
+	if m.app != nil {
+		currentPath = m.app.targetDir
+	}
+	for i, ws := range m.workspaces {
+		line := "  " + ws.Path
+		style := lipgloss.NewStyle()
+		if i == m.cursor {
+			line = "▶ " + ws.Path
+			style = style.Foreground(lipgloss.Color(m.theme.CursorFG)).Bold(true)
+		}
+		b.WriteString(style.Render(line))
+		if ws.Path == currentPath {
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(" (open)"))
+		}
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("  %s  %d files", ws.LastAccessed.Format("2006-01-02 15:04"), len(ws.SelectedFiles))))
+		b.WriteString("\n")
+	}
+
+	if m.err != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.err))
+		b.WriteString("\n")
+	}
+
+	help := "↑/↓: navigate • Enter: open • s: sort • d: clear from list • q: quit"
+	if m.app != nil {
+		help = "↑/↓: navigate • Enter: open • s: sort • d: clear from list • Esc: back • q: quit"
+	}
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Italic(true).Render(help))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"coding-prompts-tui/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestWorkspaceList creates a workspace list over an isolated config holding
+// two workspaces, the second of which was accessed most recently
+func newTestWorkspaceList(t *testing.T) (*WorkspaceListModel, string, string) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+
+	older, newer := t.TempDir(), t.TempDir()
+	cfgManager.GetWorkspace(older).LastAccessed = time.Now().Add(-time.Hour)
+	cfgManager.GetWorkspace(newer)
+
+	openWorkspace := func(path string) (*App, error) {
+		settingsManager, err := config.NewSettingsManager("")
+		if err != nil {
+			return nil, err
+		}
+		return NewApp(path, WithConfigManager(cfgManager), WithSettingsManager(settingsManager), WithWorkspace(cfgManager.GetWorkspace(path))), nil
+	}
+	return NewWorkspaceListModel(cfgManager, openWorkspace, DefaultTheme()), older, newer
+}
+
+func TestWorkspaceListOpensWorkspace(t *testing.T) {
+	m, older, newer := newTestWorkspaceList(t)
+
+	if len(m.workspaces) != 2 || m.workspaces[0].Path != newer || m.workspaces[1].Path != older {
+		t.Fatalf("Expected workspaces most recent first, got %+v", m.workspaces)
+	}
+	if !strings.Contains(m.View(), older) {
+		t.Error("Expected the list to show the workspace paths")
+	}
+
+	// Open the second (older) workspace
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the opened workspace to be initialized")
+	}
+	if m.app == nil || m.app.targetDir != older {
+		t.Fatalf("Expected %s to be open", older)
+	}
+	if m.listVisible {
+		t.Error("Expected the list to be hidden once a workspace is open")
+	}
+
+	// The list can be shown again and dismissed with esc
+	m.Update(ShowWorkspaceListMsg{})
+	if !m.listVisible || m.workspaces[0].Path != older {
+		t.Errorf("Expected the list to be shown with the open workspace first, got %+v", m.workspaces)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.listVisible {
+		t.Error("Expected esc to return to the open workspace")
+	}
+}
+
+func TestWorkspaceListClearsWorkspace(t *testing.T) {
+	m, older, newer := newTestWorkspaceList(t)
+	if err := m.Open(newer); err != nil {
+		t.Fatalf("Failed to open workspace: %v", err)
+	}
+	m.Update(ShowWorkspaceListMsg{})
+
+	// The open workspace can't be cleared
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if len(m.workspaces) != 2 || m.err == "" {
+		t.Errorf("Expected clearing the open workspace to fail, got %d workspaces, err %q", len(m.workspaces), m.err)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if len(m.workspaces) != 1 || m.workspaces[0].Path == older {
+		t.Errorf("Expected %s to be cleared, got %+v", older, m.workspaces)
+	}
+	if len(m.configManager.GetRecentWorkspaces()) != 1 {
+		t.Error("Expected the cleared workspace to be removed from the config")
+	}
+}
+
+func TestWorkspaceListKeyFromApp(t *testing.T) {
+	app := createTestApp(t)
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if cmd == nil {
+		t.Fatal("Expected a command for the workspace list key")
+	}
+	if _, ok := cmd().(ShowWorkspaceListMsg); !ok {
+		t.Errorf("Expected ShowWorkspaceListMsg, got %T", cmd())
+	}
+
+	// While typing in the chat, ctrl+w deletes a word instead
+	app.focused = ChatPanel
+	app.chat.textarea.SetValue("review this")
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if got := app.chat.textarea.Value(); got != "review " {
+		t.Errorf("Expected ctrl+w to delete the last word of the chat, got %q", got)
+	}
+}
+
+func TestWorkspaceListSortModes(t *testing.T) {
+	m, older, newer := newTestWorkspaceList(t)
+	workspace := m.configManager.GetWorkspace(older)
+	workspace.SelectedFiles = []config.SelectedFileState{{Path: older + "/main.go"}}
+	workspace.LastAccessed = time.Now().Add(-time.Hour)
+
+	// Most recent first, then by path, then by file count
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if !strings.Contains(m.View(), "(by name)") {
+		t.Errorf("Expected the sort mode in the title, got:\n%s", m.View())
+	}
+	first, second := older, newer
+	if newer < older {
+		first, second = newer, older
+	}
+	if m.workspaces[0].Path != first || m.workspaces[1].Path != second {
+		t.Errorf("Expected workspaces by path, got %+v", m.workspaces)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m.workspaces[0].Path != older || !strings.Contains(m.View(), "1 files") {
+		t.Errorf("Expected the workspace with selected files first, got %+v", m.workspaces)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m.workspaces[0].Path != newer {
+		t.Errorf("Expected the most recent workspace first again, got %+v", m.workspaces)
+	}
+}
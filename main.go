@@ -1,60 +1,245 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"coding-prompts-tui/internal/cli"
 	"coding-prompts-tui/internal/config"
+	"coding-prompts-tui/internal/filesystem"
 	"coding-prompts-tui/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	// Check for directory argument
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <directory>\n", os.Args[0])
+	headless := flag.Bool("headless", false, "generate the prompt to stdout without starting the TUI")
+	files := flag.String("files", "", "comma-separated files to include, or - to read them from stdin one per line (default in headless mode: stdin)")
+	personas := flag.String("personas", "", "comma-separated personas to use in headless mode")
+	userPrompt := flag.String("prompt", "", "user prompt to use in headless mode")
+	format := flag.String("format", "xml", "output format in headless mode (xml, json or markdown)")
+	output := flag.String("output", "", "write the prompt to this file without starting the TUI (- for stdout)")
+	watch := flag.Bool("watch", false, "regenerate and copy the prompt whenever a selected file changes")
+	completion := flag.String("completion", "", "print a completion script for the given shell (bash, zsh or fish)")
+	listPersonas := flag.Bool("list-personas", false, "print the personas of the directory, one per line, without starting the TUI")
+	listWorkspaces := flag.Bool("list-workspaces", false, "print recent workspaces, most recently opened first, without starting the TUI")
+	manifest := flag.String("manifest", "", "file listing the files to select when the TUI starts, one per line (# starts a comment)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [directory]\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Example: %s .\n", os.Args[0])
-		os.Exit(1)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Print shell completions without requiring a directory
+	if *completion != "" {
+		if err := cli.GenerateCompletion(*completion, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Print persona names for scripts; the directory defaults to the current one
+	if *listPersonas {
+		targetDir := "."
+		if flag.NArg() > 0 {
+			targetDir = flag.Arg(0)
+		}
+		if err := cli.ListPersonas(targetDir, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// -output implies headless mode
+	if *output != "" {
+		*headless = true
 	}
 
-	targetDir := os.Args[1]
+	// "-files -" reads the file list from stdin
+	filesFromStdin := *files == "-"
 
-	// Verify directory exists
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Directory '%s' does not exist\n", targetDir)
+	// Headless mode needs a directory; the TUI shows recent workspaces without one
+	if (*headless || filesFromStdin || *manifest != "") && flag.NArg() < 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	// Get absolute path for workspace management
-	absPath, err := filepath.Abs(targetDir)
+	// Generate the prompt without the TUI
+	if *headless {
+		targetDir := flag.Arg(0)
+
+		// Verify directory exists
+		if _, err := os.Stat(targetDir); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: Directory '%s' does not exist\n", targetDir)
+			os.Exit(1)
+		}
+
+		fileList := cli.SplitList(*files)
+		if len(fileList) == 0 || filesFromStdin {
+			var err error
+			fileList, err = cli.ReadFileList(os.Stdin)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		out := os.Stdout
+		if *output != "" && *output != "-" {
+			file, err := os.Create(*output)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			out = file
+		}
+		err := cli.RunHeadless(targetDir, fileList, cli.SplitList(*personas), *userPrompt, *format, out)
+		if out != os.Stdout {
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating prompt: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Initialize config manager, forgetting workspaces per the global settings
+	globalSettings, err := config.NewSettingsManager("")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error initializing settings manager: %v\n", err)
 		os.Exit(1)
 	}
-
-	// Initialize config manager
-	cfgManager, err := config.NewManager()
+	cfgManager, err := config.NewManagerWithPruneAge(globalSettings.GetPruneWorkspacesDays())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing config manager: %v\n", err)
 		os.Exit(1)
 	}
+	cfgManager.SetMaxRecentWorkspaces(globalSettings.GetMaxRecentWorkspaces())
 
-	// Initialize settings manager
-	settingsManager, err := config.NewSettingsManager()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing settings manager: %v\n", err)
-		os.Exit(1)
+	// Print recent workspaces for scripts
+	if *listWorkspaces {
+		if err := cli.ListWorkspaces(cfgManager.GetRecentWorkspaces(), os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Files read from stdin or the manifest replace the selection of the workspace
+	// opened first, with notice announcing them
+	var preselected []string
+	var notice string
+
+	// openWorkspace creates the TUI application for a workspace directory
+	openWorkspace := func(absPath string) (*tui.App, error) {
+		// Initialize settings manager
+		settingsManager, err := config.NewSettingsManager(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing settings manager: %w", err)
+		}
+
+		// Look for state left behind by a crash before opening the workspace updates the config
+		autosave := config.NewAutosaveManager(cfgManager.ConfigPath(), absPath)
+		recovered := autosave.Recoverable()
+
+		// Get the workspace state
+		workspace := cfgManager.GetWorkspace(absPath)
+		if preselected != nil {
+			workspace.SelectedFiles = config.NewSelectedFileStates(preselected)
+			preselected = nil
+		}
+
+		// Initialize TUI application
+		app := tui.NewApp(absPath, tui.WithConfigManager(cfgManager), tui.WithSettingsManager(settingsManager), tui.WithWorkspace(workspace))
+		app.EnableAutosave(autosave, recovered)
+		if notice != "" {
+			app.AnnounceOnStart(notice)
+			notice = ""
+		}
+
+		// Keep the file tree current; it just isn't refreshed if the directory can't be watched
+		if dirWatcher, err := filesystem.NewDirWatcher(absPath, filesystem.DefaultDebounce); err == nil {
+			app.EnableDirWatch(dirWatcher)
+		}
+
+		// Watch selected files for changes if requested
+		if *watch {
+			watcher, err := filesystem.NewFileWatcher(filesystem.DefaultDebounce)
+			if err != nil {
+				return nil, fmt.Errorf("error starting watch mode: %w", err)
+			}
+			app.EnableWatchMode(watcher)
+		}
+		return app, nil
 	}
 
-	// Get the workspace state
-	workspace := cfgManager.GetWorkspace(absPath)
+	// No workspace is open yet, so the workspace list takes its colors from the global settings
+	theme := tui.ThemeFromSettings(globalSettings.GetThemeSettings())
+
+	// Without a directory argument, start with the list of recent workspaces
+	root := tui.NewWorkspaceListModel(cfgManager, openWorkspace, theme)
+	if flag.NArg() > 0 {
+		targetDir := flag.Arg(0)
+
+		// Verify directory exists
+		if _, err := os.Stat(targetDir); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: Directory '%s' does not exist\n", targetDir)
+			os.Exit(1)
+		}
+
+		// Get absolute path for workspace management
+		absPath, err := filepath.Abs(targetDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting absolute path: %v\n", err)
+			os.Exit(1)
+		}
 
-	// Initialize TUI application
-	app := tui.NewApp(absPath, cfgManager, settingsManager, workspace)
+		if filesFromStdin {
+			fileList, err := cli.ReadFileList(os.Stdin)
+			if err == nil {
+				preselected, err = cli.ResolveFileList(absPath, fileList)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if *manifest != "" {
+			files, err := cli.ReadManifest(*manifest, absPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			// An empty manifest still replaces the selection
+			preselected = append(preselected, files...)
+			if preselected == nil {
+				preselected = []string{}
+			}
+			notice = fmt.Sprintf("selected %d files from %s", len(files), filepath.Base(*manifest))
+		}
+
+		if err := root.Open(absPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	defer root.Close()
 
 	// Create Bubble Tea program with alt screen and mouse support
-	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if filesFromStdin {
+		// stdin was used for the file list, so read keys from the terminal
+		opts = append(opts, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(root, opts...)
 
 	// Run the program
 	if _, err := p.Run(); err != nil {
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildBinary builds the program into dir and returns its path
+func buildBinary(t *testing.T, dir string) string {
+	t.Helper()
+	binary := filepath.Join(dir, "prompter")
+	if out, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build: %v\n%s", err, out)
+	}
+	return binary
+}
+
+// TestFilesFromStdin builds the binary and pipes a file list to it with "-files -"
+func TestFilesFromStdin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+
+	tmp := t.TempDir()
+	binary := buildBinary(t, tmp)
+
+	project := filepath.Join(tmp, "project")
+	files := map[string]string{
+		"main.go":     "package main",
+		"pkg/util.go": "package pkg",
+		"README.md":   "# Project",
+	}
+	for name, content := range files {
+		path := filepath.Join(project, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	output := filepath.Join(tmp, "prompt.xml")
+	cmd := exec.Command(binary, "-files", "-", "-output", output, project)
+	cmd.Stdin = strings.NewReader("main.go\n" + filepath.Join(project, "pkg", "util.go") + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read prompt: %v", err)
+	}
+	var p struct {
+		Files []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Output is not valid XML: %v\n%s", err, data)
+	}
+	if len(p.Files) != 2 || p.Files[0].Name != "main.go" || p.Files[1].Name != filepath.Join("pkg", "util.go") {
+		t.Errorf("Expected main.go and pkg/util.go, got %+v", p.Files)
+	}
+
+	// Paths outside the project are rejected
+	cmd = exec.Command(binary, "-files", "-", "-output", "-", project)
+	cmd.Stdin = strings.NewReader(filepath.Join(tmp, "prompt.xml") + "\n")
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Errorf("Expected an error for a file outside the project, got:\n%s", out)
+	}
+}
+
+// TestListCommands builds the binary and checks the output of -list-personas
+// and -list-workspaces
+func TestListCommands(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+
+	tmp := t.TempDir()
+	binary := buildBinary(t, tmp)
+
+	project := filepath.Join(tmp, "project")
+	if err := os.MkdirAll(filepath.Join(project, "personas"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"reviewer.md", "default.md"} {
+		if err := os.WriteFile(filepath.Join(project, "personas", name), []byte("persona"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	out, err := exec.Command(binary, "-list-personas", project).Output()
+	if err != nil {
+		t.Fatalf("Failed to run -list-personas: %v", err)
+	}
+	if string(out) != "default\nreviewer\n" {
+		t.Errorf("Expected default and reviewer, got %q", out)
+	}
+
+	// Workspaces come from the config in an isolated config directory
+	home := filepath.Join(tmp, "home")
+	configHome := filepath.Join(tmp, "config")
+	if err := os.MkdirAll(filepath.Join(configHome, "prompter"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	recent := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	older := recent.Add(-24 * time.Hour)
+	cfg := map[string]any{
+		"recent_workspaces": map[string]any{
+			"/projects/older":  map[string]any{"path": "/projects/older", "last_accessed": older},
+			"/projects/recent": map[string]any{"path": "/projects/recent", "last_accessed": recent},
+		},
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "prompter", "config.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(binary, "-list-workspaces")
+	cmd.Env = append(os.Environ(), "HOME="+home, "XDG_CONFIG_HOME="+configHome)
+	out, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to run -list-workspaces: %v", err)
+	}
+	expected := "/projects/recent\t" + recent.Format(time.RFC3339) + "\n" +
+		"/projects/older\t" + older.Format(time.RFC3339) + "\n"
+	if string(out) != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}